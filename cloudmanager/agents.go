@@ -0,0 +1,384 @@
+// Copyright 2020 MongoDB Inc
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package cloudmanager
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"net/http"
+	"strings"
+	"time"
+
+	atlas "github.com/mongodb/go-client-mongodb-atlas/mongodbatlas"
+)
+
+const (
+	agentsBasePath       = "groups/%s/agents"
+	agentAPIKeysBasePath = "groups/%s/agentapikeys"
+
+	// DefaultRotationPollInterval the default interval between two checks of the agents during a key rotation
+	DefaultRotationPollInterval = 10 * time.Second
+	// DefaultRotationTimeout the default time to wait for the agents to report with a rotated key
+	DefaultRotationTimeout = 15 * time.Minute
+	// DefaultAgentDownAfter the default time after which an agent which did not contact Cloud Manager is considered down
+	DefaultAgentDownAfter = 5 * time.Minute
+)
+
+// ErrRotationTimeout is returned when agents did not report with a rotated key in time;
+// the previous key is kept in that case
+var ErrRotationTimeout = errors.New("timed out waiting for the agents to report with the new API key")
+
+// AgentType the kind of an agent
+type AgentType string
+
+// Agent types
+const (
+	AgentTypeAutomation AgentType = "AUTOMATION"
+	AgentTypeMonitoring AgentType = "MONITORING"
+	AgentTypeBackup     AgentType = "BACKUP"
+)
+
+// AgentTypes all agent types
+var AgentTypes = []AgentType{AgentTypeAutomation, AgentTypeMonitoring, AgentTypeBackup}
+
+// AgentState the state of an agent
+type AgentState string
+
+// Agent states
+const (
+	AgentStateActive      AgentState = "ACTIVE"       // the agent is working
+	AgentStateStandby     AgentState = "STANDBY"      // the agent is waiting to take over from the active one
+	AgentStateNoProcesses AgentState = "NO_PROCESSES" // the agent is not managing any process
+)
+
+// AgentsService is an interface for interfacing with the Agents and Agent API Keys
+// endpoints of the MongoDB Cloud Manager API.
+// See more: https://docs.cloudmanager.mongodb.com/reference/api/agents/
+type AgentsService interface {
+	List(context.Context, string, AgentType, *atlas.ListOptions) (*Agents, *atlas.Response, error)
+	ListAPIKeys(context.Context, string) ([]*AgentAPIKey, *atlas.Response, error)
+	CreateAPIKey(context.Context, string, string) (*AgentAPIKey, *atlas.Response, error)
+	DeleteAPIKey(context.Context, string, string) (*atlas.Response, error)
+	RotateAPIKey(context.Context, string, string, *RotateAPIKeyOptions) (*APIKeyRotation, error)
+}
+
+// AgentsServiceOp handles communication with the Agents related methods of the
+// MongoDB Cloud Manager API
+type AgentsServiceOp struct {
+	client *Client
+}
+
+var _ AgentsService = &AgentsServiceOp{}
+
+// Agent represents an agent of a project
+type Agent struct {
+	ConfCount int        `json:"confCount,omitempty"`
+	Hostname  string     `json:"hostname"`
+	IsManaged bool       `json:"isManaged,omitempty"`
+	LastConf  time.Time  `json:"lastConf"` // the last time the agent retrieved its configuration
+	LastPing  *time.Time `json:"lastPing,omitempty"`
+	PingCount int        `json:"pingCount,omitempty"`
+	StateName AgentState `json:"stateName"`
+	TypeName  AgentType  `json:"typeName"`
+}
+
+// Agents represents a array of agents
+type Agents struct {
+	Links      []*atlas.Link `json:"links"`
+	Results    []*Agent      `json:"results"`
+	TotalCount int           `json:"totalCount"`
+}
+
+// AgentAPIKey represents an API key the agents use to authenticate; the key is only returned in full when created
+type AgentAPIKey struct {
+	ID            string `json:"_id,omitempty"`
+	CreatedBy     string `json:"createdBy,omitempty"`
+	CreatedIPAddr string `json:"createdIpAddr,omitempty"`
+	CreatedTime   int64  `json:"createdTime,omitempty"` // milliseconds since epoch
+	CreatedUserID string `json:"createdUserId,omitempty"`
+	Desc          string `json:"desc,omitempty"`
+	Key           string `json:"key,omitempty"`
+}
+
+// RotateAPIKeyOptions configure RotateAPIKey
+type RotateAPIKeyOptions struct {
+	// Desc the description of the new key
+	Desc string
+	// Deploy configures the agents with the new key, e.g. by updating their config files and restarting them
+	Deploy func(context.Context, *AgentAPIKey) error
+	// PollInterval DefaultRotationPollInterval if zero
+	PollInterval time.Duration
+	// Timeout DefaultRotationTimeout if zero
+	Timeout time.Duration
+	// DownAfter agents which neither pinged nor retrieved their configuration for this long when the rotation starts
+	// are considered down, and are not waited for; DefaultAgentDownAfter if zero
+	DownAfter time.Duration
+}
+
+// APIKeyRotation the outcome of RotateAPIKey
+type APIKeyRotation struct {
+	// Key the new key, set as soon as it is created, even if the rotation fails afterwards
+	Key *AgentAPIKey
+	// DownAgents the agents which were down when the rotation started; they were not waited for,
+	// and need the new key deployed before they come back, since the old key is deleted
+	DownAgents []*Agent
+}
+
+// List gets the agents of the specified type in a project.
+// See more: https://docs.cloudmanager.mongodb.com/reference/api/agents-get-by-type/
+func (s *AgentsServiceOp) List(ctx context.Context, projectID string, agentType AgentType, opts *atlas.ListOptions) (*Agents, *atlas.Response, error) {
+	if projectID == "" {
+		return nil, nil, atlas.NewArgError("projectID", "must be set")
+	}
+	if agentType == "" {
+		return nil, nil, atlas.NewArgError("agentType", "must be set")
+	}
+
+	basePath := fmt.Sprintf(agentsBasePath, projectID)
+	path, err := setListOptions(fmt.Sprintf("%s/%s", basePath, agentType), opts)
+	if err != nil {
+		return nil, nil, err
+	}
+
+	req, err := s.client.NewRequest(ctx, http.MethodGet, path, nil)
+	if err != nil {
+		return nil, nil, err
+	}
+
+	root := new(Agents)
+	resp, err := s.client.Do(ctx, req, root)
+	if err != nil {
+		return nil, resp, err
+	}
+
+	if l := root.Links; l != nil {
+		resp.Links = l
+	}
+
+	return root, resp, nil
+}
+
+// ListAPIKeys gets the agent API keys of a project; the keys are redacted.
+// See more: https://docs.cloudmanager.mongodb.com/reference/api/agentapikeys/get-all-agent-api-keys-for-project/
+func (s *AgentsServiceOp) ListAPIKeys(ctx context.Context, projectID string) ([]*AgentAPIKey, *atlas.Response, error) {
+	if projectID == "" {
+		return nil, nil, atlas.NewArgError("projectID", "must be set")
+	}
+
+	path := fmt.Sprintf(agentAPIKeysBasePath, projectID)
+
+	req, err := s.client.NewRequest(ctx, http.MethodGet, path, nil)
+	if err != nil {
+		return nil, nil, err
+	}
+
+	var root []*AgentAPIKey
+	resp, err := s.client.Do(ctx, req, &root)
+	if err != nil {
+		return nil, resp, err
+	}
+
+	return root, resp, nil
+}
+
+// CreateAPIKey creates an agent API key in a project.
+// See more: https://docs.cloudmanager.mongodb.com/reference/api/agentapikeys/create-one-agent-api-key/
+func (s *AgentsServiceOp) CreateAPIKey(ctx context.Context, projectID, desc string) (*AgentAPIKey, *atlas.Response, error) {
+	if projectID == "" {
+		return nil, nil, atlas.NewArgError("projectID", "must be set")
+	}
+	if desc == "" {
+		return nil, nil, atlas.NewArgError("desc", "must be set")
+	}
+
+	path := fmt.Sprintf(agentAPIKeysBasePath, projectID)
+
+	req, err := s.client.NewRequest(ctx, http.MethodPost, path, &AgentAPIKey{Desc: desc})
+	if err != nil {
+		return nil, nil, err
+	}
+
+	root := new(AgentAPIKey)
+	resp, err := s.client.Do(ctx, req, root)
+	if err != nil {
+		return nil, resp, err
+	}
+
+	return root, resp, err
+}
+
+// DeleteAPIKey deletes an agent API key; agents still using it can no longer authenticate.
+// See more: https://docs.cloudmanager.mongodb.com/reference/api/agentapikeys/delete-one-agent-api-key/
+func (s *AgentsServiceOp) DeleteAPIKey(ctx context.Context, projectID, keyID string) (*atlas.Response, error) {
+	if projectID == "" {
+		return nil, atlas.NewArgError("projectID", "must be set")
+	}
+	if keyID == "" {
+		return nil, atlas.NewArgError("keyID", "must be set")
+	}
+
+	basePath := fmt.Sprintf(agentAPIKeysBasePath, projectID)
+	path := fmt.Sprintf("%s/%s", basePath, keyID)
+
+	req, err := s.client.NewRequest(ctx, http.MethodDelete, path, nil)
+	if err != nil {
+		return nil, err
+	}
+
+	resp, err := s.client.Do(ctx, req, nil)
+
+	return resp, err
+}
+
+// RotateAPIKey replaces an agent API key: it creates a new key, hands it to opts.Deploy,
+// waits until every agent which is up retrieved its configuration after the deployment,
+// and only then deletes the old key.
+//
+// The API does not tell which key an agent authenticates with, so an agent retrieving its configuration
+// after Deploy returned is taken as proof that it uses the new key: Deploy must only return once the agents
+// were restarted with it. Agents which are down when the rotation starts would block the rotation until the timeout;
+// they are skipped instead, and reported in APIKeyRotation.DownAgents.
+func (s *AgentsServiceOp) RotateAPIKey(ctx context.Context, projectID, oldKeyID string, opts *RotateAPIKeyOptions) (*APIKeyRotation, error) {
+	if oldKeyID == "" {
+		return nil, atlas.NewArgError("oldKeyID", "must be set")
+	}
+	if opts == nil || opts.Deploy == nil {
+		return nil, atlas.NewArgError("opts.Deploy", "must be set")
+	}
+
+	downAfter := opts.DownAfter
+	if downAfter <= 0 {
+		downAfter = DefaultAgentDownAfter
+	}
+	agents, err := s.listAllAgents(ctx, projectID)
+	if err != nil {
+		return nil, err
+	}
+
+	rotation := &APIKeyRotation{}
+	up := make(map[string]bool, len(agents))
+	threshold := time.Now().Add(-downAfter)
+	for _, agent := range agents {
+		if isAgentDown(agent, threshold) {
+			rotation.DownAgents = append(rotation.DownAgents, agent)
+		} else {
+			up[describeAgent(agent)] = true
+		}
+	}
+
+	desc := opts.Desc
+	if desc == "" {
+		desc = fmt.Sprintf("rotated %s", time.Now().UTC().Format(time.RFC3339))
+	}
+
+	key, _, err := s.CreateAPIKey(ctx, projectID, desc)
+	if err != nil {
+		return rotation, err
+	}
+	rotation.Key = key
+
+	if err := opts.Deploy(ctx, key); err != nil {
+		return rotation, err
+	}
+	// lastConf has a granularity of seconds
+	deployed := time.Now().Truncate(time.Second)
+
+	interval := opts.PollInterval
+	if interval <= 0 {
+		interval = DefaultRotationPollInterval
+	}
+	timeout := opts.Timeout
+	if timeout <= 0 {
+		timeout = DefaultRotationTimeout
+	}
+	deadline := time.Now().Add(timeout)
+
+	for {
+		pending, err := s.pendingAgents(ctx, projectID, deployed, up)
+		if err != nil {
+			return rotation, err
+		}
+		if len(pending) == 0 {
+			break
+		}
+		if time.Now().After(deadline) {
+			return rotation, fmt.Errorf("%w: %s", ErrRotationTimeout, strings.Join(pending, ", "))
+		}
+
+		select {
+		case <-ctx.Done():
+			return rotation, ctx.Err()
+		case <-time.After(interval):
+		}
+	}
+
+	_, err = s.DeleteAPIKey(ctx, projectID, oldKeyID)
+	return rotation, err
+}
+
+// listAllAgents gets the agents of all types in a project
+func (s *AgentsServiceOp) listAllAgents(ctx context.Context, projectID string) ([]*Agent, error) {
+	var result []*Agent
+	for _, agentType := range AgentTypes {
+		agentType := agentType
+		opts := &atlas.ListOptions{}
+		err := listPages(opts, func() (int, int, error) {
+			agents, _, err := s.List(ctx, projectID, agentType, opts)
+			if err != nil {
+				return 0, 0, err
+			}
+			result = append(result, agents.Results...)
+			return len(agents.Results), agents.TotalCount, nil
+		})
+		if err != nil {
+			return nil, err
+		}
+	}
+
+	return result, nil
+}
+
+// pendingAgents describes the agents which were up, but did not retrieve their configuration since the specified time
+func (s *AgentsServiceOp) pendingAgents(ctx context.Context, projectID string, since time.Time, up map[string]bool) ([]string, error) {
+	agents, err := s.listAllAgents(ctx, projectID)
+	if err != nil {
+		return nil, err
+	}
+
+	var pending []string
+	for _, agent := range agents {
+		if description := describeAgent(agent); up[description] && agent.LastConf.Before(since) {
+			pending = append(pending, description)
+		}
+	}
+
+	return pending, nil
+}
+
+// isAgentDown returns true if the agent neither pinged nor retrieved its configuration since the threshold
+func isAgentDown(agent *Agent, threshold time.Time) bool {
+	last := agent.LastConf
+	if agent.LastPing != nil && agent.LastPing.After(last) {
+		last = *agent.LastPing
+	}
+
+	return last.Before(threshold)
+}
+
+// describeAgent identifies an agent, e.g. "AUTOMATION agent on host1"
+func describeAgent(agent *Agent) string {
+	return fmt.Sprintf("%s agent on %s", agent.TypeName, agent.Hostname)
+}
@@ -0,0 +1,308 @@
+// Copyright 2020 MongoDB Inc
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package cloudmanager
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"net/http"
+	"strings"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/go-test/deep"
+	"github.com/mongodb/go-client-mongodb-atlas/mongodbatlas"
+)
+
+func TestAgents_List(t *testing.T) {
+	setup()
+	defer teardown()
+
+	projectID := "5a0a1e7e0f2912c554080adc"
+
+	mux.HandleFunc(fmt.Sprintf("/groups/%s/agents/MONITORING", projectID), func(w http.ResponseWriter, r *http.Request) {
+		testMethod(t, r, http.MethodGet)
+		_, _ = fmt.Fprint(w, `{
+			"links": [],
+			"results": [{
+				"confCount": 59,
+				"hostname": "example",
+				"isManaged": true,
+				"lastConf": "2015-06-18T14:21:42Z",
+				"lastPing": "2015-06-18T14:21:42Z",
+				"pingCount": 6,
+				"stateName": "ACTIVE",
+				"typeName": "MONITORING"
+			}],
+			"totalCount": 1
+		}`)
+	})
+
+	agents, _, err := client.Agents.List(ctx, projectID, AgentTypeMonitoring, nil)
+	if err != nil {
+		t.Fatalf("Agents.List returned error: %v", err)
+	}
+
+	lastConf := time.Date(2015, 6, 18, 14, 21, 42, 0, time.UTC)
+	expected := &Agents{
+		Links: []*mongodbatlas.Link{},
+		Results: []*Agent{
+			{
+				ConfCount: 59,
+				Hostname:  "example",
+				IsManaged: true,
+				LastConf:  lastConf,
+				LastPing:  &lastConf,
+				PingCount: 6,
+				StateName: AgentStateActive,
+				TypeName:  AgentTypeMonitoring,
+			},
+		},
+		TotalCount: 1,
+	}
+
+	if diff := deep.Equal(agents, expected); diff != nil {
+		t.Error(diff)
+	}
+}
+
+func TestAgents_ListAPIKeys(t *testing.T) {
+	setup()
+	defer teardown()
+
+	projectID := "5a0a1e7e0f2912c554080adc"
+
+	mux.HandleFunc(fmt.Sprintf("/groups/%s/agentapikeys", projectID), func(w http.ResponseWriter, r *http.Request) {
+		testMethod(t, r, http.MethodGet)
+		_, _ = fmt.Fprint(w, `[{
+			"_id": "5c47503320eef5da2fdbd8c9",
+			"createdBy": "PUBLIC_API",
+			"createdIpAddr": "192.0.2.1",
+			"createdTime": 1557438431196,
+			"createdUserId": "5c47503320eef5da2fdbd8c8",
+			"desc": "Agent API Key for this project",
+			"key": "****************************8b87"
+		}]`)
+	})
+
+	keys, _, err := client.Agents.ListAPIKeys(ctx, projectID)
+	if err != nil {
+		t.Fatalf("Agents.ListAPIKeys returned error: %v", err)
+	}
+
+	expected := []*AgentAPIKey{
+		{
+			ID:            "5c47503320eef5da2fdbd8c9",
+			CreatedBy:     "PUBLIC_API",
+			CreatedIPAddr: "192.0.2.1",
+			CreatedTime:   1557438431196,
+			CreatedUserID: "5c47503320eef5da2fdbd8c8",
+			Desc:          "Agent API Key for this project",
+			Key:           "****************************8b87",
+		},
+	}
+
+	if diff := deep.Equal(keys, expected); diff != nil {
+		t.Error(diff)
+	}
+}
+
+func TestAgents_CreateAPIKey(t *testing.T) {
+	setup()
+	defer teardown()
+
+	projectID := "5a0a1e7e0f2912c554080adc"
+
+	mux.HandleFunc(fmt.Sprintf("/groups/%s/agentapikeys", projectID), func(w http.ResponseWriter, r *http.Request) {
+		testMethod(t, r, http.MethodPost)
+
+		expected := map[string]interface{}{"desc": "Agent API Key for this project"}
+		var v map[string]interface{}
+		if err := json.NewDecoder(r.Body).Decode(&v); err != nil {
+			t.Fatalf("decode json: %v", err)
+		}
+		if diff := deep.Equal(v, expected); diff != nil {
+			t.Error(diff)
+		}
+
+		_, _ = fmt.Fprint(w, `{
+			"_id": "5c47503320eef5da2fdbd8c9",
+			"createdBy": "PUBLIC_API",
+			"createdIpAddr": "192.0.2.1",
+			"createdTime": 1557438431196,
+			"createdUserId": "5c47503320eef5da2fdbd8c8",
+			"desc": "Agent API Key for this project",
+			"key": "****************************8b87"
+		}`)
+	})
+
+	key, _, err := client.Agents.CreateAPIKey(ctx, projectID, "Agent API Key for this project")
+	if err != nil {
+		t.Fatalf("Agents.CreateAPIKey returned error: %v", err)
+	}
+
+	expected := &AgentAPIKey{
+		ID:            "5c47503320eef5da2fdbd8c9",
+		CreatedBy:     "PUBLIC_API",
+		CreatedIPAddr: "192.0.2.1",
+		CreatedTime:   1557438431196,
+		CreatedUserID: "5c47503320eef5da2fdbd8c8",
+		Desc:          "Agent API Key for this project",
+		Key:           "****************************8b87",
+	}
+
+	if diff := deep.Equal(key, expected); diff != nil {
+		t.Error(diff)
+	}
+}
+
+func TestAgents_DeleteAPIKey(t *testing.T) {
+	setup()
+	defer teardown()
+
+	projectID := "5a0a1e7e0f2912c554080adc"
+	keyID := "5c47503320eef5da2fdbd8c9"
+
+	mux.HandleFunc(fmt.Sprintf("/groups/%s/agentapikeys/%s", projectID, keyID), func(w http.ResponseWriter, r *http.Request) {
+		testMethod(t, r, http.MethodDelete)
+	})
+
+	_, err := client.Agents.DeleteAPIKey(ctx, projectID, keyID)
+	if err != nil {
+		t.Fatalf("Agents.DeleteAPIKey returned error: %v", err)
+	}
+}
+
+// fakeAgentsServer serves two automation agents: host1, which reports with the new key once it is deployed,
+// and host2, which is down
+type fakeAgentsServer struct {
+	mu       sync.Mutex
+	deployed bool
+	polls    int
+	deleted  []string
+}
+
+func (s *fakeAgentsServer) register(t *testing.T, projectID string) {
+	for _, agentType := range AgentTypes {
+		agentType := agentType
+		mux.HandleFunc(fmt.Sprintf("/groups/%s/agents/%s", projectID, agentType), func(w http.ResponseWriter, r *http.Request) {
+			s.mu.Lock()
+			defer s.mu.Unlock()
+
+			if agentType != AgentTypeAutomation {
+				_, _ = fmt.Fprint(w, `{"results": [], "totalCount": 0}`)
+				return
+			}
+
+			// host1 picks the new key up on the second poll after the deployment
+			lastConf := time.Now().Add(-time.Hour)
+			if s.deployed {
+				s.polls++
+				if s.polls > 1 {
+					lastConf = time.Now().Add(time.Second)
+				}
+			}
+			down := time.Now().Add(-time.Hour).UTC().Format(time.RFC3339)
+			_, _ = fmt.Fprintf(w, `{"results": [
+				{"hostname": "host1", "lastConf": %q, "lastPing": %q, "stateName": "ACTIVE", "typeName": "AUTOMATION"},
+				{"hostname": "host2", "lastConf": %q, "lastPing": %q, "stateName": "ACTIVE", "typeName": "AUTOMATION"}
+			], "totalCount": 2}`, lastConf.UTC().Format(time.RFC3339), time.Now().UTC().Format(time.RFC3339), down, down)
+		})
+	}
+
+	mux.HandleFunc(fmt.Sprintf("/groups/%s/agentapikeys", projectID), func(w http.ResponseWriter, r *http.Request) {
+		testMethod(t, r, http.MethodPost)
+		_, _ = fmt.Fprint(w, `{"_id": "new", "desc": "rotated", "key": "secret"}`)
+	})
+	mux.HandleFunc(fmt.Sprintf("/groups/%s/agentapikeys/", projectID), func(w http.ResponseWriter, r *http.Request) {
+		testMethod(t, r, http.MethodDelete)
+		s.mu.Lock()
+		defer s.mu.Unlock()
+		s.deleted = append(s.deleted, r.URL.Path)
+	})
+}
+
+func TestAgents_RotateAPIKey(t *testing.T) {
+	setup()
+	defer teardown()
+
+	projectID := "5a0a1e7e0f2912c554080adc"
+	fake := &fakeAgentsServer{}
+	fake.register(t, projectID)
+
+	opts := &RotateAPIKeyOptions{
+		Desc: "rotated",
+		Deploy: func(_ context.Context, key *AgentAPIKey) error {
+			if key.Key != "secret" {
+				t.Errorf("expected the new key to be deployed, got %q", key.Key)
+			}
+			fake.mu.Lock()
+			defer fake.mu.Unlock()
+			fake.deployed = true
+			return nil
+		},
+		PollInterval: 10 * time.Millisecond,
+		Timeout:      5 * time.Second,
+	}
+
+	rotation, err := client.Agents.RotateAPIKey(ctx, projectID, "old", opts)
+	if err != nil {
+		t.Fatalf("Agents.RotateAPIKey returned error: %v", err)
+	}
+	if rotation.Key.ID != "new" {
+		t.Errorf("expected the new key, got %+v", rotation.Key)
+	}
+	if len(rotation.DownAgents) != 1 || rotation.DownAgents[0].Hostname != "host2" {
+		t.Errorf("expected host2 to be reported as down, got %+v", rotation.DownAgents)
+	}
+
+	expected := []string{fmt.Sprintf("/groups/%s/agentapikeys/old", projectID)}
+	if diff := deep.Equal(fake.deleted, expected); diff != nil {
+		t.Error(diff)
+	}
+}
+
+func TestAgents_RotateAPIKey_timeout(t *testing.T) {
+	setup()
+	defer teardown()
+
+	projectID := "5a0a1e7e0f2912c554080adc"
+	fake := &fakeAgentsServer{}
+	fake.register(t, projectID)
+
+	opts := &RotateAPIKeyOptions{
+		// the agents never report with the new key
+		Deploy:       func(context.Context, *AgentAPIKey) error { return nil },
+		PollInterval: 10 * time.Millisecond,
+		Timeout:      50 * time.Millisecond,
+	}
+
+	rotation, err := client.Agents.RotateAPIKey(ctx, projectID, "old", opts)
+	if !errors.Is(err, ErrRotationTimeout) {
+		t.Fatalf("expected ErrRotationTimeout, got %v", err)
+	}
+	if !strings.HasSuffix(err.Error(), ": AUTOMATION agent on host1") {
+		t.Errorf("expected only host1 to be waited for, got %v", err)
+	}
+	if rotation == nil || rotation.Key == nil || rotation.Key.ID != "new" {
+		t.Errorf("expected the new key to be returned, got %+v", rotation)
+	}
+	if len(fake.deleted) != 0 {
+		t.Errorf("expected the old key to be kept, deleted %v", fake.deleted)
+	}
+}
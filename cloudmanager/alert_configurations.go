@@ -0,0 +1,559 @@
+// Copyright 2020 MongoDB Inc
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package cloudmanager
+
+import (
+	"context"
+	"fmt"
+	"net/http"
+	"sort"
+
+	atlas "github.com/mongodb/go-client-mongodb-atlas/mongodbatlas"
+)
+
+const (
+	alertConfigurationsBasePath = "groups/%s/alertConfigs"
+)
+
+// MatcherOperator compares a matcher field against its value
+type MatcherOperator string
+
+// Matcher operators
+const (
+	MatcherEquals      MatcherOperator = "EQUALS"
+	MatcherNotEquals   MatcherOperator = "NOT_EQUALS"
+	MatcherContains    MatcherOperator = "CONTAINS"
+	MatcherNotContains MatcherOperator = "NOT_CONTAINS"
+	MatcherStartsWith  MatcherOperator = "STARTS_WITH"
+	MatcherEndsWith    MatcherOperator = "ENDS_WITH"
+	MatcherRegex       MatcherOperator = "REGEX"
+)
+
+// ThresholdOperator compares a metric against its threshold
+type ThresholdOperator string
+
+// Threshold operators
+const (
+	ThresholdGreaterThan ThresholdOperator = "GREATER_THAN"
+	ThresholdLessThan    ThresholdOperator = "LESS_THAN"
+)
+
+// NotificationTypeName the channel a notification is delivered through
+type NotificationTypeName string
+
+// Notification types
+const (
+	NotificationEmail   NotificationTypeName = "EMAIL"
+	NotificationSMS     NotificationTypeName = "SMS"
+	NotificationWebhook NotificationTypeName = "WEBHOOK"
+	NotificationTeam    NotificationTypeName = "TEAM"
+	NotificationUser    NotificationTypeName = "USER"
+	NotificationGroup   NotificationTypeName = "GROUP"
+	NotificationOrg     NotificationTypeName = "ORG"
+)
+
+// AlertConfigurationsService is an interface for interfacing with the Alert Configurations
+// endpoints of the MongoDB Cloud Manager API.
+// See more: https://docs.cloudmanager.mongodb.com/reference/api/alert-configurations/
+type AlertConfigurationsService interface {
+	List(context.Context, string, *atlas.ListOptions) (*AlertConfigurations, *atlas.Response, error)
+	Get(context.Context, string, string) (*AlertConfiguration, *atlas.Response, error)
+	Create(context.Context, string, *AlertConfiguration) (*AlertConfiguration, *atlas.Response, error)
+	Update(context.Context, string, string, *AlertConfiguration) (*AlertConfiguration, *atlas.Response, error)
+	SetEnabled(context.Context, string, string, bool) (*AlertConfiguration, *atlas.Response, error)
+	Delete(context.Context, string, string) (*atlas.Response, error)
+	Copy(context.Context, string, string) ([]*AlertConfiguration, *atlas.Response, error)
+}
+
+// AlertConfigurationsServiceOp handles communication with the Alert Configurations related methods of the
+// MongoDB Cloud Manager API
+type AlertConfigurationsServiceOp struct {
+	client *Client
+}
+
+var _ AlertConfigurationsService = &AlertConfigurationsServiceOp{}
+
+// Matcher restricts an alert configuration to the entities whose field matches the value, e.g. HOSTNAME STARTS_WITH db-
+type Matcher struct {
+	FieldName string          `json:"fieldName"`
+	Operator  MatcherOperator `json:"operator"`
+	Value     string          `json:"value"`
+}
+
+// MetricThreshold the threshold of an OUTSIDE_METRIC_THRESHOLD alert configuration
+type MetricThreshold struct {
+	MetricName string            `json:"metricName"`
+	Mode       string            `json:"mode,omitempty"`
+	Operator   ThresholdOperator `json:"operator"`
+	Threshold  float64           `json:"threshold"`
+	Units      string            `json:"units,omitempty"`
+}
+
+// Threshold the threshold of an alert configuration which is not based on a metric, e.g. TOO_FEW_HEALTHY_MEMBERS
+type Threshold struct {
+	Operator  ThresholdOperator `json:"operator"`
+	Threshold float64           `json:"threshold"`
+	Units     string            `json:"units,omitempty"`
+}
+
+// Notification a channel through which an alert is delivered.
+// DelayMin is the number of minutes to wait after the condition is detected, and
+// IntervalMin the number of minutes between notifications while the alert is open.
+type Notification struct {
+	TypeName      NotificationTypeName `json:"typeName"`
+	DelayMin      *int                 `json:"delayMin,omitempty"`
+	IntervalMin   int                  `json:"intervalMin,omitempty"`
+	EmailAddress  string               `json:"emailAddress,omitempty"`
+	EmailEnabled  *bool                `json:"emailEnabled,omitempty"`
+	SMSEnabled    *bool                `json:"smsEnabled,omitempty"`
+	MobileNumber  string               `json:"mobileNumber,omitempty"`
+	WebhookURL    string               `json:"webhookUrl,omitempty"`
+	WebhookSecret string               `json:"webhookSecret,omitempty"`
+	TeamID        string               `json:"teamId,omitempty"`
+	TeamName      string               `json:"teamName,omitempty"`
+	Username      string               `json:"username,omitempty"`
+	Roles         []string             `json:"roles,omitempty"`
+}
+
+// AlertConfiguration represents the conditions that trigger an alert, and how it is delivered
+type AlertConfiguration struct {
+	ID              string           `json:"id,omitempty"`
+	Created         string           `json:"created,omitempty"`
+	Enabled         *bool            `json:"enabled,omitempty"`
+	EventTypeName   EventTypeName    `json:"eventTypeName,omitempty"`
+	GroupID         string           `json:"groupId,omitempty"`
+	Links           []*atlas.Link    `json:"links,omitempty"`
+	Matchers        []*Matcher       `json:"matchers,omitempty"`
+	MetricThreshold *MetricThreshold `json:"metricThreshold,omitempty"`
+	Notifications   []*Notification  `json:"notifications,omitempty"`
+	Threshold       *Threshold       `json:"threshold,omitempty"`
+	TypeName        AlertTypeName    `json:"typeName,omitempty"`
+	Updated         string           `json:"updated,omitempty"`
+}
+
+// AlertConfigurations represents a array of alert configurations
+type AlertConfigurations struct {
+	Links      []*atlas.Link         `json:"links"`
+	Results    []*AlertConfiguration `json:"results"`
+	TotalCount int                   `json:"totalCount"`
+}
+
+// List gets all alert configurations in a project.
+// See more: https://docs.cloudmanager.mongodb.com/reference/api/alert-configurations-get-all-configs/
+func (s *AlertConfigurationsServiceOp) List(ctx context.Context, projectID string, opts *atlas.ListOptions) (*AlertConfigurations, *atlas.Response, error) {
+	if projectID == "" {
+		return nil, nil, atlas.NewArgError("projectID", "must be set")
+	}
+
+	basePath := fmt.Sprintf(alertConfigurationsBasePath, projectID)
+	path, err := setListOptions(basePath, opts)
+	if err != nil {
+		return nil, nil, err
+	}
+
+	req, err := s.client.NewRequest(ctx, http.MethodGet, path, nil)
+	if err != nil {
+		return nil, nil, err
+	}
+
+	root := new(AlertConfigurations)
+	resp, err := s.client.Do(ctx, req, root)
+	if err != nil {
+		return nil, resp, err
+	}
+
+	if l := root.Links; l != nil {
+		resp.Links = l
+	}
+
+	return root, resp, nil
+}
+
+// Get gets a single alert configuration by its ID.
+// See more: https://docs.cloudmanager.mongodb.com/reference/api/alert-configurations-get-config/
+func (s *AlertConfigurationsServiceOp) Get(ctx context.Context, projectID, alertConfigID string) (*AlertConfiguration, *atlas.Response, error) {
+	if projectID == "" {
+		return nil, nil, atlas.NewArgError("projectID", "must be set")
+	}
+	if alertConfigID == "" {
+		return nil, nil, atlas.NewArgError("alertConfigID", "must be set")
+	}
+
+	basePath := fmt.Sprintf(alertConfigurationsBasePath, projectID)
+	path := fmt.Sprintf("%s/%s", basePath, alertConfigID)
+
+	req, err := s.client.NewRequest(ctx, http.MethodGet, path, nil)
+	if err != nil {
+		return nil, nil, err
+	}
+
+	root := new(AlertConfiguration)
+	resp, err := s.client.Do(ctx, req, root)
+	if err != nil {
+		return nil, resp, err
+	}
+
+	return root, resp, err
+}
+
+// Create creates an alert configuration in a project.
+// See more: https://docs.cloudmanager.mongodb.com/reference/api/alert-configurations-create-config/
+func (s *AlertConfigurationsServiceOp) Create(ctx context.Context, projectID string, createRequest *AlertConfiguration) (*AlertConfiguration, *atlas.Response, error) {
+	if projectID == "" {
+		return nil, nil, atlas.NewArgError("projectID", "must be set")
+	}
+	if createRequest == nil {
+		return nil, nil, atlas.NewArgError("createRequest", "cannot be nil")
+	}
+	if createRequest.EventTypeName == "" {
+		return nil, nil, atlas.NewArgError("eventTypeName", "must be set")
+	}
+
+	path := fmt.Sprintf(alertConfigurationsBasePath, projectID)
+
+	req, err := s.client.NewRequest(ctx, http.MethodPost, path, createRequest)
+	if err != nil {
+		return nil, nil, err
+	}
+
+	root := new(AlertConfiguration)
+	resp, err := s.client.Do(ctx, req, root)
+	if err != nil {
+		return nil, resp, err
+	}
+
+	return root, resp, err
+}
+
+// Update replaces an alert configuration; fields which are not specified are reset to their defaults.
+// See more: https://docs.cloudmanager.mongodb.com/reference/api/alert-configurations-update-config/
+func (s *AlertConfigurationsServiceOp) Update(ctx context.Context, projectID, alertConfigID string, updateRequest *AlertConfiguration) (*AlertConfiguration, *atlas.Response, error) {
+	if projectID == "" {
+		return nil, nil, atlas.NewArgError("projectID", "must be set")
+	}
+	if alertConfigID == "" {
+		return nil, nil, atlas.NewArgError("alertConfigID", "must be set")
+	}
+	if updateRequest == nil {
+		return nil, nil, atlas.NewArgError("updateRequest", "cannot be nil")
+	}
+
+	basePath := fmt.Sprintf(alertConfigurationsBasePath, projectID)
+	path := fmt.Sprintf("%s/%s", basePath, alertConfigID)
+
+	req, err := s.client.NewRequest(ctx, http.MethodPut, path, updateRequest)
+	if err != nil {
+		return nil, nil, err
+	}
+
+	root := new(AlertConfiguration)
+	resp, err := s.client.Do(ctx, req, root)
+	if err != nil {
+		return nil, resp, err
+	}
+
+	return root, resp, err
+}
+
+// SetEnabled enables or disables an alert configuration.
+// See more: https://docs.cloudmanager.mongodb.com/reference/api/alert-configurations-enable-disable-config/
+func (s *AlertConfigurationsServiceOp) SetEnabled(ctx context.Context, projectID, alertConfigID string, enabled bool) (*AlertConfiguration, *atlas.Response, error) {
+	if projectID == "" {
+		return nil, nil, atlas.NewArgError("projectID", "must be set")
+	}
+	if alertConfigID == "" {
+		return nil, nil, atlas.NewArgError("alertConfigID", "must be set")
+	}
+
+	basePath := fmt.Sprintf(alertConfigurationsBasePath, projectID)
+	path := fmt.Sprintf("%s/%s", basePath, alertConfigID)
+
+	req, err := s.client.NewRequest(ctx, http.MethodPatch, path, &AlertConfiguration{Enabled: &enabled})
+	if err != nil {
+		return nil, nil, err
+	}
+
+	root := new(AlertConfiguration)
+	resp, err := s.client.Do(ctx, req, root)
+	if err != nil {
+		return nil, resp, err
+	}
+
+	return root, resp, err
+}
+
+// Delete deletes an alert configuration.
+// See more: https://docs.cloudmanager.mongodb.com/reference/api/alert-configurations-delete-config/
+func (s *AlertConfigurationsServiceOp) Delete(ctx context.Context, projectID, alertConfigID string) (*atlas.Response, error) {
+	if projectID == "" {
+		return nil, atlas.NewArgError("projectID", "must be set")
+	}
+	if alertConfigID == "" {
+		return nil, atlas.NewArgError("alertConfigID", "must be set")
+	}
+
+	basePath := fmt.Sprintf(alertConfigurationsBasePath, projectID)
+	path := fmt.Sprintf("%s/%s", basePath, alertConfigID)
+
+	req, err := s.client.NewRequest(ctx, http.MethodDelete, path, nil)
+	if err != nil {
+		return nil, err
+	}
+
+	resp, err := s.client.Do(ctx, req, nil)
+
+	return resp, err
+}
+
+// Copy creates a copy of every alert configuration of the source project in the target project,
+// and returns the created configurations. Configurations the target project already has are skipped, so Copy
+// can be run again. Notifications of teams which are not assigned to the target project, and of users who are not
+// members of it, are dropped, and configurations left without any notification are skipped.
+// It stops at the first error, returning the configurations copied so far.
+func (s *AlertConfigurationsServiceOp) Copy(ctx context.Context, sourceProjectID, targetProjectID string) ([]*AlertConfiguration, *atlas.Response, error) {
+	if sourceProjectID == "" {
+		return nil, nil, atlas.NewArgError("sourceProjectID", "must be set")
+	}
+	if targetProjectID == "" {
+		return nil, nil, atlas.NewArgError("targetProjectID", "must be set")
+	}
+
+	source, resp, err := s.listAll(ctx, sourceProjectID)
+	if err != nil {
+		return nil, resp, err
+	}
+
+	existing, resp, err := s.listAll(ctx, targetProjectID)
+	if err != nil {
+		return nil, resp, err
+	}
+
+	recipients, resp, err := s.listRecipients(ctx, targetProjectID, source)
+	if err != nil {
+		return nil, resp, err
+	}
+
+	var result []*AlertConfiguration
+	for _, config := range source {
+		copiedConfig := copyAlertConfiguration(config, recipients)
+		if len(config.Notifications) > 0 && len(copiedConfig.Notifications) == 0 {
+			continue
+		}
+
+		if hasAlertConfiguration(existing, copiedConfig) {
+			continue
+		}
+
+		created, r, err := s.Create(ctx, targetProjectID, copiedConfig)
+		resp = r
+		if err != nil {
+			return result, resp, err
+		}
+		existing = append(existing, copiedConfig)
+		result = append(result, created)
+	}
+
+	return result, resp, nil
+}
+
+// listAll lists every alert configuration of a project
+func (s *AlertConfigurationsServiceOp) listAll(ctx context.Context, projectID string) ([]*AlertConfiguration, *atlas.Response, error) {
+	var (
+		configs []*AlertConfiguration
+		resp    *atlas.Response
+	)
+	opts := &atlas.ListOptions{}
+	err := listPages(opts, func() (int, int, error) {
+		page, r, err := s.List(ctx, projectID, opts)
+		resp = r
+		if err != nil {
+			return 0, 0, err
+		}
+		configs = append(configs, page.Results...)
+		return len(page.Results), page.TotalCount, nil
+	})
+
+	return configs, resp, err
+}
+
+// alertRecipients the teams and users of a project which can be notified
+type alertRecipients struct {
+	teamIDs   map[string]bool
+	usernames map[string]bool
+}
+
+// listRecipients lists the teams and users of a project, when a team or user is notified by one of configs
+func (s *AlertConfigurationsServiceOp) listRecipients(ctx context.Context, projectID string, configs []*AlertConfiguration) (*alertRecipients, *atlas.Response, error) {
+	var notifiesTeams, notifiesUsers bool
+	for _, config := range configs {
+		for _, n := range config.Notifications {
+			notifiesTeams = notifiesTeams || n.TypeName == NotificationTeam
+			notifiesUsers = notifiesUsers || n.TypeName == NotificationUser
+		}
+	}
+
+	var resp *atlas.Response
+	recipients := &alertRecipients{teamIDs: map[string]bool{}, usernames: map[string]bool{}}
+	if notifiesTeams {
+		teams, r, err := s.client.Teams.ListProjectTeams(ctx, projectID)
+		resp = r
+		if err != nil {
+			return nil, resp, err
+		}
+		for _, team := range teams.Results {
+			recipients.teamIDs[team.TeamID] = true
+		}
+	}
+
+	if notifiesUsers {
+		// members of the teams of the project can be notified too
+		opts := &ProjectUsersListOptions{FlattenTeams: true}
+		err := listPages(&opts.ListOptions, func() (int, int, error) {
+			users, r, err := s.client.Projects.ListUsers(ctx, projectID, opts)
+			resp = r
+			if err != nil {
+				return 0, 0, err
+			}
+			for _, user := range users.Results {
+				recipients.usernames[user.Username] = true
+			}
+			return len(users.Results), users.TotalCount, nil
+		})
+		if err != nil {
+			return nil, resp, err
+		}
+	}
+
+	return recipients, resp, nil
+}
+
+// canNotify reports whether n can be delivered to one of the recipients
+func (r *alertRecipients) canNotify(n *Notification) bool {
+	switch n.TypeName {
+	case NotificationTeam:
+		return r.teamIDs[n.TeamID]
+	case NotificationUser:
+		return r.usernames[n.Username]
+	default:
+		return true
+	}
+}
+
+// copyAlertConfiguration copies config without the fields owned by the server,
+// and without the notifications the recipients cannot receive
+func copyAlertConfiguration(config *AlertConfiguration, recipients *alertRecipients) *AlertConfiguration {
+	copied := *config
+	copied.ID = ""
+	copied.GroupID = ""
+	copied.Created = ""
+	copied.Updated = ""
+	copied.Links = nil
+
+	copied.Notifications = nil
+	for _, n := range config.Notifications {
+		if recipients.canNotify(n) {
+			copied.Notifications = append(copied.Notifications, n)
+		}
+	}
+
+	return &copied
+}
+
+// hasAlertConfiguration reports whether one of configs is triggered by the same conditions as config,
+// and notifies the same recipients
+func hasAlertConfiguration(configs []*AlertConfiguration, config *AlertConfiguration) bool {
+	for _, c := range configs {
+		if sameAlertConfiguration(c, config) {
+			return true
+		}
+	}
+
+	return false
+}
+
+// sameAlertConfiguration compares the event type, matchers, thresholds and notification recipients of a and b.
+// The fields the server fills in when they are not set, such as the enabled flag, the units and mode of thresholds,
+// and the delays and intervals of notifications, are not compared.
+func sameAlertConfiguration(a, b *AlertConfiguration) bool {
+	if a.EventTypeName != b.EventTypeName {
+		return false
+	}
+	if !sameThreshold(a.Threshold, b.Threshold) || !sameMetricThreshold(a.MetricThreshold, b.MetricThreshold) {
+		return false
+	}
+
+	return sameStrings(matcherKeys(a.Matchers), matcherKeys(b.Matchers)) &&
+		sameStrings(notificationRecipients(a.Notifications), notificationRecipients(b.Notifications))
+}
+
+func sameThreshold(a, b *Threshold) bool {
+	if a == nil || b == nil {
+		return a == b
+	}
+
+	return a.Operator == b.Operator && a.Threshold == b.Threshold && sameDefaulted(a.Units, b.Units)
+}
+
+func sameMetricThreshold(a, b *MetricThreshold) bool {
+	if a == nil || b == nil {
+		return a == b
+	}
+
+	return a.MetricName == b.MetricName && a.Operator == b.Operator && a.Threshold == b.Threshold &&
+		sameDefaulted(a.Mode, b.Mode) && sameDefaulted(a.Units, b.Units)
+}
+
+// sameDefaulted compares two values the server defaults when they are not set
+func sameDefaulted(a, b string) bool {
+	return a == "" || b == "" || a == b
+}
+
+// matcherKeys returns the sorted field, operator and value of each matcher
+func matcherKeys(matchers []*Matcher) []string {
+	keys := make([]string, 0, len(matchers))
+	for _, m := range matchers {
+		keys = append(keys, fmt.Sprintf("%s %s %s", m.FieldName, m.Operator, m.Value))
+	}
+	sort.Strings(keys)
+
+	return keys
+}
+
+// notificationRecipients returns the sorted type and recipient of each notification
+func notificationRecipients(notifications []*Notification) []string {
+	recipients := make([]string, 0, len(notifications))
+	for _, n := range notifications {
+		roles := append([]string(nil), n.Roles...)
+		sort.Strings(roles)
+		recipients = append(recipients, fmt.Sprintf("%s %s %s %s %s %s %v",
+			n.TypeName, n.EmailAddress, n.MobileNumber, n.WebhookURL, n.TeamID, n.Username, roles))
+	}
+	sort.Strings(recipients)
+
+	return recipients
+}
+
+func sameStrings(a, b []string) bool {
+	if len(a) != len(b) {
+		return false
+	}
+	for i := range a {
+		if a[i] != b[i] {
+			return false
+		}
+	}
+
+	return true
+}
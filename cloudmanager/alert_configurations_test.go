@@ -0,0 +1,737 @@
+// Copyright 2020 MongoDB Inc
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package cloudmanager
+
+import (
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"net/url"
+	"testing"
+
+	"github.com/go-test/deep"
+	"github.com/mongodb/go-client-mongodb-atlas/mongodbatlas"
+)
+
+func TestAlertConfigurations_List(t *testing.T) {
+	setup()
+	defer teardown()
+
+	projectID := "5a0a1e7e0f2912c554080adc"
+
+	mux.HandleFunc(fmt.Sprintf("/groups/%s/alertConfigs", projectID), func(w http.ResponseWriter, r *http.Request) {
+		testMethod(t, r, http.MethodGet)
+		_, _ = fmt.Fprint(w, `{
+			"links": [],
+			"results": [{
+				"created": "2016-08-18T11:23:41Z",
+				"enabled": true,
+				"eventTypeName": "OUTSIDE_METRIC_THRESHOLD",
+				"groupId": "5a0a1e7e0f2912c554080adc",
+				"id": "57b76ddc96e8215c017ceafb",
+				"links": [],
+				"matchers": [{
+					"fieldName": "HOSTNAME",
+					"operator": "STARTS_WITH",
+					"value": "db-"
+				}],
+				"metricThreshold": {
+					"metricName": "ASSERT_REGULAR",
+					"mode": "AVERAGE",
+					"operator": "GREATER_THAN",
+					"threshold": 99.0,
+					"units": "RAW"
+				},
+				"notifications": [{
+					"typeName": "GROUP",
+					"delayMin": 5,
+					"intervalMin": 60,
+					"emailEnabled": true,
+					"smsEnabled": false,
+					"roles": ["GROUP_OWNER"]
+				}, {
+					"typeName": "WEBHOOK",
+					"delayMin": 0,
+					"webhookUrl": "https://example.com/hook"
+				}],
+				"typeName": "HOST_METRIC",
+				"updated": "2016-08-18T11:23:41Z"
+			}],
+			"totalCount": 1
+		}`)
+	})
+
+	configs, _, err := client.AlertConfigurations.List(ctx, projectID, nil)
+	if err != nil {
+		t.Fatalf("AlertConfigurations.List returned error: %v", err)
+	}
+
+	expected := &AlertConfigurations{
+		Links: []*mongodbatlas.Link{},
+		Results: []*AlertConfiguration{
+			{
+				ID:            "57b76ddc96e8215c017ceafb",
+				Created:       "2016-08-18T11:23:41Z",
+				Enabled:       boolPtr(true),
+				EventTypeName: EventOutsideMetricThreshold,
+				GroupID:       "5a0a1e7e0f2912c554080adc",
+				Links:         []*mongodbatlas.Link{},
+				Matchers: []*Matcher{
+					{FieldName: "HOSTNAME", Operator: MatcherStartsWith, Value: "db-"},
+				},
+				MetricThreshold: &MetricThreshold{
+					MetricName: "ASSERT_REGULAR",
+					Mode:       "AVERAGE",
+					Operator:   ThresholdGreaterThan,
+					Threshold:  99.0,
+					Units:      "RAW",
+				},
+				Notifications: []*Notification{
+					{
+						TypeName:     NotificationGroup,
+						DelayMin:     intPtr(5),
+						IntervalMin:  60,
+						EmailEnabled: boolPtr(true),
+						SMSEnabled:   boolPtr(false),
+						Roles:        []string{"GROUP_OWNER"},
+					},
+					{
+						TypeName:   NotificationWebhook,
+						DelayMin:   intPtr(0),
+						WebhookURL: "https://example.com/hook",
+					},
+				},
+				TypeName: AlertTypeHostMetric,
+				Updated:  "2016-08-18T11:23:41Z",
+			},
+		},
+		TotalCount: 1,
+	}
+
+	if diff := deep.Equal(configs, expected); diff != nil {
+		t.Error(diff)
+	}
+}
+
+func TestAlertConfigurations_Get(t *testing.T) {
+	setup()
+	defer teardown()
+
+	projectID := "5a0a1e7e0f2912c554080adc"
+	alertConfigID := "57b76ddc96e8215c017ceafb"
+
+	mux.HandleFunc(fmt.Sprintf("/groups/%s/alertConfigs/%s", projectID, alertConfigID), func(w http.ResponseWriter, r *http.Request) {
+		testMethod(t, r, http.MethodGet)
+		_, _ = fmt.Fprint(w, `{
+			"created": "2016-08-18T11:23:41Z",
+			"enabled": true,
+			"eventTypeName": "OUTSIDE_METRIC_THRESHOLD",
+			"groupId": "5a0a1e7e0f2912c554080adc",
+			"id": "57b76ddc96e8215c017ceafb",
+			"links": [],
+			"matchers": [{
+				"fieldName": "HOSTNAME",
+				"operator": "STARTS_WITH",
+				"value": "db-"
+			}],
+			"metricThreshold": {
+				"metricName": "ASSERT_REGULAR",
+				"mode": "AVERAGE",
+				"operator": "GREATER_THAN",
+				"threshold": 99.0,
+				"units": "RAW"
+			},
+			"notifications": [{
+				"typeName": "GROUP",
+				"delayMin": 5,
+				"intervalMin": 60,
+				"emailEnabled": true,
+				"smsEnabled": false,
+				"roles": ["GROUP_OWNER"]
+			}, {
+				"typeName": "WEBHOOK",
+				"delayMin": 0,
+				"webhookUrl": "https://example.com/hook"
+			}],
+			"typeName": "HOST_METRIC",
+			"updated": "2016-08-18T11:23:41Z"
+		}`)
+	})
+
+	config, _, err := client.AlertConfigurations.Get(ctx, projectID, alertConfigID)
+	if err != nil {
+		t.Fatalf("AlertConfigurations.Get returned error: %v", err)
+	}
+
+	expected := &AlertConfiguration{
+		ID:            "57b76ddc96e8215c017ceafb",
+		Created:       "2016-08-18T11:23:41Z",
+		Enabled:       boolPtr(true),
+		EventTypeName: EventOutsideMetricThreshold,
+		GroupID:       "5a0a1e7e0f2912c554080adc",
+		Links:         []*mongodbatlas.Link{},
+		Matchers: []*Matcher{
+			{FieldName: "HOSTNAME", Operator: MatcherStartsWith, Value: "db-"},
+		},
+		MetricThreshold: &MetricThreshold{
+			MetricName: "ASSERT_REGULAR",
+			Mode:       "AVERAGE",
+			Operator:   ThresholdGreaterThan,
+			Threshold:  99.0,
+			Units:      "RAW",
+		},
+		Notifications: []*Notification{
+			{
+				TypeName:     NotificationGroup,
+				DelayMin:     intPtr(5),
+				IntervalMin:  60,
+				EmailEnabled: boolPtr(true),
+				SMSEnabled:   boolPtr(false),
+				Roles:        []string{"GROUP_OWNER"},
+			},
+			{
+				TypeName:   NotificationWebhook,
+				DelayMin:   intPtr(0),
+				WebhookURL: "https://example.com/hook",
+			},
+		},
+		TypeName: AlertTypeHostMetric,
+		Updated:  "2016-08-18T11:23:41Z",
+	}
+
+	if diff := deep.Equal(config, expected); diff != nil {
+		t.Error(diff)
+	}
+}
+
+func TestAlertConfigurations_Create(t *testing.T) {
+	setup()
+	defer teardown()
+
+	projectID := "5a0a1e7e0f2912c554080adc"
+	createRequest := &AlertConfiguration{
+		Enabled:       boolPtr(true),
+		EventTypeName: EventHostDown,
+		Notifications: []*Notification{
+			{
+				TypeName:     NotificationEmail,
+				DelayMin:     intPtr(0),
+				IntervalMin:  5,
+				EmailAddress: "oncall@example.com",
+			},
+		},
+	}
+
+	mux.HandleFunc(fmt.Sprintf("/groups/%s/alertConfigs", projectID), func(w http.ResponseWriter, r *http.Request) {
+		testMethod(t, r, http.MethodPost)
+
+		expected := map[string]interface{}{
+			"enabled":       true,
+			"eventTypeName": "HOST_DOWN",
+			"notifications": []interface{}{
+				map[string]interface{}{
+					"typeName":     "EMAIL",
+					"delayMin":     float64(0),
+					"intervalMin":  float64(5),
+					"emailAddress": "oncall@example.com",
+				},
+			},
+		}
+		var v map[string]interface{}
+		if err := json.NewDecoder(r.Body).Decode(&v); err != nil {
+			t.Fatalf("decode json: %v", err)
+		}
+		if diff := deep.Equal(v, expected); diff != nil {
+			t.Error(diff)
+		}
+
+		_, _ = fmt.Fprint(w, `{
+			"created": "2016-08-18T11:23:41Z",
+			"enabled": true,
+			"eventTypeName": "OUTSIDE_METRIC_THRESHOLD",
+			"groupId": "5a0a1e7e0f2912c554080adc",
+			"id": "57b76ddc96e8215c017ceafb",
+			"links": [],
+			"matchers": [{
+				"fieldName": "HOSTNAME",
+				"operator": "STARTS_WITH",
+				"value": "db-"
+			}],
+			"metricThreshold": {
+				"metricName": "ASSERT_REGULAR",
+				"mode": "AVERAGE",
+				"operator": "GREATER_THAN",
+				"threshold": 99.0,
+				"units": "RAW"
+			},
+			"notifications": [{
+				"typeName": "GROUP",
+				"delayMin": 5,
+				"intervalMin": 60,
+				"emailEnabled": true,
+				"smsEnabled": false,
+				"roles": ["GROUP_OWNER"]
+			}, {
+				"typeName": "WEBHOOK",
+				"delayMin": 0,
+				"webhookUrl": "https://example.com/hook"
+			}],
+			"typeName": "HOST_METRIC",
+			"updated": "2016-08-18T11:23:41Z"
+		}`)
+	})
+
+	_, _, err := client.AlertConfigurations.Create(ctx, projectID, createRequest)
+	if err != nil {
+		t.Fatalf("AlertConfigurations.Create returned error: %v", err)
+	}
+}
+
+func TestAlertConfigurations_Update(t *testing.T) {
+	setup()
+	defer teardown()
+
+	projectID := "5a0a1e7e0f2912c554080adc"
+	alertConfigID := "57b76ddc96e8215c017ceafb"
+
+	mux.HandleFunc(fmt.Sprintf("/groups/%s/alertConfigs/%s", projectID, alertConfigID), func(w http.ResponseWriter, r *http.Request) {
+		testMethod(t, r, http.MethodPut)
+		_, _ = fmt.Fprint(w, `{
+			"created": "2016-08-18T11:23:41Z",
+			"enabled": true,
+			"eventTypeName": "OUTSIDE_METRIC_THRESHOLD",
+			"groupId": "5a0a1e7e0f2912c554080adc",
+			"id": "57b76ddc96e8215c017ceafb",
+			"links": [],
+			"matchers": [{
+				"fieldName": "HOSTNAME",
+				"operator": "STARTS_WITH",
+				"value": "db-"
+			}],
+			"metricThreshold": {
+				"metricName": "ASSERT_REGULAR",
+				"mode": "AVERAGE",
+				"operator": "GREATER_THAN",
+				"threshold": 99.0,
+				"units": "RAW"
+			},
+			"notifications": [{
+				"typeName": "GROUP",
+				"delayMin": 5,
+				"intervalMin": 60,
+				"emailEnabled": true,
+				"smsEnabled": false,
+				"roles": ["GROUP_OWNER"]
+			}, {
+				"typeName": "WEBHOOK",
+				"delayMin": 0,
+				"webhookUrl": "https://example.com/hook"
+			}],
+			"typeName": "HOST_METRIC",
+			"updated": "2016-08-18T11:23:41Z"
+		}`)
+	})
+
+	expected := &AlertConfiguration{
+		ID:            "57b76ddc96e8215c017ceafb",
+		Created:       "2016-08-18T11:23:41Z",
+		Enabled:       boolPtr(true),
+		EventTypeName: EventOutsideMetricThreshold,
+		GroupID:       "5a0a1e7e0f2912c554080adc",
+		Links:         []*mongodbatlas.Link{},
+		Matchers: []*Matcher{
+			{FieldName: "HOSTNAME", Operator: MatcherStartsWith, Value: "db-"},
+		},
+		MetricThreshold: &MetricThreshold{
+			MetricName: "ASSERT_REGULAR",
+			Mode:       "AVERAGE",
+			Operator:   ThresholdGreaterThan,
+			Threshold:  99.0,
+			Units:      "RAW",
+		},
+		Notifications: []*Notification{
+			{
+				TypeName:     NotificationGroup,
+				DelayMin:     intPtr(5),
+				IntervalMin:  60,
+				EmailEnabled: boolPtr(true),
+				SMSEnabled:   boolPtr(false),
+				Roles:        []string{"GROUP_OWNER"},
+			},
+			{
+				TypeName:   NotificationWebhook,
+				DelayMin:   intPtr(0),
+				WebhookURL: "https://example.com/hook",
+			},
+		},
+		TypeName: AlertTypeHostMetric,
+		Updated:  "2016-08-18T11:23:41Z",
+	}
+
+	config, _, err := client.AlertConfigurations.Update(ctx, projectID, alertConfigID, expected)
+	if err != nil {
+		t.Fatalf("AlertConfigurations.Update returned error: %v", err)
+	}
+
+	if diff := deep.Equal(config, expected); diff != nil {
+		t.Error(diff)
+	}
+}
+
+func TestAlertConfigurations_SetEnabled(t *testing.T) {
+	setup()
+	defer teardown()
+
+	projectID := "5a0a1e7e0f2912c554080adc"
+	alertConfigID := "57b76ddc96e8215c017ceafb"
+
+	mux.HandleFunc(fmt.Sprintf("/groups/%s/alertConfigs/%s", projectID, alertConfigID), func(w http.ResponseWriter, r *http.Request) {
+		testMethod(t, r, http.MethodPatch)
+
+		expected := map[string]interface{}{"enabled": false}
+		var v map[string]interface{}
+		if err := json.NewDecoder(r.Body).Decode(&v); err != nil {
+			t.Fatalf("decode json: %v", err)
+		}
+		if diff := deep.Equal(v, expected); diff != nil {
+			t.Error(diff)
+		}
+
+		_, _ = fmt.Fprint(w, `{
+			"created": "2016-08-18T11:23:41Z",
+			"enabled": true,
+			"eventTypeName": "OUTSIDE_METRIC_THRESHOLD",
+			"groupId": "5a0a1e7e0f2912c554080adc",
+			"id": "57b76ddc96e8215c017ceafb",
+			"links": [],
+			"matchers": [{
+				"fieldName": "HOSTNAME",
+				"operator": "STARTS_WITH",
+				"value": "db-"
+			}],
+			"metricThreshold": {
+				"metricName": "ASSERT_REGULAR",
+				"mode": "AVERAGE",
+				"operator": "GREATER_THAN",
+				"threshold": 99.0,
+				"units": "RAW"
+			},
+			"notifications": [{
+				"typeName": "GROUP",
+				"delayMin": 5,
+				"intervalMin": 60,
+				"emailEnabled": true,
+				"smsEnabled": false,
+				"roles": ["GROUP_OWNER"]
+			}, {
+				"typeName": "WEBHOOK",
+				"delayMin": 0,
+				"webhookUrl": "https://example.com/hook"
+			}],
+			"typeName": "HOST_METRIC",
+			"updated": "2016-08-18T11:23:41Z"
+		}`)
+	})
+
+	_, _, err := client.AlertConfigurations.SetEnabled(ctx, projectID, alertConfigID, false)
+	if err != nil {
+		t.Fatalf("AlertConfigurations.SetEnabled returned error: %v", err)
+	}
+}
+
+func TestAlertConfigurations_Delete(t *testing.T) {
+	setup()
+	defer teardown()
+
+	projectID := "5a0a1e7e0f2912c554080adc"
+	alertConfigID := "57b76ddc96e8215c017ceafb"
+
+	mux.HandleFunc(fmt.Sprintf("/groups/%s/alertConfigs/%s", projectID, alertConfigID), func(w http.ResponseWriter, r *http.Request) {
+		testMethod(t, r, http.MethodDelete)
+	})
+
+	_, err := client.AlertConfigurations.Delete(ctx, projectID, alertConfigID)
+	if err != nil {
+		t.Fatalf("AlertConfigurations.Delete returned error: %v", err)
+	}
+}
+
+func TestAlertConfigurations_Copy(t *testing.T) {
+	setup()
+	defer teardown()
+
+	sourceProjectID := "5a0a1e7e0f2912c554080adc"
+	targetProjectID := "5e2211c17a3e5a48f5497de3"
+
+	mux.HandleFunc(fmt.Sprintf("/groups/%s/alertConfigs", sourceProjectID), func(w http.ResponseWriter, r *http.Request) {
+		testMethod(t, r, http.MethodGet)
+
+		// serve one configuration per page, to exercise pagination
+		switch page := r.URL.Query().Get("pageNum"); page {
+		case "1":
+			_, _ = fmt.Fprint(w, `{
+				"links": [],
+				"results": [{
+					"id": "57b76ddc96e8215c017ceafb",
+					"groupId": "5a0a1e7e0f2912c554080adc",
+					"created": "2016-08-18T11:23:41Z",
+					"updated": "2016-08-18T11:23:41Z",
+					"links": [],
+					"enabled": true,
+					"eventTypeName": "HOST_DOWN",
+					"notifications": [{"typeName": "GROUP", "intervalMin": 60, "emailEnabled": true}]
+				}],
+				"totalCount": 2
+			}`)
+		case "2":
+			_, _ = fmt.Fprint(w, `{
+				"links": [],
+				"results": [{
+					"id": "57b76ddc96e8215c017ceafc",
+					"groupId": "5a0a1e7e0f2912c554080adc",
+					"enabled": true,
+					"eventTypeName": "OUTSIDE_METRIC_THRESHOLD",
+					"metricThreshold": {"metricName": "ASSERT_REGULAR", "operator": "GREATER_THAN", "threshold": 99.0},
+					"notifications": [{"typeName": "GROUP", "intervalMin": 60, "emailEnabled": true}]
+				}],
+				"totalCount": 2
+			}`)
+		default:
+			t.Fatalf("unexpected page %q", page)
+		}
+	})
+
+	var created []map[string]interface{}
+	mux.HandleFunc(fmt.Sprintf("/groups/%s/alertConfigs", targetProjectID), func(w http.ResponseWriter, r *http.Request) {
+		if r.Method == http.MethodGet {
+			// the target already has the first configuration
+			_, _ = fmt.Fprint(w, `{
+				"links": [],
+				"results": [{
+					"id": "5e2211c17a3e5a48f5497de4",
+					"groupId": "5e2211c17a3e5a48f5497de3",
+					"created": "2020-01-17T14:17:37Z",
+					"enabled": false,
+					"eventTypeName": "HOST_DOWN",
+					"notifications": [{"typeName": "GROUP", "delayMin": 0, "intervalMin": 5, "emailEnabled": true, "smsEnabled": false}]
+				}],
+				"totalCount": 1
+			}`)
+			return
+		}
+
+		testMethod(t, r, http.MethodPost)
+		created = append(created, decodeBody(t, r).(map[string]interface{}))
+		_, _ = fmt.Fprint(w, `{
+			"id": "5e2211c17a3e5a48f5497de5",
+			"groupId": "5e2211c17a3e5a48f5497de3",
+			"enabled": true,
+			"eventTypeName": "OUTSIDE_METRIC_THRESHOLD"
+		}`)
+	})
+
+	configs, _, err := client.AlertConfigurations.Copy(ctx, sourceProjectID, targetProjectID)
+	if err != nil {
+		t.Fatalf("AlertConfigurations.Copy returned error: %v", err)
+	}
+
+	expectedCreated := []map[string]interface{}{
+		{
+			"enabled":       true,
+			"eventTypeName": "OUTSIDE_METRIC_THRESHOLD",
+			"metricThreshold": map[string]interface{}{
+				"metricName": "ASSERT_REGULAR",
+				"operator":   "GREATER_THAN",
+				"threshold":  float64(99),
+			},
+			"notifications": []interface{}{
+				map[string]interface{}{"typeName": "GROUP", "intervalMin": float64(60), "emailEnabled": true},
+			},
+		},
+	}
+	if diff := deep.Equal(created, expectedCreated); diff != nil {
+		t.Error(diff)
+	}
+
+	expected := []*AlertConfiguration{
+		{
+			ID:            "5e2211c17a3e5a48f5497de5",
+			GroupID:       "5e2211c17a3e5a48f5497de3",
+			Enabled:       boolPtr(true),
+			EventTypeName: EventOutsideMetricThreshold,
+		},
+	}
+	if diff := deep.Equal(configs, expected); diff != nil {
+		t.Error(diff)
+	}
+}
+
+func TestAlertConfigurations_Copy_notifications(t *testing.T) {
+	setup()
+	defer teardown()
+
+	sourceProjectID := "5a0a1e7e0f2912c554080adc"
+	targetProjectID := "5e2211c17a3e5a48f5497de3"
+
+	mux.HandleFunc(fmt.Sprintf("/groups/%s/alertConfigs", sourceProjectID), func(w http.ResponseWriter, r *http.Request) {
+		testMethod(t, r, http.MethodGet)
+		_, _ = fmt.Fprint(w, `{
+			"links": [],
+			"results": [{
+				"id": "57b76ddc96e8215c017ceafb",
+				"eventTypeName": "HOST_DOWN",
+				"notifications": [
+					{"typeName": "TEAM", "teamId": "6b610e1087d9d66b272f0c86"},
+					{"typeName": "TEAM", "teamId": "6b610e1087d9d66b272f0c87"},
+					{"typeName": "USER", "username": "jane.doe@example.com"}
+				]
+			}, {
+				"id": "57b76ddc96e8215c017ceafc",
+				"eventTypeName": "AGENT_DOWN",
+				"notifications": [{"typeName": "USER", "username": "john.doe@example.com"}]
+			}],
+			"totalCount": 2
+		}`)
+	})
+
+	mux.HandleFunc(fmt.Sprintf("/groups/%s/teams", targetProjectID), func(w http.ResponseWriter, r *http.Request) {
+		testMethod(t, r, http.MethodGet)
+		_, _ = fmt.Fprint(w, `{
+			"links": [],
+			"results": [{"roleNames": ["GROUP_OWNER"], "teamId": "6b610e1087d9d66b272f0c86"}],
+			"totalCount": 1
+		}`)
+	})
+
+	mux.HandleFunc(fmt.Sprintf("/groups/%s/users", targetProjectID), func(w http.ResponseWriter, r *http.Request) {
+		testMethod(t, r, http.MethodGet)
+		testQuery(t, r, url.Values{"flattenTeams": {"true"}, "pageNum": {"1"}})
+		_, _ = fmt.Fprint(w, `{
+			"links": [],
+			"results": [{"id": "5e2211c17a3e5a48f5497de6", "username": "jane.doe@example.com"}],
+			"totalCount": 1
+		}`)
+	})
+
+	var created []map[string]interface{}
+	mux.HandleFunc(fmt.Sprintf("/groups/%s/alertConfigs", targetProjectID), func(w http.ResponseWriter, r *http.Request) {
+		if r.Method == http.MethodGet {
+			_, _ = fmt.Fprint(w, `{"links": [], "results": [], "totalCount": 0}`)
+			return
+		}
+
+		testMethod(t, r, http.MethodPost)
+		created = append(created, decodeBody(t, r).(map[string]interface{}))
+		_, _ = fmt.Fprint(w, `{"id": "5e2211c17a3e5a48f5497de5", "eventTypeName": "HOST_DOWN"}`)
+	})
+
+	_, _, err := client.AlertConfigurations.Copy(ctx, sourceProjectID, targetProjectID)
+	if err != nil {
+		t.Fatalf("AlertConfigurations.Copy returned error: %v", err)
+	}
+
+	// the second configuration only notifies a user who is not a member of the target project
+	expected := []map[string]interface{}{
+		{
+			"eventTypeName": "HOST_DOWN",
+			"notifications": []interface{}{
+				map[string]interface{}{"typeName": "TEAM", "teamId": "6b610e1087d9d66b272f0c86"},
+				map[string]interface{}{"typeName": "USER", "username": "jane.doe@example.com"},
+			},
+		},
+	}
+	if diff := deep.Equal(created, expected); diff != nil {
+		t.Error(diff)
+	}
+}
+
+func TestSameAlertConfiguration(t *testing.T) {
+	config := &AlertConfiguration{
+		EventTypeName: EventOutsideMetricThreshold,
+		Matchers: []*Matcher{
+			{FieldName: "HOSTNAME", Operator: MatcherStartsWith, Value: "db-"},
+			{FieldName: "REPLICA_SET_NAME", Operator: MatcherEquals, Value: "rs0"},
+		},
+		MetricThreshold: &MetricThreshold{MetricName: "ASSERT_REGULAR", Operator: ThresholdGreaterThan, Threshold: 99},
+		Notifications: []*Notification{
+			{TypeName: NotificationGroup, Roles: []string{"GROUP_OWNER", "GROUP_READ_ONLY"}},
+			{TypeName: NotificationWebhook, WebhookURL: "https://example.com/hook"},
+		},
+	}
+
+	tests := []struct {
+		name  string
+		other *AlertConfiguration
+		same  bool
+	}{
+		{"identical", config, true},
+		{
+			"server defaults",
+			&AlertConfiguration{
+				Enabled:       boolPtr(true),
+				EventTypeName: EventOutsideMetricThreshold,
+				Matchers: []*Matcher{
+					{FieldName: "REPLICA_SET_NAME", Operator: MatcherEquals, Value: "rs0"},
+					{FieldName: "HOSTNAME", Operator: MatcherStartsWith, Value: "db-"},
+				},
+				MetricThreshold: &MetricThreshold{MetricName: "ASSERT_REGULAR", Mode: "AVERAGE", Operator: ThresholdGreaterThan, Threshold: 99, Units: "RAW"},
+				Notifications: []*Notification{
+					{TypeName: NotificationWebhook, DelayMin: intPtr(0), IntervalMin: 60, WebhookURL: "https://example.com/hook"},
+					{TypeName: NotificationGroup, EmailEnabled: boolPtr(true), Roles: []string{"GROUP_READ_ONLY", "GROUP_OWNER"}},
+				},
+			},
+			true,
+		},
+		{
+			"other threshold",
+			&AlertConfiguration{
+				EventTypeName:   EventOutsideMetricThreshold,
+				Matchers:        config.Matchers,
+				MetricThreshold: &MetricThreshold{MetricName: "ASSERT_REGULAR", Operator: ThresholdGreaterThan, Threshold: 50},
+				Notifications:   config.Notifications,
+			},
+			false,
+		},
+		{
+			"other recipient",
+			&AlertConfiguration{
+				EventTypeName:   EventOutsideMetricThreshold,
+				Matchers:        config.Matchers,
+				MetricThreshold: config.MetricThreshold,
+				Notifications: []*Notification{
+					{TypeName: NotificationGroup, Roles: []string{"GROUP_OWNER", "GROUP_READ_ONLY"}},
+					{TypeName: NotificationWebhook, WebhookURL: "https://example.com/other"},
+				},
+			},
+			false,
+		},
+		{
+			"fewer matchers",
+			&AlertConfiguration{
+				EventTypeName:   EventOutsideMetricThreshold,
+				Matchers:        config.Matchers[:1],
+				MetricThreshold: config.MetricThreshold,
+				Notifications:   config.Notifications,
+			},
+			false,
+		},
+	}
+
+	for _, tt := range tests {
+		if same := sameAlertConfiguration(config, tt.other); same != tt.same {
+			t.Errorf("%s: sameAlertConfiguration() = %v, expected %v", tt.name, same, tt.same)
+		}
+	}
+}
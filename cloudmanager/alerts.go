@@ -0,0 +1,277 @@
+// Copyright 2020 MongoDB Inc
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package cloudmanager
+
+import (
+	"context"
+	"fmt"
+	"net/http"
+	"time"
+
+	atlas "github.com/mongodb/go-client-mongodb-atlas/mongodbatlas"
+)
+
+const (
+	alertsBasePath = "groups/%s/alerts"
+)
+
+// AlertStatus the lifecycle state of an alert
+type AlertStatus string
+
+// Alert statuses
+const (
+	AlertStatusOpen     AlertStatus = "OPEN"     // the alert condition is currently met
+	AlertStatusTracking AlertStatus = "TRACKING" // the condition is met, but the alert did not yet fire
+	AlertStatusClosed   AlertStatus = "CLOSED"   // the condition was resolved
+)
+
+// AlertTypeName the kind of entity an alert refers to
+type AlertTypeName string
+
+// Alert type names
+const (
+	AlertTypeHost       AlertTypeName = "HOST"
+	AlertTypeHostMetric AlertTypeName = "HOST_METRIC"
+	AlertTypeReplicaSet AlertTypeName = "REPLICA_SET"
+	AlertTypeAgent      AlertTypeName = "AGENT"
+	AlertTypeBackup     AlertTypeName = "BACKUP"
+	AlertTypeCluster    AlertTypeName = "CLUSTER"
+)
+
+// EventTypeName the event which triggered an alert
+type EventTypeName string
+
+// Host event types
+const (
+	EventHostDown                EventTypeName = "HOST_DOWN"
+	EventHostRecovering          EventTypeName = "HOST_RECOVERING"
+	EventHostRecovered           EventTypeName = "HOST_RECOVERED"
+	EventHostRestarted           EventTypeName = "HOST_RESTARTED"
+	EventHostUpgraded            EventTypeName = "HOST_UPGRADED"
+	EventHostDowngraded          EventTypeName = "HOST_DOWNGRADED"
+	EventHostRollback            EventTypeName = "HOST_ROLLBACK"
+	EventHostNowPrimary          EventTypeName = "HOST_NOW_PRIMARY"
+	EventHostNowSecondary        EventTypeName = "HOST_NOW_SECONDARY"
+	EventHostNowStandalone       EventTypeName = "HOST_NOW_STANDALONE"
+	EventHostExposed             EventTypeName = "HOST_EXPOSED"
+	EventHostSSLCertificateStale EventTypeName = "HOST_SSL_CERTIFICATE_STALE"
+	EventHostVersionBehind       EventTypeName = "VERSION_BEHIND"
+	EventOutsideMetricThreshold  EventTypeName = "OUTSIDE_METRIC_THRESHOLD"
+)
+
+// Replica set event types
+const (
+	EventPrimaryElected                   EventTypeName = "PRIMARY_ELECTED"
+	EventNoPrimary                        EventTypeName = "NO_PRIMARY"
+	EventTooManyElections                 EventTypeName = "TOO_MANY_ELECTIONS"
+	EventTooFewHealthyMembers             EventTypeName = "TOO_FEW_HEALTHY_MEMBERS"
+	EventTooManyUnhealthyMembers          EventTypeName = "TOO_MANY_UNHEALTHY_MEMBERS"
+	EventReplicationOplogWindowRunningOut EventTypeName = "REPLICATION_OPLOG_WINDOW_RUNNING_OUT"
+)
+
+// Agent event types
+const (
+	EventMonitoringAgentDown          EventTypeName = "MONITORING_AGENT_DOWN"
+	EventMonitoringAgentVersionBehind EventTypeName = "MONITORING_AGENT_VERSION_BEHIND"
+	EventBackupAgentDown              EventTypeName = "BACKUP_AGENT_DOWN"
+	EventBackupAgentVersionBehind     EventTypeName = "BACKUP_AGENT_VERSION_BEHIND"
+	EventBackupAgentConfCallFailure   EventTypeName = "BACKUP_AGENT_CONF_CALL_FAILURE"
+	EventAutomationAgentDown          EventTypeName = "AUTOMATION_AGENT_DOWN"
+)
+
+// Backup event types
+const (
+	EventOplogBehind                EventTypeName = "OPLOG_BEHIND"
+	EventResyncRequired             EventTypeName = "RESYNC_REQUIRED"
+	EventClusterMongosIsMissing     EventTypeName = "CLUSTER_MONGOS_IS_MISSING"
+	EventBackupTooManyErrors        EventTypeName = "BACKUP_TOO_MANY_ERRORS"
+	EventConsistentBackupInBadState EventTypeName = "CONSISTENT_BACKUP_IN_BAD_STATE"
+	EventGoodClustershot            EventTypeName = "GOOD_CLUSTERSHOT"
+	EventBadClustershot             EventTypeName = "BAD_CLUSTERSHOT"
+)
+
+// AlertsService is an interface for interfacing with the Alerts
+// endpoints of the MongoDB Cloud Manager API.
+// See more: https://docs.cloudmanager.mongodb.com/reference/api/alerts/
+type AlertsService interface {
+	List(context.Context, string, *AlertsListOptions) (*Alerts, *atlas.Response, error)
+	Get(context.Context, string, string) (*Alert, *atlas.Response, error)
+	Acknowledge(context.Context, string, string, time.Time, string) (*Alert, *atlas.Response, error)
+	Unacknowledge(context.Context, string, string, string) (*Alert, *atlas.Response, error)
+}
+
+// AlertsServiceOp handles communication with the Alerts related methods of the
+// MongoDB Cloud Manager API
+type AlertsServiceOp struct {
+	client *Client
+}
+
+var _ AlertsService = &AlertsServiceOp{}
+
+// CurrentValue the value of the metric that triggered an alert
+type CurrentValue struct {
+	Number float64 `json:"number"`
+	Units  string  `json:"units"`
+}
+
+// Alert represents an alert raised by Cloud Manager
+type Alert struct {
+	ID                     string        `json:"id,omitempty"`
+	AcknowledgedUntil      *time.Time    `json:"acknowledgedUntil,omitempty"`
+	AcknowledgementComment string        `json:"acknowledgementComment,omitempty"`
+	AcknowledgingUsername  string        `json:"acknowledgingUsername,omitempty"`
+	AlertConfigID          string        `json:"alertConfigId,omitempty"`
+	ClusterID              string        `json:"clusterId,omitempty"`
+	ClusterName            string        `json:"clusterName,omitempty"`
+	Created                string        `json:"created,omitempty"`
+	CurrentValue           *CurrentValue `json:"currentValue,omitempty"`
+	EventTypeName          EventTypeName `json:"eventTypeName,omitempty"`
+	GroupID                string        `json:"groupId,omitempty"`
+	HostID                 string        `json:"hostId,omitempty"`
+	HostnameAndPort        string        `json:"hostnameAndPort,omitempty"`
+	LastNotified           string        `json:"lastNotified,omitempty"`
+	Links                  []*atlas.Link `json:"links,omitempty"`
+	MetricName             string        `json:"metricName,omitempty"`
+	ReplicaSetName         string        `json:"replicaSetName,omitempty"`
+	Resolved               string        `json:"resolved,omitempty"`
+	Status                 AlertStatus   `json:"status,omitempty"`
+	TypeName               AlertTypeName `json:"typeName,omitempty"`
+	Updated                string        `json:"updated,omitempty"`
+}
+
+// Alerts represents a array of alerts
+type Alerts struct {
+	Links      []*atlas.Link `json:"links"`
+	Results    []*Alert      `json:"results"`
+	TotalCount int           `json:"totalCount"`
+}
+
+// AlertsListOptions filter the alerts returned by List
+type AlertsListOptions struct {
+	Status AlertStatus `url:"status,omitempty"`
+
+	atlas.ListOptions
+}
+
+// acknowledgeRequest the body of an acknowledgement update
+type acknowledgeRequest struct {
+	AcknowledgedUntil      time.Time `json:"acknowledgedUntil"`
+	AcknowledgementComment string    `json:"acknowledgementComment,omitempty"`
+}
+
+// List gets all alerts in a project, optionally filtered by status.
+// See more: https://docs.cloudmanager.mongodb.com/reference/api/alerts-get-all-alerts/
+func (s *AlertsServiceOp) List(ctx context.Context, projectID string, opts *AlertsListOptions) (*Alerts, *atlas.Response, error) {
+	if projectID == "" {
+		return nil, nil, atlas.NewArgError("projectID", "must be set")
+	}
+
+	basePath := fmt.Sprintf(alertsBasePath, projectID)
+	path, err := setListOptions(basePath, opts)
+	if err != nil {
+		return nil, nil, err
+	}
+
+	req, err := s.client.NewRequest(ctx, http.MethodGet, path, nil)
+	if err != nil {
+		return nil, nil, err
+	}
+
+	root := new(Alerts)
+	resp, err := s.client.Do(ctx, req, root)
+	if err != nil {
+		return nil, resp, err
+	}
+
+	if l := root.Links; l != nil {
+		resp.Links = l
+	}
+
+	return root, resp, nil
+}
+
+// Get gets a single alert by its ID.
+// See more: https://docs.cloudmanager.mongodb.com/reference/api/alerts-get-alert/
+func (s *AlertsServiceOp) Get(ctx context.Context, projectID, alertID string) (*Alert, *atlas.Response, error) {
+	if projectID == "" {
+		return nil, nil, atlas.NewArgError("projectID", "must be set")
+	}
+	if alertID == "" {
+		return nil, nil, atlas.NewArgError("alertID", "must be set")
+	}
+
+	basePath := fmt.Sprintf(alertsBasePath, projectID)
+	path := fmt.Sprintf("%s/%s", basePath, alertID)
+
+	req, err := s.client.NewRequest(ctx, http.MethodGet, path, nil)
+	if err != nil {
+		return nil, nil, err
+	}
+
+	root := new(Alert)
+	resp, err := s.client.Do(ctx, req, root)
+	if err != nil {
+		return nil, resp, err
+	}
+
+	return root, resp, err
+}
+
+// Acknowledge acknowledges an alert until the specified time; no notifications are sent until then.
+// See more: https://docs.cloudmanager.mongodb.com/reference/api/alerts-acknowledge-alert/
+func (s *AlertsServiceOp) Acknowledge(ctx context.Context, projectID, alertID string, until time.Time, comment string) (*Alert, *atlas.Response, error) {
+	if until.IsZero() {
+		return nil, nil, atlas.NewArgError("until", "must be set")
+	}
+
+	return s.acknowledge(ctx, projectID, alertID, &acknowledgeRequest{
+		AcknowledgedUntil:      until.UTC(),
+		AcknowledgementComment: comment,
+	})
+}
+
+// Unacknowledge removes a previous acknowledgement, by acknowledging the alert until a time in the past.
+// See more: https://docs.cloudmanager.mongodb.com/reference/api/alerts-acknowledge-alert/
+func (s *AlertsServiceOp) Unacknowledge(ctx context.Context, projectID, alertID, comment string) (*Alert, *atlas.Response, error) {
+	return s.acknowledge(ctx, projectID, alertID, &acknowledgeRequest{
+		AcknowledgedUntil:      time.Unix(0, 0).UTC(),
+		AcknowledgementComment: comment,
+	})
+}
+
+func (s *AlertsServiceOp) acknowledge(ctx context.Context, projectID, alertID string, ackRequest *acknowledgeRequest) (*Alert, *atlas.Response, error) {
+	if projectID == "" {
+		return nil, nil, atlas.NewArgError("projectID", "must be set")
+	}
+	if alertID == "" {
+		return nil, nil, atlas.NewArgError("alertID", "must be set")
+	}
+
+	basePath := fmt.Sprintf(alertsBasePath, projectID)
+	path := fmt.Sprintf("%s/%s", basePath, alertID)
+
+	req, err := s.client.NewRequest(ctx, http.MethodPatch, path, ackRequest)
+	if err != nil {
+		return nil, nil, err
+	}
+
+	root := new(Alert)
+	resp, err := s.client.Do(ctx, req, root)
+	if err != nil {
+		return nil, resp, err
+	}
+
+	return root, resp, err
+}
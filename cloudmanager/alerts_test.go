@@ -0,0 +1,284 @@
+// Copyright 2020 MongoDB Inc
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package cloudmanager
+
+import (
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"net/url"
+	"testing"
+	"time"
+
+	"github.com/go-test/deep"
+	"github.com/mongodb/go-client-mongodb-atlas/mongodbatlas"
+)
+
+func TestAlerts_List(t *testing.T) {
+	setup()
+	defer teardown()
+
+	projectID := "5a0a1e7e0f2912c554080adc"
+
+	mux.HandleFunc(fmt.Sprintf("/groups/%s/alerts", projectID), func(w http.ResponseWriter, r *http.Request) {
+		testMethod(t, r, http.MethodGet)
+		testQuery(t, r, url.Values{"status": {"OPEN"}})
+		_, _ = fmt.Fprint(w, `{
+			"links": [],
+			"results": [{
+				"acknowledgedUntil": "2016-08-18T12:00:00Z",
+				"acknowledgementComment": "Investigating",
+				"acknowledgingUsername": "someone@example.com",
+				"alertConfigId": "57b76ddc96e8215c017ceafb",
+				"clusterId": "5a0a1e7e0f2912c554080ae1",
+				"created": "2016-08-18T11:23:41Z",
+				"eventTypeName": "HOST_DOWN",
+				"groupId": "5a0a1e7e0f2912c554080adc",
+				"hostId": "22e3e3fbd8d7cd6f8ab2d7f1ff65d44b",
+				"hostnameAndPort": "host1.example.com:27017",
+				"id": "57b76ddc96e8215c017ceafc",
+				"lastNotified": "2016-08-18T11:23:42Z",
+				"links": [],
+				"replicaSetName": "rs0",
+				"status": "OPEN",
+				"typeName": "HOST",
+				"updated": "2016-08-18T11:23:42Z"
+			}],
+			"totalCount": 1
+		}`)
+	})
+
+	alerts, _, err := client.Alerts.List(ctx, projectID, &AlertsListOptions{Status: AlertStatusOpen})
+	if err != nil {
+		t.Fatalf("Alerts.List returned error: %v", err)
+	}
+
+	until := time.Date(2016, 8, 18, 12, 0, 0, 0, time.UTC)
+
+	expected := &Alerts{
+		Links: []*mongodbatlas.Link{},
+		Results: []*Alert{
+			{
+				ID:                     "57b76ddc96e8215c017ceafc",
+				AcknowledgedUntil:      &until,
+				AcknowledgementComment: "Investigating",
+				AcknowledgingUsername:  "someone@example.com",
+				AlertConfigID:          "57b76ddc96e8215c017ceafb",
+				ClusterID:              "5a0a1e7e0f2912c554080ae1",
+				Created:                "2016-08-18T11:23:41Z",
+				EventTypeName:          EventHostDown,
+				GroupID:                "5a0a1e7e0f2912c554080adc",
+				HostID:                 "22e3e3fbd8d7cd6f8ab2d7f1ff65d44b",
+				HostnameAndPort:        "host1.example.com:27017",
+				LastNotified:           "2016-08-18T11:23:42Z",
+				Links:                  []*mongodbatlas.Link{},
+				ReplicaSetName:         "rs0",
+				Status:                 AlertStatusOpen,
+				TypeName:               AlertTypeHost,
+				Updated:                "2016-08-18T11:23:42Z",
+			},
+		},
+		TotalCount: 1,
+	}
+
+	if diff := deep.Equal(alerts, expected); diff != nil {
+		t.Error(diff)
+	}
+}
+
+func TestAlerts_Get(t *testing.T) {
+	setup()
+	defer teardown()
+
+	projectID := "5a0a1e7e0f2912c554080adc"
+	alertID := "57b76ddc96e8215c017ceafc"
+
+	mux.HandleFunc(fmt.Sprintf("/groups/%s/alerts/%s", projectID, alertID), func(w http.ResponseWriter, r *http.Request) {
+		testMethod(t, r, http.MethodGet)
+		_, _ = fmt.Fprint(w, `{
+			"acknowledgedUntil": "2016-08-18T12:00:00Z",
+			"acknowledgementComment": "Investigating",
+			"acknowledgingUsername": "someone@example.com",
+			"alertConfigId": "57b76ddc96e8215c017ceafb",
+			"clusterId": "5a0a1e7e0f2912c554080ae1",
+			"created": "2016-08-18T11:23:41Z",
+			"eventTypeName": "HOST_DOWN",
+			"groupId": "5a0a1e7e0f2912c554080adc",
+			"hostId": "22e3e3fbd8d7cd6f8ab2d7f1ff65d44b",
+			"hostnameAndPort": "host1.example.com:27017",
+			"id": "57b76ddc96e8215c017ceafc",
+			"lastNotified": "2016-08-18T11:23:42Z",
+			"links": [],
+			"replicaSetName": "rs0",
+			"status": "OPEN",
+			"typeName": "HOST",
+			"updated": "2016-08-18T11:23:42Z"
+		}`)
+	})
+
+	alert, _, err := client.Alerts.Get(ctx, projectID, alertID)
+	if err != nil {
+		t.Fatalf("Alerts.Get returned error: %v", err)
+	}
+
+	until := time.Date(2016, 8, 18, 12, 0, 0, 0, time.UTC)
+
+	expected := &Alert{
+		ID:                     "57b76ddc96e8215c017ceafc",
+		AcknowledgedUntil:      &until,
+		AcknowledgementComment: "Investigating",
+		AcknowledgingUsername:  "someone@example.com",
+		AlertConfigID:          "57b76ddc96e8215c017ceafb",
+		ClusterID:              "5a0a1e7e0f2912c554080ae1",
+		Created:                "2016-08-18T11:23:41Z",
+		EventTypeName:          EventHostDown,
+		GroupID:                "5a0a1e7e0f2912c554080adc",
+		HostID:                 "22e3e3fbd8d7cd6f8ab2d7f1ff65d44b",
+		HostnameAndPort:        "host1.example.com:27017",
+		LastNotified:           "2016-08-18T11:23:42Z",
+		Links:                  []*mongodbatlas.Link{},
+		ReplicaSetName:         "rs0",
+		Status:                 AlertStatusOpen,
+		TypeName:               AlertTypeHost,
+		Updated:                "2016-08-18T11:23:42Z",
+	}
+
+	if diff := deep.Equal(alert, expected); diff != nil {
+		t.Error(diff)
+	}
+}
+
+func TestAlerts_Acknowledge(t *testing.T) {
+	setup()
+	defer teardown()
+
+	projectID := "5a0a1e7e0f2912c554080adc"
+	alertID := "57b76ddc96e8215c017ceafc"
+
+	mux.HandleFunc(fmt.Sprintf("/groups/%s/alerts/%s", projectID, alertID), func(w http.ResponseWriter, r *http.Request) {
+		testMethod(t, r, http.MethodPatch)
+
+		expected := map[string]interface{}{
+			"acknowledgedUntil":      "2016-08-18T12:00:00Z",
+			"acknowledgementComment": "Investigating",
+		}
+		var v map[string]interface{}
+		if err := json.NewDecoder(r.Body).Decode(&v); err != nil {
+			t.Fatalf("decode json: %v", err)
+		}
+		if diff := deep.Equal(v, expected); diff != nil {
+			t.Error(diff)
+		}
+
+		_, _ = fmt.Fprint(w, `{
+			"acknowledgedUntil": "2016-08-18T12:00:00Z",
+			"acknowledgementComment": "Investigating",
+			"acknowledgingUsername": "someone@example.com",
+			"alertConfigId": "57b76ddc96e8215c017ceafb",
+			"clusterId": "5a0a1e7e0f2912c554080ae1",
+			"created": "2016-08-18T11:23:41Z",
+			"eventTypeName": "HOST_DOWN",
+			"groupId": "5a0a1e7e0f2912c554080adc",
+			"hostId": "22e3e3fbd8d7cd6f8ab2d7f1ff65d44b",
+			"hostnameAndPort": "host1.example.com:27017",
+			"id": "57b76ddc96e8215c017ceafc",
+			"lastNotified": "2016-08-18T11:23:42Z",
+			"links": [],
+			"replicaSetName": "rs0",
+			"status": "OPEN",
+			"typeName": "HOST",
+			"updated": "2016-08-18T11:23:42Z"
+		}`)
+	})
+
+	until := time.Date(2016, 8, 18, 12, 0, 0, 0, time.UTC)
+	alert, _, err := client.Alerts.Acknowledge(ctx, projectID, alertID, until, "Investigating")
+	if err != nil {
+		t.Fatalf("Alerts.Acknowledge returned error: %v", err)
+	}
+
+	expected := &Alert{
+		ID:                     "57b76ddc96e8215c017ceafc",
+		AcknowledgedUntil:      &until,
+		AcknowledgementComment: "Investigating",
+		AcknowledgingUsername:  "someone@example.com",
+		AlertConfigID:          "57b76ddc96e8215c017ceafb",
+		ClusterID:              "5a0a1e7e0f2912c554080ae1",
+		Created:                "2016-08-18T11:23:41Z",
+		EventTypeName:          EventHostDown,
+		GroupID:                "5a0a1e7e0f2912c554080adc",
+		HostID:                 "22e3e3fbd8d7cd6f8ab2d7f1ff65d44b",
+		HostnameAndPort:        "host1.example.com:27017",
+		LastNotified:           "2016-08-18T11:23:42Z",
+		Links:                  []*mongodbatlas.Link{},
+		ReplicaSetName:         "rs0",
+		Status:                 AlertStatusOpen,
+		TypeName:               AlertTypeHost,
+		Updated:                "2016-08-18T11:23:42Z",
+	}
+
+	if diff := deep.Equal(alert, expected); diff != nil {
+		t.Error(diff)
+	}
+}
+
+func TestAlerts_Unacknowledge(t *testing.T) {
+	setup()
+	defer teardown()
+
+	projectID := "5a0a1e7e0f2912c554080adc"
+	alertID := "57b76ddc96e8215c017ceafc"
+
+	mux.HandleFunc(fmt.Sprintf("/groups/%s/alerts/%s", projectID, alertID), func(w http.ResponseWriter, r *http.Request) {
+		testMethod(t, r, http.MethodPatch)
+
+		expected := map[string]interface{}{
+			"acknowledgedUntil":      "1970-01-01T00:00:00Z",
+			"acknowledgementComment": "Fixed",
+		}
+		var v map[string]interface{}
+		if err := json.NewDecoder(r.Body).Decode(&v); err != nil {
+			t.Fatalf("decode json: %v", err)
+		}
+		if diff := deep.Equal(v, expected); diff != nil {
+			t.Error(diff)
+		}
+
+		_, _ = fmt.Fprint(w, `{
+			"acknowledgedUntil": "2016-08-18T12:00:00Z",
+			"acknowledgementComment": "Investigating",
+			"acknowledgingUsername": "someone@example.com",
+			"alertConfigId": "57b76ddc96e8215c017ceafb",
+			"clusterId": "5a0a1e7e0f2912c554080ae1",
+			"created": "2016-08-18T11:23:41Z",
+			"eventTypeName": "HOST_DOWN",
+			"groupId": "5a0a1e7e0f2912c554080adc",
+			"hostId": "22e3e3fbd8d7cd6f8ab2d7f1ff65d44b",
+			"hostnameAndPort": "host1.example.com:27017",
+			"id": "57b76ddc96e8215c017ceafc",
+			"lastNotified": "2016-08-18T11:23:42Z",
+			"links": [],
+			"replicaSetName": "rs0",
+			"status": "OPEN",
+			"typeName": "HOST",
+			"updated": "2016-08-18T11:23:42Z"
+		}`)
+	})
+
+	_, _, err := client.Alerts.Unacknowledge(ctx, projectID, alertID, "Fixed")
+	if err != nil {
+		t.Fatalf("Alerts.Unacknowledge returned error: %v", err)
+	}
+}
@@ -0,0 +1,567 @@
+// Copyright 2020 MongoDB Inc
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package cloudmanager
+
+import (
+	"context"
+	"fmt"
+	"net"
+	"net/http"
+	"net/url"
+
+	atlas "github.com/mongodb/go-client-mongodb-atlas/mongodbatlas"
+)
+
+const (
+	apiKeysBasePath        = "orgs/%s/apiKeys"
+	projectAPIKeysBasePath = "groups/%s/apiKeys"
+)
+
+// APIKeysService is an interface for interfacing with the Programmatic API Keys
+// endpoints of the MongoDB Cloud Manager API.
+// See more: https://docs.cloudmanager.mongodb.com/reference/api/api-keys/
+type APIKeysService interface {
+	List(context.Context, string, *atlas.ListOptions) (*APIKeys, *atlas.Response, error)
+	Get(context.Context, string, string) (*APIKey, *atlas.Response, error)
+	Create(context.Context, string, *APIKeyInput) (*APIKey, *atlas.Response, error)
+	Update(context.Context, string, string, *APIKeyInput) (*APIKey, *atlas.Response, error)
+	Delete(context.Context, string, string) (*atlas.Response, error)
+	ListProjectKeys(context.Context, string, *atlas.ListOptions) (*APIKeys, *atlas.Response, error)
+	CreateInProject(context.Context, string, *APIKeyInput) (*APIKey, *atlas.Response, error)
+	AssignToProject(context.Context, string, string, []RoleName) (*APIKey, *atlas.Response, error)
+	UnassignFromProject(context.Context, string, string) (*atlas.Response, error)
+	ListAccessList(context.Context, string, string, *atlas.ListOptions) (*AccessListEntries, *atlas.Response, error)
+	AddAccessList(context.Context, string, string, []*AccessListEntry) (*AccessListEntries, *atlas.Response, error)
+	RemoveAccessListEntry(context.Context, string, string, string) (*atlas.Response, error)
+	Rotate(context.Context, string, string, func(context.Context, *APIKey) error) (*APIKey, error)
+}
+
+// APIKeysServiceOp handles communication with the Programmatic API Keys related methods of the
+// MongoDB Cloud Manager API
+type APIKeysServiceOp struct {
+	client *Client
+}
+
+var _ APIKeysService = &APIKeysServiceOp{}
+
+// APIKey represents a programmatic API key.
+// The private key is only returned when the key is created.
+type APIKey struct {
+	ID         string        `json:"id"`
+	Desc       string        `json:"desc,omitempty"`
+	Links      []*atlas.Link `json:"links,omitempty"`
+	PrivateKey string        `json:"privateKey,omitempty"`
+	PublicKey  string        `json:"publicKey,omitempty"`
+	Roles      []*UserRole   `json:"roles,omitempty"`
+}
+
+// APIKeys represents a array of programmatic API keys
+type APIKeys struct {
+	Links      []*atlas.Link `json:"links"`
+	Results    []*APIKey     `json:"results"`
+	TotalCount int           `json:"totalCount"`
+}
+
+// APIKeyInput the description and organization roles of an API key to create or update
+type APIKeyInput struct {
+	Desc  string     `json:"desc,omitempty"`
+	Roles []RoleName `json:"roles,omitempty"`
+}
+
+// AccessListEntry an IP address or CIDR block allowed to use an API key or a user's API credentials.
+// Only one of IPAddress and CIDRBlock is set.
+type AccessListEntry struct {
+	CIDRBlock       string        `json:"cidrBlock,omitempty"`
+	Comment         string        `json:"comment,omitempty"`
+	Count           int           `json:"count,omitempty"`
+	Created         string        `json:"created,omitempty"`
+	IPAddress       string        `json:"ipAddress,omitempty"`
+	LastUsed        string        `json:"lastUsed,omitempty"`
+	LastUsedAddress string        `json:"lastUsedAddress,omitempty"`
+	Links           []*atlas.Link `json:"links,omitempty"`
+}
+
+// AccessListEntries represents a array of access list entries
+type AccessListEntries struct {
+	Links      []*atlas.Link      `json:"links"`
+	Results    []*AccessListEntry `json:"results"`
+	TotalCount int                `json:"totalCount"`
+}
+
+// projectAPIKeyRoles the body of a request assigning an API key to a project
+type projectAPIKeyRoles struct {
+	Roles []RoleName `json:"roles"`
+}
+
+// validateAccessListEntries checks each entry has a valid IP address or CIDR block, but not both
+func validateAccessListEntries(entries []*AccessListEntry) error {
+	if len(entries) == 0 {
+		return atlas.NewArgError("entries", "must be set")
+	}
+	for _, entry := range entries {
+		switch {
+		case entry == nil || (entry.IPAddress == "") == (entry.CIDRBlock == ""):
+			return atlas.NewArgError("entries", "must set one of ipAddress and cidrBlock")
+		case entry.IPAddress != "" && net.ParseIP(entry.IPAddress) == nil:
+			return atlas.NewArgError("ipAddress", fmt.Sprintf("%q is not a valid IP address", entry.IPAddress))
+		case entry.CIDRBlock != "":
+			if _, _, err := net.ParseCIDR(entry.CIDRBlock); err != nil {
+				return atlas.NewArgError("cidrBlock", fmt.Sprintf("%q is not a valid CIDR block", entry.CIDRBlock))
+			}
+		}
+	}
+	return nil
+}
+
+// List gets the programmatic API keys of an organization.
+// See more: https://docs.cloudmanager.mongodb.com/reference/api/api-keys/org/get-all-org-api-keys/
+func (s *APIKeysServiceOp) List(ctx context.Context, orgID string, opts *atlas.ListOptions) (*APIKeys, *atlas.Response, error) {
+	if orgID == "" {
+		return nil, nil, atlas.NewArgError("orgID", "must be set")
+	}
+
+	return s.list(ctx, fmt.Sprintf(apiKeysBasePath, orgID), opts)
+}
+
+// ListProjectKeys gets the programmatic API keys assigned to a project.
+// See more: https://docs.cloudmanager.mongodb.com/reference/api/api-keys/project/view-all-apiKeys-in-project/
+func (s *APIKeysServiceOp) ListProjectKeys(ctx context.Context, projectID string, opts *atlas.ListOptions) (*APIKeys, *atlas.Response, error) {
+	if projectID == "" {
+		return nil, nil, atlas.NewArgError("projectID", "must be set")
+	}
+
+	return s.list(ctx, fmt.Sprintf(projectAPIKeysBasePath, projectID), opts)
+}
+
+func (s *APIKeysServiceOp) list(ctx context.Context, basePath string, opts *atlas.ListOptions) (*APIKeys, *atlas.Response, error) {
+	path, err := setListOptions(basePath, opts)
+	if err != nil {
+		return nil, nil, err
+	}
+
+	req, err := s.client.NewRequest(ctx, http.MethodGet, path, nil)
+	if err != nil {
+		return nil, nil, err
+	}
+
+	root := new(APIKeys)
+	resp, err := s.client.Do(ctx, req, root)
+	if err != nil {
+		return nil, resp, err
+	}
+
+	if l := root.Links; l != nil {
+		resp.Links = l
+	}
+
+	return root, resp, nil
+}
+
+// Get gets a single programmatic API key.
+// See more: https://docs.cloudmanager.mongodb.com/reference/api/api-keys/org/get-one-org-api-key/
+func (s *APIKeysServiceOp) Get(ctx context.Context, orgID, keyID string) (*APIKey, *atlas.Response, error) {
+	if orgID == "" {
+		return nil, nil, atlas.NewArgError("orgID", "must be set")
+	}
+	if keyID == "" {
+		return nil, nil, atlas.NewArgError("keyID", "must be set")
+	}
+
+	basePath := fmt.Sprintf(apiKeysBasePath, orgID)
+	path := fmt.Sprintf("%s/%s", basePath, keyID)
+
+	req, err := s.client.NewRequest(ctx, http.MethodGet, path, nil)
+	if err != nil {
+		return nil, nil, err
+	}
+
+	root := new(APIKey)
+	resp, err := s.client.Do(ctx, req, root)
+	if err != nil {
+		return nil, resp, err
+	}
+
+	return root, resp, err
+}
+
+// Create creates a programmatic API key with organization roles; the description and roles are required.
+// See more: https://docs.cloudmanager.mongodb.com/reference/api/api-keys/org/create-one-org-api-key/
+func (s *APIKeysServiceOp) Create(ctx context.Context, orgID string, createRequest *APIKeyInput) (*APIKey, *atlas.Response, error) {
+	if orgID == "" {
+		return nil, nil, atlas.NewArgError("orgID", "must be set")
+	}
+	if createRequest == nil {
+		return nil, nil, atlas.NewArgError("createRequest", "cannot be nil")
+	}
+	if createRequest.Desc == "" {
+		return nil, nil, atlas.NewArgError("desc", "must be set")
+	}
+	if len(createRequest.Roles) == 0 {
+		return nil, nil, atlas.NewArgError("roles", "must be set")
+	}
+	if err := validateRoleNames(createRequest.Roles, OrgRoles, "API keys can only be created with organization roles"); err != nil {
+		return nil, nil, err
+	}
+
+	path := fmt.Sprintf(apiKeysBasePath, orgID)
+
+	req, err := s.client.NewRequest(ctx, http.MethodPost, path, createRequest)
+	if err != nil {
+		return nil, nil, err
+	}
+
+	root := new(APIKey)
+	resp, err := s.client.Do(ctx, req, root)
+	if err != nil {
+		return nil, resp, err
+	}
+
+	return root, resp, err
+}
+
+// Update updates the description or organization roles of a programmatic API key; only the specified fields are modified.
+// See more: https://docs.cloudmanager.mongodb.com/reference/api/api-keys/org/update-one-org-api-key/
+func (s *APIKeysServiceOp) Update(ctx context.Context, orgID, keyID string, updateRequest *APIKeyInput) (*APIKey, *atlas.Response, error) {
+	if orgID == "" {
+		return nil, nil, atlas.NewArgError("orgID", "must be set")
+	}
+	if keyID == "" {
+		return nil, nil, atlas.NewArgError("keyID", "must be set")
+	}
+	if updateRequest == nil {
+		return nil, nil, atlas.NewArgError("updateRequest", "cannot be nil")
+	}
+	if err := validateRoleNames(updateRequest.Roles, OrgRoles, "API keys can only be granted organization roles"); err != nil {
+		return nil, nil, err
+	}
+
+	basePath := fmt.Sprintf(apiKeysBasePath, orgID)
+	path := fmt.Sprintf("%s/%s", basePath, keyID)
+
+	req, err := s.client.NewRequest(ctx, http.MethodPatch, path, updateRequest)
+	if err != nil {
+		return nil, nil, err
+	}
+
+	root := new(APIKey)
+	resp, err := s.client.Do(ctx, req, root)
+	if err != nil {
+		return nil, resp, err
+	}
+
+	return root, resp, err
+}
+
+// Delete deletes a programmatic API key, and removes it from every project.
+// See more: https://docs.cloudmanager.mongodb.com/reference/api/api-keys/org/delete-one-api-key/
+func (s *APIKeysServiceOp) Delete(ctx context.Context, orgID, keyID string) (*atlas.Response, error) {
+	if orgID == "" {
+		return nil, atlas.NewArgError("orgID", "must be set")
+	}
+	if keyID == "" {
+		return nil, atlas.NewArgError("keyID", "must be set")
+	}
+
+	basePath := fmt.Sprintf(apiKeysBasePath, orgID)
+	path := fmt.Sprintf("%s/%s", basePath, keyID)
+
+	req, err := s.client.NewRequest(ctx, http.MethodDelete, path, nil)
+	if err != nil {
+		return nil, err
+	}
+
+	resp, err := s.client.Do(ctx, req, nil)
+
+	return resp, err
+}
+
+// CreateInProject creates a programmatic API key in the project's organization and assigns it to the project;
+// the description and project roles are required.
+// See more: https://docs.cloudmanager.mongodb.com/reference/api/api-keys/project/create-one-apiKey-in-one-project/
+func (s *APIKeysServiceOp) CreateInProject(ctx context.Context, projectID string, createRequest *APIKeyInput) (*APIKey, *atlas.Response, error) {
+	if projectID == "" {
+		return nil, nil, atlas.NewArgError("projectID", "must be set")
+	}
+	if createRequest == nil {
+		return nil, nil, atlas.NewArgError("createRequest", "cannot be nil")
+	}
+	if createRequest.Desc == "" {
+		return nil, nil, atlas.NewArgError("desc", "must be set")
+	}
+	if len(createRequest.Roles) == 0 {
+		return nil, nil, atlas.NewArgError("roles", "must be set")
+	}
+	if err := validateRoleNames(createRequest.Roles, ProjectRoles, "API keys can only be created with project roles in a project"); err != nil {
+		return nil, nil, err
+	}
+
+	path := fmt.Sprintf(projectAPIKeysBasePath, projectID)
+
+	req, err := s.client.NewRequest(ctx, http.MethodPost, path, createRequest)
+	if err != nil {
+		return nil, nil, err
+	}
+
+	root := new(APIKey)
+	resp, err := s.client.Do(ctx, req, root)
+	if err != nil {
+		return nil, resp, err
+	}
+
+	return root, resp, err
+}
+
+// AssignToProject assigns a programmatic API key of the project's organization to the project,
+// or replaces its roles in the project if it is already assigned.
+// See more: https://docs.cloudmanager.mongodb.com/reference/api/api-keys/project/assign-one-org-apiKey-to-one-project/
+func (s *APIKeysServiceOp) AssignToProject(ctx context.Context, projectID, keyID string, roleNames []RoleName) (*APIKey, *atlas.Response, error) {
+	if projectID == "" {
+		return nil, nil, atlas.NewArgError("projectID", "must be set")
+	}
+	if keyID == "" {
+		return nil, nil, atlas.NewArgError("keyID", "must be set")
+	}
+	if len(roleNames) == 0 {
+		return nil, nil, atlas.NewArgError("roleNames", "must be set")
+	}
+	if err := validateRoleNames(roleNames, ProjectRoles, "API keys can only be assigned project roles in a project"); err != nil {
+		return nil, nil, err
+	}
+
+	basePath := fmt.Sprintf(projectAPIKeysBasePath, projectID)
+	path := fmt.Sprintf("%s/%s", basePath, keyID)
+
+	req, err := s.client.NewRequest(ctx, http.MethodPatch, path, &projectAPIKeyRoles{Roles: roleNames})
+	if err != nil {
+		return nil, nil, err
+	}
+
+	root := new(APIKey)
+	resp, err := s.client.Do(ctx, req, root)
+	if err != nil {
+		return nil, resp, err
+	}
+
+	return root, resp, err
+}
+
+// UnassignFromProject removes a programmatic API key from a project; the key still exists in the organization.
+// See more: https://docs.cloudmanager.mongodb.com/reference/api/api-keys/project/delete-one-apiKey-in-one-project/
+func (s *APIKeysServiceOp) UnassignFromProject(ctx context.Context, projectID, keyID string) (*atlas.Response, error) {
+	if projectID == "" {
+		return nil, atlas.NewArgError("projectID", "must be set")
+	}
+	if keyID == "" {
+		return nil, atlas.NewArgError("keyID", "must be set")
+	}
+
+	basePath := fmt.Sprintf(projectAPIKeysBasePath, projectID)
+	path := fmt.Sprintf("%s/%s", basePath, keyID)
+
+	req, err := s.client.NewRequest(ctx, http.MethodDelete, path, nil)
+	if err != nil {
+		return nil, err
+	}
+
+	resp, err := s.client.Do(ctx, req, nil)
+
+	return resp, err
+}
+
+// ListAccessList gets the IP addresses and CIDR blocks allowed to use a programmatic API key.
+// See more: https://docs.cloudmanager.mongodb.com/reference/api/api-keys/org/get-all-org-api-key-whitelist/
+func (s *APIKeysServiceOp) ListAccessList(ctx context.Context, orgID, keyID string, opts *atlas.ListOptions) (*AccessListEntries, *atlas.Response, error) {
+	if orgID == "" {
+		return nil, nil, atlas.NewArgError("orgID", "must be set")
+	}
+	if keyID == "" {
+		return nil, nil, atlas.NewArgError("keyID", "must be set")
+	}
+
+	basePath := fmt.Sprintf(apiKeysBasePath, orgID)
+	path, err := setListOptions(fmt.Sprintf("%s/%s/accessList", basePath, keyID), opts)
+	if err != nil {
+		return nil, nil, err
+	}
+
+	req, err := s.client.NewRequest(ctx, http.MethodGet, path, nil)
+	if err != nil {
+		return nil, nil, err
+	}
+
+	root := new(AccessListEntries)
+	resp, err := s.client.Do(ctx, req, root)
+	if err != nil {
+		return nil, resp, err
+	}
+
+	if l := root.Links; l != nil {
+		resp.Links = l
+	}
+
+	return root, resp, nil
+}
+
+// AddAccessList allows IP addresses or CIDR blocks to use a programmatic API key, and returns the whole access list.
+// See more: https://docs.cloudmanager.mongodb.com/reference/api/api-keys/org/create-org-api-key-whitelist/
+func (s *APIKeysServiceOp) AddAccessList(ctx context.Context, orgID, keyID string, entries []*AccessListEntry) (*AccessListEntries, *atlas.Response, error) {
+	if orgID == "" {
+		return nil, nil, atlas.NewArgError("orgID", "must be set")
+	}
+	if keyID == "" {
+		return nil, nil, atlas.NewArgError("keyID", "must be set")
+	}
+	if err := validateAccessListEntries(entries); err != nil {
+		return nil, nil, err
+	}
+
+	basePath := fmt.Sprintf(apiKeysBasePath, orgID)
+	path := fmt.Sprintf("%s/%s/accessList", basePath, keyID)
+
+	req, err := s.client.NewRequest(ctx, http.MethodPost, path, entries)
+	if err != nil {
+		return nil, nil, err
+	}
+
+	root := new(AccessListEntries)
+	resp, err := s.client.Do(ctx, req, root)
+	if err != nil {
+		return nil, resp, err
+	}
+
+	return root, resp, err
+}
+
+// RemoveAccessListEntry removes an IP address or CIDR block from the access list of a programmatic API key.
+// See more: https://docs.cloudmanager.mongodb.com/reference/api/api-keys/org/delete-one-ip-address-from-org-api-key-whitelist/
+func (s *APIKeysServiceOp) RemoveAccessListEntry(ctx context.Context, orgID, keyID, ipAddressOrCIDR string) (*atlas.Response, error) {
+	if orgID == "" {
+		return nil, atlas.NewArgError("orgID", "must be set")
+	}
+	if keyID == "" {
+		return nil, atlas.NewArgError("keyID", "must be set")
+	}
+	if ipAddressOrCIDR == "" {
+		return nil, atlas.NewArgError("ipAddressOrCIDR", "must be set")
+	}
+
+	basePath := fmt.Sprintf(apiKeysBasePath, orgID)
+	path := fmt.Sprintf("%s/%s/accessList/%s", basePath, keyID, url.PathEscape(ipAddressOrCIDR))
+
+	req, err := s.client.NewRequest(ctx, http.MethodDelete, path, nil)
+	if err != nil {
+		return nil, err
+	}
+
+	resp, err := s.client.Do(ctx, req, nil)
+
+	return resp, err
+}
+
+// Rotate replaces a programmatic API key: it creates a key with the same description, roles, project assignments
+// and access list, calls swap so the caller can start using the new key, then deletes the old key.
+// A key without organization roles is recreated in its first project, then assigned to the others.
+// If the replacement cannot be set up, or swap fails, the new key is deleted and the old one is kept.
+// If only the deletion of the old key fails, the new key is returned along with the error.
+func (s *APIKeysServiceOp) Rotate(ctx context.Context, orgID, keyID string, swap func(context.Context, *APIKey) error) (*APIKey, error) {
+	if swap == nil {
+		return nil, atlas.NewArgError("swap", "cannot be nil")
+	}
+
+	old, _, err := s.Get(ctx, orgID, keyID)
+	if err != nil {
+		return nil, err
+	}
+
+	var (
+		orgRoles     []RoleName
+		projectIDs   []string
+		projectRoles = make(map[string][]RoleName)
+	)
+	for _, role := range old.Roles {
+		if role.GroupID == "" {
+			orgRoles = append(orgRoles, RoleName(role.RoleName))
+			continue
+		}
+		if _, ok := projectRoles[role.GroupID]; !ok {
+			projectIDs = append(projectIDs, role.GroupID)
+		}
+		projectRoles[role.GroupID] = append(projectRoles[role.GroupID], RoleName(role.RoleName))
+	}
+
+	var entries []*AccessListEntry
+	opts := &atlas.ListOptions{}
+	err = listPages(opts, func() (int, int, error) {
+		page, _, err := s.ListAccessList(ctx, orgID, keyID, opts)
+		if err != nil {
+			return 0, 0, err
+		}
+		for _, entry := range page.Results {
+			// the API returns the /32 CIDR block of single IP address entries too, but only one of them can be added
+			copied := &AccessListEntry{CIDRBlock: entry.CIDRBlock, Comment: entry.Comment}
+			if entry.IPAddress != "" {
+				copied = &AccessListEntry{IPAddress: entry.IPAddress, Comment: entry.Comment}
+			}
+			entries = append(entries, copied)
+		}
+		return len(page.Results), page.TotalCount, nil
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	var replacement *APIKey
+	switch {
+	case len(orgRoles) > 0:
+		replacement, _, err = s.Create(ctx, orgID, &APIKeyInput{Desc: old.Desc, Roles: orgRoles})
+	case len(projectIDs) > 0:
+		replacement, _, err = s.CreateInProject(ctx, projectIDs[0], &APIKeyInput{Desc: old.Desc, Roles: projectRoles[projectIDs[0]]})
+		projectIDs = projectIDs[1:]
+	default:
+		return nil, fmt.Errorf("API key %s has no roles to copy to a new key", keyID)
+	}
+	if err != nil {
+		return nil, err
+	}
+
+	for _, projectID := range projectIDs {
+		if _, _, err := s.AssignToProject(ctx, projectID, replacement.ID, projectRoles[projectID]); err != nil {
+			return nil, s.abandonReplacement(ctx, orgID, replacement.ID, fmt.Errorf("assigning the new API key to project %s: %w", projectID, err))
+		}
+	}
+	if len(entries) > 0 {
+		if _, _, err := s.AddAccessList(ctx, orgID, replacement.ID, entries); err != nil {
+			return nil, s.abandonReplacement(ctx, orgID, replacement.ID, fmt.Errorf("copying the access list to the new API key: %w", err))
+		}
+	}
+
+	if err := swap(ctx, replacement); err != nil {
+		return nil, s.abandonReplacement(ctx, orgID, replacement.ID, err)
+	}
+
+	if _, err := s.Delete(ctx, orgID, keyID); err != nil {
+		return replacement, fmt.Errorf("deleting the old API key %s: %w", keyID, err)
+	}
+
+	return replacement, nil
+}
+
+// abandonReplacement deletes a replacement key which will not be used, and returns the error which caused it
+func (s *APIKeysServiceOp) abandonReplacement(ctx context.Context, orgID, keyID string, cause error) error {
+	if _, err := s.Delete(ctx, orgID, keyID); err != nil {
+		return fmt.Errorf("%w (deleting the new API key %s also failed: %v)", cause, keyID, err)
+	}
+	return cause
+}
@@ -0,0 +1,608 @@
+// Copyright 2020 MongoDB Inc
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package cloudmanager
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"net/http"
+	"sync"
+	"testing"
+
+	"github.com/go-test/deep"
+	"github.com/mongodb/go-client-mongodb-atlas/mongodbatlas"
+)
+
+func TestAPIKeys_List(t *testing.T) {
+	setup()
+	defer teardown()
+
+	orgID := "5a0a1e7e0f2912c554080adb"
+
+	mux.HandleFunc(fmt.Sprintf("/orgs/%s/apiKeys", orgID), func(w http.ResponseWriter, r *http.Request) {
+		testMethod(t, r, http.MethodGet)
+		_, _ = fmt.Fprint(w, `{
+			"links": [],
+			"results": [{
+				"desc": "automation",
+				"id": "5c47503320eef5da2fdbd8c9",
+				"links": [],
+				"privateKey": "********-****-****-db2c132ca78d",
+				"publicKey": "ewmaqvdo",
+				"roles": [
+					{"orgId": "5a0a1e7e0f2912c554080adb", "roleName": "ORG_MEMBER"},
+					{"groupId": "5a0a1e7e0f2912c554080adc", "roleName": "GROUP_READ_ONLY"}
+				]
+			}],
+			"totalCount": 1
+		}`)
+	})
+
+	keys, _, err := client.APIKeys.List(ctx, orgID, nil)
+	if err != nil {
+		t.Fatalf("APIKeys.List returned error: %v", err)
+	}
+
+	expected := &APIKeys{
+		Links: []*mongodbatlas.Link{},
+		Results: []*APIKey{
+			{
+				ID:         "5c47503320eef5da2fdbd8c9",
+				Desc:       "automation",
+				Links:      []*mongodbatlas.Link{},
+				PrivateKey: "********-****-****-db2c132ca78d",
+				PublicKey:  "ewmaqvdo",
+				Roles: []*UserRole{
+					{OrgID: "5a0a1e7e0f2912c554080adb", RoleName: string(RoleOrgMember)},
+					{GroupID: "5a0a1e7e0f2912c554080adc", RoleName: string(RoleGroupReadOnly)},
+				},
+			},
+		},
+		TotalCount: 1,
+	}
+
+	if diff := deep.Equal(keys, expected); diff != nil {
+		t.Error(diff)
+	}
+}
+
+func TestAPIKeys_Create(t *testing.T) {
+	setup()
+	defer teardown()
+
+	orgID := "5a0a1e7e0f2912c554080adb"
+
+	mux.HandleFunc(fmt.Sprintf("/orgs/%s/apiKeys", orgID), func(w http.ResponseWriter, r *http.Request) {
+		testMethod(t, r, http.MethodPost)
+
+		expected := map[string]interface{}{
+			"desc":  "automation",
+			"roles": []interface{}{"ORG_MEMBER"},
+		}
+		if diff := deep.Equal(decodeBody(t, r), expected); diff != nil {
+			t.Error(diff)
+		}
+
+		_, _ = fmt.Fprint(w, `{
+			"desc": "automation",
+			"id": "5c47503320eef5da2fdbd8c9",
+			"links": [],
+			"privateKey": "********-****-****-db2c132ca78d",
+			"publicKey": "ewmaqvdo",
+			"roles": [
+				{"orgId": "5a0a1e7e0f2912c554080adb", "roleName": "ORG_MEMBER"},
+				{"groupId": "5a0a1e7e0f2912c554080adc", "roleName": "GROUP_READ_ONLY"}
+			]
+		}`)
+	})
+
+	key, _, err := client.APIKeys.Create(ctx, orgID, &APIKeyInput{Desc: "automation", Roles: []RoleName{RoleOrgMember}})
+	if err != nil {
+		t.Fatalf("APIKeys.Create returned error: %v", err)
+	}
+
+	expected := &APIKey{
+		ID:         "5c47503320eef5da2fdbd8c9",
+		Desc:       "automation",
+		Links:      []*mongodbatlas.Link{},
+		PrivateKey: "********-****-****-db2c132ca78d",
+		PublicKey:  "ewmaqvdo",
+		Roles: []*UserRole{
+			{OrgID: "5a0a1e7e0f2912c554080adb", RoleName: string(RoleOrgMember)},
+			{GroupID: "5a0a1e7e0f2912c554080adc", RoleName: string(RoleGroupReadOnly)},
+		},
+	}
+
+	if diff := deep.Equal(key, expected); diff != nil {
+		t.Error(diff)
+	}
+}
+
+func TestAPIKeys_Create_projectRole(t *testing.T) {
+	setup()
+	defer teardown()
+
+	_, _, err := client.APIKeys.Create(ctx, "5a0a1e7e0f2912c554080adb", &APIKeyInput{Desc: "automation", Roles: []RoleName{RoleGroupOwner}})
+
+	if _, ok := err.(*InvalidRoleError); !ok {
+		t.Fatalf("expected an *InvalidRoleError, got %v", err)
+	}
+}
+
+func TestAPIKeys_Update(t *testing.T) {
+	setup()
+	defer teardown()
+
+	orgID := "5a0a1e7e0f2912c554080adb"
+	keyID := "5c47503320eef5da2fdbd8c9"
+
+	mux.HandleFunc(fmt.Sprintf("/orgs/%s/apiKeys/%s", orgID, keyID), func(w http.ResponseWriter, r *http.Request) {
+		testMethod(t, r, http.MethodPatch)
+
+		expected := map[string]interface{}{"desc": "automation"}
+		if diff := deep.Equal(decodeBody(t, r), expected); diff != nil {
+			t.Error(diff)
+		}
+
+		_, _ = fmt.Fprint(w, `{
+			"desc": "automation",
+			"id": "5c47503320eef5da2fdbd8c9",
+			"links": [],
+			"privateKey": "********-****-****-db2c132ca78d",
+			"publicKey": "ewmaqvdo",
+			"roles": [
+				{"orgId": "5a0a1e7e0f2912c554080adb", "roleName": "ORG_MEMBER"},
+				{"groupId": "5a0a1e7e0f2912c554080adc", "roleName": "GROUP_READ_ONLY"}
+			]
+		}`)
+	})
+
+	key, _, err := client.APIKeys.Update(ctx, orgID, keyID, &APIKeyInput{Desc: "automation"})
+	if err != nil {
+		t.Fatalf("APIKeys.Update returned error: %v", err)
+	}
+
+	expected := &APIKey{
+		ID:         "5c47503320eef5da2fdbd8c9",
+		Desc:       "automation",
+		Links:      []*mongodbatlas.Link{},
+		PrivateKey: "********-****-****-db2c132ca78d",
+		PublicKey:  "ewmaqvdo",
+		Roles: []*UserRole{
+			{OrgID: "5a0a1e7e0f2912c554080adb", RoleName: string(RoleOrgMember)},
+			{GroupID: "5a0a1e7e0f2912c554080adc", RoleName: string(RoleGroupReadOnly)},
+		},
+	}
+
+	if diff := deep.Equal(key, expected); diff != nil {
+		t.Error(diff)
+	}
+}
+
+func TestAPIKeys_CreateInProject(t *testing.T) {
+	setup()
+	defer teardown()
+
+	projectID := "5a0a1e7e0f2912c554080adc"
+
+	mux.HandleFunc(fmt.Sprintf("/groups/%s/apiKeys", projectID), func(w http.ResponseWriter, r *http.Request) {
+		testMethod(t, r, http.MethodPost)
+
+		expected := map[string]interface{}{
+			"desc":  "automation",
+			"roles": []interface{}{"GROUP_READ_ONLY"},
+		}
+		if diff := deep.Equal(decodeBody(t, r), expected); diff != nil {
+			t.Error(diff)
+		}
+
+		_, _ = fmt.Fprint(w, `{
+			"desc": "automation",
+			"id": "5c47503320eef5da2fdbd8c9",
+			"links": [],
+			"privateKey": "********-****-****-db2c132ca78d",
+			"publicKey": "ewmaqvdo",
+			"roles": [
+				{"orgId": "5a0a1e7e0f2912c554080adb", "roleName": "ORG_MEMBER"},
+				{"groupId": "5a0a1e7e0f2912c554080adc", "roleName": "GROUP_READ_ONLY"}
+			]
+		}`)
+	})
+
+	key, _, err := client.APIKeys.CreateInProject(ctx, projectID, &APIKeyInput{Desc: "automation", Roles: []RoleName{RoleGroupReadOnly}})
+	if err != nil {
+		t.Fatalf("APIKeys.CreateInProject returned error: %v", err)
+	}
+
+	expected := &APIKey{
+		ID:         "5c47503320eef5da2fdbd8c9",
+		Desc:       "automation",
+		Links:      []*mongodbatlas.Link{},
+		PrivateKey: "********-****-****-db2c132ca78d",
+		PublicKey:  "ewmaqvdo",
+		Roles: []*UserRole{
+			{OrgID: "5a0a1e7e0f2912c554080adb", RoleName: string(RoleOrgMember)},
+			{GroupID: "5a0a1e7e0f2912c554080adc", RoleName: string(RoleGroupReadOnly)},
+		},
+	}
+
+	if diff := deep.Equal(key, expected); diff != nil {
+		t.Error(diff)
+	}
+}
+
+func TestAPIKeys_CreateInProject_orgRole(t *testing.T) {
+	setup()
+	defer teardown()
+
+	_, _, err := client.APIKeys.CreateInProject(ctx, "5a0a1e7e0f2912c554080adc", &APIKeyInput{Desc: "automation", Roles: []RoleName{RoleOrgOwner}})
+
+	if _, ok := err.(*InvalidRoleError); !ok {
+		t.Fatalf("expected an *InvalidRoleError, got %v", err)
+	}
+}
+
+func TestAPIKeys_AssignToProject(t *testing.T) {
+	setup()
+	defer teardown()
+
+	projectID := "5a0a1e7e0f2912c554080adc"
+	keyID := "5c47503320eef5da2fdbd8c9"
+
+	mux.HandleFunc(fmt.Sprintf("/groups/%s/apiKeys/%s", projectID, keyID), func(w http.ResponseWriter, r *http.Request) {
+		testMethod(t, r, http.MethodPatch)
+
+		expected := map[string]interface{}{"roles": []interface{}{"GROUP_READ_ONLY"}}
+		if diff := deep.Equal(decodeBody(t, r), expected); diff != nil {
+			t.Error(diff)
+		}
+
+		_, _ = fmt.Fprint(w, `{
+			"desc": "automation",
+			"id": "5c47503320eef5da2fdbd8c9",
+			"links": [],
+			"privateKey": "********-****-****-db2c132ca78d",
+			"publicKey": "ewmaqvdo",
+			"roles": [
+				{"orgId": "5a0a1e7e0f2912c554080adb", "roleName": "ORG_MEMBER"},
+				{"groupId": "5a0a1e7e0f2912c554080adc", "roleName": "GROUP_READ_ONLY"}
+			]
+		}`)
+	})
+
+	key, _, err := client.APIKeys.AssignToProject(ctx, projectID, keyID, []RoleName{RoleGroupReadOnly})
+	if err != nil {
+		t.Fatalf("APIKeys.AssignToProject returned error: %v", err)
+	}
+
+	expected := &APIKey{
+		ID:         "5c47503320eef5da2fdbd8c9",
+		Desc:       "automation",
+		Links:      []*mongodbatlas.Link{},
+		PrivateKey: "********-****-****-db2c132ca78d",
+		PublicKey:  "ewmaqvdo",
+		Roles: []*UserRole{
+			{OrgID: "5a0a1e7e0f2912c554080adb", RoleName: string(RoleOrgMember)},
+			{GroupID: "5a0a1e7e0f2912c554080adc", RoleName: string(RoleGroupReadOnly)},
+		},
+	}
+
+	if diff := deep.Equal(key, expected); diff != nil {
+		t.Error(diff)
+	}
+}
+
+func TestAPIKeys_UnassignFromProject(t *testing.T) {
+	setup()
+	defer teardown()
+
+	projectID := "5a0a1e7e0f2912c554080adc"
+	keyID := "5c47503320eef5da2fdbd8c9"
+
+	mux.HandleFunc(fmt.Sprintf("/groups/%s/apiKeys/%s", projectID, keyID), func(w http.ResponseWriter, r *http.Request) {
+		testMethod(t, r, http.MethodDelete)
+	})
+
+	_, err := client.APIKeys.UnassignFromProject(ctx, projectID, keyID)
+	if err != nil {
+		t.Fatalf("APIKeys.UnassignFromProject returned error: %v", err)
+	}
+}
+
+func TestAPIKeys_AddAccessList(t *testing.T) {
+	setup()
+	defer teardown()
+
+	orgID := "5a0a1e7e0f2912c554080adb"
+	keyID := "5c47503320eef5da2fdbd8c9"
+
+	mux.HandleFunc(fmt.Sprintf("/orgs/%s/apiKeys/%s/accessList", orgID, keyID), func(w http.ResponseWriter, r *http.Request) {
+		testMethod(t, r, http.MethodPost)
+
+		expected := []interface{}{
+			map[string]interface{}{"ipAddress": "192.0.2.1"},
+			map[string]interface{}{"cidrBlock": "198.51.100.0/24"},
+		}
+		if diff := deep.Equal(decodeBody(t, r), expected); diff != nil {
+			t.Error(diff)
+		}
+
+		_, _ = fmt.Fprint(w, `{
+			"links": [],
+			"results": [
+				{"count": 0, "created": "2019-01-24T16:34:57Z", "ipAddress": "192.0.2.1", "links": []},
+				{"cidrBlock": "198.51.100.0/24", "count": 0, "created": "2019-01-24T16:34:57Z", "links": []}
+			],
+			"totalCount": 2
+		}`)
+	})
+
+	entries := []*AccessListEntry{{IPAddress: "192.0.2.1"}, {CIDRBlock: "198.51.100.0/24"}}
+	accessList, _, err := client.APIKeys.AddAccessList(ctx, orgID, keyID, entries)
+	if err != nil {
+		t.Fatalf("APIKeys.AddAccessList returned error: %v", err)
+	}
+
+	expected := &AccessListEntries{
+		Links: []*mongodbatlas.Link{},
+		Results: []*AccessListEntry{
+			{Created: "2019-01-24T16:34:57Z", IPAddress: "192.0.2.1", Links: []*mongodbatlas.Link{}},
+			{CIDRBlock: "198.51.100.0/24", Created: "2019-01-24T16:34:57Z", Links: []*mongodbatlas.Link{}},
+		},
+		TotalCount: 2,
+	}
+
+	if diff := deep.Equal(accessList, expected); diff != nil {
+		t.Error(diff)
+	}
+}
+
+func TestAPIKeys_AddAccessList_invalid(t *testing.T) {
+	setup()
+	defer teardown()
+
+	tests := map[string][]*AccessListEntry{
+		"empty":           {},
+		"neither":         {{}},
+		"both":            {{IPAddress: "192.0.2.1", CIDRBlock: "198.51.100.0/24"}},
+		"invalid address": {{IPAddress: "192.0.2"}},
+		"invalid block":   {{CIDRBlock: "198.51.100.0/33"}},
+	}
+
+	for name, entries := range tests {
+		if _, _, err := client.APIKeys.AddAccessList(ctx, "5a0a1e7e0f2912c554080adb", "5c47503320eef5da2fdbd8c9", entries); err == nil {
+			t.Errorf("%s: expected an error", name)
+		}
+	}
+}
+
+func TestAPIKeys_RemoveAccessListEntry(t *testing.T) {
+	setup()
+	defer teardown()
+
+	orgID := "5a0a1e7e0f2912c554080adb"
+	keyID := "5c47503320eef5da2fdbd8c9"
+
+	mux.HandleFunc(fmt.Sprintf("/orgs/%s/apiKeys/%s/accessList/", orgID, keyID), func(w http.ResponseWriter, r *http.Request) {
+		testMethod(t, r, http.MethodDelete)
+
+		// the CIDR block is a single path segment
+		expected := fmt.Sprintf("/orgs/%s/apiKeys/%s/accessList/198.51.100.0%%2F24", orgID, keyID)
+		if r.URL.EscapedPath() != expected {
+			t.Errorf("expected path %s, got %s", expected, r.URL.EscapedPath())
+		}
+	})
+
+	_, err := client.APIKeys.RemoveAccessListEntry(ctx, orgID, keyID, "198.51.100.0/24")
+	if err != nil {
+		t.Fatalf("APIKeys.RemoveAccessListEntry returned error: %v", err)
+	}
+}
+
+// fakeAPIKeysServer serves an old key with a project assignment and an access list entry, and records the changes
+type fakeAPIKeysServer struct {
+	mu          sync.Mutex
+	calls       []string
+	failAssign  bool
+	noOrgRoles  bool
+	orgID       string
+	projectID   string
+	oldKeyID    string
+	newKeyID    string
+	createBody  interface{}
+	projectBody interface{}
+	accessBody  interface{}
+}
+
+func (s *fakeAPIKeysServer) register(t *testing.T) {
+	record := func(call string) {
+		s.mu.Lock()
+		defer s.mu.Unlock()
+		s.calls = append(s.calls, call)
+	}
+
+	mux.HandleFunc(fmt.Sprintf("/orgs/%s/apiKeys/%s", s.orgID, s.oldKeyID), func(w http.ResponseWriter, r *http.Request) {
+		if r.Method == http.MethodDelete {
+			record("delete old")
+			return
+		}
+		testMethod(t, r, http.MethodGet)
+		orgRole := fmt.Sprintf(`{"orgId": %q, "roleName": "ORG_MEMBER"},`, s.orgID)
+		if s.noOrgRoles {
+			orgRole = ""
+		}
+		_, _ = fmt.Fprintf(w, `{"id": %q, "desc": "automation", "roles": [
+			%s
+			{"groupId": %q, "roleName": "GROUP_READ_ONLY"},
+			{"groupId": %q, "roleName": "GROUP_AUTOMATION_ADMIN"}
+		]}`, s.oldKeyID, orgRole, s.projectID, s.projectID)
+	})
+	mux.HandleFunc(fmt.Sprintf("/orgs/%s/apiKeys/%s/accessList", s.orgID, s.oldKeyID), func(w http.ResponseWriter, r *http.Request) {
+		testMethod(t, r, http.MethodGet)
+		_, _ = fmt.Fprint(w, `{"results": [
+			{"ipAddress": "192.0.2.1", "cidrBlock": "192.0.2.1/32", "comment": "ci", "count": 3},
+			{"cidrBlock": "198.51.100.0/24", "comment": "office", "count": 0}
+		], "totalCount": 2}`)
+	})
+	mux.HandleFunc(fmt.Sprintf("/orgs/%s/apiKeys", s.orgID), func(w http.ResponseWriter, r *http.Request) {
+		testMethod(t, r, http.MethodPost)
+		record("create")
+		s.createBody = decodeBody(t, r)
+		_, _ = fmt.Fprintf(w, `{"id": %q, "desc": "automation", "privateKey": "secret"}`, s.newKeyID)
+	})
+	mux.HandleFunc(fmt.Sprintf("/groups/%s/apiKeys", s.projectID), func(w http.ResponseWriter, r *http.Request) {
+		testMethod(t, r, http.MethodPost)
+		record("create in project")
+		s.createBody = decodeBody(t, r)
+		_, _ = fmt.Fprintf(w, `{"id": %q, "desc": "automation", "privateKey": "secret"}`, s.newKeyID)
+	})
+	mux.HandleFunc(fmt.Sprintf("/groups/%s/apiKeys/%s", s.projectID, s.newKeyID), func(w http.ResponseWriter, r *http.Request) {
+		testMethod(t, r, http.MethodPatch)
+		record("assign")
+		s.projectBody = decodeBody(t, r)
+		if s.failAssign {
+			w.WriteHeader(http.StatusForbidden)
+			_, _ = fmt.Fprint(w, `{"error": 403, "reason": "Forbidden"}`)
+			return
+		}
+		_, _ = fmt.Fprintf(w, `{"id": %q}`, s.newKeyID)
+	})
+	mux.HandleFunc(fmt.Sprintf("/orgs/%s/apiKeys/%s/accessList", s.orgID, s.newKeyID), func(w http.ResponseWriter, r *http.Request) {
+		testMethod(t, r, http.MethodPost)
+		record("access list")
+		s.accessBody = decodeBody(t, r)
+		_, _ = fmt.Fprint(w, `{"results": [{"ipAddress": "192.0.2.1"}], "totalCount": 1}`)
+	})
+	mux.HandleFunc(fmt.Sprintf("/orgs/%s/apiKeys/%s", s.orgID, s.newKeyID), func(w http.ResponseWriter, r *http.Request) {
+		testMethod(t, r, http.MethodDelete)
+		record("delete new")
+	})
+}
+
+func newFakeAPIKeysServer() *fakeAPIKeysServer {
+	return &fakeAPIKeysServer{
+		orgID:     "5a0a1e7e0f2912c554080adb",
+		projectID: "5a0a1e7e0f2912c554080adc",
+		oldKeyID:  "old",
+		newKeyID:  "new",
+	}
+}
+
+func TestAPIKeys_Rotate(t *testing.T) {
+	setup()
+	defer teardown()
+
+	fake := newFakeAPIKeysServer()
+	fake.register(t)
+
+	key, err := client.APIKeys.Rotate(ctx, fake.orgID, fake.oldKeyID, func(_ context.Context, key *APIKey) error {
+		if key.PrivateKey != "secret" {
+			t.Errorf("expected the private key of the new key, got %q", key.PrivateKey)
+		}
+		fake.calls = append(fake.calls, "swap")
+		return nil
+	})
+	if err != nil {
+		t.Fatalf("APIKeys.Rotate returned error: %v", err)
+	}
+	if key.ID != fake.newKeyID {
+		t.Errorf("expected the new key, got %+v", key)
+	}
+
+	if diff := deep.Equal(fake.calls, []string{"create", "assign", "access list", "swap", "delete old"}); diff != nil {
+		t.Error(diff)
+	}
+	expectedProject := map[string]interface{}{"roles": []interface{}{"GROUP_READ_ONLY", "GROUP_AUTOMATION_ADMIN"}}
+	if diff := deep.Equal(fake.projectBody, expectedProject); diff != nil {
+		t.Error(diff)
+	}
+	expectedAccessList := []interface{}{
+		map[string]interface{}{"ipAddress": "192.0.2.1", "comment": "ci"},
+		map[string]interface{}{"cidrBlock": "198.51.100.0/24", "comment": "office"},
+	}
+	if diff := deep.Equal(fake.accessBody, expectedAccessList); diff != nil {
+		t.Error(diff)
+	}
+}
+
+func TestAPIKeys_Rotate_noOrgRoles(t *testing.T) {
+	setup()
+	defer teardown()
+
+	fake := newFakeAPIKeysServer()
+	fake.noOrgRoles = true
+	fake.register(t)
+
+	_, err := client.APIKeys.Rotate(ctx, fake.orgID, fake.oldKeyID, func(context.Context, *APIKey) error {
+		fake.calls = append(fake.calls, "swap")
+		return nil
+	})
+	if err != nil {
+		t.Fatalf("APIKeys.Rotate returned error: %v", err)
+	}
+
+	if diff := deep.Equal(fake.calls, []string{"create in project", "access list", "swap", "delete old"}); diff != nil {
+		t.Error(diff)
+	}
+	expectedCreate := map[string]interface{}{
+		"desc":  "automation",
+		"roles": []interface{}{"GROUP_READ_ONLY", "GROUP_AUTOMATION_ADMIN"},
+	}
+	if diff := deep.Equal(fake.createBody, expectedCreate); diff != nil {
+		t.Error(diff)
+	}
+}
+
+func TestAPIKeys_Rotate_swapFailure(t *testing.T) {
+	setup()
+	defer teardown()
+
+	fake := newFakeAPIKeysServer()
+	fake.register(t)
+
+	errSwap := errors.New("swap failed")
+	_, err := client.APIKeys.Rotate(ctx, fake.orgID, fake.oldKeyID, func(context.Context, *APIKey) error {
+		return errSwap
+	})
+	if err != errSwap {
+		t.Fatalf("expected the error of the swap, got %v", err)
+	}
+
+	if diff := deep.Equal(fake.calls, []string{"create", "assign", "access list", "delete new"}); diff != nil {
+		t.Error(diff)
+	}
+}
+
+func TestAPIKeys_Rotate_assignFailure(t *testing.T) {
+	setup()
+	defer teardown()
+
+	fake := newFakeAPIKeysServer()
+	fake.failAssign = true
+	fake.register(t)
+
+	_, err := client.APIKeys.Rotate(ctx, fake.orgID, fake.oldKeyID, func(context.Context, *APIKey) error {
+		t.Error("expected swap not to be called")
+		return nil
+	})
+	if err == nil {
+		t.Fatal("expected an error")
+	}
+
+	if diff := deep.Equal(fake.calls, []string{"create", "assign", "delete new"}); diff != nil {
+		t.Error(diff)
+	}
+}
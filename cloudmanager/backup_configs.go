@@ -0,0 +1,237 @@
+// Copyright 2020 MongoDB Inc
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package cloudmanager
+
+import (
+	"context"
+	"fmt"
+	"net/http"
+
+	atlas "github.com/mongodb/go-client-mongodb-atlas/mongodbatlas"
+)
+
+const (
+	backupConfigsBasePath = "groups/%s/backupConfigs"
+)
+
+// BackupStatus the state of the backup of a cluster
+type BackupStatus string
+
+// Backup statuses
+const (
+	BackupStatusInactive     BackupStatus = "INACTIVE"
+	BackupStatusProvisioning BackupStatus = "PROVISIONING"
+	BackupStatusStarted      BackupStatus = "STARTED"
+	BackupStatusStopped      BackupStatus = "STOPPED"
+	BackupStatusTerminating  BackupStatus = "TERMINATING"
+)
+
+// backupTransitions the statuses a user can request, by current status
+var backupTransitions = map[BackupStatus][]BackupStatus{
+	BackupStatusInactive: {BackupStatusStarted},
+	BackupStatusStarted:  {BackupStatusStopped},
+	BackupStatusStopped:  {BackupStatusStarted, BackupStatusTerminating},
+}
+
+// InvalidBackupTransitionError is returned when the requested backup status cannot be reached from the current one
+type InvalidBackupTransitionError struct {
+	From    BackupStatus
+	To      BackupStatus
+	Allowed []BackupStatus
+}
+
+func (e *InvalidBackupTransitionError) Error() string {
+	if len(e.Allowed) == 0 {
+		return fmt.Sprintf("cannot change the backup status from %s, wait until the operation in progress completes", e.From)
+	}
+	return fmt.Sprintf("cannot change the backup status from %s to %s, allowed: %v", e.From, e.To, e.Allowed)
+}
+
+// ValidateBackupTransition returns an *InvalidBackupTransitionError if the backup status cannot be changed
+// from one status to the other, e.g. backup must be stopped before it can be terminated
+func ValidateBackupTransition(from, to BackupStatus) error {
+	if from == to {
+		return nil
+	}
+
+	allowed := backupTransitions[from]
+	for _, status := range allowed {
+		if status == to {
+			return nil
+		}
+	}
+
+	return &InvalidBackupTransitionError{From: from, To: to, Allowed: allowed}
+}
+
+// BackupConfigsService is an interface for interfacing with the Backup Configurations
+// endpoints of the MongoDB Cloud Manager API.
+// See more: https://docs.cloudmanager.mongodb.com/reference/api/backup/backup-configurations/
+type BackupConfigsService interface {
+	List(context.Context, string, *atlas.ListOptions) (*BackupConfigs, *atlas.Response, error)
+	Get(context.Context, string, string) (*BackupConfig, *atlas.Response, error)
+	Update(context.Context, string, string, *BackupConfig) (*BackupConfig, *atlas.Response, error)
+	Start(context.Context, string, string) (*BackupConfig, *atlas.Response, error)
+	Stop(context.Context, string, string) (*BackupConfig, *atlas.Response, error)
+	Terminate(context.Context, string, string) (*BackupConfig, *atlas.Response, error)
+}
+
+// BackupConfigsServiceOp handles communication with the Backup Configurations related methods of the
+// MongoDB Cloud Manager API
+type BackupConfigsServiceOp struct {
+	client *Client
+}
+
+var _ BackupConfigsService = &BackupConfigsServiceOp{}
+
+// BackupConfig represents the backup configuration of a cluster.
+// Password is only used to update the credentials, and never returned.
+// The namespace filters are only updated when set, so a pointer to an empty slice clears a filter.
+type BackupConfig struct {
+	AuthMechanismName  string        `json:"authMechanismName,omitempty"`
+	ClusterID          string        `json:"clusterId,omitempty"`
+	EncryptionEnabled  *bool         `json:"encryptionEnabled,omitempty"`
+	ExcludedNamespaces *[]string     `json:"excludedNamespaces,omitempty"`
+	GroupID            string        `json:"groupId,omitempty"`
+	IncludedNamespaces *[]string     `json:"includedNamespaces,omitempty"`
+	Links              []*atlas.Link `json:"links,omitempty"`
+	Password           string        `json:"password,omitempty"`
+	SSLEnabled         *bool         `json:"sslEnabled,omitempty"`
+	StatusName         BackupStatus  `json:"statusName,omitempty"`
+	StorageEngineName  string        `json:"storageEngineName,omitempty"`
+	SyncSource         string        `json:"syncSource,omitempty"`
+	Username           string        `json:"username,omitempty"`
+}
+
+// BackupConfigs represents a array of backup configurations
+type BackupConfigs struct {
+	Links      []*atlas.Link   `json:"links"`
+	Results    []*BackupConfig `json:"results"`
+	TotalCount int             `json:"totalCount"`
+}
+
+// List gets the backup configurations of all clusters in a project.
+// See more: https://docs.cloudmanager.mongodb.com/reference/api/backup/get-all-backup-configs-for-group/
+func (s *BackupConfigsServiceOp) List(ctx context.Context, projectID string, opts *atlas.ListOptions) (*BackupConfigs, *atlas.Response, error) {
+	if projectID == "" {
+		return nil, nil, atlas.NewArgError("projectID", "must be set")
+	}
+
+	basePath := fmt.Sprintf(backupConfigsBasePath, projectID)
+	path, err := setListOptions(basePath, opts)
+	if err != nil {
+		return nil, nil, err
+	}
+
+	req, err := s.client.NewRequest(ctx, http.MethodGet, path, nil)
+	if err != nil {
+		return nil, nil, err
+	}
+
+	root := new(BackupConfigs)
+	resp, err := s.client.Do(ctx, req, root)
+	if err != nil {
+		return nil, resp, err
+	}
+
+	if l := root.Links; l != nil {
+		resp.Links = l
+	}
+
+	return root, resp, nil
+}
+
+// Get gets the backup configuration of a cluster.
+// See more: https://docs.cloudmanager.mongodb.com/reference/api/backup/get-one-backup-config-by-cluster-id/
+func (s *BackupConfigsServiceOp) Get(ctx context.Context, projectID, clusterID string) (*BackupConfig, *atlas.Response, error) {
+	if projectID == "" {
+		return nil, nil, atlas.NewArgError("projectID", "must be set")
+	}
+	if clusterID == "" {
+		return nil, nil, atlas.NewArgError("clusterID", "must be set")
+	}
+
+	basePath := fmt.Sprintf(backupConfigsBasePath, projectID)
+	path := fmt.Sprintf("%s/%s", basePath, clusterID)
+
+	req, err := s.client.NewRequest(ctx, http.MethodGet, path, nil)
+	if err != nil {
+		return nil, nil, err
+	}
+
+	root := new(BackupConfig)
+	resp, err := s.client.Do(ctx, req, root)
+	if err != nil {
+		return nil, resp, err
+	}
+
+	return root, resp, err
+}
+
+// Update updates the backup configuration of a cluster; only the specified fields are modified.
+// When the status is changed, the transition is validated against the current status first.
+// See more: https://docs.cloudmanager.mongodb.com/reference/api/backup/update-backup-config/
+func (s *BackupConfigsServiceOp) Update(ctx context.Context, projectID, clusterID string, updateRequest *BackupConfig) (*BackupConfig, *atlas.Response, error) {
+	if projectID == "" {
+		return nil, nil, atlas.NewArgError("projectID", "must be set")
+	}
+	if clusterID == "" {
+		return nil, nil, atlas.NewArgError("clusterID", "must be set")
+	}
+	if updateRequest == nil {
+		return nil, nil, atlas.NewArgError("updateRequest", "cannot be nil")
+	}
+
+	if updateRequest.StatusName != "" {
+		current, resp, err := s.Get(ctx, projectID, clusterID)
+		if err != nil {
+			return nil, resp, err
+		}
+		if err := ValidateBackupTransition(current.StatusName, updateRequest.StatusName); err != nil {
+			return nil, resp, err
+		}
+	}
+
+	basePath := fmt.Sprintf(backupConfigsBasePath, projectID)
+	path := fmt.Sprintf("%s/%s", basePath, clusterID)
+
+	req, err := s.client.NewRequest(ctx, http.MethodPatch, path, updateRequest)
+	if err != nil {
+		return nil, nil, err
+	}
+
+	root := new(BackupConfig)
+	resp, err := s.client.Do(ctx, req, root)
+	if err != nil {
+		return nil, resp, err
+	}
+
+	return root, resp, err
+}
+
+// Start starts, or restarts, the backup of a cluster
+func (s *BackupConfigsServiceOp) Start(ctx context.Context, projectID, clusterID string) (*BackupConfig, *atlas.Response, error) {
+	return s.Update(ctx, projectID, clusterID, &BackupConfig{StatusName: BackupStatusStarted})
+}
+
+// Stop stops the backup of a cluster; the snapshots are kept
+func (s *BackupConfigsServiceOp) Stop(ctx context.Context, projectID, clusterID string) (*BackupConfig, *atlas.Response, error) {
+	return s.Update(ctx, projectID, clusterID, &BackupConfig{StatusName: BackupStatusStopped})
+}
+
+// Terminate terminates the stopped backup of a cluster, and deletes its snapshots
+func (s *BackupConfigsServiceOp) Terminate(ctx context.Context, projectID, clusterID string) (*BackupConfig, *atlas.Response, error) {
+	return s.Update(ctx, projectID, clusterID, &BackupConfig{StatusName: BackupStatusTerminating})
+}
@@ -0,0 +1,326 @@
+// Copyright 2020 MongoDB Inc
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package cloudmanager
+
+import (
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"testing"
+
+	"github.com/go-test/deep"
+	"github.com/mongodb/go-client-mongodb-atlas/mongodbatlas"
+)
+
+func TestBackupConfigs_List(t *testing.T) {
+	setup()
+	defer teardown()
+
+	projectID := "5a0a1e7e0f2912c554080adc"
+
+	mux.HandleFunc(fmt.Sprintf("/groups/%s/backupConfigs", projectID), func(w http.ResponseWriter, r *http.Request) {
+		testMethod(t, r, http.MethodGet)
+		_, _ = fmt.Fprint(w, `{
+			"links": [],
+			"results": [{
+				"authMechanismName": "SCRAM-SHA-1",
+				"clusterId": "5a0a1e7e0f2912c554080ae1",
+				"encryptionEnabled": false,
+				"excludedNamespaces": ["test.logs"],
+				"groupId": "5a0a1e7e0f2912c554080adc",
+				"links": [],
+				"sslEnabled": true,
+				"statusName": "STARTED",
+				"storageEngineName": "WIRED_TIGER",
+				"username": "backup"
+			}],
+			"totalCount": 1
+		}`)
+	})
+
+	configs, _, err := client.BackupConfigs.List(ctx, projectID, nil)
+	if err != nil {
+		t.Fatalf("BackupConfigs.List returned error: %v", err)
+	}
+
+	expected := &BackupConfigs{
+		Links: []*mongodbatlas.Link{},
+		Results: []*BackupConfig{
+			{
+				AuthMechanismName:  "SCRAM-SHA-1",
+				ClusterID:          "5a0a1e7e0f2912c554080ae1",
+				EncryptionEnabled:  boolPtr(false),
+				ExcludedNamespaces: &[]string{"test.logs"},
+				GroupID:            "5a0a1e7e0f2912c554080adc",
+				Links:              []*mongodbatlas.Link{},
+				SSLEnabled:         boolPtr(true),
+				StatusName:         BackupStatusStarted,
+				StorageEngineName:  "WIRED_TIGER",
+				Username:           "backup",
+			},
+		},
+		TotalCount: 1,
+	}
+
+	if diff := deep.Equal(configs, expected); diff != nil {
+		t.Error(diff)
+	}
+}
+
+func TestBackupConfigs_Get(t *testing.T) {
+	setup()
+	defer teardown()
+
+	projectID := "5a0a1e7e0f2912c554080adc"
+	clusterID := "5a0a1e7e0f2912c554080ae1"
+
+	mux.HandleFunc(fmt.Sprintf("/groups/%s/backupConfigs/%s", projectID, clusterID), func(w http.ResponseWriter, r *http.Request) {
+		testMethod(t, r, http.MethodGet)
+		_, _ = fmt.Fprint(w, `{
+			"authMechanismName": "SCRAM-SHA-1",
+			"clusterId": "5a0a1e7e0f2912c554080ae1",
+			"encryptionEnabled": false,
+			"excludedNamespaces": ["test.logs"],
+			"groupId": "5a0a1e7e0f2912c554080adc",
+			"links": [],
+			"sslEnabled": true,
+			"statusName": "STARTED",
+			"storageEngineName": "WIRED_TIGER",
+			"username": "backup"
+		}`)
+	})
+
+	config, _, err := client.BackupConfigs.Get(ctx, projectID, clusterID)
+	if err != nil {
+		t.Fatalf("BackupConfigs.Get returned error: %v", err)
+	}
+
+	expected := &BackupConfig{
+		AuthMechanismName:  "SCRAM-SHA-1",
+		ClusterID:          "5a0a1e7e0f2912c554080ae1",
+		EncryptionEnabled:  boolPtr(false),
+		ExcludedNamespaces: &[]string{"test.logs"},
+		GroupID:            "5a0a1e7e0f2912c554080adc",
+		Links:              []*mongodbatlas.Link{},
+		SSLEnabled:         boolPtr(true),
+		StatusName:         BackupStatusStarted,
+		StorageEngineName:  "WIRED_TIGER",
+		Username:           "backup",
+	}
+
+	if diff := deep.Equal(config, expected); diff != nil {
+		t.Error(diff)
+	}
+}
+
+func TestBackupConfigs_Update(t *testing.T) {
+	setup()
+	defer teardown()
+
+	projectID := "5a0a1e7e0f2912c554080adc"
+	clusterID := "5a0a1e7e0f2912c554080ae1"
+
+	mux.HandleFunc(fmt.Sprintf("/groups/%s/backupConfigs/%s", projectID, clusterID), func(w http.ResponseWriter, r *http.Request) {
+		// the status is not changed, so the current configuration is not needed
+		testMethod(t, r, http.MethodPatch)
+
+		expected := map[string]interface{}{
+			"authMechanismName":  "SCRAM-SHA-1",
+			"excludedNamespaces": []interface{}{"test.logs"},
+			"password":           "secret",
+			"sslEnabled":         true,
+			"username":           "backup",
+		}
+		var v map[string]interface{}
+		if err := json.NewDecoder(r.Body).Decode(&v); err != nil {
+			t.Fatalf("decode json: %v", err)
+		}
+		if diff := deep.Equal(v, expected); diff != nil {
+			t.Error(diff)
+		}
+
+		_, _ = fmt.Fprint(w, `{
+			"authMechanismName": "SCRAM-SHA-1",
+			"clusterId": "5a0a1e7e0f2912c554080ae1",
+			"encryptionEnabled": false,
+			"excludedNamespaces": ["test.logs"],
+			"groupId": "5a0a1e7e0f2912c554080adc",
+			"links": [],
+			"sslEnabled": true,
+			"statusName": "STARTED",
+			"storageEngineName": "WIRED_TIGER",
+			"username": "backup"
+		}`)
+	})
+
+	updateRequest := &BackupConfig{
+		AuthMechanismName:  "SCRAM-SHA-1",
+		ExcludedNamespaces: &[]string{"test.logs"},
+		Password:           "secret",
+		SSLEnabled:         boolPtr(true),
+		Username:           "backup",
+	}
+	config, _, err := client.BackupConfigs.Update(ctx, projectID, clusterID, updateRequest)
+	if err != nil {
+		t.Fatalf("BackupConfigs.Update returned error: %v", err)
+	}
+
+	expected := &BackupConfig{
+		AuthMechanismName:  "SCRAM-SHA-1",
+		ClusterID:          "5a0a1e7e0f2912c554080ae1",
+		EncryptionEnabled:  boolPtr(false),
+		ExcludedNamespaces: &[]string{"test.logs"},
+		GroupID:            "5a0a1e7e0f2912c554080adc",
+		Links:              []*mongodbatlas.Link{},
+		SSLEnabled:         boolPtr(true),
+		StatusName:         BackupStatusStarted,
+		StorageEngineName:  "WIRED_TIGER",
+		Username:           "backup",
+	}
+
+	if diff := deep.Equal(config, expected); diff != nil {
+		t.Error(diff)
+	}
+}
+
+func TestBackupConfigs_Update_clearNamespaces(t *testing.T) {
+	setup()
+	defer teardown()
+
+	projectID := "5a0a1e7e0f2912c554080adc"
+	clusterID := "5a0a1e7e0f2912c554080ae1"
+
+	mux.HandleFunc(fmt.Sprintf("/groups/%s/backupConfigs/%s", projectID, clusterID), func(w http.ResponseWriter, r *http.Request) {
+		testMethod(t, r, http.MethodPatch)
+
+		expected := map[string]interface{}{
+			"excludedNamespaces": []interface{}{},
+		}
+		var v map[string]interface{}
+		if err := json.NewDecoder(r.Body).Decode(&v); err != nil {
+			t.Fatalf("decode json: %v", err)
+		}
+		if diff := deep.Equal(v, expected); diff != nil {
+			t.Error(diff)
+		}
+
+		_, _ = fmt.Fprint(w, `{
+			"clusterId": "5a0a1e7e0f2912c554080ae1",
+			"excludedNamespaces": [],
+			"groupId": "5a0a1e7e0f2912c554080adc",
+			"statusName": "STARTED"
+		}`)
+	})
+
+	config, _, err := client.BackupConfigs.Update(ctx, projectID, clusterID, &BackupConfig{ExcludedNamespaces: &[]string{}})
+	if err != nil {
+		t.Fatalf("BackupConfigs.Update returned error: %v", err)
+	}
+
+	expected := &BackupConfig{
+		ClusterID:          "5a0a1e7e0f2912c554080ae1",
+		ExcludedNamespaces: &[]string{},
+		GroupID:            "5a0a1e7e0f2912c554080adc",
+		StatusName:         BackupStatusStarted,
+	}
+
+	if diff := deep.Equal(config, expected); diff != nil {
+		t.Error(diff)
+	}
+}
+
+func TestBackupConfigs_Start(t *testing.T) {
+	setup()
+	defer teardown()
+
+	projectID := "5a0a1e7e0f2912c554080adc"
+	clusterID := "5a0a1e7e0f2912c554080ae1"
+
+	mux.HandleFunc(fmt.Sprintf("/groups/%s/backupConfigs/%s", projectID, clusterID), func(w http.ResponseWriter, r *http.Request) {
+		if r.Method == http.MethodGet {
+			_, _ = fmt.Fprint(w, `{"clusterId": "5a0a1e7e0f2912c554080ae1", "groupId": "5a0a1e7e0f2912c554080adc", "statusName": "INACTIVE"}`)
+			return
+		}
+		testMethod(t, r, http.MethodPatch)
+
+		expected := map[string]interface{}{"statusName": "STARTED"}
+		var v map[string]interface{}
+		if err := json.NewDecoder(r.Body).Decode(&v); err != nil {
+			t.Fatalf("decode json: %v", err)
+		}
+		if diff := deep.Equal(v, expected); diff != nil {
+			t.Error(diff)
+		}
+
+		_, _ = fmt.Fprint(w, `{"clusterId": "5a0a1e7e0f2912c554080ae1", "groupId": "5a0a1e7e0f2912c554080adc", "statusName": "PROVISIONING"}`)
+	})
+
+	config, _, err := client.BackupConfigs.Start(ctx, projectID, clusterID)
+	if err != nil {
+		t.Fatalf("BackupConfigs.Start returned error: %v", err)
+	}
+
+	if config.StatusName != BackupStatusProvisioning {
+		t.Errorf("expected %s, got %s", BackupStatusProvisioning, config.StatusName)
+	}
+}
+
+func TestBackupConfigs_Terminate_invalidTransition(t *testing.T) {
+	setup()
+	defer teardown()
+
+	projectID := "5a0a1e7e0f2912c554080adc"
+	clusterID := "5a0a1e7e0f2912c554080ae1"
+
+	mux.HandleFunc(fmt.Sprintf("/groups/%s/backupConfigs/%s", projectID, clusterID), func(w http.ResponseWriter, r *http.Request) {
+		testMethod(t, r, http.MethodGet)
+		_, _ = fmt.Fprint(w, `{"clusterId": "5a0a1e7e0f2912c554080ae1", "groupId": "5a0a1e7e0f2912c554080adc", "statusName": "STARTED"}`)
+	})
+
+	_, _, err := client.BackupConfigs.Terminate(ctx, projectID, clusterID)
+
+	expected := &InvalidBackupTransitionError{
+		From:    BackupStatusStarted,
+		To:      BackupStatusTerminating,
+		Allowed: []BackupStatus{BackupStatusStopped},
+	}
+	if diff := deep.Equal(err, expected); diff != nil {
+		t.Error(diff)
+	}
+}
+
+func TestValidateBackupTransition(t *testing.T) {
+	tests := []struct {
+		from, to BackupStatus
+		valid    bool
+	}{
+		{BackupStatusInactive, BackupStatusStarted, true},
+		{BackupStatusInactive, BackupStatusStopped, false},
+		{BackupStatusStarted, BackupStatusStopped, true},
+		{BackupStatusStarted, BackupStatusTerminating, false},
+		{BackupStatusStarted, BackupStatusStarted, true},
+		{BackupStatusStopped, BackupStatusStarted, true},
+		{BackupStatusStopped, BackupStatusTerminating, true},
+		{BackupStatusProvisioning, BackupStatusStopped, false},
+		{BackupStatusTerminating, BackupStatusStarted, false},
+	}
+
+	for _, tt := range tests {
+		err := ValidateBackupTransition(tt.from, tt.to)
+		if (err == nil) != tt.valid {
+			t.Errorf("ValidateBackupTransition(%s, %s) = %v, expected valid: %v", tt.from, tt.to, err, tt.valid)
+		}
+	}
+}
@@ -18,12 +18,15 @@ import (
 	"bytes"
 	"context"
 	"encoding/json"
+	"errors"
 	"fmt"
 	"io"
 	"net/http"
 	"net/url"
+	"reflect"
 	"runtime"
 
+	"github.com/google/go-querystring/query"
 	atlas "github.com/mongodb/go-client-mongodb-atlas/mongodbatlas"
 )
 
@@ -42,10 +45,28 @@ type Client struct {
 	BaseURL   *url.URL
 	UserAgent string
 
-	Organizations    OrganizationsService
-	Projects         ProjectsService
-	AutomationConfig AutomationService
-	UnauthUsers      UnauthUsersService
+	Organizations          OrganizationsService
+	Projects               ProjectsService
+	AutomationConfig       AutomationService
+	UnauthUsers            UnauthUsersService
+	Hosts                  HostsService
+	Measurements           MeasurementsService
+	Alerts                 AlertsService
+	AlertConfigurations    AlertConfigurationsService
+	Events                 EventsService
+	Agents                 AgentsService
+	BackupConfigs          BackupConfigsService
+	Snapshots              SnapshotsService
+	RestoreJobs            RestoreJobsService
+	Clusters               ClustersService
+	MaintenanceWindows     MaintenanceWindowsService
+	Users                  UsersService
+	Teams                  TeamsService
+	APIKeys                APIKeysService
+	Invitations            InvitationsService
+	GlobalAPIKeys          GlobalAPIKeysService
+	GlobalAccessList       GlobalAccessListService
+	FeatureControlPolicies FeatureControlPoliciesService
 
 	onRequestCompleted RequestCompletionCallback
 }
@@ -71,6 +92,24 @@ func NewClient(httpClient *http.Client) *Client {
 	c.Projects = &ProjectsServiceOp{client: c}
 	c.AutomationConfig = &AutomationServiceOp{client: c}
 	c.UnauthUsers = &UnauthUsersServiceOp{client: c}
+	c.Hosts = &HostsServiceOp{client: c}
+	c.Measurements = &MeasurementsServiceOp{client: c}
+	c.Alerts = &AlertsServiceOp{client: c}
+	c.AlertConfigurations = &AlertConfigurationsServiceOp{client: c}
+	c.Events = &EventsServiceOp{client: c}
+	c.Agents = &AgentsServiceOp{client: c}
+	c.BackupConfigs = &BackupConfigsServiceOp{client: c}
+	c.Snapshots = &SnapshotsServiceOp{client: c}
+	c.RestoreJobs = &RestoreJobsServiceOp{client: c}
+	c.Clusters = &ClustersServiceOp{client: c}
+	c.MaintenanceWindows = &MaintenanceWindowsServiceOp{client: c}
+	c.Users = &UsersServiceOp{client: c}
+	c.Teams = &TeamsServiceOp{client: c}
+	c.APIKeys = &APIKeysServiceOp{client: c}
+	c.Invitations = &InvitationsServiceOp{client: c}
+	c.GlobalAPIKeys = &GlobalAPIKeysServiceOp{client: c}
+	c.GlobalAccessList = &GlobalAccessListServiceOp{client: c}
+	c.FeatureControlPolicies = &FeatureControlPoliciesServiceOp{client: c}
 
 	return c
 }
@@ -187,3 +226,61 @@ func newResponse(r *http.Response) *atlas.Response {
 
 	return &response
 }
+
+// errStopListing can be returned by the fetch function of listPages to stop before the last page, without an error
+var errStopListing = errors.New("stop listing")
+
+// listPages requests consecutive pages of a list, starting at opts.PageNum (or 1 if unset), until every item was
+// listed or a page is empty. fetch requests the page described by opts and returns the number of items it contained,
+// and the total number of items.
+func listPages(opts *atlas.ListOptions, fetch func() (count, total int, err error)) error {
+	if opts.PageNum == 0 {
+		opts.PageNum = 1
+	}
+
+	listed := 0
+	for {
+		count, total, err := fetch()
+		if err == errStopListing {
+			return nil
+		}
+		if err != nil {
+			return err
+		}
+
+		listed += count
+		if count == 0 || listed >= total {
+			return nil
+		}
+		opts.PageNum++
+	}
+}
+
+// setListOptions adds the parameters in opt as URL query parameters to s. opt must be a struct whose fields may
+// contain "url" tags.
+func setListOptions(s string, opt interface{}) (string, error) {
+	v := reflect.ValueOf(opt)
+
+	if v.Kind() == reflect.Ptr && v.IsNil() {
+		return s, nil
+	}
+
+	origURL, err := url.Parse(s)
+	if err != nil {
+		return s, err
+	}
+
+	origValues := origURL.Query()
+
+	newValues, err := query.Values(opt)
+	if err != nil {
+		return s, err
+	}
+
+	for k, v := range newValues {
+		origValues[k] = v
+	}
+
+	origURL.RawQuery = origValues.Encode()
+	return origURL.String(), nil
+}
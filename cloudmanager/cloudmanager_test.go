@@ -16,6 +16,7 @@ package cloudmanager
 
 import (
 	"context"
+	"encoding/json"
 	"fmt"
 	"io/ioutil"
 	"net/http"
@@ -56,6 +57,30 @@ func testMethod(t *testing.T, r *http.Request, expected string) {
 	}
 }
 
+func testQuery(t *testing.T, r *http.Request, expected url.Values) {
+	if got := r.URL.Query(); !reflect.DeepEqual(got, expected) {
+		t.Errorf("Request query = %v, expected %v", got, expected)
+	}
+}
+
+// decodeBody decodes the JSON body of a request, to compare it with the expected body
+func decodeBody(t *testing.T, r *http.Request) interface{} {
+	t.Helper()
+	var v interface{}
+	if err := json.NewDecoder(r.Body).Decode(&v); err != nil {
+		t.Fatalf("decode json: %v", err)
+	}
+	return v
+}
+
+func boolPtr(b bool) *bool {
+	return &b
+}
+
+func intPtr(i int) *int {
+	return &i
+}
+
 func testURLParseError(t *testing.T, err error) {
 	if err == nil {
 		t.Errorf("Expected error to be returned")
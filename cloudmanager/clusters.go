@@ -0,0 +1,267 @@
+// Copyright 2020 MongoDB Inc
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package cloudmanager
+
+import (
+	"context"
+	"fmt"
+	"net/http"
+
+	atlas "github.com/mongodb/go-client-mongodb-atlas/mongodbatlas"
+)
+
+const (
+	clustersBasePath    = "groups/%s/clusters"
+	allClustersBasePath = "clusters"
+)
+
+// ClustersService is an interface for interfacing with the Clusters
+// endpoints of the MongoDB Cloud Manager API.
+// See more: https://docs.cloudmanager.mongodb.com/reference/api/clusters/
+type ClustersService interface {
+	List(context.Context, string, *atlas.ListOptions) (*Clusters, *atlas.Response, error)
+	ListAll(context.Context) (*AllClusters, *atlas.Response, error)
+	Get(context.Context, string, string) (*Cluster, *atlas.Response, error)
+	Rename(context.Context, string, string, string) (*Cluster, *atlas.Response, error)
+	ListWithHosts(context.Context, string) ([]*ClusterWithHosts, *atlas.Response, error)
+}
+
+// ClustersServiceOp handles communication with the Clusters related methods of the
+// MongoDB Cloud Manager API
+type ClustersServiceOp struct {
+	client *Client
+}
+
+var _ ClustersService = &ClustersServiceOp{}
+
+// Cluster represents a replica set or sharded cluster discovered by monitoring
+type Cluster struct {
+	ID             string        `json:"id,omitempty"`
+	ClusterName    string        `json:"clusterName,omitempty"`
+	GroupID        string        `json:"groupId,omitempty"`
+	LastHeartbeat  string        `json:"lastHeartbeat,omitempty"`
+	Links          []*atlas.Link `json:"links,omitempty"`
+	ReplicaSetName string        `json:"replicaSetName,omitempty"`
+	ShardName      string        `json:"shardName,omitempty"`
+	TypeName       string        `json:"typeName,omitempty"`
+}
+
+// Clusters represents a array of clusters
+type Clusters struct {
+	Links      []*atlas.Link `json:"links"`
+	Results    []*Cluster    `json:"results"`
+	TotalCount int           `json:"totalCount"`
+}
+
+// ClusterSummary the state of a cluster, as listed across all projects
+type ClusterSummary struct {
+	AlertCount    int      `json:"alertCount"`
+	AuthEnabled   bool     `json:"authEnabled"`
+	Availability  string   `json:"availability"`
+	BackupEnabled bool     `json:"backupEnabled"`
+	ClusterID     string   `json:"clusterId"`
+	DataSizeBytes float64  `json:"dataSizeBytes"`
+	Name          string   `json:"name"`
+	NodeCount     int      `json:"nodeCount"`
+	SSLEnabled    bool     `json:"sslEnabled"`
+	Type          string   `json:"type"`
+	Versions      []string `json:"versions"`
+}
+
+// ProjectClusters the clusters of a project, as listed across all projects
+type ProjectClusters struct {
+	Clusters  []*ClusterSummary `json:"clusters"`
+	GroupID   string            `json:"groupId"`
+	GroupName string            `json:"groupName"`
+	OrgID     string            `json:"orgId"`
+	OrgName   string            `json:"orgName"`
+	PlanType  string            `json:"planType,omitempty"`
+	Tags      []string          `json:"tags,omitempty"`
+}
+
+// AllClusters represents the clusters of every project the user can access
+type AllClusters struct {
+	Links      []*atlas.Link      `json:"links"`
+	Results    []*ProjectClusters `json:"results"`
+	TotalCount int                `json:"totalCount"`
+}
+
+// ClusterWithHosts a cluster and the hosts which belong to it
+type ClusterWithHosts struct {
+	*Cluster
+	Hosts []*Host `json:"hosts"`
+}
+
+// List gets the clusters of a project.
+// See more: https://docs.cloudmanager.mongodb.com/reference/api/clusters/clusters-get-all/
+func (s *ClustersServiceOp) List(ctx context.Context, projectID string, opts *atlas.ListOptions) (*Clusters, *atlas.Response, error) {
+	if projectID == "" {
+		return nil, nil, atlas.NewArgError("projectID", "must be set")
+	}
+
+	basePath := fmt.Sprintf(clustersBasePath, projectID)
+	path, err := setListOptions(basePath, opts)
+	if err != nil {
+		return nil, nil, err
+	}
+
+	req, err := s.client.NewRequest(ctx, http.MethodGet, path, nil)
+	if err != nil {
+		return nil, nil, err
+	}
+
+	root := new(Clusters)
+	resp, err := s.client.Do(ctx, req, root)
+	if err != nil {
+		return nil, resp, err
+	}
+
+	if l := root.Links; l != nil {
+		resp.Links = l
+	}
+
+	return root, resp, nil
+}
+
+// ListAll gets the clusters of every project the user can access, grouped by project.
+// See more: https://docs.cloudmanager.mongodb.com/reference/api/clusters/clusters-get-all-clusters/
+func (s *ClustersServiceOp) ListAll(ctx context.Context) (*AllClusters, *atlas.Response, error) {
+	req, err := s.client.NewRequest(ctx, http.MethodGet, allClustersBasePath, nil)
+	if err != nil {
+		return nil, nil, err
+	}
+
+	root := new(AllClusters)
+	resp, err := s.client.Do(ctx, req, root)
+	if err != nil {
+		return nil, resp, err
+	}
+
+	if l := root.Links; l != nil {
+		resp.Links = l
+	}
+
+	return root, resp, nil
+}
+
+// Get gets a single cluster of a project.
+// See more: https://docs.cloudmanager.mongodb.com/reference/api/clusters/clusters-get-one/
+func (s *ClustersServiceOp) Get(ctx context.Context, projectID, clusterID string) (*Cluster, *atlas.Response, error) {
+	if projectID == "" {
+		return nil, nil, atlas.NewArgError("projectID", "must be set")
+	}
+	if clusterID == "" {
+		return nil, nil, atlas.NewArgError("clusterID", "must be set")
+	}
+
+	basePath := fmt.Sprintf(clustersBasePath, projectID)
+	path := fmt.Sprintf("%s/%s", basePath, clusterID)
+
+	req, err := s.client.NewRequest(ctx, http.MethodGet, path, nil)
+	if err != nil {
+		return nil, nil, err
+	}
+
+	root := new(Cluster)
+	resp, err := s.client.Do(ctx, req, root)
+	if err != nil {
+		return nil, resp, err
+	}
+
+	return root, resp, err
+}
+
+// Rename changes the name of a cluster; only the name can be updated.
+// See more: https://docs.cloudmanager.mongodb.com/reference/api/clusters/clusters-update-name/
+func (s *ClustersServiceOp) Rename(ctx context.Context, projectID, clusterID, clusterName string) (*Cluster, *atlas.Response, error) {
+	if projectID == "" {
+		return nil, nil, atlas.NewArgError("projectID", "must be set")
+	}
+	if clusterID == "" {
+		return nil, nil, atlas.NewArgError("clusterID", "must be set")
+	}
+	if clusterName == "" {
+		return nil, nil, atlas.NewArgError("clusterName", "must be set")
+	}
+
+	basePath := fmt.Sprintf(clustersBasePath, projectID)
+	path := fmt.Sprintf("%s/%s", basePath, clusterID)
+
+	req, err := s.client.NewRequest(ctx, http.MethodPatch, path, &Cluster{ClusterName: clusterName})
+	if err != nil {
+		return nil, nil, err
+	}
+
+	root := new(Cluster)
+	resp, err := s.client.Do(ctx, req, root)
+	if err != nil {
+		return nil, resp, err
+	}
+
+	return root, resp, err
+}
+
+// ListWithHosts gets every cluster of a project, with the hosts which report it as their cluster.
+// The shards of a sharded cluster are clusters of their own, so the sharded cluster only lists its mongos hosts.
+func (s *ClustersServiceOp) ListWithHosts(ctx context.Context, projectID string) ([]*ClusterWithHosts, *atlas.Response, error) {
+	if projectID == "" {
+		return nil, nil, atlas.NewArgError("projectID", "must be set")
+	}
+
+	var (
+		clusters []*Cluster
+		resp     *atlas.Response
+	)
+	opts := &atlas.ListOptions{}
+	err := listPages(opts, func() (int, int, error) {
+		page, r, err := s.List(ctx, projectID, opts)
+		resp = r
+		if err != nil {
+			return 0, 0, err
+		}
+		clusters = append(clusters, page.Results...)
+		return len(page.Results), page.TotalCount, nil
+	})
+	if err != nil {
+		return nil, resp, err
+	}
+
+	var hosts []*Host
+	hostOpts := &HostListOptions{}
+	err = listPages(&hostOpts.ListOptions, func() (int, int, error) {
+		page, r, err := s.client.Hosts.List(ctx, projectID, hostOpts)
+		resp = r
+		if err != nil {
+			return 0, 0, err
+		}
+		hosts = append(hosts, page.Results...)
+		return len(page.Results), page.TotalCount, nil
+	})
+	if err != nil {
+		return nil, resp, err
+	}
+
+	hostsByCluster := make(map[string][]*Host)
+	for _, host := range hosts {
+		hostsByCluster[host.ClusterID] = append(hostsByCluster[host.ClusterID], host)
+	}
+
+	result := make([]*ClusterWithHosts, len(clusters))
+	for i, cluster := range clusters {
+		result[i] = &ClusterWithHosts{Cluster: cluster, Hosts: hostsByCluster[cluster.ID]}
+	}
+
+	return result, resp, nil
+}
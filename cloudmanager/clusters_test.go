@@ -0,0 +1,273 @@
+// Copyright 2020 MongoDB Inc
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package cloudmanager
+
+import (
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"testing"
+
+	"github.com/go-test/deep"
+	"github.com/mongodb/go-client-mongodb-atlas/mongodbatlas"
+)
+
+func TestClusters_List(t *testing.T) {
+	setup()
+	defer teardown()
+
+	projectID := "5a0a1e7e0f2912c554080adc"
+
+	mux.HandleFunc(fmt.Sprintf("/groups/%s/clusters", projectID), func(w http.ResponseWriter, r *http.Request) {
+		testMethod(t, r, http.MethodGet)
+		_, _ = fmt.Fprint(w, `{
+			"links": [],
+			"results": [{
+				"clusterName": "Cluster0",
+				"groupId": "5a0a1e7e0f2912c554080adc",
+				"id": "5a0a1e7e0f2912c554080ae1",
+				"lastHeartbeat": "2017-11-13T22:04:17Z",
+				"links": [],
+				"replicaSetName": "rs0",
+				"typeName": "REPLICA_SET"
+			}],
+			"totalCount": 1
+		}`)
+	})
+
+	clusters, _, err := client.Clusters.List(ctx, projectID, nil)
+	if err != nil {
+		t.Fatalf("Clusters.List returned error: %v", err)
+	}
+
+	expected := &Clusters{
+		Links: []*mongodbatlas.Link{},
+		Results: []*Cluster{
+			{
+				ClusterName:    "Cluster0",
+				GroupID:        "5a0a1e7e0f2912c554080adc",
+				ID:             "5a0a1e7e0f2912c554080ae1",
+				LastHeartbeat:  "2017-11-13T22:04:17Z",
+				Links:          []*mongodbatlas.Link{},
+				ReplicaSetName: "rs0",
+				TypeName:       "REPLICA_SET",
+			},
+		},
+		TotalCount: 1,
+	}
+
+	if diff := deep.Equal(clusters, expected); diff != nil {
+		t.Error(diff)
+	}
+}
+
+func TestClusters_ListAll(t *testing.T) {
+	setup()
+	defer teardown()
+
+	mux.HandleFunc("/clusters", func(w http.ResponseWriter, r *http.Request) {
+		testMethod(t, r, http.MethodGet)
+		_, _ = fmt.Fprint(w, `{
+			"links": [],
+			"results": [{
+				"clusters": [{
+					"alertCount": 0,
+					"authEnabled": true,
+					"availability": "available",
+					"backupEnabled": false,
+					"clusterId": "5a0a1e7e0f2912c554080ae1",
+					"dataSizeBytes": 1024,
+					"name": "Cluster0",
+					"nodeCount": 3,
+					"sslEnabled": true,
+					"type": "replica set",
+					"versions": ["4.2.2"]
+				}],
+				"groupId": "5a0a1e7e0f2912c554080adc",
+				"groupName": "Project0",
+				"orgId": "5a0a1e7e0f2912c554080adb",
+				"orgName": "Org0",
+				"tags": ["prod"]
+			}],
+			"totalCount": 1
+		}`)
+	})
+
+	clusters, _, err := client.Clusters.ListAll(ctx)
+	if err != nil {
+		t.Fatalf("Clusters.ListAll returned error: %v", err)
+	}
+
+	expected := &AllClusters{
+		Links: []*mongodbatlas.Link{},
+		Results: []*ProjectClusters{
+			{
+				Clusters: []*ClusterSummary{
+					{
+						AuthEnabled:   true,
+						Availability:  "available",
+						ClusterID:     "5a0a1e7e0f2912c554080ae1",
+						DataSizeBytes: 1024,
+						Name:          "Cluster0",
+						NodeCount:     3,
+						SSLEnabled:    true,
+						Type:          "replica set",
+						Versions:      []string{"4.2.2"},
+					},
+				},
+				GroupID:   "5a0a1e7e0f2912c554080adc",
+				GroupName: "Project0",
+				OrgID:     "5a0a1e7e0f2912c554080adb",
+				OrgName:   "Org0",
+				Tags:      []string{"prod"},
+			},
+		},
+		TotalCount: 1,
+	}
+
+	if diff := deep.Equal(clusters, expected); diff != nil {
+		t.Error(diff)
+	}
+}
+
+func TestClusters_Get(t *testing.T) {
+	setup()
+	defer teardown()
+
+	projectID := "5a0a1e7e0f2912c554080adc"
+	clusterID := "5a0a1e7e0f2912c554080ae1"
+
+	mux.HandleFunc(fmt.Sprintf("/groups/%s/clusters/%s", projectID, clusterID), func(w http.ResponseWriter, r *http.Request) {
+		testMethod(t, r, http.MethodGet)
+		_, _ = fmt.Fprint(w, `{
+			"clusterName": "Cluster0",
+			"groupId": "5a0a1e7e0f2912c554080adc",
+			"id": "5a0a1e7e0f2912c554080ae1",
+			"lastHeartbeat": "2017-11-13T22:04:17Z",
+			"links": [],
+			"replicaSetName": "rs0",
+			"typeName": "REPLICA_SET"
+		}`)
+	})
+
+	cluster, _, err := client.Clusters.Get(ctx, projectID, clusterID)
+	if err != nil {
+		t.Fatalf("Clusters.Get returned error: %v", err)
+	}
+
+	expected := &Cluster{
+		ClusterName:    "Cluster0",
+		GroupID:        "5a0a1e7e0f2912c554080adc",
+		ID:             "5a0a1e7e0f2912c554080ae1",
+		LastHeartbeat:  "2017-11-13T22:04:17Z",
+		Links:          []*mongodbatlas.Link{},
+		ReplicaSetName: "rs0",
+		TypeName:       "REPLICA_SET",
+	}
+
+	if diff := deep.Equal(cluster, expected); diff != nil {
+		t.Error(diff)
+	}
+}
+
+func TestClusters_Rename(t *testing.T) {
+	setup()
+	defer teardown()
+
+	projectID := "5a0a1e7e0f2912c554080adc"
+	clusterID := "5a0a1e7e0f2912c554080ae1"
+
+	mux.HandleFunc(fmt.Sprintf("/groups/%s/clusters/%s", projectID, clusterID), func(w http.ResponseWriter, r *http.Request) {
+		testMethod(t, r, http.MethodPatch)
+
+		expected := map[string]interface{}{"clusterName": "Cluster0"}
+		var v map[string]interface{}
+		if err := json.NewDecoder(r.Body).Decode(&v); err != nil {
+			t.Fatalf("decode json: %v", err)
+		}
+		if diff := deep.Equal(v, expected); diff != nil {
+			t.Error(diff)
+		}
+
+		_, _ = fmt.Fprint(w, `{
+			"clusterName": "Cluster0",
+			"groupId": "5a0a1e7e0f2912c554080adc",
+			"id": "5a0a1e7e0f2912c554080ae1",
+			"lastHeartbeat": "2017-11-13T22:04:17Z",
+			"links": [],
+			"replicaSetName": "rs0",
+			"typeName": "REPLICA_SET"
+		}`)
+	})
+
+	cluster, _, err := client.Clusters.Rename(ctx, projectID, clusterID, "Cluster0")
+	if err != nil {
+		t.Fatalf("Clusters.Rename returned error: %v", err)
+	}
+
+	expected := &Cluster{
+		ClusterName:    "Cluster0",
+		GroupID:        "5a0a1e7e0f2912c554080adc",
+		ID:             "5a0a1e7e0f2912c554080ae1",
+		LastHeartbeat:  "2017-11-13T22:04:17Z",
+		Links:          []*mongodbatlas.Link{},
+		ReplicaSetName: "rs0",
+		TypeName:       "REPLICA_SET",
+	}
+
+	if diff := deep.Equal(cluster, expected); diff != nil {
+		t.Error(diff)
+	}
+}
+
+func TestClusters_ListWithHosts(t *testing.T) {
+	setup()
+	defer teardown()
+
+	projectID := "5a0a1e7e0f2912c554080adc"
+
+	mux.HandleFunc(fmt.Sprintf("/groups/%s/clusters", projectID), func(w http.ResponseWriter, r *http.Request) {
+		testMethod(t, r, http.MethodGet)
+		_, _ = fmt.Fprint(w, `{"results": [{"id": "c1", "clusterName": "rs0"}, {"id": "c2", "clusterName": "rs1"}], "totalCount": 2}`)
+	})
+	mux.HandleFunc(fmt.Sprintf("/groups/%s/hosts", projectID), func(w http.ResponseWriter, r *http.Request) {
+		testMethod(t, r, http.MethodGet)
+		// the hosts span two pages
+		if r.URL.Query().Get("pageNum") == "2" {
+			_, _ = fmt.Fprint(w, `{"results": [{"id": "h3", "clusterId": "c1"}], "totalCount": 3}`)
+			return
+		}
+		_, _ = fmt.Fprint(w, `{"results": [{"id": "h1", "clusterId": "c1"}, {"id": "h2", "clusterId": "unknown"}], "totalCount": 3}`)
+	})
+
+	clusters, _, err := client.Clusters.ListWithHosts(ctx, projectID)
+	if err != nil {
+		t.Fatalf("Clusters.ListWithHosts returned error: %v", err)
+	}
+
+	expected := []*ClusterWithHosts{
+		{
+			Cluster: &Cluster{ID: "c1", ClusterName: "rs0"},
+			Hosts:   []*Host{{ID: "h1", ClusterID: "c1"}, {ID: "h3", ClusterID: "c1"}},
+		},
+		{
+			Cluster: &Cluster{ID: "c2", ClusterName: "rs1"},
+		},
+	}
+
+	if diff := deep.Equal(clusters, expected); diff != nil {
+		t.Error(diff)
+	}
+}
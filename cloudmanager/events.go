@@ -0,0 +1,177 @@
+// Copyright 2020 MongoDB Inc
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package cloudmanager
+
+import (
+	"context"
+	"fmt"
+	"net/http"
+	"time"
+
+	atlas "github.com/mongodb/go-client-mongodb-atlas/mongodbatlas"
+)
+
+const (
+	projectEventsBasePath = "groups/%s/events"
+	orgEventsBasePath     = "orgs/%s/events"
+)
+
+// EventsService is an interface for interfacing with the Events
+// endpoints of the MongoDB Cloud Manager API.
+// See more: https://docs.cloudmanager.mongodb.com/reference/api/events/
+type EventsService interface {
+	ListProjectEvents(context.Context, string, *EventListOptions) (*Events, *atlas.Response, error)
+	GetProjectEvent(context.Context, string, string) (*Event, *atlas.Response, error)
+	ListOrganizationEvents(context.Context, string, *EventListOptions) (*Events, *atlas.Response, error)
+	GetOrganizationEvent(context.Context, string, string) (*Event, *atlas.Response, error)
+}
+
+// EventsServiceOp handles communication with the Events related methods of the
+// MongoDB Cloud Manager API
+type EventsServiceOp struct {
+	client *Client
+}
+
+var _ EventsService = &EventsServiceOp{}
+
+// Event represents an audit event of a project or organization
+type Event struct {
+	ID              string        `json:"id"`
+	AlertConfigID   string        `json:"alertConfigId,omitempty"`
+	AlertID         string        `json:"alertId,omitempty"`
+	APIKeyID        string        `json:"apiKeyId,omitempty"`
+	ClusterID       string        `json:"clusterId,omitempty"`
+	ClusterName     string        `json:"clusterName,omitempty"`
+	Created         time.Time     `json:"created"`
+	EventTypeName   string        `json:"eventTypeName"`
+	GroupID         string        `json:"groupId,omitempty"`
+	HostID          string        `json:"hostId,omitempty"`
+	Hostname        string        `json:"hostname,omitempty"`
+	IsGlobalAdmin   bool          `json:"isGlobalAdmin,omitempty"`
+	Links           []*atlas.Link `json:"links,omitempty"`
+	OrgID           string        `json:"orgId,omitempty"`
+	Port            int           `json:"port,omitempty"`
+	PublicKey       string        `json:"publicKey,omitempty"`
+	RemoteAddress   string        `json:"remoteAddress,omitempty"`
+	ReplicaSetName  string        `json:"replicaSetName,omitempty"`
+	ShardName       string        `json:"shardName,omitempty"`
+	TargetPublicKey string        `json:"targetPublicKey,omitempty"`
+	TargetUsername  string        `json:"targetUsername,omitempty"`
+	TeamID          string        `json:"teamId,omitempty"`
+	UserID          string        `json:"userId,omitempty"`
+	Username        string        `json:"username,omitempty"`
+}
+
+// Events represents a array of events
+type Events struct {
+	Links      []*atlas.Link `json:"links"`
+	Results    []*Event      `json:"results"`
+	TotalCount int           `json:"totalCount"`
+}
+
+// EventListOptions filter the events returned by the list methods.
+// MinDate and MaxDate are inclusive.
+type EventListOptions struct {
+	EventType string     `url:"eventType,omitempty"`
+	MinDate   *time.Time `url:"minDate,omitempty"`
+	MaxDate   *time.Time `url:"maxDate,omitempty"`
+
+	atlas.ListOptions
+}
+
+// ListProjectEvents gets the events of a project, most recent first.
+// See more: https://docs.cloudmanager.mongodb.com/reference/api/events/get-all-events-for-project/
+func (s *EventsServiceOp) ListProjectEvents(ctx context.Context, projectID string, opts *EventListOptions) (*Events, *atlas.Response, error) {
+	if projectID == "" {
+		return nil, nil, atlas.NewArgError("projectID", "must be set")
+	}
+
+	return s.list(ctx, fmt.Sprintf(projectEventsBasePath, projectID), opts)
+}
+
+// GetProjectEvent gets a single event of a project by its ID.
+// See more: https://docs.cloudmanager.mongodb.com/reference/api/events/get-one-event-for-project/
+func (s *EventsServiceOp) GetProjectEvent(ctx context.Context, projectID, eventID string) (*Event, *atlas.Response, error) {
+	if projectID == "" {
+		return nil, nil, atlas.NewArgError("projectID", "must be set")
+	}
+
+	return s.get(ctx, fmt.Sprintf(projectEventsBasePath, projectID), eventID)
+}
+
+// ListOrganizationEvents gets the events of an organization, most recent first.
+// See more: https://docs.cloudmanager.mongodb.com/reference/api/events/get-all-events-for-org/
+func (s *EventsServiceOp) ListOrganizationEvents(ctx context.Context, orgID string, opts *EventListOptions) (*Events, *atlas.Response, error) {
+	if orgID == "" {
+		return nil, nil, atlas.NewArgError("orgID", "must be set")
+	}
+
+	return s.list(ctx, fmt.Sprintf(orgEventsBasePath, orgID), opts)
+}
+
+// GetOrganizationEvent gets a single event of an organization by its ID.
+// See more: https://docs.cloudmanager.mongodb.com/reference/api/events/get-one-event-for-org/
+func (s *EventsServiceOp) GetOrganizationEvent(ctx context.Context, orgID, eventID string) (*Event, *atlas.Response, error) {
+	if orgID == "" {
+		return nil, nil, atlas.NewArgError("orgID", "must be set")
+	}
+
+	return s.get(ctx, fmt.Sprintf(orgEventsBasePath, orgID), eventID)
+}
+
+func (s *EventsServiceOp) list(ctx context.Context, basePath string, opts *EventListOptions) (*Events, *atlas.Response, error) {
+	path, err := setListOptions(basePath, opts)
+	if err != nil {
+		return nil, nil, err
+	}
+
+	req, err := s.client.NewRequest(ctx, http.MethodGet, path, nil)
+	if err != nil {
+		return nil, nil, err
+	}
+
+	root := new(Events)
+	resp, err := s.client.Do(ctx, req, root)
+	if err != nil {
+		return nil, resp, err
+	}
+
+	if l := root.Links; l != nil {
+		resp.Links = l
+	}
+
+	return root, resp, nil
+}
+
+func (s *EventsServiceOp) get(ctx context.Context, basePath, eventID string) (*Event, *atlas.Response, error) {
+	if eventID == "" {
+		return nil, nil, atlas.NewArgError("eventID", "must be set")
+	}
+
+	path := fmt.Sprintf("%s/%s", basePath, eventID)
+
+	req, err := s.client.NewRequest(ctx, http.MethodGet, path, nil)
+	if err != nil {
+		return nil, nil, err
+	}
+
+	root := new(Event)
+	resp, err := s.client.Do(ctx, req, root)
+	if err != nil {
+		return nil, resp, err
+	}
+
+	return root, resp, err
+}
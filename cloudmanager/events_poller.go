@@ -0,0 +1,279 @@
+// Copyright 2020 MongoDB Inc
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package cloudmanager
+
+import (
+	"context"
+	"encoding/json"
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"sort"
+	"sync"
+	"time"
+
+	atlas "github.com/mongodb/go-client-mongodb-atlas/mongodbatlas"
+)
+
+const (
+	// DefaultEventsPollInterval the default interval between two polls of an EventsPoller
+	DefaultEventsPollInterval = 30 * time.Second
+	defaultEventsPageSize     = 100
+)
+
+// EventsCheckpoint records the most recent event delivered by an EventsPoller.
+// SeenEventIDs lists every event delivered at LastEventTime, since they are all listed again on the next poll.
+type EventsCheckpoint struct {
+	LastEventID   string    `json:"lastEventId"`
+	LastEventTime time.Time `json:"lastEventTime"`
+	SeenEventIDs  []string  `json:"seenEventIds,omitempty"`
+}
+
+// CheckpointStore persists the checkpoint of an EventsPoller, so polling resumes where it left off after a restart
+type CheckpointStore interface {
+	// Load returns the saved checkpoint, or nil if none was saved yet
+	Load(context.Context) (*EventsCheckpoint, error)
+	Save(context.Context, *EventsCheckpoint) error
+}
+
+// MemoryCheckpointStore keeps the checkpoint in memory; it does not survive restarts
+type MemoryCheckpointStore struct {
+	mu         sync.Mutex
+	checkpoint *EventsCheckpoint
+}
+
+var _ CheckpointStore = &MemoryCheckpointStore{}
+
+// Load returns the saved checkpoint
+func (s *MemoryCheckpointStore) Load(context.Context) (*EventsCheckpoint, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	if s.checkpoint == nil {
+		return nil, nil
+	}
+	checkpoint := *s.checkpoint
+	checkpoint.SeenEventIDs = append([]string(nil), s.checkpoint.SeenEventIDs...)
+	return &checkpoint, nil
+}
+
+// Save saves the checkpoint
+func (s *MemoryCheckpointStore) Save(_ context.Context, checkpoint *EventsCheckpoint) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	saved := *checkpoint
+	saved.SeenEventIDs = append([]string(nil), checkpoint.SeenEventIDs...)
+	s.checkpoint = &saved
+	return nil
+}
+
+// FileCheckpointStore keeps the checkpoint in a JSON file
+type FileCheckpointStore struct {
+	Path string
+}
+
+var _ CheckpointStore = &FileCheckpointStore{}
+
+// Load reads the checkpoint from the file, if it exists
+func (s *FileCheckpointStore) Load(context.Context) (*EventsCheckpoint, error) {
+	data, err := ioutil.ReadFile(s.Path)
+	if os.IsNotExist(err) {
+		return nil, nil
+	}
+	if err != nil {
+		return nil, err
+	}
+
+	checkpoint := new(EventsCheckpoint)
+	if err := json.Unmarshal(data, checkpoint); err != nil {
+		return nil, err
+	}
+	return checkpoint, nil
+}
+
+// Save atomically replaces the file with the checkpoint
+func (s *FileCheckpointStore) Save(_ context.Context, checkpoint *EventsCheckpoint) error {
+	data, err := json.Marshal(checkpoint)
+	if err != nil {
+		return err
+	}
+
+	tmp, err := ioutil.TempFile(filepath.Dir(s.Path), filepath.Base(s.Path)+".tmp")
+	if err != nil {
+		return err
+	}
+	defer os.Remove(tmp.Name())
+
+	if _, err := tmp.Write(data); err != nil {
+		_ = tmp.Close()
+		return err
+	}
+	if err := tmp.Close(); err != nil {
+		return err
+	}
+
+	return os.Rename(tmp.Name(), s.Path)
+}
+
+type listEventsFunc func(context.Context, *EventListOptions) (*Events, *atlas.Response, error)
+
+// EventsPoller delivers the events of a project or organization as they are created.
+// Events are delivered oldest first, at most once per event ID, and a checkpoint is saved after each delivery.
+type EventsPoller struct {
+	// Interval between two polls, DefaultEventsPollInterval if zero
+	Interval time.Duration
+	// EventType optionally restricts the delivered events to a single type
+	EventType string
+	// Since is where polling starts when the store has no checkpoint; all events are delivered if zero
+	Since time.Time
+
+	list  listEventsFunc
+	store CheckpointStore
+
+	checkpoint *EventsCheckpoint
+	seen       map[string]bool
+}
+
+// NewProjectEventsPoller returns a poller for the events of a project
+func NewProjectEventsPoller(events EventsService, projectID string, store CheckpointStore) *EventsPoller {
+	return &EventsPoller{
+		list: func(ctx context.Context, opts *EventListOptions) (*Events, *atlas.Response, error) {
+			return events.ListProjectEvents(ctx, projectID, opts)
+		},
+		store: store,
+	}
+}
+
+// NewOrganizationEventsPoller returns a poller for the events of an organization
+func NewOrganizationEventsPoller(events EventsService, orgID string, store CheckpointStore) *EventsPoller {
+	return &EventsPoller{
+		list: func(ctx context.Context, opts *EventListOptions) (*Events, *atlas.Response, error) {
+			return events.ListOrganizationEvents(ctx, orgID, opts)
+		},
+		store: store,
+	}
+}
+
+// Run polls for new events and sends them to the channel, until the context is cancelled or an error occurs.
+// It resumes from the checkpoint in the store, and never closes the channel.
+func (p *EventsPoller) Run(ctx context.Context, events chan<- *Event) error {
+	checkpoint, err := p.store.Load(ctx)
+	if err != nil {
+		return err
+	}
+
+	// checkpoints saved before SeenEventIDs was added only record the last event
+	if checkpoint != nil && len(checkpoint.SeenEventIDs) == 0 && checkpoint.LastEventID != "" {
+		checkpoint.SeenEventIDs = []string{checkpoint.LastEventID}
+	}
+
+	p.checkpoint = checkpoint
+	p.seen = map[string]bool{}
+	if checkpoint != nil {
+		for _, id := range checkpoint.SeenEventIDs {
+			p.seen[id] = true
+		}
+	}
+
+	interval := p.Interval
+	if interval <= 0 {
+		interval = DefaultEventsPollInterval
+	}
+
+	for {
+		if err := p.poll(ctx, events); err != nil {
+			return err
+		}
+
+		select {
+		case <-ctx.Done():
+			return ctx.Err()
+		case <-time.After(interval):
+		}
+	}
+}
+
+// poll delivers the events created since the checkpoint
+func (p *EventsPoller) poll(ctx context.Context, events chan<- *Event) error {
+	opts := &EventListOptions{
+		EventType:   p.EventType,
+		ListOptions: atlas.ListOptions{ItemsPerPage: defaultEventsPageSize},
+	}
+	// minDate is inclusive, so the events of the checkpoint's timestamp are listed again and skipped as seen
+	if p.checkpoint != nil {
+		minDate := p.checkpoint.LastEventTime
+		opts.MinDate = &minDate
+	} else if !p.Since.IsZero() {
+		since := p.Since
+		opts.MinDate = &since
+	}
+
+	var pending []*Event
+	err := listPages(&opts.ListOptions, func() (int, int, error) {
+		page, _, err := p.list(ctx, opts)
+		if err != nil {
+			return 0, 0, err
+		}
+		pending = append(pending, page.Results...)
+		return len(page.Results), page.TotalCount, nil
+	})
+	if err != nil {
+		return err
+	}
+
+	// the API lists the most recent events first; reverse them so events created at the same time keep their order
+	for i, j := 0, len(pending)-1; i < j; i, j = i+1, j-1 {
+		pending[i], pending[j] = pending[j], pending[i]
+	}
+	sort.SliceStable(pending, func(i, j int) bool {
+		return pending[i].Created.Before(pending[j].Created)
+	})
+
+	for _, event := range pending {
+		if p.seen[event.ID] {
+			continue
+		}
+		if p.checkpoint != nil {
+			if event.Created.Before(p.checkpoint.LastEventTime) {
+				continue
+			}
+			// only the IDs of the checkpoint's timestamp can be listed again
+			if event.Created.After(p.checkpoint.LastEventTime) {
+				p.seen = map[string]bool{}
+			}
+		}
+
+		select {
+		case <-ctx.Done():
+			return ctx.Err()
+		case events <- event:
+		}
+
+		var seenIDs []string
+		if p.checkpoint != nil && p.checkpoint.LastEventTime.Equal(event.Created) {
+			seenIDs = append(seenIDs, p.checkpoint.SeenEventIDs...)
+		}
+		seenIDs = append(seenIDs, event.ID)
+
+		p.seen[event.ID] = true
+		p.checkpoint = &EventsCheckpoint{LastEventID: event.ID, LastEventTime: event.Created, SeenEventIDs: seenIDs}
+		if err := p.store.Save(ctx, p.checkpoint); err != nil {
+			return err
+		}
+	}
+
+	return nil
+}
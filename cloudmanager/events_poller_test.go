@@ -0,0 +1,266 @@
+// Copyright 2020 MongoDB Inc
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package cloudmanager
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"io/ioutil"
+	"net/http"
+	"os"
+	"path/filepath"
+	"sync"
+	"testing"
+	"time"
+)
+
+// fakeEventsServer serves the events of a project, most recent first, honoring minDate
+type fakeEventsServer struct {
+	mu     sync.Mutex
+	events []*Event
+}
+
+func (s *fakeEventsServer) add(id string, created time.Time) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	s.events = append([]*Event{{ID: id, Created: created, EventTypeName: "JOINED_GROUP"}}, s.events...)
+}
+
+func (s *fakeEventsServer) ServeHTTP(w http.ResponseWriter, r *http.Request) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	var minDate time.Time
+	if v := r.URL.Query().Get("minDate"); v != "" {
+		minDate, _ = time.Parse(time.RFC3339, v)
+	}
+
+	result := &Events{Results: []*Event{}}
+	for _, e := range s.events {
+		if !e.Created.Before(minDate) {
+			result.Results = append(result.Results, e)
+		}
+	}
+	result.TotalCount = len(result.Results)
+
+	_ = json.NewEncoder(w).Encode(result)
+}
+
+func receiveEvents(t *testing.T, events <-chan *Event, n int) []string {
+	var ids []string
+	for i := 0; i < n; i++ {
+		select {
+		case e := <-events:
+			ids = append(ids, e.ID)
+		case <-time.After(5 * time.Second):
+			t.Fatalf("timed out after receiving %v", ids)
+		}
+	}
+	return ids
+}
+
+func expectNoEvent(t *testing.T, events <-chan *Event) {
+	select {
+	case e := <-events:
+		t.Errorf("unexpected event %s", e.ID)
+	case <-time.After(50 * time.Millisecond):
+	}
+}
+
+func TestEventsPoller_Run(t *testing.T) {
+	setup()
+	defer teardown()
+
+	projectID := "5a0a1e7e0f2912c554080adc"
+	base := time.Date(2020, 2, 14, 15, 16, 6, 0, time.UTC)
+
+	fake := &fakeEventsServer{}
+	fake.add("1", base)
+	fake.add("2", base.Add(time.Second))
+	fake.add("3", base.Add(time.Second))
+	mux.Handle(fmt.Sprintf("/groups/%s/events", projectID), fake)
+
+	store := &MemoryCheckpointStore{}
+	run := func() (chan *Event, context.CancelFunc, chan error) {
+		poller := NewProjectEventsPoller(client.Events, projectID, store)
+		poller.Interval = 10 * time.Millisecond
+
+		events := make(chan *Event)
+		done := make(chan error, 1)
+		runCtx, cancel := context.WithCancel(ctx)
+		go func() { done <- poller.Run(runCtx, events) }()
+		return events, cancel, done
+	}
+
+	events, cancel, done := run()
+
+	if ids := receiveEvents(t, events, 3); fmt.Sprint(ids) != "[1 2 3]" {
+		t.Errorf("expected events oldest first, got %v", ids)
+	}
+	// later polls list events 2 and 3 again, since minDate is inclusive
+	expectNoEvent(t, events)
+
+	fake.add("4", base.Add(time.Second))
+	fake.add("5", base.Add(2*time.Second))
+	if ids := receiveEvents(t, events, 2); fmt.Sprint(ids) != "[4 5]" {
+		t.Errorf("expected new events only, got %v", ids)
+	}
+
+	cancel()
+	if err := <-done; err != context.Canceled {
+		t.Errorf("expected the poller to stop with context.Canceled, got %v", err)
+	}
+
+	checkpoint, _ := store.Load(ctx)
+	if checkpoint == nil || checkpoint.LastEventID != "5" || !checkpoint.LastEventTime.Equal(base.Add(2*time.Second)) {
+		t.Errorf("unexpected checkpoint %+v", checkpoint)
+	}
+
+	// a restarted poller resumes from the checkpoint
+	fake.add("6", base.Add(3*time.Second))
+	events, cancel, done = run()
+	defer func() {
+		cancel()
+		<-done
+	}()
+
+	if ids := receiveEvents(t, events, 1); fmt.Sprint(ids) != "[6]" {
+		t.Errorf("expected the poller to resume after the checkpoint, got %v", ids)
+	}
+	expectNoEvent(t, events)
+}
+
+func TestEventsPoller_Run_restartWithSameTimestamp(t *testing.T) {
+	setup()
+	defer teardown()
+
+	projectID := "5a0a1e7e0f2912c554080adc"
+	base := time.Date(2020, 2, 14, 15, 16, 6, 0, time.UTC)
+
+	fake := &fakeEventsServer{}
+	fake.add("1", base)
+	fake.add("2", base.Add(time.Second))
+	fake.add("3", base.Add(time.Second))
+	mux.Handle(fmt.Sprintf("/groups/%s/events", projectID), fake)
+
+	store := &MemoryCheckpointStore{}
+	run := func() (chan *Event, context.CancelFunc, chan error) {
+		poller := NewProjectEventsPoller(client.Events, projectID, store)
+		poller.Interval = 10 * time.Millisecond
+
+		events := make(chan *Event)
+		done := make(chan error, 1)
+		runCtx, cancel := context.WithCancel(ctx)
+		go func() { done <- poller.Run(runCtx, events) }()
+		return events, cancel, done
+	}
+
+	events, cancel, done := run()
+	if ids := receiveEvents(t, events, 3); fmt.Sprint(ids) != "[1 2 3]" {
+		t.Errorf("expected events oldest first, got %v", ids)
+	}
+	cancel()
+	<-done
+
+	checkpoint, _ := store.Load(ctx)
+	if checkpoint == nil || fmt.Sprint(checkpoint.SeenEventIDs) != "[2 3]" {
+		t.Fatalf("expected the checkpoint to record both events of its timestamp, got %+v", checkpoint)
+	}
+
+	// after a restart, events 2 and 3 are listed again but not delivered twice
+	fake.add("4", base.Add(time.Second))
+	events, cancel, done = run()
+	defer func() {
+		cancel()
+		<-done
+	}()
+
+	if ids := receiveEvents(t, events, 1); fmt.Sprint(ids) != "[4]" {
+		t.Errorf("expected only the new event, got %v", ids)
+	}
+	expectNoEvent(t, events)
+}
+
+func TestEventsPoller_Run_legacyCheckpoint(t *testing.T) {
+	setup()
+	defer teardown()
+
+	projectID := "5a0a1e7e0f2912c554080adc"
+	base := time.Date(2020, 2, 14, 15, 16, 6, 0, time.UTC)
+
+	fake := &fakeEventsServer{}
+	fake.add("1", base)
+	fake.add("2", base)
+	mux.Handle(fmt.Sprintf("/groups/%s/events", projectID), fake)
+
+	store := &MemoryCheckpointStore{}
+	_ = store.Save(ctx, &EventsCheckpoint{LastEventID: "1", LastEventTime: base})
+
+	poller := NewProjectEventsPoller(client.Events, projectID, store)
+	poller.Interval = 10 * time.Millisecond
+
+	events := make(chan *Event)
+	done := make(chan error, 1)
+	runCtx, cancel := context.WithCancel(ctx)
+	go func() { done <- poller.Run(runCtx, events) }()
+	defer func() {
+		cancel()
+		<-done
+	}()
+
+	if ids := receiveEvents(t, events, 1); fmt.Sprint(ids) != "[2]" {
+		t.Errorf("expected only the event after the checkpoint, got %v", ids)
+	}
+	expectNoEvent(t, events)
+
+	checkpoint, _ := store.Load(ctx)
+	if checkpoint == nil || fmt.Sprint(checkpoint.SeenEventIDs) != "[1 2]" {
+		t.Errorf("unexpected checkpoint %+v", checkpoint)
+	}
+}
+
+func TestFileCheckpointStore(t *testing.T) {
+	dir, err := ioutil.TempDir("", "checkpoint")
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer os.RemoveAll(dir)
+
+	store := &FileCheckpointStore{Path: filepath.Join(dir, "events.json")}
+
+	checkpoint, err := store.Load(ctx)
+	if err != nil || checkpoint != nil {
+		t.Fatalf("expected no checkpoint, got %+v, %v", checkpoint, err)
+	}
+
+	expected := &EventsCheckpoint{
+		LastEventID:   "5e46b8466c8f9d1a2f8bba10",
+		LastEventTime: time.Date(2020, 2, 14, 15, 16, 6, 0, time.UTC),
+		SeenEventIDs:  []string{"5e46b8466c8f9d1a2f8bba0f", "5e46b8466c8f9d1a2f8bba10"},
+	}
+	if err := store.Save(ctx, expected); err != nil {
+		t.Fatalf("Save returned error: %v", err)
+	}
+
+	checkpoint, err = store.Load(ctx)
+	if err != nil {
+		t.Fatalf("Load returned error: %v", err)
+	}
+	if checkpoint.LastEventID != expected.LastEventID || !checkpoint.LastEventTime.Equal(expected.LastEventTime) ||
+		fmt.Sprint(checkpoint.SeenEventIDs) != fmt.Sprint(expected.SeenEventIDs) {
+		t.Errorf("expected %+v, got %+v", expected, checkpoint)
+	}
+}
@@ -0,0 +1,232 @@
+// Copyright 2020 MongoDB Inc
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package cloudmanager
+
+import (
+	"fmt"
+	"net/http"
+	"net/url"
+	"testing"
+	"time"
+
+	"github.com/go-test/deep"
+	"github.com/mongodb/go-client-mongodb-atlas/mongodbatlas"
+)
+
+func TestEvents_ListProjectEvents(t *testing.T) {
+	setup()
+	defer teardown()
+
+	projectID := "5a0a1e7e0f2912c554080adc"
+
+	mux.HandleFunc(fmt.Sprintf("/groups/%s/events", projectID), func(w http.ResponseWriter, r *http.Request) {
+		testMethod(t, r, http.MethodGet)
+		testQuery(t, r, url.Values{
+			"eventType":    {"JOINED_GROUP"},
+			"minDate":      {"2020-02-01T00:00:00Z"},
+			"maxDate":      {"2020-03-01T00:00:00Z"},
+			"pageNum":      {"2"},
+			"itemsPerPage": {"10"},
+		})
+		_, _ = fmt.Fprint(w, `{
+			"links": [],
+			"results": [{
+				"created": "2020-02-14T15:16:06Z",
+				"eventTypeName": "JOINED_GROUP",
+				"groupId": "5a0a1e7e0f2912c554080adc",
+				"id": "5e46b8466c8f9d1a2f8bba10",
+				"isGlobalAdmin": false,
+				"links": [],
+				"remoteAddress": "192.0.2.1",
+				"userId": "5e46b8456c8f9d1a2f8bb9ff",
+				"username": "someone@example.com"
+			}],
+			"totalCount": 11
+		}`)
+	})
+
+	minDate := time.Date(2020, 2, 1, 0, 0, 0, 0, time.UTC)
+	maxDate := time.Date(2020, 3, 1, 0, 0, 0, 0, time.UTC)
+	opts := &EventListOptions{
+		EventType:   "JOINED_GROUP",
+		MinDate:     &minDate,
+		MaxDate:     &maxDate,
+		ListOptions: mongodbatlas.ListOptions{PageNum: 2, ItemsPerPage: 10},
+	}
+	events, _, err := client.Events.ListProjectEvents(ctx, projectID, opts)
+	if err != nil {
+		t.Fatalf("Events.ListProjectEvents returned error: %v", err)
+	}
+
+	expected := &Events{
+		Links: []*mongodbatlas.Link{},
+		Results: []*Event{
+			{
+				ID:            "5e46b8466c8f9d1a2f8bba10",
+				Created:       time.Date(2020, 2, 14, 15, 16, 6, 0, time.UTC),
+				EventTypeName: "JOINED_GROUP",
+				GroupID:       "5a0a1e7e0f2912c554080adc",
+				Links:         []*mongodbatlas.Link{},
+				RemoteAddress: "192.0.2.1",
+				UserID:        "5e46b8456c8f9d1a2f8bb9ff",
+				Username:      "someone@example.com",
+			},
+		},
+		TotalCount: 11,
+	}
+
+	if diff := deep.Equal(events, expected); diff != nil {
+		t.Error(diff)
+	}
+}
+
+func TestEvents_GetProjectEvent(t *testing.T) {
+	setup()
+	defer teardown()
+
+	projectID := "5a0a1e7e0f2912c554080adc"
+	eventID := "5e46b8466c8f9d1a2f8bba10"
+
+	mux.HandleFunc(fmt.Sprintf("/groups/%s/events/%s", projectID, eventID), func(w http.ResponseWriter, r *http.Request) {
+		testMethod(t, r, http.MethodGet)
+		_, _ = fmt.Fprint(w, `{
+			"created": "2020-02-14T15:16:06Z",
+			"eventTypeName": "JOINED_GROUP",
+			"groupId": "5a0a1e7e0f2912c554080adc",
+			"id": "5e46b8466c8f9d1a2f8bba10",
+			"isGlobalAdmin": false,
+			"links": [],
+			"remoteAddress": "192.0.2.1",
+			"userId": "5e46b8456c8f9d1a2f8bb9ff",
+			"username": "someone@example.com"
+		}`)
+	})
+
+	event, _, err := client.Events.GetProjectEvent(ctx, projectID, eventID)
+	if err != nil {
+		t.Fatalf("Events.GetProjectEvent returned error: %v", err)
+	}
+
+	expected := &Event{
+		ID:            "5e46b8466c8f9d1a2f8bba10",
+		Created:       time.Date(2020, 2, 14, 15, 16, 6, 0, time.UTC),
+		EventTypeName: "JOINED_GROUP",
+		GroupID:       "5a0a1e7e0f2912c554080adc",
+		Links:         []*mongodbatlas.Link{},
+		RemoteAddress: "192.0.2.1",
+		UserID:        "5e46b8456c8f9d1a2f8bb9ff",
+		Username:      "someone@example.com",
+	}
+
+	if diff := deep.Equal(event, expected); diff != nil {
+		t.Error(diff)
+	}
+}
+
+func TestEvents_ListOrganizationEvents(t *testing.T) {
+	setup()
+	defer teardown()
+
+	orgID := "5a0a1e7e0f2912c554080adb"
+
+	mux.HandleFunc(fmt.Sprintf("/orgs/%s/events", orgID), func(w http.ResponseWriter, r *http.Request) {
+		testMethod(t, r, http.MethodGet)
+		testQuery(t, r, url.Values{})
+		_, _ = fmt.Fprint(w, `{
+			"links": [],
+			"results": [{
+				"created": "2020-02-14T15:16:06Z",
+				"eventTypeName": "JOINED_GROUP",
+				"groupId": "5a0a1e7e0f2912c554080adc",
+				"id": "5e46b8466c8f9d1a2f8bba10",
+				"isGlobalAdmin": false,
+				"links": [],
+				"remoteAddress": "192.0.2.1",
+				"userId": "5e46b8456c8f9d1a2f8bb9ff",
+				"username": "someone@example.com"
+			}],
+			"totalCount": 1
+		}`)
+	})
+
+	events, _, err := client.Events.ListOrganizationEvents(ctx, orgID, nil)
+	if err != nil {
+		t.Fatalf("Events.ListOrganizationEvents returned error: %v", err)
+	}
+
+	expected := &Events{
+		Links: []*mongodbatlas.Link{},
+		Results: []*Event{
+			{
+				ID:            "5e46b8466c8f9d1a2f8bba10",
+				Created:       time.Date(2020, 2, 14, 15, 16, 6, 0, time.UTC),
+				EventTypeName: "JOINED_GROUP",
+				GroupID:       "5a0a1e7e0f2912c554080adc",
+				Links:         []*mongodbatlas.Link{},
+				RemoteAddress: "192.0.2.1",
+				UserID:        "5e46b8456c8f9d1a2f8bb9ff",
+				Username:      "someone@example.com",
+			},
+		},
+		TotalCount: 1,
+	}
+
+	if diff := deep.Equal(events, expected); diff != nil {
+		t.Error(diff)
+	}
+}
+
+func TestEvents_GetOrganizationEvent(t *testing.T) {
+	setup()
+	defer teardown()
+
+	orgID := "5a0a1e7e0f2912c554080adb"
+	eventID := "5e46b8466c8f9d1a2f8bba10"
+
+	mux.HandleFunc(fmt.Sprintf("/orgs/%s/events/%s", orgID, eventID), func(w http.ResponseWriter, r *http.Request) {
+		testMethod(t, r, http.MethodGet)
+		_, _ = fmt.Fprint(w, `{
+			"created": "2020-02-14T15:16:06Z",
+			"eventTypeName": "JOINED_GROUP",
+			"groupId": "5a0a1e7e0f2912c554080adc",
+			"id": "5e46b8466c8f9d1a2f8bba10",
+			"isGlobalAdmin": false,
+			"links": [],
+			"remoteAddress": "192.0.2.1",
+			"userId": "5e46b8456c8f9d1a2f8bb9ff",
+			"username": "someone@example.com"
+		}`)
+	})
+
+	event, _, err := client.Events.GetOrganizationEvent(ctx, orgID, eventID)
+	if err != nil {
+		t.Fatalf("Events.GetOrganizationEvent returned error: %v", err)
+	}
+
+	expected := &Event{
+		ID:            "5e46b8466c8f9d1a2f8bba10",
+		Created:       time.Date(2020, 2, 14, 15, 16, 6, 0, time.UTC),
+		EventTypeName: "JOINED_GROUP",
+		GroupID:       "5a0a1e7e0f2912c554080adc",
+		Links:         []*mongodbatlas.Link{},
+		RemoteAddress: "192.0.2.1",
+		UserID:        "5e46b8456c8f9d1a2f8bb9ff",
+		Username:      "someone@example.com",
+	}
+
+	if diff := deep.Equal(event, expected); diff != nil {
+		t.Error(diff)
+	}
+}
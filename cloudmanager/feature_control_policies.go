@@ -0,0 +1,140 @@
+// Copyright 2020 MongoDB Inc
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package cloudmanager
+
+import (
+	"context"
+	"fmt"
+	"net/http"
+
+	atlas "github.com/mongodb/go-client-mongodb-atlas/mongodbatlas"
+)
+
+const (
+	controlledFeaturePath = "groups/%s/controlledFeature"
+)
+
+// FeatureControlPoliciesService is an interface for interfacing with the Feature Control Policies
+// endpoints of the MongoDB Ops Manager API.
+// See more: https://docs.opsmanager.mongodb.com/current/reference/api/feature-control-policies/
+type FeatureControlPoliciesService interface {
+	Get(context.Context, string) (*ControlledFeature, *atlas.Response, error)
+	Update(context.Context, string, *ControlledFeature) (*ControlledFeature, *atlas.Response, error)
+}
+
+// FeatureControlPoliciesServiceOp handles communication with the Feature Control Policies related methods of the
+// MongoDB Ops Manager API
+type FeatureControlPoliciesServiceOp struct {
+	client *Client
+}
+
+var _ FeatureControlPoliciesService = &FeatureControlPoliciesServiceOp{}
+
+// PolicyName the name of a feature control policy
+type PolicyName string
+
+// Feature control policies which lock parts of a project in the Ops Manager UI
+const (
+	PolicyExternallyManagedLock           PolicyName = "EXTERNALLY_MANAGED_LOCK"
+	PolicyDisableUserManagement           PolicyName = "DISABLE_USER_MANAGEMENT"
+	PolicyDisableAuthenticationMechanisms PolicyName = "DISABLE_AUTHENTICATION_MECHANISMS"
+	PolicyDisableSetMongodVersion         PolicyName = "DISABLE_SET_MONGOD_VERSION"
+	PolicyDisableSetMongodConfig          PolicyName = "DISABLE_SET_MONGOD_CONFIG"
+	PolicyDisableBackupAgent              PolicyName = "DISABLE_BACKUP_AGENT"
+	PolicyDisableMonitoringAgent          PolicyName = "DISABLE_MONITORING_AGENT"
+)
+
+// ControlledFeature the external system managing a project, and the features it locks
+type ControlledFeature struct {
+	ExternalManagementSystem *ExternalManagementSystem `json:"externalManagementSystem,omitempty"`
+	Policies                 []*Policy                 `json:"policies"`
+}
+
+// ExternalManagementSystem identifies the system managing a project
+type ExternalManagementSystem struct {
+	Name     string `json:"name"`
+	SystemID string `json:"systemId,omitempty"`
+	Version  string `json:"version,omitempty"`
+}
+
+// Policy a locked feature; DisabledParams lists the settings it locks, e.g. the authentication mechanisms
+// of PolicyDisableAuthenticationMechanisms or the mongod options of PolicyDisableSetMongodConfig
+type Policy struct {
+	Policy         PolicyName `json:"policy"`
+	DisabledParams []string   `json:"disabledParams,omitempty"`
+}
+
+// Get gets the feature control policies of a project.
+// See more: https://docs.opsmanager.mongodb.com/current/reference/api/controlled-features/get-controlled-features-for-one-project/
+func (s *FeatureControlPoliciesServiceOp) Get(ctx context.Context, projectID string) (*ControlledFeature, *atlas.Response, error) {
+	if projectID == "" {
+		return nil, nil, atlas.NewArgError("projectID", "must be set")
+	}
+
+	path := fmt.Sprintf(controlledFeaturePath, projectID)
+
+	req, err := s.client.NewRequest(ctx, http.MethodGet, path, nil)
+	if err != nil {
+		return nil, nil, err
+	}
+
+	root := new(ControlledFeature)
+	resp, err := s.client.Do(ctx, req, root)
+	if err != nil {
+		return nil, resp, err
+	}
+
+	return root, resp, err
+}
+
+// Update replaces the feature control policies of a project; the external management system name is required,
+// and an empty list of policies unlocks all features.
+// See more: https://docs.opsmanager.mongodb.com/current/reference/api/controlled-features/update-controlled-features-for-one-project/
+func (s *FeatureControlPoliciesServiceOp) Update(ctx context.Context, projectID string, updateRequest *ControlledFeature) (*ControlledFeature, *atlas.Response, error) {
+	if projectID == "" {
+		return nil, nil, atlas.NewArgError("projectID", "must be set")
+	}
+	if updateRequest == nil {
+		return nil, nil, atlas.NewArgError("updateRequest", "cannot be nil")
+	}
+	if updateRequest.ExternalManagementSystem == nil || updateRequest.ExternalManagementSystem.Name == "" {
+		return nil, nil, atlas.NewArgError("externalManagementSystem.name", "must be set")
+	}
+	for _, policy := range updateRequest.Policies {
+		if policy == nil || policy.Policy == "" {
+			return nil, nil, atlas.NewArgError("policies", "must set a policy name")
+		}
+	}
+
+	body := *updateRequest
+	if body.Policies == nil {
+		body.Policies = []*Policy{}
+	}
+
+	path := fmt.Sprintf(controlledFeaturePath, projectID)
+
+	req, err := s.client.NewRequest(ctx, http.MethodPut, path, &body)
+	if err != nil {
+		return nil, nil, err
+	}
+
+	root := new(ControlledFeature)
+	resp, err := s.client.Do(ctx, req, root)
+	if err != nil {
+		return nil, resp, err
+	}
+
+	return root, resp, err
+}
@@ -0,0 +1,165 @@
+// Copyright 2020 MongoDB Inc
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package cloudmanager
+
+import (
+	"fmt"
+	"net/http"
+	"testing"
+
+	"github.com/go-test/deep"
+)
+
+func TestFeatureControlPolicies_Get(t *testing.T) {
+	setup()
+	defer teardown()
+
+	projectID := "5a0a1e7e0f2912c554080adc"
+
+	mux.HandleFunc(fmt.Sprintf("/groups/%s/controlledFeature", projectID), func(w http.ResponseWriter, r *http.Request) {
+		testMethod(t, r, http.MethodGet)
+		_, _ = fmt.Fprint(w, `{
+			"externalManagementSystem": {"name": "project-controller", "systemId": "6d3a2c1b", "version": "1.4.0"},
+			"policies": [
+				{"policy": "EXTERNALLY_MANAGED_LOCK"},
+				{"policy": "DISABLE_AUTHENTICATION_MECHANISMS", "disabledParams": ["MONGODB-CR", "SCRAM-SHA-256"]},
+				{"policy": "DISABLE_SET_MONGOD_VERSION"}
+			]
+		}`)
+	})
+
+	feature, _, err := client.FeatureControlPolicies.Get(ctx, projectID)
+	if err != nil {
+		t.Fatalf("FeatureControlPolicies.Get returned error: %v", err)
+	}
+
+	expected := &ControlledFeature{
+		ExternalManagementSystem: &ExternalManagementSystem{Name: "project-controller", SystemID: "6d3a2c1b", Version: "1.4.0"},
+		Policies: []*Policy{
+			{Policy: PolicyExternallyManagedLock},
+			{Policy: PolicyDisableAuthenticationMechanisms, DisabledParams: []string{"MONGODB-CR", "SCRAM-SHA-256"}},
+			{Policy: PolicyDisableSetMongodVersion},
+		},
+	}
+
+	if diff := deep.Equal(feature, expected); diff != nil {
+		t.Error(diff)
+	}
+}
+
+func TestFeatureControlPolicies_Update(t *testing.T) {
+	setup()
+	defer teardown()
+
+	projectID := "5a0a1e7e0f2912c554080adc"
+
+	mux.HandleFunc(fmt.Sprintf("/groups/%s/controlledFeature", projectID), func(w http.ResponseWriter, r *http.Request) {
+		testMethod(t, r, http.MethodPut)
+
+		expected := map[string]interface{}{
+			"externalManagementSystem": map[string]interface{}{
+				"name":     "project-controller",
+				"systemId": "6d3a2c1b",
+				"version":  "1.4.0",
+			},
+			"policies": []interface{}{
+				map[string]interface{}{"policy": "EXTERNALLY_MANAGED_LOCK"},
+				map[string]interface{}{"policy": "DISABLE_AUTHENTICATION_MECHANISMS", "disabledParams": []interface{}{"MONGODB-CR", "SCRAM-SHA-256"}},
+				map[string]interface{}{"policy": "DISABLE_SET_MONGOD_VERSION"},
+			},
+		}
+		if diff := deep.Equal(decodeBody(t, r), expected); diff != nil {
+			t.Error(diff)
+		}
+
+		_, _ = fmt.Fprint(w, `{
+			"externalManagementSystem": {"name": "project-controller", "systemId": "6d3a2c1b", "version": "1.4.0"},
+			"policies": [
+				{"policy": "EXTERNALLY_MANAGED_LOCK"},
+				{"policy": "DISABLE_AUTHENTICATION_MECHANISMS", "disabledParams": ["MONGODB-CR", "SCRAM-SHA-256"]},
+				{"policy": "DISABLE_SET_MONGOD_VERSION"}
+			]
+		}`)
+	})
+
+	expected := &ControlledFeature{
+		ExternalManagementSystem: &ExternalManagementSystem{Name: "project-controller", SystemID: "6d3a2c1b", Version: "1.4.0"},
+		Policies: []*Policy{
+			{Policy: PolicyExternallyManagedLock},
+			{Policy: PolicyDisableAuthenticationMechanisms, DisabledParams: []string{"MONGODB-CR", "SCRAM-SHA-256"}},
+			{Policy: PolicyDisableSetMongodVersion},
+		},
+	}
+
+	feature, _, err := client.FeatureControlPolicies.Update(ctx, projectID, expected)
+	if err != nil {
+		t.Fatalf("FeatureControlPolicies.Update returned error: %v", err)
+	}
+
+	if diff := deep.Equal(feature, expected); diff != nil {
+		t.Error(diff)
+	}
+}
+
+func TestFeatureControlPolicies_Update_unlockAll(t *testing.T) {
+	setup()
+	defer teardown()
+
+	projectID := "5a0a1e7e0f2912c554080adc"
+
+	mux.HandleFunc(fmt.Sprintf("/groups/%s/controlledFeature", projectID), func(w http.ResponseWriter, r *http.Request) {
+		testMethod(t, r, http.MethodPut)
+
+		expected := map[string]interface{}{
+			"externalManagementSystem": map[string]interface{}{"name": "project-controller"},
+			"policies":                 []interface{}{},
+		}
+		if diff := deep.Equal(decodeBody(t, r), expected); diff != nil {
+			t.Error(diff)
+		}
+
+		_, _ = fmt.Fprint(w, `{"externalManagementSystem": {"name": "project-controller"}, "policies": []}`)
+	})
+
+	_, _, err := client.FeatureControlPolicies.Update(ctx, projectID, &ControlledFeature{
+		ExternalManagementSystem: &ExternalManagementSystem{Name: "project-controller"},
+	})
+	if err != nil {
+		t.Fatalf("FeatureControlPolicies.Update returned error: %v", err)
+	}
+}
+
+func TestFeatureControlPolicies_Update_invalid(t *testing.T) {
+	setup()
+	defer teardown()
+
+	tests := map[string]*ControlledFeature{
+		"missing system":      {Policies: []*Policy{{Policy: PolicyExternallyManagedLock}}},
+		"missing system name": {ExternalManagementSystem: &ExternalManagementSystem{Version: "1.4.0"}},
+		"missing policy name": {
+			ExternalManagementSystem: &ExternalManagementSystem{Name: "project-controller"},
+			Policies:                 []*Policy{{DisabledParams: []string{"MONGODB-CR"}}},
+		},
+	}
+
+	for name, feature := range tests {
+		feature := feature
+		t.Run(name, func(t *testing.T) {
+			if _, _, err := client.FeatureControlPolicies.Update(ctx, "5a0a1e7e0f2912c554080adc", feature); err == nil {
+				t.Error("expected an error")
+			}
+		})
+	}
+}
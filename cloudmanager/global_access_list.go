@@ -0,0 +1,192 @@
+// Copyright 2020 MongoDB Inc
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package cloudmanager
+
+import (
+	"context"
+	"fmt"
+	"net"
+	"net/http"
+
+	atlas "github.com/mongodb/go-client-mongodb-atlas/mongodbatlas"
+)
+
+const (
+	globalAccessListBasePath = "admin/accessList"
+)
+
+// GlobalAccessListService is an interface for interfacing with the Global API Access List
+// endpoints of the MongoDB Ops Manager API.
+// See more: https://docs.opsmanager.mongodb.com/current/reference/api/global-access-list/
+type GlobalAccessListService interface {
+	List(context.Context, *atlas.ListOptions) (*GlobalAccessListEntries, *atlas.Response, error)
+	Get(context.Context, string) (*GlobalAccessListEntry, *atlas.Response, error)
+	Create(context.Context, *GlobalAccessListEntry) (*GlobalAccessListEntry, *atlas.Response, error)
+	Update(context.Context, string, *GlobalAccessListEntry) (*GlobalAccessListEntry, *atlas.Response, error)
+	Delete(context.Context, string) (*atlas.Response, error)
+}
+
+// GlobalAccessListServiceOp handles communication with the Global API Access List related methods of the
+// MongoDB Ops Manager API
+type GlobalAccessListServiceOp struct {
+	client *Client
+}
+
+var _ GlobalAccessListService = &GlobalAccessListServiceOp{}
+
+// GlobalAccessListEntry a CIDR block allowed to use global API keys and the API of users with global roles
+type GlobalAccessListEntry struct {
+	ID          string        `json:"id,omitempty"`
+	CIDRBlock   string        `json:"cidrBlock,omitempty"`
+	Created     string        `json:"created,omitempty"`
+	Description string        `json:"description,omitempty"`
+	LastUsed    string        `json:"lastUsed,omitempty"`
+	Links       []*atlas.Link `json:"links,omitempty"`
+	Type        string        `json:"type,omitempty"`
+	Updated     string        `json:"updated,omitempty"`
+}
+
+// GlobalAccessListEntries represents a array of global access list entries
+type GlobalAccessListEntries struct {
+	Links      []*atlas.Link            `json:"links"`
+	Results    []*GlobalAccessListEntry `json:"results"`
+	TotalCount int                      `json:"totalCount"`
+}
+
+// List gets the global API access list.
+// See more: https://docs.opsmanager.mongodb.com/current/reference/api/global-access-list/get-all-access-list-entries/
+func (s *GlobalAccessListServiceOp) List(ctx context.Context, opts *atlas.ListOptions) (*GlobalAccessListEntries, *atlas.Response, error) {
+	path, err := setListOptions(globalAccessListBasePath, opts)
+	if err != nil {
+		return nil, nil, err
+	}
+
+	req, err := s.client.NewRequest(ctx, http.MethodGet, path, nil)
+	if err != nil {
+		return nil, nil, err
+	}
+
+	root := new(GlobalAccessListEntries)
+	resp, err := s.client.Do(ctx, req, root)
+	if err != nil {
+		return nil, resp, err
+	}
+
+	if l := root.Links; l != nil {
+		resp.Links = l
+	}
+
+	return root, resp, nil
+}
+
+// Get gets a single entry of the global API access list.
+// See more: https://docs.opsmanager.mongodb.com/current/reference/api/global-access-list/get-one-access-list-entry/
+func (s *GlobalAccessListServiceOp) Get(ctx context.Context, entryID string) (*GlobalAccessListEntry, *atlas.Response, error) {
+	if entryID == "" {
+		return nil, nil, atlas.NewArgError("entryID", "must be set")
+	}
+
+	path := fmt.Sprintf("%s/%s", globalAccessListBasePath, entryID)
+
+	req, err := s.client.NewRequest(ctx, http.MethodGet, path, nil)
+	if err != nil {
+		return nil, nil, err
+	}
+
+	root := new(GlobalAccessListEntry)
+	resp, err := s.client.Do(ctx, req, root)
+	if err != nil {
+		return nil, resp, err
+	}
+
+	return root, resp, err
+}
+
+// Create adds a CIDR block to the global API access list; the CIDR block and description are required.
+// See more: https://docs.opsmanager.mongodb.com/current/reference/api/global-access-list/create-one-access-list-entry/
+func (s *GlobalAccessListServiceOp) Create(ctx context.Context, createRequest *GlobalAccessListEntry) (*GlobalAccessListEntry, *atlas.Response, error) {
+	if createRequest == nil {
+		return nil, nil, atlas.NewArgError("createRequest", "cannot be nil")
+	}
+	if _, _, err := net.ParseCIDR(createRequest.CIDRBlock); err != nil {
+		return nil, nil, atlas.NewArgError("cidrBlock", fmt.Sprintf("%q is not a valid CIDR block", createRequest.CIDRBlock))
+	}
+	if createRequest.Description == "" {
+		return nil, nil, atlas.NewArgError("description", "must be set")
+	}
+
+	req, err := s.client.NewRequest(ctx, http.MethodPost, globalAccessListBasePath, createRequest)
+	if err != nil {
+		return nil, nil, err
+	}
+
+	root := new(GlobalAccessListEntry)
+	resp, err := s.client.Do(ctx, req, root)
+	if err != nil {
+		return nil, resp, err
+	}
+
+	return root, resp, err
+}
+
+// Update updates the CIDR block or description of a global API access list entry; only the specified fields are modified.
+// See more: https://docs.opsmanager.mongodb.com/current/reference/api/global-access-list/update-one-access-list-entry/
+func (s *GlobalAccessListServiceOp) Update(ctx context.Context, entryID string, updateRequest *GlobalAccessListEntry) (*GlobalAccessListEntry, *atlas.Response, error) {
+	if entryID == "" {
+		return nil, nil, atlas.NewArgError("entryID", "must be set")
+	}
+	if updateRequest == nil {
+		return nil, nil, atlas.NewArgError("updateRequest", "cannot be nil")
+	}
+	if updateRequest.CIDRBlock != "" {
+		if _, _, err := net.ParseCIDR(updateRequest.CIDRBlock); err != nil {
+			return nil, nil, atlas.NewArgError("cidrBlock", fmt.Sprintf("%q is not a valid CIDR block", updateRequest.CIDRBlock))
+		}
+	}
+
+	path := fmt.Sprintf("%s/%s", globalAccessListBasePath, entryID)
+
+	req, err := s.client.NewRequest(ctx, http.MethodPatch, path, updateRequest)
+	if err != nil {
+		return nil, nil, err
+	}
+
+	root := new(GlobalAccessListEntry)
+	resp, err := s.client.Do(ctx, req, root)
+	if err != nil {
+		return nil, resp, err
+	}
+
+	return root, resp, err
+}
+
+// Delete removes an entry from the global API access list.
+// See more: https://docs.opsmanager.mongodb.com/current/reference/api/global-access-list/delete-one-access-list-entry/
+func (s *GlobalAccessListServiceOp) Delete(ctx context.Context, entryID string) (*atlas.Response, error) {
+	if entryID == "" {
+		return nil, atlas.NewArgError("entryID", "must be set")
+	}
+
+	path := fmt.Sprintf("%s/%s", globalAccessListBasePath, entryID)
+
+	req, err := s.client.NewRequest(ctx, http.MethodDelete, path, nil)
+	if err != nil {
+		return nil, err
+	}
+
+	resp, err := s.client.Do(ctx, req, nil)
+
+	return resp, err
+}
@@ -0,0 +1,236 @@
+// Copyright 2020 MongoDB Inc
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package cloudmanager
+
+import (
+	"fmt"
+	"net/http"
+	"testing"
+
+	"github.com/go-test/deep"
+	"github.com/mongodb/go-client-mongodb-atlas/mongodbatlas"
+)
+
+func TestGlobalAccessList_List(t *testing.T) {
+	setup()
+	defer teardown()
+
+	mux.HandleFunc("/admin/accessList", func(w http.ResponseWriter, r *http.Request) {
+		testMethod(t, r, http.MethodGet)
+		_, _ = fmt.Fprint(w, `{
+			"links": [],
+			"results": [{
+				"cidrBlock": "10.0.0.0/24",
+				"created": "2020-09-07T15:23:25Z",
+				"description": "internal network",
+				"id": "5f5650e8ac3a7b3a0a1e6f5c",
+				"links": [],
+				"type": "GLOBAL_ROLE",
+				"updated": "2020-09-07T15:23:25Z"
+			}],
+			"totalCount": 1
+		}`)
+	})
+
+	entries, _, err := client.GlobalAccessList.List(ctx, nil)
+	if err != nil {
+		t.Fatalf("GlobalAccessList.List returned error: %v", err)
+	}
+
+	expected := &GlobalAccessListEntries{
+		Links: []*mongodbatlas.Link{},
+		Results: []*GlobalAccessListEntry{
+			{
+				ID:          "5f5650e8ac3a7b3a0a1e6f5c",
+				CIDRBlock:   "10.0.0.0/24",
+				Created:     "2020-09-07T15:23:25Z",
+				Description: "internal network",
+				Links:       []*mongodbatlas.Link{},
+				Type:        "GLOBAL_ROLE",
+				Updated:     "2020-09-07T15:23:25Z",
+			},
+		},
+		TotalCount: 1,
+	}
+
+	if diff := deep.Equal(entries, expected); diff != nil {
+		t.Error(diff)
+	}
+}
+
+func TestGlobalAccessList_Get(t *testing.T) {
+	setup()
+	defer teardown()
+
+	entryID := "5f5650e8ac3a7b3a0a1e6f5c"
+
+	mux.HandleFunc(fmt.Sprintf("/admin/accessList/%s", entryID), func(w http.ResponseWriter, r *http.Request) {
+		testMethod(t, r, http.MethodGet)
+		_, _ = fmt.Fprint(w, `{
+			"cidrBlock": "10.0.0.0/24",
+			"created": "2020-09-07T15:23:25Z",
+			"description": "internal network",
+			"id": "5f5650e8ac3a7b3a0a1e6f5c",
+			"links": [],
+			"type": "GLOBAL_ROLE",
+			"updated": "2020-09-07T15:23:25Z"
+		}`)
+	})
+
+	entry, _, err := client.GlobalAccessList.Get(ctx, entryID)
+	if err != nil {
+		t.Fatalf("GlobalAccessList.Get returned error: %v", err)
+	}
+
+	expected := &GlobalAccessListEntry{
+		ID:          "5f5650e8ac3a7b3a0a1e6f5c",
+		CIDRBlock:   "10.0.0.0/24",
+		Created:     "2020-09-07T15:23:25Z",
+		Description: "internal network",
+		Links:       []*mongodbatlas.Link{},
+		Type:        "GLOBAL_ROLE",
+		Updated:     "2020-09-07T15:23:25Z",
+	}
+
+	if diff := deep.Equal(entry, expected); diff != nil {
+		t.Error(diff)
+	}
+}
+
+func TestGlobalAccessList_Create(t *testing.T) {
+	setup()
+	defer teardown()
+
+	mux.HandleFunc("/admin/accessList", func(w http.ResponseWriter, r *http.Request) {
+		testMethod(t, r, http.MethodPost)
+
+		expected := map[string]interface{}{
+			"cidrBlock":   "10.0.0.0/24",
+			"description": "internal network",
+		}
+		if diff := deep.Equal(decodeBody(t, r), expected); diff != nil {
+			t.Error(diff)
+		}
+
+		_, _ = fmt.Fprint(w, `{
+			"cidrBlock": "10.0.0.0/24",
+			"created": "2020-09-07T15:23:25Z",
+			"description": "internal network",
+			"id": "5f5650e8ac3a7b3a0a1e6f5c",
+			"links": [],
+			"type": "GLOBAL_ROLE",
+			"updated": "2020-09-07T15:23:25Z"
+		}`)
+	})
+
+	entry, _, err := client.GlobalAccessList.Create(ctx, &GlobalAccessListEntry{CIDRBlock: "10.0.0.0/24", Description: "internal network"})
+	if err != nil {
+		t.Fatalf("GlobalAccessList.Create returned error: %v", err)
+	}
+
+	expected := &GlobalAccessListEntry{
+		ID:          "5f5650e8ac3a7b3a0a1e6f5c",
+		CIDRBlock:   "10.0.0.0/24",
+		Created:     "2020-09-07T15:23:25Z",
+		Description: "internal network",
+		Links:       []*mongodbatlas.Link{},
+		Type:        "GLOBAL_ROLE",
+		Updated:     "2020-09-07T15:23:25Z",
+	}
+
+	if diff := deep.Equal(entry, expected); diff != nil {
+		t.Error(diff)
+	}
+}
+
+func TestGlobalAccessList_Create_invalid(t *testing.T) {
+	setup()
+	defer teardown()
+
+	tests := map[string]*GlobalAccessListEntry{
+		"missing cidr":        {Description: "internal network"},
+		"ip address":          {CIDRBlock: "10.0.0.1", Description: "internal network"},
+		"missing description": {CIDRBlock: "10.0.0.0/24"},
+	}
+
+	for name, entry := range tests {
+		entry := entry
+		t.Run(name, func(t *testing.T) {
+			if _, _, err := client.GlobalAccessList.Create(ctx, entry); err == nil {
+				t.Error("expected an error")
+			}
+		})
+	}
+}
+
+func TestGlobalAccessList_Update(t *testing.T) {
+	setup()
+	defer teardown()
+
+	entryID := "5f5650e8ac3a7b3a0a1e6f5c"
+
+	mux.HandleFunc(fmt.Sprintf("/admin/accessList/%s", entryID), func(w http.ResponseWriter, r *http.Request) {
+		testMethod(t, r, http.MethodPatch)
+
+		expected := map[string]interface{}{"description": "internal network"}
+		if diff := deep.Equal(decodeBody(t, r), expected); diff != nil {
+			t.Error(diff)
+		}
+
+		_, _ = fmt.Fprint(w, `{
+			"cidrBlock": "10.0.0.0/24",
+			"created": "2020-09-07T15:23:25Z",
+			"description": "internal network",
+			"id": "5f5650e8ac3a7b3a0a1e6f5c",
+			"links": [],
+			"type": "GLOBAL_ROLE",
+			"updated": "2020-09-07T15:23:25Z"
+		}`)
+	})
+
+	entry, _, err := client.GlobalAccessList.Update(ctx, entryID, &GlobalAccessListEntry{Description: "internal network"})
+	if err != nil {
+		t.Fatalf("GlobalAccessList.Update returned error: %v", err)
+	}
+
+	expected := &GlobalAccessListEntry{
+		ID:          "5f5650e8ac3a7b3a0a1e6f5c",
+		CIDRBlock:   "10.0.0.0/24",
+		Created:     "2020-09-07T15:23:25Z",
+		Description: "internal network",
+		Links:       []*mongodbatlas.Link{},
+		Type:        "GLOBAL_ROLE",
+		Updated:     "2020-09-07T15:23:25Z",
+	}
+
+	if diff := deep.Equal(entry, expected); diff != nil {
+		t.Error(diff)
+	}
+}
+
+func TestGlobalAccessList_Delete(t *testing.T) {
+	setup()
+	defer teardown()
+
+	entryID := "5f5650e8ac3a7b3a0a1e6f5c"
+
+	mux.HandleFunc(fmt.Sprintf("/admin/accessList/%s", entryID), func(w http.ResponseWriter, r *http.Request) {
+		testMethod(t, r, http.MethodDelete)
+	})
+
+	if _, err := client.GlobalAccessList.Delete(ctx, entryID); err != nil {
+		t.Fatalf("GlobalAccessList.Delete returned error: %v", err)
+	}
+}
@@ -0,0 +1,173 @@
+// Copyright 2020 MongoDB Inc
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package cloudmanager
+
+import (
+	"context"
+	"fmt"
+	"net/http"
+
+	atlas "github.com/mongodb/go-client-mongodb-atlas/mongodbatlas"
+)
+
+const (
+	globalAPIKeysBasePath = "admin/apiKeys"
+)
+
+// GlobalAPIKeysService is an interface for interfacing with the Global Programmatic API Keys
+// endpoints of the MongoDB Ops Manager API.
+// See more: https://docs.opsmanager.mongodb.com/current/reference/api/global-api-keys/
+type GlobalAPIKeysService interface {
+	List(context.Context, *atlas.ListOptions) (*APIKeys, *atlas.Response, error)
+	Get(context.Context, string) (*APIKey, *atlas.Response, error)
+	Create(context.Context, *APIKeyInput) (*APIKey, *atlas.Response, error)
+	Update(context.Context, string, *APIKeyInput) (*APIKey, *atlas.Response, error)
+	Delete(context.Context, string) (*atlas.Response, error)
+}
+
+// GlobalAPIKeysServiceOp handles communication with the Global Programmatic API Keys related methods of the
+// MongoDB Ops Manager API
+type GlobalAPIKeysServiceOp struct {
+	client *Client
+}
+
+var _ GlobalAPIKeysService = &GlobalAPIKeysServiceOp{}
+
+// List gets the global programmatic API keys.
+// See more: https://docs.opsmanager.mongodb.com/current/reference/api/api-keys/global/get-all-global-api-keys/
+func (s *GlobalAPIKeysServiceOp) List(ctx context.Context, opts *atlas.ListOptions) (*APIKeys, *atlas.Response, error) {
+	path, err := setListOptions(globalAPIKeysBasePath, opts)
+	if err != nil {
+		return nil, nil, err
+	}
+
+	req, err := s.client.NewRequest(ctx, http.MethodGet, path, nil)
+	if err != nil {
+		return nil, nil, err
+	}
+
+	root := new(APIKeys)
+	resp, err := s.client.Do(ctx, req, root)
+	if err != nil {
+		return nil, resp, err
+	}
+
+	if l := root.Links; l != nil {
+		resp.Links = l
+	}
+
+	return root, resp, nil
+}
+
+// Get gets a single global programmatic API key.
+// See more: https://docs.opsmanager.mongodb.com/current/reference/api/api-keys/global/get-one-global-api-key/
+func (s *GlobalAPIKeysServiceOp) Get(ctx context.Context, keyID string) (*APIKey, *atlas.Response, error) {
+	if keyID == "" {
+		return nil, nil, atlas.NewArgError("keyID", "must be set")
+	}
+
+	path := fmt.Sprintf("%s/%s", globalAPIKeysBasePath, keyID)
+
+	req, err := s.client.NewRequest(ctx, http.MethodGet, path, nil)
+	if err != nil {
+		return nil, nil, err
+	}
+
+	root := new(APIKey)
+	resp, err := s.client.Do(ctx, req, root)
+	if err != nil {
+		return nil, resp, err
+	}
+
+	return root, resp, err
+}
+
+// Create creates a global programmatic API key with global roles; the description and roles are required.
+// See more: https://docs.opsmanager.mongodb.com/current/reference/api/api-keys/global/create-one-global-api-key/
+func (s *GlobalAPIKeysServiceOp) Create(ctx context.Context, createRequest *APIKeyInput) (*APIKey, *atlas.Response, error) {
+	if createRequest == nil {
+		return nil, nil, atlas.NewArgError("createRequest", "cannot be nil")
+	}
+	if createRequest.Desc == "" {
+		return nil, nil, atlas.NewArgError("desc", "must be set")
+	}
+	if len(createRequest.Roles) == 0 {
+		return nil, nil, atlas.NewArgError("roles", "must be set")
+	}
+	if err := validateRoleNames(createRequest.Roles, GlobalRoles, "global API keys can only be granted global roles"); err != nil {
+		return nil, nil, err
+	}
+
+	req, err := s.client.NewRequest(ctx, http.MethodPost, globalAPIKeysBasePath, createRequest)
+	if err != nil {
+		return nil, nil, err
+	}
+
+	root := new(APIKey)
+	resp, err := s.client.Do(ctx, req, root)
+	if err != nil {
+		return nil, resp, err
+	}
+
+	return root, resp, err
+}
+
+// Update updates the description or global roles of a global programmatic API key; only the specified fields are modified.
+// See more: https://docs.opsmanager.mongodb.com/current/reference/api/api-keys/global/update-one-global-api-key/
+func (s *GlobalAPIKeysServiceOp) Update(ctx context.Context, keyID string, updateRequest *APIKeyInput) (*APIKey, *atlas.Response, error) {
+	if keyID == "" {
+		return nil, nil, atlas.NewArgError("keyID", "must be set")
+	}
+	if updateRequest == nil {
+		return nil, nil, atlas.NewArgError("updateRequest", "cannot be nil")
+	}
+	if err := validateRoleNames(updateRequest.Roles, GlobalRoles, "global API keys can only be granted global roles"); err != nil {
+		return nil, nil, err
+	}
+
+	path := fmt.Sprintf("%s/%s", globalAPIKeysBasePath, keyID)
+
+	req, err := s.client.NewRequest(ctx, http.MethodPatch, path, updateRequest)
+	if err != nil {
+		return nil, nil, err
+	}
+
+	root := new(APIKey)
+	resp, err := s.client.Do(ctx, req, root)
+	if err != nil {
+		return nil, resp, err
+	}
+
+	return root, resp, err
+}
+
+// Delete deletes a global programmatic API key.
+// See more: https://docs.opsmanager.mongodb.com/current/reference/api/api-keys/global/delete-one-global-api-key/
+func (s *GlobalAPIKeysServiceOp) Delete(ctx context.Context, keyID string) (*atlas.Response, error) {
+	if keyID == "" {
+		return nil, atlas.NewArgError("keyID", "must be set")
+	}
+
+	path := fmt.Sprintf("%s/%s", globalAPIKeysBasePath, keyID)
+
+	req, err := s.client.NewRequest(ctx, http.MethodDelete, path, nil)
+	if err != nil {
+		return nil, err
+	}
+
+	resp, err := s.client.Do(ctx, req, nil)
+
+	return resp, err
+}
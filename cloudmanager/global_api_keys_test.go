@@ -0,0 +1,219 @@
+// Copyright 2020 MongoDB Inc
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package cloudmanager
+
+import (
+	"fmt"
+	"net/http"
+	"testing"
+
+	"github.com/go-test/deep"
+	"github.com/mongodb/go-client-mongodb-atlas/mongodbatlas"
+)
+
+func TestGlobalAPIKeys_List(t *testing.T) {
+	setup()
+	defer teardown()
+
+	mux.HandleFunc("/admin/apiKeys", func(w http.ResponseWriter, r *http.Request) {
+		testMethod(t, r, http.MethodGet)
+		_, _ = fmt.Fprint(w, `{
+			"links": [],
+			"results": [{
+				"desc": "global automation",
+				"id": "5d1d12c087d9d63e6d682438",
+				"links": [],
+				"privateKey": "********-****-****-c4e26334754f",
+				"publicKey": "zmmrboas",
+				"roles": [{"roleName": "GLOBAL_READ_ONLY"}]
+			}],
+			"totalCount": 1
+		}`)
+	})
+
+	keys, _, err := client.GlobalAPIKeys.List(ctx, nil)
+	if err != nil {
+		t.Fatalf("GlobalAPIKeys.List returned error: %v", err)
+	}
+
+	expected := &APIKeys{
+		Links: []*mongodbatlas.Link{},
+		Results: []*APIKey{
+			{
+				ID:         "5d1d12c087d9d63e6d682438",
+				Desc:       "global automation",
+				Links:      []*mongodbatlas.Link{},
+				PrivateKey: "********-****-****-c4e26334754f",
+				PublicKey:  "zmmrboas",
+				Roles:      []*UserRole{{RoleName: string(RoleGlobalReadOnly)}},
+			},
+		},
+		TotalCount: 1,
+	}
+
+	if diff := deep.Equal(keys, expected); diff != nil {
+		t.Error(diff)
+	}
+}
+
+func TestGlobalAPIKeys_Get(t *testing.T) {
+	setup()
+	defer teardown()
+
+	keyID := "5d1d12c087d9d63e6d682438"
+
+	mux.HandleFunc(fmt.Sprintf("/admin/apiKeys/%s", keyID), func(w http.ResponseWriter, r *http.Request) {
+		testMethod(t, r, http.MethodGet)
+		_, _ = fmt.Fprint(w, `{
+			"desc": "global automation",
+			"id": "5d1d12c087d9d63e6d682438",
+			"links": [],
+			"privateKey": "********-****-****-c4e26334754f",
+			"publicKey": "zmmrboas",
+			"roles": [{"roleName": "GLOBAL_READ_ONLY"}]
+		}`)
+	})
+
+	key, _, err := client.GlobalAPIKeys.Get(ctx, keyID)
+	if err != nil {
+		t.Fatalf("GlobalAPIKeys.Get returned error: %v", err)
+	}
+
+	expected := &APIKey{
+		ID:         "5d1d12c087d9d63e6d682438",
+		Desc:       "global automation",
+		Links:      []*mongodbatlas.Link{},
+		PrivateKey: "********-****-****-c4e26334754f",
+		PublicKey:  "zmmrboas",
+		Roles:      []*UserRole{{RoleName: string(RoleGlobalReadOnly)}},
+	}
+
+	if diff := deep.Equal(key, expected); diff != nil {
+		t.Error(diff)
+	}
+}
+
+func TestGlobalAPIKeys_Create(t *testing.T) {
+	setup()
+	defer teardown()
+
+	mux.HandleFunc("/admin/apiKeys", func(w http.ResponseWriter, r *http.Request) {
+		testMethod(t, r, http.MethodPost)
+
+		expected := map[string]interface{}{
+			"desc":  "global automation",
+			"roles": []interface{}{"GLOBAL_READ_ONLY"},
+		}
+		if diff := deep.Equal(decodeBody(t, r), expected); diff != nil {
+			t.Error(diff)
+		}
+
+		_, _ = fmt.Fprint(w, `{
+			"desc": "global automation",
+			"id": "5d1d12c087d9d63e6d682438",
+			"links": [],
+			"privateKey": "********-****-****-c4e26334754f",
+			"publicKey": "zmmrboas",
+			"roles": [{"roleName": "GLOBAL_READ_ONLY"}]
+		}`)
+	})
+
+	key, _, err := client.GlobalAPIKeys.Create(ctx, &APIKeyInput{Desc: "global automation", Roles: []RoleName{RoleGlobalReadOnly}})
+	if err != nil {
+		t.Fatalf("GlobalAPIKeys.Create returned error: %v", err)
+	}
+
+	expected := &APIKey{
+		ID:         "5d1d12c087d9d63e6d682438",
+		Desc:       "global automation",
+		Links:      []*mongodbatlas.Link{},
+		PrivateKey: "********-****-****-c4e26334754f",
+		PublicKey:  "zmmrboas",
+		Roles:      []*UserRole{{RoleName: string(RoleGlobalReadOnly)}},
+	}
+
+	if diff := deep.Equal(key, expected); diff != nil {
+		t.Error(diff)
+	}
+}
+
+func TestGlobalAPIKeys_Create_orgRole(t *testing.T) {
+	setup()
+	defer teardown()
+
+	_, _, err := client.GlobalAPIKeys.Create(ctx, &APIKeyInput{Desc: "global automation", Roles: []RoleName{RoleOrgOwner}})
+
+	if _, ok := err.(*InvalidRoleError); !ok {
+		t.Fatalf("expected an *InvalidRoleError, got %v", err)
+	}
+}
+
+func TestGlobalAPIKeys_Update(t *testing.T) {
+	setup()
+	defer teardown()
+
+	keyID := "5d1d12c087d9d63e6d682438"
+
+	mux.HandleFunc(fmt.Sprintf("/admin/apiKeys/%s", keyID), func(w http.ResponseWriter, r *http.Request) {
+		testMethod(t, r, http.MethodPatch)
+
+		expected := map[string]interface{}{"roles": []interface{}{"GLOBAL_READ_ONLY"}}
+		if diff := deep.Equal(decodeBody(t, r), expected); diff != nil {
+			t.Error(diff)
+		}
+
+		_, _ = fmt.Fprint(w, `{
+			"desc": "global automation",
+			"id": "5d1d12c087d9d63e6d682438",
+			"links": [],
+			"privateKey": "********-****-****-c4e26334754f",
+			"publicKey": "zmmrboas",
+			"roles": [{"roleName": "GLOBAL_READ_ONLY"}]
+		}`)
+	})
+
+	key, _, err := client.GlobalAPIKeys.Update(ctx, keyID, &APIKeyInput{Roles: []RoleName{RoleGlobalReadOnly}})
+	if err != nil {
+		t.Fatalf("GlobalAPIKeys.Update returned error: %v", err)
+	}
+
+	expected := &APIKey{
+		ID:         "5d1d12c087d9d63e6d682438",
+		Desc:       "global automation",
+		Links:      []*mongodbatlas.Link{},
+		PrivateKey: "********-****-****-c4e26334754f",
+		PublicKey:  "zmmrboas",
+		Roles:      []*UserRole{{RoleName: string(RoleGlobalReadOnly)}},
+	}
+
+	if diff := deep.Equal(key, expected); diff != nil {
+		t.Error(diff)
+	}
+}
+
+func TestGlobalAPIKeys_Delete(t *testing.T) {
+	setup()
+	defer teardown()
+
+	keyID := "5d1d12c087d9d63e6d682438"
+
+	mux.HandleFunc(fmt.Sprintf("/admin/apiKeys/%s", keyID), func(w http.ResponseWriter, r *http.Request) {
+		testMethod(t, r, http.MethodDelete)
+	})
+
+	if _, err := client.GlobalAPIKeys.Delete(ctx, keyID); err != nil {
+		t.Fatalf("GlobalAPIKeys.Delete returned error: %v", err)
+	}
+}
@@ -0,0 +1,269 @@
+// Copyright 2020 MongoDB Inc
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package cloudmanager
+
+import (
+	"context"
+	"fmt"
+	"net/http"
+
+	atlas "github.com/mongodb/go-client-mongodb-atlas/mongodbatlas"
+)
+
+const (
+	hostsBasePath = "groups/%s/hosts"
+)
+
+// HostsService is an interface for interfacing with the Hosts
+// endpoints of the MongoDB Cloud Manager API.
+// See more: https://docs.cloudmanager.mongodb.com/reference/api/hosts/
+type HostsService interface {
+	List(context.Context, string, *HostListOptions) (*Hosts, *atlas.Response, error)
+	Get(context.Context, string, string) (*Host, *atlas.Response, error)
+	GetByHostname(context.Context, string, string, int) (*Host, *atlas.Response, error)
+	Create(context.Context, string, *Host) (*Host, *atlas.Response, error)
+	Update(context.Context, string, string, *Host) (*Host, *atlas.Response, error)
+	Delete(context.Context, string, string) (*atlas.Response, error)
+}
+
+// HostsServiceOp handles communication with the Hosts related methods of the
+// MongoDB Cloud Manager API
+type HostsServiceOp struct {
+	client *Client
+}
+
+var _ HostsService = &HostsServiceOp{}
+
+// Host represents a MongoDB process monitored by Cloud Manager.
+// The credentials and the *bool settings can be specified when adding or updating a host.
+type Host struct {
+	ID                 string        `json:"id,omitempty"`
+	Aliases            []string      `json:"aliases,omitempty"`
+	AlertsEnabled      *bool         `json:"alertsEnabled,omitempty"`
+	AuthMechanismName  string        `json:"authMechanismName,omitempty"`
+	ClusterID          string        `json:"clusterId,omitempty"`
+	Created            string        `json:"created,omitempty"`
+	Deactivated        bool          `json:"deactivated,omitempty"`
+	GroupID            string        `json:"groupId,omitempty"`
+	HasStartupWarnings bool          `json:"hasStartupWarnings,omitempty"`
+	Hidden             bool          `json:"hidden,omitempty"`
+	HiddenSecondary    bool          `json:"hiddenSecondary,omitempty"`
+	HostEnabled        bool          `json:"hostEnabled,omitempty"`
+	Hostname           string        `json:"hostname,omitempty"`
+	IPAddress          string        `json:"ipAddress,omitempty"`
+	JournalingEnabled  bool          `json:"journalingEnabled,omitempty"`
+	LastDataSizeBytes  float64       `json:"lastDataSizeBytes,omitempty"`
+	LastIndexSizeBytes float64       `json:"lastIndexSizeBytes,omitempty"`
+	LastPing           string        `json:"lastPing,omitempty"`
+	LastRestart        string        `json:"lastRestart,omitempty"`
+	Links              []*atlas.Link `json:"links,omitempty"`
+	LogsEnabled        *bool         `json:"logsEnabled,omitempty"`
+	LowULimit          bool          `json:"lowULimit,omitempty"`
+	MuninEnabled       *bool         `json:"muninEnabled,omitempty"`
+	MuninPort          int           `json:"muninPort,omitempty"`
+	Password           string        `json:"password,omitempty"`
+	Port               int           `json:"port,omitempty"`
+	ProfilerEnabled    *bool         `json:"profilerEnabled,omitempty"`
+	ReplicaSetName     string        `json:"replicaSetName,omitempty"`
+	ReplicaStateName   string        `json:"replicaStateName,omitempty"`
+	ShardName          string        `json:"shardName,omitempty"`
+	SlaveDelaySec      int           `json:"slaveDelaySec,omitempty"`
+	SSLEnabled         *bool         `json:"sslEnabled,omitempty"`
+	TypeName           string        `json:"typeName,omitempty"`
+	UptimeMsec         int64         `json:"uptimeMsec,omitempty"`
+	Username           string        `json:"username,omitempty"`
+	Version            string        `json:"version,omitempty"`
+}
+
+// Hosts represents a array of hosts
+type Hosts struct {
+	Links      []*atlas.Link `json:"links"`
+	Results    []*Host       `json:"results"`
+	TotalCount int           `json:"totalCount"`
+}
+
+// HostListOptions filter the hosts returned by List
+type HostListOptions struct {
+	ClusterID string `url:"clusterId,omitempty"`
+
+	atlas.ListOptions
+}
+
+// List gets all hosts in a project, optionally filtered by cluster.
+// See more: https://docs.cloudmanager.mongodb.com/reference/api/hosts/get-all-hosts-in-group/
+func (s *HostsServiceOp) List(ctx context.Context, projectID string, opts *HostListOptions) (*Hosts, *atlas.Response, error) {
+	if projectID == "" {
+		return nil, nil, atlas.NewArgError("projectID", "must be set")
+	}
+
+	basePath := fmt.Sprintf(hostsBasePath, projectID)
+	path, err := setListOptions(basePath, opts)
+	if err != nil {
+		return nil, nil, err
+	}
+
+	req, err := s.client.NewRequest(ctx, http.MethodGet, path, nil)
+	if err != nil {
+		return nil, nil, err
+	}
+
+	root := new(Hosts)
+	resp, err := s.client.Do(ctx, req, root)
+	if err != nil {
+		return nil, resp, err
+	}
+
+	if l := root.Links; l != nil {
+		resp.Links = l
+	}
+
+	return root, resp, nil
+}
+
+// Get gets a single host by its ID.
+// See more: https://docs.cloudmanager.mongodb.com/reference/api/hosts/get-one-host-by-id/
+func (s *HostsServiceOp) Get(ctx context.Context, projectID, hostID string) (*Host, *atlas.Response, error) {
+	if projectID == "" {
+		return nil, nil, atlas.NewArgError("projectID", "must be set")
+	}
+	if hostID == "" {
+		return nil, nil, atlas.NewArgError("hostID", "must be set")
+	}
+
+	basePath := fmt.Sprintf(hostsBasePath, projectID)
+	path := fmt.Sprintf("%s/%s", basePath, hostID)
+
+	req, err := s.client.NewRequest(ctx, http.MethodGet, path, nil)
+	if err != nil {
+		return nil, nil, err
+	}
+
+	root := new(Host)
+	resp, err := s.client.Do(ctx, req, root)
+	if err != nil {
+		return nil, resp, err
+	}
+
+	return root, resp, err
+}
+
+// GetByHostname gets a single host by its hostname and port.
+// See more: https://docs.cloudmanager.mongodb.com/reference/api/hosts/get-one-host-by-hostname-port/
+func (s *HostsServiceOp) GetByHostname(ctx context.Context, projectID, hostname string, port int) (*Host, *atlas.Response, error) {
+	if projectID == "" {
+		return nil, nil, atlas.NewArgError("projectID", "must be set")
+	}
+	if hostname == "" {
+		return nil, nil, atlas.NewArgError("hostname", "must be set")
+	}
+	if port <= 0 {
+		return nil, nil, atlas.NewArgError("port", "must be a positive number")
+	}
+
+	basePath := fmt.Sprintf(hostsBasePath, projectID)
+	path := fmt.Sprintf("%s/byName/%s:%d", basePath, hostname, port)
+
+	req, err := s.client.NewRequest(ctx, http.MethodGet, path, nil)
+	if err != nil {
+		return nil, nil, err
+	}
+
+	root := new(Host)
+	resp, err := s.client.Do(ctx, req, root)
+	if err != nil {
+		return nil, resp, err
+	}
+
+	return root, resp, err
+}
+
+// Create adds a monitoring only host to a project.
+// See more: https://docs.cloudmanager.mongodb.com/reference/api/hosts/create-one-host/
+func (s *HostsServiceOp) Create(ctx context.Context, projectID string, createRequest *Host) (*Host, *atlas.Response, error) {
+	if projectID == "" {
+		return nil, nil, atlas.NewArgError("projectID", "must be set")
+	}
+	if createRequest == nil {
+		return nil, nil, atlas.NewArgError("createRequest", "cannot be nil")
+	}
+
+	path := fmt.Sprintf(hostsBasePath, projectID)
+
+	req, err := s.client.NewRequest(ctx, http.MethodPost, path, createRequest)
+	if err != nil {
+		return nil, nil, err
+	}
+
+	root := new(Host)
+	resp, err := s.client.Do(ctx, req, root)
+	if err != nil {
+		return nil, resp, err
+	}
+
+	return root, resp, err
+}
+
+// Update updates a host's credentials and monitoring settings; only the specified fields are modified.
+// See more: https://docs.cloudmanager.mongodb.com/reference/api/hosts/update-one-host/
+func (s *HostsServiceOp) Update(ctx context.Context, projectID, hostID string, updateRequest *Host) (*Host, *atlas.Response, error) {
+	if projectID == "" {
+		return nil, nil, atlas.NewArgError("projectID", "must be set")
+	}
+	if hostID == "" {
+		return nil, nil, atlas.NewArgError("hostID", "must be set")
+	}
+	if updateRequest == nil {
+		return nil, nil, atlas.NewArgError("updateRequest", "cannot be nil")
+	}
+
+	basePath := fmt.Sprintf(hostsBasePath, projectID)
+	path := fmt.Sprintf("%s/%s", basePath, hostID)
+
+	req, err := s.client.NewRequest(ctx, http.MethodPatch, path, updateRequest)
+	if err != nil {
+		return nil, nil, err
+	}
+
+	root := new(Host)
+	resp, err := s.client.Do(ctx, req, root)
+	if err != nil {
+		return nil, resp, err
+	}
+
+	return root, resp, err
+}
+
+// Delete stops monitoring a host.
+// See more: https://docs.cloudmanager.mongodb.com/reference/api/hosts/delete-one-host/
+func (s *HostsServiceOp) Delete(ctx context.Context, projectID, hostID string) (*atlas.Response, error) {
+	if projectID == "" {
+		return nil, atlas.NewArgError("projectID", "must be set")
+	}
+	if hostID == "" {
+		return nil, atlas.NewArgError("hostID", "must be set")
+	}
+
+	basePath := fmt.Sprintf(hostsBasePath, projectID)
+	path := fmt.Sprintf("%s/%s", basePath, hostID)
+
+	req, err := s.client.NewRequest(ctx, http.MethodDelete, path, nil)
+	if err != nil {
+		return nil, err
+	}
+
+	resp, err := s.client.Do(ctx, req, nil)
+
+	return resp, err
+}
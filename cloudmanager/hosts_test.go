@@ -0,0 +1,529 @@
+// Copyright 2020 MongoDB Inc
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package cloudmanager
+
+import (
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"net/url"
+	"testing"
+
+	"github.com/go-test/deep"
+	"github.com/mongodb/go-client-mongodb-atlas/mongodbatlas"
+)
+
+func TestHosts_List(t *testing.T) {
+	setup()
+	defer teardown()
+
+	projectID := "5a0a1e7e0f2912c554080adc"
+
+	mux.HandleFunc(fmt.Sprintf("/groups/%s/hosts", projectID), func(w http.ResponseWriter, r *http.Request) {
+		testMethod(t, r, http.MethodGet)
+		testQuery(t, r, url.Values{
+			"clusterId":    {"5a0a1e7e0f2912c554080ae1"},
+			"pageNum":      {"2"},
+			"itemsPerPage": {"1"},
+		})
+		_, _ = fmt.Fprint(w, `{
+			"links": [{
+				"href": "https://cloud.mongodb.com/api/public/v1.0/groups/5a0a1e7e0f2912c554080adc/hosts?pageNum=2&itemsPerPage=1",
+				"rel": "self"
+			}],
+			"results": [{
+				"alertsEnabled": true,
+				"authMechanismName": "SCRAM-SHA-1",
+				"clusterId": "5a0a1e7e0f2912c554080ae1",
+				"created": "2016-03-09T18:19:37Z",
+				"groupId": "5a0a1e7e0f2912c554080adc",
+				"hasStartupWarnings": false,
+				"hidden": false,
+				"hostEnabled": true,
+				"hostname": "host1.example.com",
+				"id": "22e3e3fbd8d7cd6f8ab2d7f1ff65d44b",
+				"ipAddress": "127.0.0.1",
+				"journalingEnabled": false,
+				"lastDataSizeBytes": 633208918,
+				"lastIndexSizeBytes": 101420524,
+				"lastPing": "2016-08-18T11:23:41Z",
+				"links": [{
+					"href": "https://cloud.mongodb.com/api/public/v1.0/groups/5a0a1e7e0f2912c554080adc/hosts/22e3e3fbd8d7cd6f8ab2d7f1ff65d44b",
+					"rel": "self"
+				}],
+				"logsEnabled": false,
+				"lowULimit": false,
+				"port": 27017,
+				"profilerEnabled": false,
+				"replicaSetName": "rs0",
+				"replicaStateName": "SECONDARY",
+				"sslEnabled": true,
+				"typeName": "REPLICA_SECONDARY",
+				"uptimeMsec": 1827300394,
+				"username": "mongodb",
+				"version": "4.2.2"
+			}],
+			"totalCount": 2
+		}`)
+	})
+
+	opts := &HostListOptions{
+		ClusterID:   "5a0a1e7e0f2912c554080ae1",
+		ListOptions: mongodbatlas.ListOptions{PageNum: 2, ItemsPerPage: 1},
+	}
+	hosts, _, err := client.Hosts.List(ctx, projectID, opts)
+	if err != nil {
+		t.Fatalf("Hosts.List returned error: %v", err)
+	}
+
+	expected := &Hosts{
+		Links: []*mongodbatlas.Link{
+			{
+				Href: "https://cloud.mongodb.com/api/public/v1.0/groups/5a0a1e7e0f2912c554080adc/hosts?pageNum=2&itemsPerPage=1",
+				Rel:  "self",
+			},
+		},
+		Results: []*Host{
+			{
+				AlertsEnabled:      boolPtr(true),
+				AuthMechanismName:  "SCRAM-SHA-1",
+				ClusterID:          "5a0a1e7e0f2912c554080ae1",
+				Created:            "2016-03-09T18:19:37Z",
+				GroupID:            "5a0a1e7e0f2912c554080adc",
+				HostEnabled:        true,
+				Hostname:           "host1.example.com",
+				ID:                 "22e3e3fbd8d7cd6f8ab2d7f1ff65d44b",
+				IPAddress:          "127.0.0.1",
+				LastDataSizeBytes:  633208918,
+				LastIndexSizeBytes: 101420524,
+				LastPing:           "2016-08-18T11:23:41Z",
+				Links: []*mongodbatlas.Link{
+					{
+						Href: "https://cloud.mongodb.com/api/public/v1.0/groups/5a0a1e7e0f2912c554080adc/hosts/22e3e3fbd8d7cd6f8ab2d7f1ff65d44b",
+						Rel:  "self",
+					},
+				},
+				LogsEnabled:      boolPtr(false),
+				Port:             27017,
+				ProfilerEnabled:  boolPtr(false),
+				ReplicaSetName:   "rs0",
+				ReplicaStateName: "SECONDARY",
+				SSLEnabled:       boolPtr(true),
+				TypeName:         "REPLICA_SECONDARY",
+				UptimeMsec:       1827300394,
+				Username:         "mongodb",
+				Version:          "4.2.2",
+			},
+		},
+		TotalCount: 2,
+	}
+
+	if diff := deep.Equal(hosts, expected); diff != nil {
+		t.Error(diff)
+	}
+}
+
+func TestHosts_Get(t *testing.T) {
+	setup()
+	defer teardown()
+
+	projectID := "5a0a1e7e0f2912c554080adc"
+	hostID := "22e3e3fbd8d7cd6f8ab2d7f1ff65d44b"
+
+	mux.HandleFunc(fmt.Sprintf("/groups/%s/hosts/%s", projectID, hostID), func(w http.ResponseWriter, r *http.Request) {
+		testMethod(t, r, http.MethodGet)
+		_, _ = fmt.Fprint(w, `{
+			"alertsEnabled": true,
+			"authMechanismName": "SCRAM-SHA-1",
+			"clusterId": "5a0a1e7e0f2912c554080ae1",
+			"created": "2016-03-09T18:19:37Z",
+			"groupId": "5a0a1e7e0f2912c554080adc",
+			"hasStartupWarnings": false,
+			"hidden": false,
+			"hostEnabled": true,
+			"hostname": "host1.example.com",
+			"id": "22e3e3fbd8d7cd6f8ab2d7f1ff65d44b",
+			"ipAddress": "127.0.0.1",
+			"journalingEnabled": false,
+			"lastDataSizeBytes": 633208918,
+			"lastIndexSizeBytes": 101420524,
+			"lastPing": "2016-08-18T11:23:41Z",
+			"links": [{
+				"href": "https://cloud.mongodb.com/api/public/v1.0/groups/5a0a1e7e0f2912c554080adc/hosts/22e3e3fbd8d7cd6f8ab2d7f1ff65d44b",
+				"rel": "self"
+			}],
+			"logsEnabled": false,
+			"lowULimit": false,
+			"port": 27017,
+			"profilerEnabled": false,
+			"replicaSetName": "rs0",
+			"replicaStateName": "SECONDARY",
+			"sslEnabled": true,
+			"typeName": "REPLICA_SECONDARY",
+			"uptimeMsec": 1827300394,
+			"username": "mongodb",
+			"version": "4.2.2"
+		}`)
+	})
+
+	host, _, err := client.Hosts.Get(ctx, projectID, hostID)
+	if err != nil {
+		t.Fatalf("Hosts.Get returned error: %v", err)
+	}
+
+	expected := &Host{
+		AlertsEnabled:      boolPtr(true),
+		AuthMechanismName:  "SCRAM-SHA-1",
+		ClusterID:          "5a0a1e7e0f2912c554080ae1",
+		Created:            "2016-03-09T18:19:37Z",
+		GroupID:            "5a0a1e7e0f2912c554080adc",
+		HostEnabled:        true,
+		Hostname:           "host1.example.com",
+		ID:                 "22e3e3fbd8d7cd6f8ab2d7f1ff65d44b",
+		IPAddress:          "127.0.0.1",
+		LastDataSizeBytes:  633208918,
+		LastIndexSizeBytes: 101420524,
+		LastPing:           "2016-08-18T11:23:41Z",
+		Links: []*mongodbatlas.Link{
+			{
+				Href: "https://cloud.mongodb.com/api/public/v1.0/groups/5a0a1e7e0f2912c554080adc/hosts/22e3e3fbd8d7cd6f8ab2d7f1ff65d44b",
+				Rel:  "self",
+			},
+		},
+		LogsEnabled:      boolPtr(false),
+		Port:             27017,
+		ProfilerEnabled:  boolPtr(false),
+		ReplicaSetName:   "rs0",
+		ReplicaStateName: "SECONDARY",
+		SSLEnabled:       boolPtr(true),
+		TypeName:         "REPLICA_SECONDARY",
+		UptimeMsec:       1827300394,
+		Username:         "mongodb",
+		Version:          "4.2.2",
+	}
+
+	if diff := deep.Equal(host, expected); diff != nil {
+		t.Error(diff)
+	}
+}
+
+func TestHosts_GetByHostname(t *testing.T) {
+	setup()
+	defer teardown()
+
+	projectID := "5a0a1e7e0f2912c554080adc"
+
+	mux.HandleFunc(fmt.Sprintf("/groups/%s/hosts/byName/host1.example.com:27017", projectID), func(w http.ResponseWriter, r *http.Request) {
+		testMethod(t, r, http.MethodGet)
+		_, _ = fmt.Fprint(w, `{
+			"alertsEnabled": true,
+			"authMechanismName": "SCRAM-SHA-1",
+			"clusterId": "5a0a1e7e0f2912c554080ae1",
+			"created": "2016-03-09T18:19:37Z",
+			"groupId": "5a0a1e7e0f2912c554080adc",
+			"hasStartupWarnings": false,
+			"hidden": false,
+			"hostEnabled": true,
+			"hostname": "host1.example.com",
+			"id": "22e3e3fbd8d7cd6f8ab2d7f1ff65d44b",
+			"ipAddress": "127.0.0.1",
+			"journalingEnabled": false,
+			"lastDataSizeBytes": 633208918,
+			"lastIndexSizeBytes": 101420524,
+			"lastPing": "2016-08-18T11:23:41Z",
+			"links": [{
+				"href": "https://cloud.mongodb.com/api/public/v1.0/groups/5a0a1e7e0f2912c554080adc/hosts/22e3e3fbd8d7cd6f8ab2d7f1ff65d44b",
+				"rel": "self"
+			}],
+			"logsEnabled": false,
+			"lowULimit": false,
+			"port": 27017,
+			"profilerEnabled": false,
+			"replicaSetName": "rs0",
+			"replicaStateName": "SECONDARY",
+			"sslEnabled": true,
+			"typeName": "REPLICA_SECONDARY",
+			"uptimeMsec": 1827300394,
+			"username": "mongodb",
+			"version": "4.2.2"
+		}`)
+	})
+
+	host, _, err := client.Hosts.GetByHostname(ctx, projectID, "host1.example.com", 27017)
+	if err != nil {
+		t.Fatalf("Hosts.GetByHostname returned error: %v", err)
+	}
+
+	expected := &Host{
+		AlertsEnabled:      boolPtr(true),
+		AuthMechanismName:  "SCRAM-SHA-1",
+		ClusterID:          "5a0a1e7e0f2912c554080ae1",
+		Created:            "2016-03-09T18:19:37Z",
+		GroupID:            "5a0a1e7e0f2912c554080adc",
+		HostEnabled:        true,
+		Hostname:           "host1.example.com",
+		ID:                 "22e3e3fbd8d7cd6f8ab2d7f1ff65d44b",
+		IPAddress:          "127.0.0.1",
+		LastDataSizeBytes:  633208918,
+		LastIndexSizeBytes: 101420524,
+		LastPing:           "2016-08-18T11:23:41Z",
+		Links: []*mongodbatlas.Link{
+			{
+				Href: "https://cloud.mongodb.com/api/public/v1.0/groups/5a0a1e7e0f2912c554080adc/hosts/22e3e3fbd8d7cd6f8ab2d7f1ff65d44b",
+				Rel:  "self",
+			},
+		},
+		LogsEnabled:      boolPtr(false),
+		Port:             27017,
+		ProfilerEnabled:  boolPtr(false),
+		ReplicaSetName:   "rs0",
+		ReplicaStateName: "SECONDARY",
+		SSLEnabled:       boolPtr(true),
+		TypeName:         "REPLICA_SECONDARY",
+		UptimeMsec:       1827300394,
+		Username:         "mongodb",
+		Version:          "4.2.2",
+	}
+
+	if diff := deep.Equal(host, expected); diff != nil {
+		t.Error(diff)
+	}
+}
+
+func TestHosts_Create(t *testing.T) {
+	setup()
+	defer teardown()
+
+	projectID := "5a0a1e7e0f2912c554080adc"
+	createRequest := &Host{
+		Hostname:          "host1.example.com",
+		Port:              27017,
+		AuthMechanismName: "SCRAM-SHA-1",
+		Username:          "mongodb",
+		Password:          "password",
+		SSLEnabled:        boolPtr(true),
+	}
+
+	mux.HandleFunc(fmt.Sprintf("/groups/%s/hosts", projectID), func(w http.ResponseWriter, r *http.Request) {
+		testMethod(t, r, http.MethodPost)
+
+		expected := map[string]interface{}{
+			"hostname":          "host1.example.com",
+			"port":              float64(27017),
+			"authMechanismName": "SCRAM-SHA-1",
+			"username":          "mongodb",
+			"password":          "password",
+			"sslEnabled":        true,
+		}
+		var v map[string]interface{}
+		if err := json.NewDecoder(r.Body).Decode(&v); err != nil {
+			t.Fatalf("decode json: %v", err)
+		}
+		if diff := deep.Equal(v, expected); diff != nil {
+			t.Error(diff)
+		}
+
+		_, _ = fmt.Fprint(w, `{
+			"alertsEnabled": true,
+			"authMechanismName": "SCRAM-SHA-1",
+			"clusterId": "5a0a1e7e0f2912c554080ae1",
+			"created": "2016-03-09T18:19:37Z",
+			"groupId": "5a0a1e7e0f2912c554080adc",
+			"hasStartupWarnings": false,
+			"hidden": false,
+			"hostEnabled": true,
+			"hostname": "host1.example.com",
+			"id": "22e3e3fbd8d7cd6f8ab2d7f1ff65d44b",
+			"ipAddress": "127.0.0.1",
+			"journalingEnabled": false,
+			"lastDataSizeBytes": 633208918,
+			"lastIndexSizeBytes": 101420524,
+			"lastPing": "2016-08-18T11:23:41Z",
+			"links": [{
+				"href": "https://cloud.mongodb.com/api/public/v1.0/groups/5a0a1e7e0f2912c554080adc/hosts/22e3e3fbd8d7cd6f8ab2d7f1ff65d44b",
+				"rel": "self"
+			}],
+			"logsEnabled": false,
+			"lowULimit": false,
+			"port": 27017,
+			"profilerEnabled": false,
+			"replicaSetName": "rs0",
+			"replicaStateName": "SECONDARY",
+			"sslEnabled": true,
+			"typeName": "REPLICA_SECONDARY",
+			"uptimeMsec": 1827300394,
+			"username": "mongodb",
+			"version": "4.2.2"
+		}`)
+	})
+
+	host, _, err := client.Hosts.Create(ctx, projectID, createRequest)
+	if err != nil {
+		t.Fatalf("Hosts.Create returned error: %v", err)
+	}
+
+	expected := &Host{
+		AlertsEnabled:      boolPtr(true),
+		AuthMechanismName:  "SCRAM-SHA-1",
+		ClusterID:          "5a0a1e7e0f2912c554080ae1",
+		Created:            "2016-03-09T18:19:37Z",
+		GroupID:            "5a0a1e7e0f2912c554080adc",
+		HostEnabled:        true,
+		Hostname:           "host1.example.com",
+		ID:                 "22e3e3fbd8d7cd6f8ab2d7f1ff65d44b",
+		IPAddress:          "127.0.0.1",
+		LastDataSizeBytes:  633208918,
+		LastIndexSizeBytes: 101420524,
+		LastPing:           "2016-08-18T11:23:41Z",
+		Links: []*mongodbatlas.Link{
+			{
+				Href: "https://cloud.mongodb.com/api/public/v1.0/groups/5a0a1e7e0f2912c554080adc/hosts/22e3e3fbd8d7cd6f8ab2d7f1ff65d44b",
+				Rel:  "self",
+			},
+		},
+		LogsEnabled:      boolPtr(false),
+		Port:             27017,
+		ProfilerEnabled:  boolPtr(false),
+		ReplicaSetName:   "rs0",
+		ReplicaStateName: "SECONDARY",
+		SSLEnabled:       boolPtr(true),
+		TypeName:         "REPLICA_SECONDARY",
+		UptimeMsec:       1827300394,
+		Username:         "mongodb",
+		Version:          "4.2.2",
+	}
+
+	if diff := deep.Equal(host, expected); diff != nil {
+		t.Error(diff)
+	}
+}
+
+func TestHosts_Update(t *testing.T) {
+	setup()
+	defer teardown()
+
+	projectID := "5a0a1e7e0f2912c554080adc"
+	hostID := "22e3e3fbd8d7cd6f8ab2d7f1ff65d44b"
+	updateRequest := &Host{
+		LogsEnabled:     boolPtr(false),
+		ProfilerEnabled: boolPtr(false),
+	}
+
+	mux.HandleFunc(fmt.Sprintf("/groups/%s/hosts/%s", projectID, hostID), func(w http.ResponseWriter, r *http.Request) {
+		testMethod(t, r, http.MethodPatch)
+
+		expected := map[string]interface{}{
+			"logsEnabled":     false,
+			"profilerEnabled": false,
+		}
+		var v map[string]interface{}
+		if err := json.NewDecoder(r.Body).Decode(&v); err != nil {
+			t.Fatalf("decode json: %v", err)
+		}
+		if diff := deep.Equal(v, expected); diff != nil {
+			t.Error(diff)
+		}
+
+		_, _ = fmt.Fprint(w, `{
+			"alertsEnabled": true,
+			"authMechanismName": "SCRAM-SHA-1",
+			"clusterId": "5a0a1e7e0f2912c554080ae1",
+			"created": "2016-03-09T18:19:37Z",
+			"groupId": "5a0a1e7e0f2912c554080adc",
+			"hasStartupWarnings": false,
+			"hidden": false,
+			"hostEnabled": true,
+			"hostname": "host1.example.com",
+			"id": "22e3e3fbd8d7cd6f8ab2d7f1ff65d44b",
+			"ipAddress": "127.0.0.1",
+			"journalingEnabled": false,
+			"lastDataSizeBytes": 633208918,
+			"lastIndexSizeBytes": 101420524,
+			"lastPing": "2016-08-18T11:23:41Z",
+			"links": [{
+				"href": "https://cloud.mongodb.com/api/public/v1.0/groups/5a0a1e7e0f2912c554080adc/hosts/22e3e3fbd8d7cd6f8ab2d7f1ff65d44b",
+				"rel": "self"
+			}],
+			"logsEnabled": false,
+			"lowULimit": false,
+			"port": 27017,
+			"profilerEnabled": false,
+			"replicaSetName": "rs0",
+			"replicaStateName": "SECONDARY",
+			"sslEnabled": true,
+			"typeName": "REPLICA_SECONDARY",
+			"uptimeMsec": 1827300394,
+			"username": "mongodb",
+			"version": "4.2.2"
+		}`)
+	})
+
+	host, _, err := client.Hosts.Update(ctx, projectID, hostID, updateRequest)
+	if err != nil {
+		t.Fatalf("Hosts.Update returned error: %v", err)
+	}
+
+	expected := &Host{
+		AlertsEnabled:      boolPtr(true),
+		AuthMechanismName:  "SCRAM-SHA-1",
+		ClusterID:          "5a0a1e7e0f2912c554080ae1",
+		Created:            "2016-03-09T18:19:37Z",
+		GroupID:            "5a0a1e7e0f2912c554080adc",
+		HostEnabled:        true,
+		Hostname:           "host1.example.com",
+		ID:                 "22e3e3fbd8d7cd6f8ab2d7f1ff65d44b",
+		IPAddress:          "127.0.0.1",
+		LastDataSizeBytes:  633208918,
+		LastIndexSizeBytes: 101420524,
+		LastPing:           "2016-08-18T11:23:41Z",
+		Links: []*mongodbatlas.Link{
+			{
+				Href: "https://cloud.mongodb.com/api/public/v1.0/groups/5a0a1e7e0f2912c554080adc/hosts/22e3e3fbd8d7cd6f8ab2d7f1ff65d44b",
+				Rel:  "self",
+			},
+		},
+		LogsEnabled:      boolPtr(false),
+		Port:             27017,
+		ProfilerEnabled:  boolPtr(false),
+		ReplicaSetName:   "rs0",
+		ReplicaStateName: "SECONDARY",
+		SSLEnabled:       boolPtr(true),
+		TypeName:         "REPLICA_SECONDARY",
+		UptimeMsec:       1827300394,
+		Username:         "mongodb",
+		Version:          "4.2.2",
+	}
+
+	if diff := deep.Equal(host, expected); diff != nil {
+		t.Error(diff)
+	}
+}
+
+func TestHosts_Delete(t *testing.T) {
+	setup()
+	defer teardown()
+
+	projectID := "5a0a1e7e0f2912c554080adc"
+	hostID := "22e3e3fbd8d7cd6f8ab2d7f1ff65d44b"
+
+	mux.HandleFunc(fmt.Sprintf("/groups/%s/hosts/%s", projectID, hostID), func(w http.ResponseWriter, r *http.Request) {
+		testMethod(t, r, http.MethodDelete)
+	})
+
+	_, err := client.Hosts.Delete(ctx, projectID, hostID)
+	if err != nil {
+		t.Fatalf("Hosts.Delete returned error: %v", err)
+	}
+}
@@ -0,0 +1,321 @@
+// Copyright 2020 MongoDB Inc
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package cloudmanager
+
+import (
+	"context"
+	"fmt"
+	"net/http"
+
+	atlas "github.com/mongodb/go-client-mongodb-atlas/mongodbatlas"
+)
+
+const (
+	orgInvitationsBasePath     = "orgs/%s/invites"
+	projectInvitationsBasePath = "groups/%s/invites"
+)
+
+// InvitationsService is an interface for interfacing with the Organization and Project Invitations
+// endpoints of the MongoDB Cloud Manager API.
+// See more: https://docs.cloudmanager.mongodb.com/reference/api/invitations/
+type InvitationsService interface {
+	ListOrgInvitations(context.Context, string, *InvitationListOptions) ([]*Invitation, *atlas.Response, error)
+	GetOrgInvitation(context.Context, string, string) (*Invitation, *atlas.Response, error)
+	CreateOrgInvitation(context.Context, string, *InvitationRequest) (*Invitation, *atlas.Response, error)
+	ResendOrgInvitation(context.Context, string, string) (*Invitation, *atlas.Response, error)
+	RevokeOrgInvitation(context.Context, string, string) (*atlas.Response, error)
+	ListProjectInvitations(context.Context, string, *InvitationListOptions) ([]*Invitation, *atlas.Response, error)
+	GetProjectInvitation(context.Context, string, string) (*Invitation, *atlas.Response, error)
+	CreateProjectInvitation(context.Context, string, *InvitationRequest) (*Invitation, *atlas.Response, error)
+	ResendProjectInvitation(context.Context, string, string) (*Invitation, *atlas.Response, error)
+	RevokeProjectInvitation(context.Context, string, string) (*atlas.Response, error)
+}
+
+// InvitationsServiceOp handles communication with the Invitations related methods of the
+// MongoDB Cloud Manager API
+type InvitationsServiceOp struct {
+	client *Client
+}
+
+var _ InvitationsService = &InvitationsServiceOp{}
+
+// Invitation represents a pending invitation to join an organization or a project
+type Invitation struct {
+	ID              string        `json:"id"`
+	CreatedAt       string        `json:"createdAt,omitempty"`
+	ExpiresAt       string        `json:"expiresAt,omitempty"`
+	GroupID         string        `json:"groupId,omitempty"`
+	GroupName       string        `json:"groupName,omitempty"`
+	InviterUsername string        `json:"inviterUsername,omitempty"`
+	Links           []*atlas.Link `json:"links,omitempty"`
+	OrgID           string        `json:"orgId,omitempty"`
+	OrgName         string        `json:"orgName,omitempty"`
+	Roles           []RoleName    `json:"roles,omitempty"`
+	TeamIDs         []string      `json:"teamIds,omitempty"`
+	Username        string        `json:"username"`
+}
+
+// InvitationRequest the user to invite, and the roles granted once the invitation is accepted.
+// Teams can only be joined through organization invitations.
+type InvitationRequest struct {
+	Roles    []RoleName `json:"roles"`
+	TeamIDs  []string   `json:"teamIds,omitempty"`
+	Username string     `json:"username"`
+}
+
+// InvitationListOptions filter the invitations returned by the list methods
+type InvitationListOptions struct {
+	Username string `url:"username,omitempty"`
+}
+
+// invitationScope the organization or project invitations are sent for
+type invitationScope struct {
+	basePath string
+	roles    []RoleName
+	reason   string
+}
+
+func orgInvitations(orgID string) *invitationScope {
+	return &invitationScope{
+		basePath: fmt.Sprintf(orgInvitationsBasePath, orgID),
+		roles:    OrgRoles,
+		reason:   "only organization roles can be granted by an organization invitation",
+	}
+}
+
+func projectInvitations(projectID string) *invitationScope {
+	return &invitationScope{
+		basePath: fmt.Sprintf(projectInvitationsBasePath, projectID),
+		roles:    ProjectRoles,
+		reason:   "only project roles can be granted by a project invitation",
+	}
+}
+
+// ListOrgInvitations gets the pending invitations to an organization.
+// See more: https://docs.cloudmanager.mongodb.com/reference/api/invitations/organizations/get-all-invitations/
+func (s *InvitationsServiceOp) ListOrgInvitations(ctx context.Context, orgID string, opts *InvitationListOptions) ([]*Invitation, *atlas.Response, error) {
+	if orgID == "" {
+		return nil, nil, atlas.NewArgError("orgID", "must be set")
+	}
+
+	return s.list(ctx, orgInvitations(orgID), opts)
+}
+
+// GetOrgInvitation gets a single pending invitation to an organization.
+// See more: https://docs.cloudmanager.mongodb.com/reference/api/invitations/organizations/get-one-invitation/
+func (s *InvitationsServiceOp) GetOrgInvitation(ctx context.Context, orgID, invitationID string) (*Invitation, *atlas.Response, error) {
+	if orgID == "" {
+		return nil, nil, atlas.NewArgError("orgID", "must be set")
+	}
+
+	return s.get(ctx, orgInvitations(orgID), invitationID)
+}
+
+// CreateOrgInvitation invites a user to an organization with organization roles, and optionally to teams.
+// See more: https://docs.cloudmanager.mongodb.com/reference/api/invitations/organizations/create-one-invitation/
+func (s *InvitationsServiceOp) CreateOrgInvitation(ctx context.Context, orgID string, createRequest *InvitationRequest) (*Invitation, *atlas.Response, error) {
+	if orgID == "" {
+		return nil, nil, atlas.NewArgError("orgID", "must be set")
+	}
+
+	return s.create(ctx, orgInvitations(orgID), createRequest)
+}
+
+// ResendOrgInvitation sends a pending invitation to an organization again, see resend.
+func (s *InvitationsServiceOp) ResendOrgInvitation(ctx context.Context, orgID, invitationID string) (*Invitation, *atlas.Response, error) {
+	if orgID == "" {
+		return nil, nil, atlas.NewArgError("orgID", "must be set")
+	}
+
+	return s.resend(ctx, orgInvitations(orgID), invitationID)
+}
+
+// RevokeOrgInvitation deletes a pending invitation to an organization.
+// See more: https://docs.cloudmanager.mongodb.com/reference/api/invitations/organizations/delete-invitation/
+func (s *InvitationsServiceOp) RevokeOrgInvitation(ctx context.Context, orgID, invitationID string) (*atlas.Response, error) {
+	if orgID == "" {
+		return nil, atlas.NewArgError("orgID", "must be set")
+	}
+
+	return s.revoke(ctx, orgInvitations(orgID), invitationID)
+}
+
+// ListProjectInvitations gets the pending invitations to a project.
+// See more: https://docs.cloudmanager.mongodb.com/reference/api/invitations/projects/get-all-invitations/
+func (s *InvitationsServiceOp) ListProjectInvitations(ctx context.Context, projectID string, opts *InvitationListOptions) ([]*Invitation, *atlas.Response, error) {
+	if projectID == "" {
+		return nil, nil, atlas.NewArgError("projectID", "must be set")
+	}
+
+	return s.list(ctx, projectInvitations(projectID), opts)
+}
+
+// GetProjectInvitation gets a single pending invitation to a project.
+// See more: https://docs.cloudmanager.mongodb.com/reference/api/invitations/projects/get-one-invitation/
+func (s *InvitationsServiceOp) GetProjectInvitation(ctx context.Context, projectID, invitationID string) (*Invitation, *atlas.Response, error) {
+	if projectID == "" {
+		return nil, nil, atlas.NewArgError("projectID", "must be set")
+	}
+
+	return s.get(ctx, projectInvitations(projectID), invitationID)
+}
+
+// CreateProjectInvitation invites a user to a project with project roles.
+// See more: https://docs.cloudmanager.mongodb.com/reference/api/invitations/projects/create-one-invitation/
+func (s *InvitationsServiceOp) CreateProjectInvitation(ctx context.Context, projectID string, createRequest *InvitationRequest) (*Invitation, *atlas.Response, error) {
+	if projectID == "" {
+		return nil, nil, atlas.NewArgError("projectID", "must be set")
+	}
+	if createRequest != nil && len(createRequest.TeamIDs) > 0 {
+		return nil, nil, atlas.NewArgError("teamIds", "can only be set on organization invitations")
+	}
+
+	return s.create(ctx, projectInvitations(projectID), createRequest)
+}
+
+// ResendProjectInvitation sends a pending invitation to a project again, see resend.
+func (s *InvitationsServiceOp) ResendProjectInvitation(ctx context.Context, projectID, invitationID string) (*Invitation, *atlas.Response, error) {
+	if projectID == "" {
+		return nil, nil, atlas.NewArgError("projectID", "must be set")
+	}
+
+	return s.resend(ctx, projectInvitations(projectID), invitationID)
+}
+
+// RevokeProjectInvitation deletes a pending invitation to a project.
+// See more: https://docs.cloudmanager.mongodb.com/reference/api/invitations/projects/delete-one-invitation/
+func (s *InvitationsServiceOp) RevokeProjectInvitation(ctx context.Context, projectID, invitationID string) (*atlas.Response, error) {
+	if projectID == "" {
+		return nil, atlas.NewArgError("projectID", "must be set")
+	}
+
+	return s.revoke(ctx, projectInvitations(projectID), invitationID)
+}
+
+func (s *InvitationsServiceOp) list(ctx context.Context, scope *invitationScope, opts *InvitationListOptions) ([]*Invitation, *atlas.Response, error) {
+	path, err := setListOptions(scope.basePath, opts)
+	if err != nil {
+		return nil, nil, err
+	}
+
+	req, err := s.client.NewRequest(ctx, http.MethodGet, path, nil)
+	if err != nil {
+		return nil, nil, err
+	}
+
+	var root []*Invitation
+	resp, err := s.client.Do(ctx, req, &root)
+	if err != nil {
+		return nil, resp, err
+	}
+
+	return root, resp, nil
+}
+
+func (s *InvitationsServiceOp) get(ctx context.Context, scope *invitationScope, invitationID string) (*Invitation, *atlas.Response, error) {
+	if invitationID == "" {
+		return nil, nil, atlas.NewArgError("invitationID", "must be set")
+	}
+
+	path := fmt.Sprintf("%s/%s", scope.basePath, invitationID)
+
+	req, err := s.client.NewRequest(ctx, http.MethodGet, path, nil)
+	if err != nil {
+		return nil, nil, err
+	}
+
+	root := new(Invitation)
+	resp, err := s.client.Do(ctx, req, root)
+	if err != nil {
+		return nil, resp, err
+	}
+
+	return root, resp, err
+}
+
+func (s *InvitationsServiceOp) create(ctx context.Context, scope *invitationScope, createRequest *InvitationRequest) (*Invitation, *atlas.Response, error) {
+	if err := validateInvitationRequest(scope, createRequest); err != nil {
+		return nil, nil, err
+	}
+
+	req, err := s.client.NewRequest(ctx, http.MethodPost, scope.basePath, createRequest)
+	if err != nil {
+		return nil, nil, err
+	}
+
+	root := new(Invitation)
+	resp, err := s.client.Do(ctx, req, root)
+	if err != nil {
+		return nil, resp, err
+	}
+
+	return root, resp, err
+}
+
+func validateInvitationRequest(scope *invitationScope, createRequest *InvitationRequest) error {
+	if createRequest == nil {
+		return atlas.NewArgError("createRequest", "cannot be nil")
+	}
+	if createRequest.Username == "" {
+		return atlas.NewArgError("username", "must be set")
+	}
+	if len(createRequest.Roles) == 0 {
+		return atlas.NewArgError("roles", "must be set")
+	}
+	return validateRoleNames(createRequest.Roles, scope.roles, scope.reason)
+}
+
+// resend sends an invitation again by revoking it and inviting the same user with the same roles and teams,
+// since the API has no resend endpoint; the new invitation has a new ID and expiration date.
+// The invitation is only revoked if the new one is valid, but if the server then rejects it,
+// the original invitation is already revoked.
+func (s *InvitationsServiceOp) resend(ctx context.Context, scope *invitationScope, invitationID string) (*Invitation, *atlas.Response, error) {
+	invitation, resp, err := s.get(ctx, scope, invitationID)
+	if err != nil {
+		return nil, resp, err
+	}
+
+	createRequest := &InvitationRequest{
+		Roles:    invitation.Roles,
+		TeamIDs:  invitation.TeamIDs,
+		Username: invitation.Username,
+	}
+	if err := validateInvitationRequest(scope, createRequest); err != nil {
+		return nil, resp, err
+	}
+
+	if resp, err := s.revoke(ctx, scope, invitationID); err != nil {
+		return nil, resp, err
+	}
+
+	return s.create(ctx, scope, createRequest)
+}
+
+func (s *InvitationsServiceOp) revoke(ctx context.Context, scope *invitationScope, invitationID string) (*atlas.Response, error) {
+	if invitationID == "" {
+		return nil, atlas.NewArgError("invitationID", "must be set")
+	}
+
+	path := fmt.Sprintf("%s/%s", scope.basePath, invitationID)
+
+	req, err := s.client.NewRequest(ctx, http.MethodDelete, path, nil)
+	if err != nil {
+		return nil, err
+	}
+
+	resp, err := s.client.Do(ctx, req, nil)
+
+	return resp, err
+}
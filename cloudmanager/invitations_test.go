@@ -0,0 +1,295 @@
+// Copyright 2020 MongoDB Inc
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package cloudmanager
+
+import (
+	"fmt"
+	"net/http"
+	"net/url"
+	"testing"
+
+	"github.com/go-test/deep"
+)
+
+func TestInvitations_ListOrgInvitations(t *testing.T) {
+	setup()
+	defer teardown()
+
+	orgID := "5a0a1e7e0f2912c554080adb"
+
+	mux.HandleFunc(fmt.Sprintf("/orgs/%s/invites", orgID), func(w http.ResponseWriter, r *http.Request) {
+		testMethod(t, r, http.MethodGet)
+		testQuery(t, r, url.Values{"username": {"jane.doe@example.com"}})
+		_, _ = fmt.Fprint(w, `[{
+			"createdAt": "2020-02-05T19:21:13Z",
+			"expiresAt": "2020-03-06T19:21:13Z",
+			"id": "5e3b1a3932a1ae6a7c3f4c8e",
+			"inviterUsername": "admin@example.com",
+			"orgId": "5a0a1e7e0f2912c554080adb",
+			"orgName": "Org0",
+			"roles": ["ORG_MEMBER"],
+			"teamIds": ["6b610e1087d9d66b272f0c86"],
+			"username": "jane.doe@example.com"
+		}]`)
+	})
+
+	invitations, _, err := client.Invitations.ListOrgInvitations(ctx, orgID, &InvitationListOptions{Username: "jane.doe@example.com"})
+	if err != nil {
+		t.Fatalf("Invitations.ListOrgInvitations returned error: %v", err)
+	}
+
+	expected := []*Invitation{
+		{
+			ID:              "5e3b1a3932a1ae6a7c3f4c8e",
+			CreatedAt:       "2020-02-05T19:21:13Z",
+			ExpiresAt:       "2020-03-06T19:21:13Z",
+			InviterUsername: "admin@example.com",
+			OrgID:           "5a0a1e7e0f2912c554080adb",
+			OrgName:         "Org0",
+			Roles:           []RoleName{RoleOrgMember},
+			TeamIDs:         []string{"6b610e1087d9d66b272f0c86"},
+			Username:        "jane.doe@example.com",
+		},
+	}
+
+	if diff := deep.Equal(invitations, expected); diff != nil {
+		t.Error(diff)
+	}
+}
+
+func TestInvitations_CreateOrgInvitation(t *testing.T) {
+	setup()
+	defer teardown()
+
+	orgID := "5a0a1e7e0f2912c554080adb"
+
+	mux.HandleFunc(fmt.Sprintf("/orgs/%s/invites", orgID), func(w http.ResponseWriter, r *http.Request) {
+		testMethod(t, r, http.MethodPost)
+
+		expected := map[string]interface{}{
+			"roles":    []interface{}{"ORG_MEMBER"},
+			"teamIds":  []interface{}{"6b610e1087d9d66b272f0c86"},
+			"username": "jane.doe@example.com",
+		}
+		if diff := deep.Equal(decodeBody(t, r), expected); diff != nil {
+			t.Error(diff)
+		}
+
+		_, _ = fmt.Fprint(w, `{
+			"createdAt": "2020-02-05T19:21:13Z",
+			"expiresAt": "2020-03-06T19:21:13Z",
+			"id": "5e3b1a3932a1ae6a7c3f4c8e",
+			"inviterUsername": "admin@example.com",
+			"orgId": "5a0a1e7e0f2912c554080adb",
+			"orgName": "Org0",
+			"roles": ["ORG_MEMBER"],
+			"teamIds": ["6b610e1087d9d66b272f0c86"],
+			"username": "jane.doe@example.com"
+		}`)
+	})
+
+	createRequest := &InvitationRequest{
+		Roles:    []RoleName{RoleOrgMember},
+		TeamIDs:  []string{"6b610e1087d9d66b272f0c86"},
+		Username: "jane.doe@example.com",
+	}
+	invitation, _, err := client.Invitations.CreateOrgInvitation(ctx, orgID, createRequest)
+	if err != nil {
+		t.Fatalf("Invitations.CreateOrgInvitation returned error: %v", err)
+	}
+
+	expected := &Invitation{
+		ID:              "5e3b1a3932a1ae6a7c3f4c8e",
+		CreatedAt:       "2020-02-05T19:21:13Z",
+		ExpiresAt:       "2020-03-06T19:21:13Z",
+		InviterUsername: "admin@example.com",
+		OrgID:           "5a0a1e7e0f2912c554080adb",
+		OrgName:         "Org0",
+		Roles:           []RoleName{RoleOrgMember},
+		TeamIDs:         []string{"6b610e1087d9d66b272f0c86"},
+		Username:        "jane.doe@example.com",
+	}
+
+	if diff := deep.Equal(invitation, expected); diff != nil {
+		t.Error(diff)
+	}
+}
+
+func TestInvitations_CreateOrgInvitation_projectRole(t *testing.T) {
+	setup()
+	defer teardown()
+
+	createRequest := &InvitationRequest{Roles: []RoleName{RoleGroupOwner}, Username: "jane.doe@example.com"}
+	_, _, err := client.Invitations.CreateOrgInvitation(ctx, "5a0a1e7e0f2912c554080adb", createRequest)
+
+	expected := &InvalidRoleError{RoleName: RoleGroupOwner, Reason: "only organization roles can be granted by an organization invitation"}
+	if diff := deep.Equal(err, expected); diff != nil {
+		t.Error(diff)
+	}
+}
+
+func TestInvitations_CreateProjectInvitation(t *testing.T) {
+	setup()
+	defer teardown()
+
+	projectID := "5a0a1e7e0f2912c554080adc"
+
+	mux.HandleFunc(fmt.Sprintf("/groups/%s/invites", projectID), func(w http.ResponseWriter, r *http.Request) {
+		testMethod(t, r, http.MethodPost)
+
+		expected := map[string]interface{}{
+			"roles":    []interface{}{"GROUP_READ_ONLY"},
+			"username": "jane.doe@example.com",
+		}
+		if diff := deep.Equal(decodeBody(t, r), expected); diff != nil {
+			t.Error(diff)
+		}
+
+		_, _ = fmt.Fprintf(w, `{"id": "5e3b1a3932a1ae6a7c3f4c8f", "groupId": %q, "roles": ["GROUP_READ_ONLY"], "username": "jane.doe@example.com"}`, projectID)
+	})
+
+	createRequest := &InvitationRequest{Roles: []RoleName{RoleGroupReadOnly}, Username: "jane.doe@example.com"}
+	invitation, _, err := client.Invitations.CreateProjectInvitation(ctx, projectID, createRequest)
+	if err != nil {
+		t.Fatalf("Invitations.CreateProjectInvitation returned error: %v", err)
+	}
+
+	expected := &Invitation{
+		ID:       "5e3b1a3932a1ae6a7c3f4c8f",
+		GroupID:  projectID,
+		Roles:    []RoleName{RoleGroupReadOnly},
+		Username: "jane.doe@example.com",
+	}
+	if diff := deep.Equal(invitation, expected); diff != nil {
+		t.Error(diff)
+	}
+}
+
+func TestInvitations_CreateProjectInvitation_orgRole(t *testing.T) {
+	setup()
+	defer teardown()
+
+	createRequest := &InvitationRequest{Roles: []RoleName{RoleOrgOwner}, Username: "jane.doe@example.com"}
+	_, _, err := client.Invitations.CreateProjectInvitation(ctx, "5a0a1e7e0f2912c554080adc", createRequest)
+
+	if _, ok := err.(*InvalidRoleError); !ok {
+		t.Fatalf("expected an *InvalidRoleError, got %v", err)
+	}
+}
+
+func TestInvitations_ResendOrgInvitation(t *testing.T) {
+	setup()
+	defer teardown()
+
+	orgID := "5a0a1e7e0f2912c554080adb"
+	invitationID := "5e3b1a3932a1ae6a7c3f4c8e"
+
+	var calls []string
+	mux.HandleFunc(fmt.Sprintf("/orgs/%s/invites/%s", orgID, invitationID), func(w http.ResponseWriter, r *http.Request) {
+		calls = append(calls, r.Method)
+		if r.Method == http.MethodGet {
+			_, _ = fmt.Fprint(w, `{
+				"createdAt": "2020-02-05T19:21:13Z",
+				"expiresAt": "2020-03-06T19:21:13Z",
+				"id": "5e3b1a3932a1ae6a7c3f4c8e",
+				"inviterUsername": "admin@example.com",
+				"orgId": "5a0a1e7e0f2912c554080adb",
+				"orgName": "Org0",
+				"roles": ["ORG_MEMBER"],
+				"teamIds": ["6b610e1087d9d66b272f0c86"],
+				"username": "jane.doe@example.com"
+			}`)
+		}
+	})
+	mux.HandleFunc(fmt.Sprintf("/orgs/%s/invites", orgID), func(w http.ResponseWriter, r *http.Request) {
+		testMethod(t, r, http.MethodPost)
+		calls = append(calls, r.Method)
+
+		expected := map[string]interface{}{
+			"roles":    []interface{}{"ORG_MEMBER"},
+			"teamIds":  []interface{}{"6b610e1087d9d66b272f0c86"},
+			"username": "jane.doe@example.com",
+		}
+		if diff := deep.Equal(decodeBody(t, r), expected); diff != nil {
+			t.Error(diff)
+		}
+
+		_, _ = fmt.Fprint(w, `{
+			"createdAt": "2020-02-05T19:21:13Z",
+			"expiresAt": "2020-03-06T19:21:13Z",
+			"id": "5e3b1a3932a1ae6a7c3f4c8e",
+			"inviterUsername": "admin@example.com",
+			"orgId": "5a0a1e7e0f2912c554080adb",
+			"orgName": "Org0",
+			"roles": ["ORG_MEMBER"],
+			"teamIds": ["6b610e1087d9d66b272f0c86"],
+			"username": "jane.doe@example.com"
+		}`)
+	})
+
+	_, _, err := client.Invitations.ResendOrgInvitation(ctx, orgID, invitationID)
+	if err != nil {
+		t.Fatalf("Invitations.ResendOrgInvitation returned error: %v", err)
+	}
+
+	if diff := deep.Equal(calls, []string{http.MethodGet, http.MethodDelete, http.MethodPost}); diff != nil {
+		t.Error(diff)
+	}
+}
+
+func TestInvitations_ResendOrgInvitation_invalid(t *testing.T) {
+	setup()
+	defer teardown()
+
+	orgID := "5a0a1e7e0f2912c554080adb"
+	invitationID := "5e3b1a3932a1ae6a7c3f4c8e"
+
+	mux.HandleFunc(fmt.Sprintf("/orgs/%s/invites/%s", orgID, invitationID), func(w http.ResponseWriter, r *http.Request) {
+		testMethod(t, r, http.MethodGet)
+		_, _ = fmt.Fprint(w, `{
+			"id": "5e3b1a3932a1ae6a7c3f4c8e",
+			"orgId": "5a0a1e7e0f2912c554080adb",
+			"roles": ["GROUP_OWNER"],
+			"username": "jane.doe@example.com"
+		}`)
+	})
+	mux.HandleFunc(fmt.Sprintf("/orgs/%s/invites", orgID), func(w http.ResponseWriter, r *http.Request) {
+		t.Error("the invitation should not be created again")
+	})
+
+	_, _, err := client.Invitations.ResendOrgInvitation(ctx, orgID, invitationID)
+
+	expected := &InvalidRoleError{RoleName: RoleGroupOwner, Reason: "only organization roles can be granted by an organization invitation"}
+	if diff := deep.Equal(err, expected); diff != nil {
+		t.Error(diff)
+	}
+}
+
+func TestInvitations_RevokeProjectInvitation(t *testing.T) {
+	setup()
+	defer teardown()
+
+	projectID := "5a0a1e7e0f2912c554080adc"
+	invitationID := "5e3b1a3932a1ae6a7c3f4c8f"
+
+	mux.HandleFunc(fmt.Sprintf("/groups/%s/invites/%s", projectID, invitationID), func(w http.ResponseWriter, r *http.Request) {
+		testMethod(t, r, http.MethodDelete)
+	})
+
+	_, err := client.Invitations.RevokeProjectInvitation(ctx, projectID, invitationID)
+	if err != nil {
+		t.Fatalf("Invitations.RevokeProjectInvitation returned error: %v", err)
+	}
+}
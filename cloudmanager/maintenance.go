@@ -0,0 +1,296 @@
+// Copyright 2020 MongoDB Inc
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package cloudmanager
+
+import (
+	"context"
+	"fmt"
+	"net/http"
+	"time"
+
+	atlas "github.com/mongodb/go-client-mongodb-atlas/mongodbatlas"
+)
+
+const (
+	maintenanceWindowsBasePath = "groups/%s/maintenanceWindows"
+
+	// maintenanceWindowCloseTimeout bounds closing a window when the caller's context is already done
+	maintenanceWindowCloseTimeout = time.Minute
+)
+
+// MaintenanceAlertTypeNames the alert types suppressed by WithMaintenanceWindow
+var MaintenanceAlertTypeNames = []AlertTypeName{
+	AlertTypeAgent,
+	AlertTypeBackup,
+	AlertTypeCluster,
+	AlertTypeHost,
+	AlertTypeReplicaSet,
+}
+
+// MaintenanceWindowsService is an interface for interfacing with the Maintenance Windows
+// endpoints of the MongoDB Cloud Manager API.
+// See more: https://docs.cloudmanager.mongodb.com/reference/api/maintenance-windows/
+type MaintenanceWindowsService interface {
+	List(context.Context, string) (*MaintenanceWindows, *atlas.Response, error)
+	Get(context.Context, string, string) (*MaintenanceWindow, *atlas.Response, error)
+	Create(context.Context, string, *MaintenanceWindow) (*MaintenanceWindow, *atlas.Response, error)
+	Update(context.Context, string, string, *MaintenanceWindow) (*MaintenanceWindow, *atlas.Response, error)
+	Delete(context.Context, string, string) (*atlas.Response, error)
+	WithMaintenanceWindow(context.Context, string, string, time.Duration, func(context.Context) error) error
+}
+
+// MaintenanceWindowsServiceOp handles communication with the Maintenance Windows related methods of the
+// MongoDB Cloud Manager API
+type MaintenanceWindowsServiceOp struct {
+	client *Client
+}
+
+var _ MaintenanceWindowsService = &MaintenanceWindowsServiceOp{}
+
+// MaintenanceWindow represents a period during which alerts of the given types are suppressed
+type MaintenanceWindow struct {
+	ID             string          `json:"id,omitempty"`
+	AlertTypeNames []AlertTypeName `json:"alertTypeNames,omitempty"`
+	Created        string          `json:"created,omitempty"`
+	Description    string          `json:"description,omitempty"`
+	EndDate        time.Time       `json:"endDate"`
+	GroupID        string          `json:"groupId,omitempty"`
+	Links          []*atlas.Link   `json:"links,omitempty"`
+	StartDate      time.Time       `json:"startDate"`
+	Updated        string          `json:"updated,omitempty"`
+}
+
+// MaintenanceWindows represents a array of maintenance windows
+type MaintenanceWindows struct {
+	Links      []*atlas.Link        `json:"links"`
+	Results    []*MaintenanceWindow `json:"results"`
+	TotalCount int                  `json:"totalCount"`
+}
+
+// maintenanceWindowRequest the body of a create or update, which omits the dates that are not set
+type maintenanceWindowRequest struct {
+	AlertTypeNames []AlertTypeName `json:"alertTypeNames,omitempty"`
+	Description    string          `json:"description,omitempty"`
+	EndDate        *time.Time      `json:"endDate,omitempty"`
+	StartDate      *time.Time      `json:"startDate,omitempty"`
+}
+
+func newMaintenanceWindowRequest(window *MaintenanceWindow) *maintenanceWindowRequest {
+	r := &maintenanceWindowRequest{
+		AlertTypeNames: window.AlertTypeNames,
+		Description:    window.Description,
+	}
+	if !window.StartDate.IsZero() {
+		start := window.StartDate.UTC()
+		r.StartDate = &start
+	}
+	if !window.EndDate.IsZero() {
+		end := window.EndDate.UTC()
+		r.EndDate = &end
+	}
+	return r
+}
+
+// List gets the maintenance windows of a project.
+// See more: https://docs.cloudmanager.mongodb.com/reference/api/maintenance-windows-get-all/
+func (s *MaintenanceWindowsServiceOp) List(ctx context.Context, projectID string) (*MaintenanceWindows, *atlas.Response, error) {
+	if projectID == "" {
+		return nil, nil, atlas.NewArgError("projectID", "must be set")
+	}
+
+	path := fmt.Sprintf(maintenanceWindowsBasePath, projectID)
+
+	req, err := s.client.NewRequest(ctx, http.MethodGet, path, nil)
+	if err != nil {
+		return nil, nil, err
+	}
+
+	root := new(MaintenanceWindows)
+	resp, err := s.client.Do(ctx, req, root)
+	if err != nil {
+		return nil, resp, err
+	}
+
+	if l := root.Links; l != nil {
+		resp.Links = l
+	}
+
+	return root, resp, nil
+}
+
+// Get gets a single maintenance window.
+// See more: https://docs.cloudmanager.mongodb.com/reference/api/maintenance-windows-get-one/
+func (s *MaintenanceWindowsServiceOp) Get(ctx context.Context, projectID, windowID string) (*MaintenanceWindow, *atlas.Response, error) {
+	if projectID == "" {
+		return nil, nil, atlas.NewArgError("projectID", "must be set")
+	}
+	if windowID == "" {
+		return nil, nil, atlas.NewArgError("windowID", "must be set")
+	}
+
+	basePath := fmt.Sprintf(maintenanceWindowsBasePath, projectID)
+	path := fmt.Sprintf("%s/%s", basePath, windowID)
+
+	req, err := s.client.NewRequest(ctx, http.MethodGet, path, nil)
+	if err != nil {
+		return nil, nil, err
+	}
+
+	root := new(MaintenanceWindow)
+	resp, err := s.client.Do(ctx, req, root)
+	if err != nil {
+		return nil, resp, err
+	}
+
+	return root, resp, err
+}
+
+// Create creates a maintenance window; the start and end dates and at least one alert type are required.
+// See more: https://docs.cloudmanager.mongodb.com/reference/api/maintenance-windows-create-one/
+func (s *MaintenanceWindowsServiceOp) Create(ctx context.Context, projectID string, createRequest *MaintenanceWindow) (*MaintenanceWindow, *atlas.Response, error) {
+	if projectID == "" {
+		return nil, nil, atlas.NewArgError("projectID", "must be set")
+	}
+	if createRequest == nil {
+		return nil, nil, atlas.NewArgError("createRequest", "cannot be nil")
+	}
+	if createRequest.StartDate.IsZero() {
+		return nil, nil, atlas.NewArgError("startDate", "must be set")
+	}
+	if !createRequest.EndDate.After(createRequest.StartDate) {
+		return nil, nil, atlas.NewArgError("endDate", "must be after startDate")
+	}
+	if len(createRequest.AlertTypeNames) == 0 {
+		return nil, nil, atlas.NewArgError("alertTypeNames", "must be set")
+	}
+
+	path := fmt.Sprintf(maintenanceWindowsBasePath, projectID)
+
+	req, err := s.client.NewRequest(ctx, http.MethodPost, path, newMaintenanceWindowRequest(createRequest))
+	if err != nil {
+		return nil, nil, err
+	}
+
+	root := new(MaintenanceWindow)
+	resp, err := s.client.Do(ctx, req, root)
+	if err != nil {
+		return nil, resp, err
+	}
+
+	return root, resp, err
+}
+
+// Update updates a maintenance window; only the specified fields are modified.
+// See more: https://docs.cloudmanager.mongodb.com/reference/api/maintenance-windows-update-one/
+func (s *MaintenanceWindowsServiceOp) Update(ctx context.Context, projectID, windowID string, updateRequest *MaintenanceWindow) (*MaintenanceWindow, *atlas.Response, error) {
+	if projectID == "" {
+		return nil, nil, atlas.NewArgError("projectID", "must be set")
+	}
+	if windowID == "" {
+		return nil, nil, atlas.NewArgError("windowID", "must be set")
+	}
+	if updateRequest == nil {
+		return nil, nil, atlas.NewArgError("updateRequest", "cannot be nil")
+	}
+	if !updateRequest.StartDate.IsZero() && !updateRequest.EndDate.IsZero() && !updateRequest.EndDate.After(updateRequest.StartDate) {
+		return nil, nil, atlas.NewArgError("endDate", "must be after startDate")
+	}
+
+	basePath := fmt.Sprintf(maintenanceWindowsBasePath, projectID)
+	path := fmt.Sprintf("%s/%s", basePath, windowID)
+
+	req, err := s.client.NewRequest(ctx, http.MethodPatch, path, newMaintenanceWindowRequest(updateRequest))
+	if err != nil {
+		return nil, nil, err
+	}
+
+	root := new(MaintenanceWindow)
+	resp, err := s.client.Do(ctx, req, root)
+	if err != nil {
+		return nil, resp, err
+	}
+
+	return root, resp, err
+}
+
+// Delete deletes a maintenance window.
+// See more: https://docs.cloudmanager.mongodb.com/reference/api/maintenance-windows-delete-one/
+func (s *MaintenanceWindowsServiceOp) Delete(ctx context.Context, projectID, windowID string) (*atlas.Response, error) {
+	if projectID == "" {
+		return nil, atlas.NewArgError("projectID", "must be set")
+	}
+	if windowID == "" {
+		return nil, atlas.NewArgError("windowID", "must be set")
+	}
+
+	basePath := fmt.Sprintf(maintenanceWindowsBasePath, projectID)
+	path := fmt.Sprintf("%s/%s", basePath, windowID)
+
+	req, err := s.client.NewRequest(ctx, http.MethodDelete, path, nil)
+	if err != nil {
+		return nil, err
+	}
+
+	resp, err := s.client.Do(ctx, req, nil)
+
+	return resp, err
+}
+
+// WithMaintenanceWindow opens a maintenance window with the given description, suppressing MaintenanceAlertTypeNames
+// for at most the duration, runs fn, then closes the window by moving its end date to now, even if fn fails or panics.
+// The window still expires on its own if it cannot be closed.
+func (s *MaintenanceWindowsServiceOp) WithMaintenanceWindow(ctx context.Context, projectID, description string, duration time.Duration, fn func(context.Context) error) (err error) {
+	if description == "" {
+		return atlas.NewArgError("description", "must be set")
+	}
+	if duration <= 0 {
+		return atlas.NewArgError("duration", "must be positive")
+	}
+	if fn == nil {
+		return atlas.NewArgError("fn", "cannot be nil")
+	}
+
+	start := time.Now()
+	window, _, err := s.Create(ctx, projectID, &MaintenanceWindow{
+		AlertTypeNames: MaintenanceAlertTypeNames,
+		Description:    description,
+		StartDate:      start,
+		EndDate:        start.Add(duration),
+	})
+	if err != nil {
+		return err
+	}
+
+	// deferred, so that the window is also closed if fn panics; the panic then carries on
+	defer func() {
+		closeCtx := ctx
+		if ctx.Err() != nil {
+			var cancel context.CancelFunc
+			closeCtx, cancel = context.WithTimeout(context.Background(), maintenanceWindowCloseTimeout)
+			defer cancel()
+		}
+		_, _, closeErr := s.Update(closeCtx, projectID, window.ID, &MaintenanceWindow{EndDate: time.Now()})
+
+		switch {
+		case closeErr == nil:
+		case err != nil:
+			err = fmt.Errorf("%w (closing maintenance window %s also failed: %v)", err, window.ID, closeErr)
+		default:
+			err = fmt.Errorf("closing maintenance window %s: %w", window.ID, closeErr)
+		}
+	}()
+
+	return fn(ctx)
+}
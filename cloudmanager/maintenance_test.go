@@ -0,0 +1,395 @@
+// Copyright 2020 MongoDB Inc
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package cloudmanager
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"net/http"
+	"testing"
+	"time"
+
+	"github.com/go-test/deep"
+	"github.com/mongodb/go-client-mongodb-atlas/mongodbatlas"
+)
+
+func TestMaintenanceWindows_List(t *testing.T) {
+	setup()
+	defer teardown()
+
+	projectID := "5a0a1e7e0f2912c554080adc"
+
+	mux.HandleFunc(fmt.Sprintf("/groups/%s/maintenanceWindows", projectID), func(w http.ResponseWriter, r *http.Request) {
+		testMethod(t, r, http.MethodGet)
+		_, _ = fmt.Fprint(w, `{
+			"links": [],
+			"results": [{
+				"alertTypeNames": ["BACKUP", "CLUSTER"],
+				"created": "2015-10-06T20:35:12Z",
+				"description": "upgrade",
+				"endDate": "2015-10-23T23:30:00Z",
+				"groupId": "5a0a1e7e0f2912c554080adc",
+				"id": "5628faffd4c606594adaa3b2",
+				"links": [],
+				"startDate": "2015-10-23T22:00:00Z",
+				"updated": "2015-10-06T20:35:12Z"
+			}],
+			"totalCount": 1
+		}`)
+	})
+
+	windows, _, err := client.MaintenanceWindows.List(ctx, projectID)
+	if err != nil {
+		t.Fatalf("MaintenanceWindows.List returned error: %v", err)
+	}
+
+	expected := &MaintenanceWindows{
+		Links: []*mongodbatlas.Link{},
+		Results: []*MaintenanceWindow{
+			{
+				ID:             "5628faffd4c606594adaa3b2",
+				AlertTypeNames: []AlertTypeName{AlertTypeBackup, AlertTypeCluster},
+				Created:        "2015-10-06T20:35:12Z",
+				Description:    "upgrade",
+				EndDate:        time.Date(2015, 10, 23, 23, 30, 0, 0, time.UTC),
+				GroupID:        "5a0a1e7e0f2912c554080adc",
+				Links:          []*mongodbatlas.Link{},
+				StartDate:      time.Date(2015, 10, 23, 22, 0, 0, 0, time.UTC),
+				Updated:        "2015-10-06T20:35:12Z",
+			},
+		},
+		TotalCount: 1,
+	}
+
+	if diff := deep.Equal(windows, expected); diff != nil {
+		t.Error(diff)
+	}
+}
+
+func TestMaintenanceWindows_Get(t *testing.T) {
+	setup()
+	defer teardown()
+
+	projectID := "5a0a1e7e0f2912c554080adc"
+	windowID := "5628faffd4c606594adaa3b2"
+
+	mux.HandleFunc(fmt.Sprintf("/groups/%s/maintenanceWindows/%s", projectID, windowID), func(w http.ResponseWriter, r *http.Request) {
+		testMethod(t, r, http.MethodGet)
+		_, _ = fmt.Fprint(w, `{
+			"alertTypeNames": ["BACKUP", "CLUSTER"],
+			"created": "2015-10-06T20:35:12Z",
+			"description": "upgrade",
+			"endDate": "2015-10-23T23:30:00Z",
+			"groupId": "5a0a1e7e0f2912c554080adc",
+			"id": "5628faffd4c606594adaa3b2",
+			"links": [],
+			"startDate": "2015-10-23T22:00:00Z",
+			"updated": "2015-10-06T20:35:12Z"
+		}`)
+	})
+
+	window, _, err := client.MaintenanceWindows.Get(ctx, projectID, windowID)
+	if err != nil {
+		t.Fatalf("MaintenanceWindows.Get returned error: %v", err)
+	}
+
+	expected := &MaintenanceWindow{
+		ID:             "5628faffd4c606594adaa3b2",
+		AlertTypeNames: []AlertTypeName{AlertTypeBackup, AlertTypeCluster},
+		Created:        "2015-10-06T20:35:12Z",
+		Description:    "upgrade",
+		EndDate:        time.Date(2015, 10, 23, 23, 30, 0, 0, time.UTC),
+		GroupID:        "5a0a1e7e0f2912c554080adc",
+		Links:          []*mongodbatlas.Link{},
+		StartDate:      time.Date(2015, 10, 23, 22, 0, 0, 0, time.UTC),
+		Updated:        "2015-10-06T20:35:12Z",
+	}
+
+	if diff := deep.Equal(window, expected); diff != nil {
+		t.Error(diff)
+	}
+}
+
+func TestMaintenanceWindows_Create(t *testing.T) {
+	setup()
+	defer teardown()
+
+	projectID := "5a0a1e7e0f2912c554080adc"
+
+	mux.HandleFunc(fmt.Sprintf("/groups/%s/maintenanceWindows", projectID), func(w http.ResponseWriter, r *http.Request) {
+		testMethod(t, r, http.MethodPost)
+
+		expected := map[string]interface{}{
+			"alertTypeNames": []interface{}{"BACKUP", "CLUSTER"},
+			"description":    "upgrade",
+			"endDate":        "2015-10-23T23:30:00Z",
+			"startDate":      "2015-10-23T22:00:00Z",
+		}
+		var v map[string]interface{}
+		if err := json.NewDecoder(r.Body).Decode(&v); err != nil {
+			t.Fatalf("decode json: %v", err)
+		}
+		if diff := deep.Equal(v, expected); diff != nil {
+			t.Error(diff)
+		}
+
+		_, _ = fmt.Fprint(w, `{
+			"alertTypeNames": ["BACKUP", "CLUSTER"],
+			"created": "2015-10-06T20:35:12Z",
+			"description": "upgrade",
+			"endDate": "2015-10-23T23:30:00Z",
+			"groupId": "5a0a1e7e0f2912c554080adc",
+			"id": "5628faffd4c606594adaa3b2",
+			"links": [],
+			"startDate": "2015-10-23T22:00:00Z",
+			"updated": "2015-10-06T20:35:12Z"
+		}`)
+	})
+
+	// the dates are sent in UTC
+	est := time.FixedZone("EST", -5*60*60)
+	createRequest := &MaintenanceWindow{
+		AlertTypeNames: []AlertTypeName{AlertTypeBackup, AlertTypeCluster},
+		Description:    "upgrade",
+		StartDate:      time.Date(2015, 10, 23, 17, 0, 0, 0, est),
+		EndDate:        time.Date(2015, 10, 23, 18, 30, 0, 0, est),
+	}
+	window, _, err := client.MaintenanceWindows.Create(ctx, projectID, createRequest)
+	if err != nil {
+		t.Fatalf("MaintenanceWindows.Create returned error: %v", err)
+	}
+
+	expected := &MaintenanceWindow{
+		ID:             "5628faffd4c606594adaa3b2",
+		AlertTypeNames: []AlertTypeName{AlertTypeBackup, AlertTypeCluster},
+		Created:        "2015-10-06T20:35:12Z",
+		Description:    "upgrade",
+		EndDate:        time.Date(2015, 10, 23, 23, 30, 0, 0, time.UTC),
+		GroupID:        "5a0a1e7e0f2912c554080adc",
+		Links:          []*mongodbatlas.Link{},
+		StartDate:      time.Date(2015, 10, 23, 22, 0, 0, 0, time.UTC),
+		Updated:        "2015-10-06T20:35:12Z",
+	}
+
+	if diff := deep.Equal(window, expected); diff != nil {
+		t.Error(diff)
+	}
+}
+
+func TestMaintenanceWindows_Create_invalid(t *testing.T) {
+	setup()
+	defer teardown()
+
+	start := time.Date(2015, 10, 23, 22, 0, 0, 0, time.UTC)
+	tests := map[string]*MaintenanceWindow{
+		"no start date":    {AlertTypeNames: []AlertTypeName{AlertTypeHost}, EndDate: start},
+		"end before start": {AlertTypeNames: []AlertTypeName{AlertTypeHost}, StartDate: start, EndDate: start.Add(-time.Hour)},
+		"no alert types":   {StartDate: start, EndDate: start.Add(time.Hour)},
+	}
+
+	for name, createRequest := range tests {
+		if _, _, err := client.MaintenanceWindows.Create(ctx, "5a0a1e7e0f2912c554080adc", createRequest); err == nil {
+			t.Errorf("%s: expected an error", name)
+		}
+	}
+}
+
+func TestMaintenanceWindows_Update(t *testing.T) {
+	setup()
+	defer teardown()
+
+	projectID := "5a0a1e7e0f2912c554080adc"
+	windowID := "5628faffd4c606594adaa3b2"
+
+	mux.HandleFunc(fmt.Sprintf("/groups/%s/maintenanceWindows/%s", projectID, windowID), func(w http.ResponseWriter, r *http.Request) {
+		testMethod(t, r, http.MethodPatch)
+
+		expected := map[string]interface{}{"endDate": "2015-10-23T23:30:00Z"}
+		var v map[string]interface{}
+		if err := json.NewDecoder(r.Body).Decode(&v); err != nil {
+			t.Fatalf("decode json: %v", err)
+		}
+		if diff := deep.Equal(v, expected); diff != nil {
+			t.Error(diff)
+		}
+
+		_, _ = fmt.Fprint(w, `{
+			"alertTypeNames": ["BACKUP", "CLUSTER"],
+			"created": "2015-10-06T20:35:12Z",
+			"description": "upgrade",
+			"endDate": "2015-10-23T23:30:00Z",
+			"groupId": "5a0a1e7e0f2912c554080adc",
+			"id": "5628faffd4c606594adaa3b2",
+			"links": [],
+			"startDate": "2015-10-23T22:00:00Z",
+			"updated": "2015-10-06T20:35:12Z"
+		}`)
+	})
+
+	updateRequest := &MaintenanceWindow{EndDate: time.Date(2015, 10, 23, 23, 30, 0, 0, time.UTC)}
+	window, _, err := client.MaintenanceWindows.Update(ctx, projectID, windowID, updateRequest)
+	if err != nil {
+		t.Fatalf("MaintenanceWindows.Update returned error: %v", err)
+	}
+
+	expected := &MaintenanceWindow{
+		ID:             "5628faffd4c606594adaa3b2",
+		AlertTypeNames: []AlertTypeName{AlertTypeBackup, AlertTypeCluster},
+		Created:        "2015-10-06T20:35:12Z",
+		Description:    "upgrade",
+		EndDate:        time.Date(2015, 10, 23, 23, 30, 0, 0, time.UTC),
+		GroupID:        "5a0a1e7e0f2912c554080adc",
+		Links:          []*mongodbatlas.Link{},
+		StartDate:      time.Date(2015, 10, 23, 22, 0, 0, 0, time.UTC),
+		Updated:        "2015-10-06T20:35:12Z",
+	}
+
+	if diff := deep.Equal(window, expected); diff != nil {
+		t.Error(diff)
+	}
+}
+
+func TestMaintenanceWindows_Delete(t *testing.T) {
+	setup()
+	defer teardown()
+
+	projectID := "5a0a1e7e0f2912c554080adc"
+	windowID := "5628faffd4c606594adaa3b2"
+
+	mux.HandleFunc(fmt.Sprintf("/groups/%s/maintenanceWindows/%s", projectID, windowID), func(w http.ResponseWriter, r *http.Request) {
+		testMethod(t, r, http.MethodDelete)
+	})
+
+	_, err := client.MaintenanceWindows.Delete(ctx, projectID, windowID)
+	if err != nil {
+		t.Fatalf("MaintenanceWindows.Delete returned error: %v", err)
+	}
+}
+
+// registerMaintenanceWindowServer records the requests made to open and close a window
+func registerMaintenanceWindowServer(t *testing.T, projectID string, calls *[]string) {
+	mux.HandleFunc(fmt.Sprintf("/groups/%s/maintenanceWindows", projectID), func(w http.ResponseWriter, r *http.Request) {
+		testMethod(t, r, http.MethodPost)
+		*calls = append(*calls, fmt.Sprintf("open %s", decodeBody(t, r).(map[string]interface{})["description"]))
+		_, _ = fmt.Fprint(w, `{
+			"alertTypeNames": ["BACKUP", "CLUSTER"],
+			"created": "2015-10-06T20:35:12Z",
+			"description": "upgrade",
+			"endDate": "2015-10-23T23:30:00Z",
+			"groupId": "5a0a1e7e0f2912c554080adc",
+			"id": "5628faffd4c606594adaa3b2",
+			"links": [],
+			"startDate": "2015-10-23T22:00:00Z",
+			"updated": "2015-10-06T20:35:12Z"
+		}`)
+	})
+	mux.HandleFunc(fmt.Sprintf("/groups/%s/maintenanceWindows/5628faffd4c606594adaa3b2", projectID), func(w http.ResponseWriter, r *http.Request) {
+		testMethod(t, r, http.MethodPatch)
+		*calls = append(*calls, "close")
+		_, _ = fmt.Fprint(w, `{
+			"alertTypeNames": ["BACKUP", "CLUSTER"],
+			"created": "2015-10-06T20:35:12Z",
+			"description": "upgrade",
+			"endDate": "2015-10-23T23:30:00Z",
+			"groupId": "5a0a1e7e0f2912c554080adc",
+			"id": "5628faffd4c606594adaa3b2",
+			"links": [],
+			"startDate": "2015-10-23T22:00:00Z",
+			"updated": "2015-10-06T20:35:12Z"
+		}`)
+	})
+}
+
+func TestMaintenanceWindows_WithMaintenanceWindow(t *testing.T) {
+	setup()
+	defer teardown()
+
+	projectID := "5a0a1e7e0f2912c554080adc"
+	var calls []string
+	registerMaintenanceWindowServer(t, projectID, &calls)
+
+	err := client.MaintenanceWindows.WithMaintenanceWindow(ctx, projectID, "upgrade to 4.4", time.Hour, func(context.Context) error {
+		calls = append(calls, "change")
+		return nil
+	})
+	if err != nil {
+		t.Fatalf("MaintenanceWindows.WithMaintenanceWindow returned error: %v", err)
+	}
+
+	if diff := deep.Equal(calls, []string{"open upgrade to 4.4", "change", "close"}); diff != nil {
+		t.Error(diff)
+	}
+}
+
+func TestMaintenanceWindows_WithMaintenanceWindow_failure(t *testing.T) {
+	setup()
+	defer teardown()
+
+	projectID := "5a0a1e7e0f2912c554080adc"
+	var calls []string
+	registerMaintenanceWindowServer(t, projectID, &calls)
+
+	errChange := errors.New("change failed")
+	err := client.MaintenanceWindows.WithMaintenanceWindow(ctx, projectID, "upgrade to 4.4", time.Hour, func(context.Context) error {
+		calls = append(calls, "change")
+		return errChange
+	})
+	if err != errChange {
+		t.Fatalf("expected the error of the change, got %v", err)
+	}
+
+	if diff := deep.Equal(calls, []string{"open upgrade to 4.4", "change", "close"}); diff != nil {
+		t.Error(diff)
+	}
+}
+
+func TestMaintenanceWindows_WithMaintenanceWindow_panic(t *testing.T) {
+	setup()
+	defer teardown()
+
+	projectID := "5a0a1e7e0f2912c554080adc"
+	var calls []string
+	registerMaintenanceWindowServer(t, projectID, &calls)
+
+	func() {
+		defer func() {
+			if r := recover(); r != "change panicked" {
+				t.Errorf("expected the panic to carry on, got %v", r)
+			}
+		}()
+		_ = client.MaintenanceWindows.WithMaintenanceWindow(ctx, projectID, "upgrade to 4.4", time.Hour, func(context.Context) error {
+			calls = append(calls, "change")
+			panic("change panicked")
+		})
+	}()
+
+	if diff := deep.Equal(calls, []string{"open upgrade to 4.4", "change", "close"}); diff != nil {
+		t.Error(diff)
+	}
+}
+
+func TestMaintenanceWindows_WithMaintenanceWindow_noDescription(t *testing.T) {
+	setup()
+	defer teardown()
+
+	err := client.MaintenanceWindows.WithMaintenanceWindow(ctx, "5a0a1e7e0f2912c554080adc", "", time.Hour, func(context.Context) error {
+		return nil
+	})
+	if err == nil {
+		t.Fatal("expected an error")
+	}
+}
@@ -0,0 +1,270 @@
+// Copyright 2020 MongoDB Inc
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package cloudmanager
+
+import (
+	"context"
+	"fmt"
+	"net/http"
+	"net/url"
+	"time"
+
+	atlas "github.com/mongodb/go-client-mongodb-atlas/mongodbatlas"
+)
+
+const (
+	hostMeasurementsBasePath = "groups/%s/hosts/%s"
+)
+
+// MeasurementsService is an interface for interfacing with the Measurements
+// endpoints of the MongoDB Cloud Manager API.
+// See more: https://docs.cloudmanager.mongodb.com/reference/api/measures/
+type MeasurementsService interface {
+	Host(context.Context, string, string, *MeasurementsOptions) (*Measurements, *atlas.Response, error)
+	Disk(context.Context, string, string, string, *MeasurementsOptions) (*Measurements, *atlas.Response, error)
+	Database(context.Context, string, string, string, *MeasurementsOptions) (*Measurements, *atlas.Response, error)
+	ListDisks(context.Context, string, string, *atlas.ListOptions) (*Disks, *atlas.Response, error)
+	ListDatabases(context.Context, string, string, *atlas.ListOptions) (*Databases, *atlas.Response, error)
+}
+
+// MeasurementsServiceOp handles communication with the Measurements related methods of the
+// MongoDB Cloud Manager API
+type MeasurementsServiceOp struct {
+	client *Client
+}
+
+var _ MeasurementsService = &MeasurementsServiceOp{}
+
+// MeasurementsOptions specify the time range, granularity, and metrics to retrieve.
+// Either Period or Start and End must be specified, but not both.
+type MeasurementsOptions struct {
+	Granularity string     `url:"granularity"`      // ISO-8601 duration between data points, e.g. PT1M
+	Period      string     `url:"period,omitempty"` // ISO-8601 duration to retrieve, ending now, e.g. PT1H
+	Start       *time.Time `url:"start,omitempty"`  // beginning of the time range to retrieve
+	End         *time.Time `url:"end,omitempty"`    // end of the time range to retrieve
+	Metrics     []string   `url:"m,omitempty"`      // names of the metrics to retrieve, all if empty
+}
+
+// DataPoint a single measurement; Value is nil if no data was collected for the timestamp
+type DataPoint struct {
+	Timestamp time.Time `json:"timestamp"`
+	Value     *float64  `json:"value"`
+}
+
+// Measurement a time series for a single metric
+type Measurement struct {
+	DataPoints []*DataPoint `json:"dataPoints"`
+	Name       string       `json:"name"`
+	Units      string       `json:"units"`
+}
+
+// Measurements represents the measurements of a host, disk partition, or database
+type Measurements struct {
+	DatabaseName  string         `json:"databaseName,omitempty"`
+	End           time.Time      `json:"end"`
+	Granularity   string         `json:"granularity"`
+	GroupID       string         `json:"groupId"`
+	HostID        string         `json:"hostId"`
+	Links         []*atlas.Link  `json:"links,omitempty"`
+	Measurements  []*Measurement `json:"measurements"`
+	PartitionName string         `json:"partitionName,omitempty"`
+	ProcessID     string         `json:"processId,omitempty"`
+	Start         time.Time      `json:"start"`
+}
+
+// Disk a disk partition of a host
+type Disk struct {
+	Links         []*atlas.Link `json:"links,omitempty"`
+	PartitionName string        `json:"partitionName"`
+}
+
+// Disks represents a array of disk partitions
+type Disks struct {
+	Links      []*atlas.Link `json:"links"`
+	Results    []*Disk       `json:"results"`
+	TotalCount int           `json:"totalCount"`
+}
+
+// Database a database of a host
+type Database struct {
+	DatabaseName string        `json:"databaseName"`
+	Links        []*atlas.Link `json:"links,omitempty"`
+}
+
+// Databases represents a array of databases
+type Databases struct {
+	Links      []*atlas.Link `json:"links"`
+	Results    []*Database   `json:"results"`
+	TotalCount int           `json:"totalCount"`
+}
+
+// Host gets the measurements of a host.
+// See more: https://docs.cloudmanager.mongodb.com/reference/api/measures/get-host-process-system-measurements/
+func (s *MeasurementsServiceOp) Host(ctx context.Context, projectID, hostID string, opts *MeasurementsOptions) (*Measurements, *atlas.Response, error) {
+	if projectID == "" {
+		return nil, nil, atlas.NewArgError("projectID", "must be set")
+	}
+	if hostID == "" {
+		return nil, nil, atlas.NewArgError("hostID", "must be set")
+	}
+
+	basePath := fmt.Sprintf(hostMeasurementsBasePath, projectID, hostID)
+	path := fmt.Sprintf("%s/measurements", basePath)
+
+	return s.get(ctx, path, opts)
+}
+
+// Disk gets the measurements of a disk partition of a host.
+// See more: https://docs.cloudmanager.mongodb.com/reference/api/measures/get-disk-measurements/
+func (s *MeasurementsServiceOp) Disk(ctx context.Context, projectID, hostID, partitionName string, opts *MeasurementsOptions) (*Measurements, *atlas.Response, error) {
+	if projectID == "" {
+		return nil, nil, atlas.NewArgError("projectID", "must be set")
+	}
+	if hostID == "" {
+		return nil, nil, atlas.NewArgError("hostID", "must be set")
+	}
+	if partitionName == "" {
+		return nil, nil, atlas.NewArgError("partitionName", "must be set")
+	}
+
+	basePath := fmt.Sprintf(hostMeasurementsBasePath, projectID, hostID)
+	path := fmt.Sprintf("%s/disks/%s/measurements", basePath, url.PathEscape(partitionName))
+
+	return s.get(ctx, path, opts)
+}
+
+// Database gets the measurements of a database of a host.
+// See more: https://docs.cloudmanager.mongodb.com/reference/api/measures/get-database-measurements/
+func (s *MeasurementsServiceOp) Database(ctx context.Context, projectID, hostID, databaseName string, opts *MeasurementsOptions) (*Measurements, *atlas.Response, error) {
+	if projectID == "" {
+		return nil, nil, atlas.NewArgError("projectID", "must be set")
+	}
+	if hostID == "" {
+		return nil, nil, atlas.NewArgError("hostID", "must be set")
+	}
+	if databaseName == "" {
+		return nil, nil, atlas.NewArgError("databaseName", "must be set")
+	}
+
+	basePath := fmt.Sprintf(hostMeasurementsBasePath, projectID, hostID)
+	path := fmt.Sprintf("%s/databases/%s/measurements", basePath, url.PathEscape(databaseName))
+
+	return s.get(ctx, path, opts)
+}
+
+// ListDisks lists the disk partitions of a host, which have measurements.
+// See more: https://docs.cloudmanager.mongodb.com/reference/api/disks/
+func (s *MeasurementsServiceOp) ListDisks(ctx context.Context, projectID, hostID string, opts *atlas.ListOptions) (*Disks, *atlas.Response, error) {
+	if projectID == "" {
+		return nil, nil, atlas.NewArgError("projectID", "must be set")
+	}
+	if hostID == "" {
+		return nil, nil, atlas.NewArgError("hostID", "must be set")
+	}
+
+	basePath := fmt.Sprintf(hostMeasurementsBasePath, projectID, hostID)
+	path, err := setListOptions(fmt.Sprintf("%s/disks", basePath), opts)
+	if err != nil {
+		return nil, nil, err
+	}
+
+	req, err := s.client.NewRequest(ctx, http.MethodGet, path, nil)
+	if err != nil {
+		return nil, nil, err
+	}
+
+	root := new(Disks)
+	resp, err := s.client.Do(ctx, req, root)
+	if err != nil {
+		return nil, resp, err
+	}
+
+	if l := root.Links; l != nil {
+		resp.Links = l
+	}
+
+	return root, resp, nil
+}
+
+// ListDatabases lists the databases of a host, which have measurements.
+// See more: https://docs.cloudmanager.mongodb.com/reference/api/databases/
+func (s *MeasurementsServiceOp) ListDatabases(ctx context.Context, projectID, hostID string, opts *atlas.ListOptions) (*Databases, *atlas.Response, error) {
+	if projectID == "" {
+		return nil, nil, atlas.NewArgError("projectID", "must be set")
+	}
+	if hostID == "" {
+		return nil, nil, atlas.NewArgError("hostID", "must be set")
+	}
+
+	basePath := fmt.Sprintf(hostMeasurementsBasePath, projectID, hostID)
+	path, err := setListOptions(fmt.Sprintf("%s/databases", basePath), opts)
+	if err != nil {
+		return nil, nil, err
+	}
+
+	req, err := s.client.NewRequest(ctx, http.MethodGet, path, nil)
+	if err != nil {
+		return nil, nil, err
+	}
+
+	root := new(Databases)
+	resp, err := s.client.Do(ctx, req, root)
+	if err != nil {
+		return nil, resp, err
+	}
+
+	if l := root.Links; l != nil {
+		resp.Links = l
+	}
+
+	return root, resp, nil
+}
+
+func (s *MeasurementsServiceOp) get(ctx context.Context, basePath string, opts *MeasurementsOptions) (*Measurements, *atlas.Response, error) {
+	if opts == nil {
+		return nil, nil, atlas.NewArgError("opts", "cannot be nil")
+	}
+	if opts.Granularity == "" {
+		return nil, nil, atlas.NewArgError("granularity", "must be set")
+	}
+	if opts.Period == "" && (opts.Start == nil || opts.End == nil) {
+		return nil, nil, atlas.NewArgError("period", "must be set, unless start and end are specified")
+	}
+	if opts.Period != "" && (opts.Start != nil || opts.End != nil) {
+		return nil, nil, atlas.NewArgError("period", "cannot be combined with start and end")
+	}
+
+	path, err := setListOptions(basePath, opts)
+	if err != nil {
+		return nil, nil, err
+	}
+
+	req, err := s.client.NewRequest(ctx, http.MethodGet, path, nil)
+	if err != nil {
+		return nil, nil, err
+	}
+
+	root := new(Measurements)
+	resp, err := s.client.Do(ctx, req, root)
+	if err != nil {
+		return nil, resp, err
+	}
+
+	if l := root.Links; l != nil {
+		resp.Links = l
+	}
+
+	return root, resp, err
+}
@@ -0,0 +1,387 @@
+// Copyright 2020 MongoDB Inc
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package cloudmanager
+
+import (
+	"fmt"
+	"net/http"
+	"net/url"
+	"testing"
+	"time"
+
+	"github.com/go-test/deep"
+	"github.com/mongodb/go-client-mongodb-atlas/mongodbatlas"
+)
+
+func TestMeasurements_Host(t *testing.T) {
+	setup()
+	defer teardown()
+
+	projectID := "5a0a1e7e0f2912c554080adc"
+	hostID := "22e3e3fbd8d7cd6f8ab2d7f1ff65d44b"
+
+	mux.HandleFunc(fmt.Sprintf("/groups/%s/hosts/%s/measurements", projectID, hostID), func(w http.ResponseWriter, r *http.Request) {
+		testMethod(t, r, http.MethodGet)
+		testQuery(t, r, url.Values{
+			"granularity": {"PT1M"},
+			"period":      {"PT1H"},
+			"m":           {"OPCOUNTER_QUERY", "OPCOUNTER_UPDATE"},
+		})
+		_, _ = fmt.Fprint(w, `{
+			"end": "2017-08-22T20:31:14Z",
+			"granularity": "PT1M",
+			"groupId": "5a0a1e7e0f2912c554080adc",
+			"hostId": "22e3e3fbd8d7cd6f8ab2d7f1ff65d44b",
+			"links": [{
+				"href": "https://cloud.mongodb.com/api/public/v1.0/groups/5a0a1e7e0f2912c554080adc/hosts/22e3e3fbd8d7cd6f8ab2d7f1ff65d44b/measurements",
+				"rel": "self"
+			}],
+			"measurements": [{
+				"dataPoints": [{
+					"timestamp": "2017-08-22T20:31:12Z",
+					"value": 12.5
+				}, {
+					"timestamp": "2017-08-22T20:31:14Z",
+					"value": null
+				}],
+				"name": "OPCOUNTER_QUERY",
+				"units": "SCALAR_PER_SECOND"
+			}],
+			"processId": "host1.example.com:27017",
+			"start": "2017-08-22T20:30:45Z"
+		}`)
+	})
+
+	opts := &MeasurementsOptions{
+		Granularity: "PT1M",
+		Period:      "PT1H",
+		Metrics:     []string{"OPCOUNTER_QUERY", "OPCOUNTER_UPDATE"},
+	}
+	measurements, _, err := client.Measurements.Host(ctx, projectID, hostID, opts)
+	if err != nil {
+		t.Fatalf("Measurements.Host returned error: %v", err)
+	}
+
+	value := 12.5
+
+	expected := &Measurements{
+		End:         time.Date(2017, 8, 22, 20, 31, 14, 0, time.UTC),
+		Granularity: "PT1M",
+		GroupID:     "5a0a1e7e0f2912c554080adc",
+		HostID:      "22e3e3fbd8d7cd6f8ab2d7f1ff65d44b",
+		Links: []*mongodbatlas.Link{
+			{
+				Href: "https://cloud.mongodb.com/api/public/v1.0/groups/5a0a1e7e0f2912c554080adc/hosts/22e3e3fbd8d7cd6f8ab2d7f1ff65d44b/measurements",
+				Rel:  "self",
+			},
+		},
+		Measurements: []*Measurement{
+			{
+				DataPoints: []*DataPoint{
+					{
+						Timestamp: time.Date(2017, 8, 22, 20, 31, 12, 0, time.UTC),
+						Value:     &value,
+					},
+					{
+						Timestamp: time.Date(2017, 8, 22, 20, 31, 14, 0, time.UTC),
+					},
+				},
+				Name:  "OPCOUNTER_QUERY",
+				Units: "SCALAR_PER_SECOND",
+			},
+		},
+		ProcessID: "host1.example.com:27017",
+		Start:     time.Date(2017, 8, 22, 20, 30, 45, 0, time.UTC),
+	}
+
+	if diff := deep.Equal(measurements, expected); diff != nil {
+		t.Error(diff)
+	}
+}
+
+func TestMeasurements_Disk(t *testing.T) {
+	setup()
+	defer teardown()
+
+	projectID := "5a0a1e7e0f2912c554080adc"
+	hostID := "22e3e3fbd8d7cd6f8ab2d7f1ff65d44b"
+
+	mux.HandleFunc(fmt.Sprintf("/groups/%s/hosts/%s/disks/data/measurements", projectID, hostID), func(w http.ResponseWriter, r *http.Request) {
+		testMethod(t, r, http.MethodGet)
+		testQuery(t, r, url.Values{
+			"granularity": {"PT1M"},
+			"start":       {"2017-08-22T20:30:45Z"},
+			"end":         {"2017-08-22T20:31:14Z"},
+		})
+		_, _ = fmt.Fprint(w, `{
+			"end": "2017-08-22T20:31:14Z",
+			"granularity": "PT1M",
+			"groupId": "5a0a1e7e0f2912c554080adc",
+			"hostId": "22e3e3fbd8d7cd6f8ab2d7f1ff65d44b",
+			"links": [{
+				"href": "https://cloud.mongodb.com/api/public/v1.0/groups/5a0a1e7e0f2912c554080adc/hosts/22e3e3fbd8d7cd6f8ab2d7f1ff65d44b/measurements",
+				"rel": "self"
+			}],
+			"measurements": [{
+				"dataPoints": [{
+					"timestamp": "2017-08-22T20:31:12Z",
+					"value": 12.5
+				}, {
+					"timestamp": "2017-08-22T20:31:14Z",
+					"value": null
+				}],
+				"name": "OPCOUNTER_QUERY",
+				"units": "SCALAR_PER_SECOND"
+			}],
+			"processId": "host1.example.com:27017",
+			"start": "2017-08-22T20:30:45Z"
+		}`)
+	})
+
+	start := time.Date(2017, 8, 22, 20, 30, 45, 0, time.UTC)
+	end := time.Date(2017, 8, 22, 20, 31, 14, 0, time.UTC)
+	opts := &MeasurementsOptions{
+		Granularity: "PT1M",
+		Start:       &start,
+		End:         &end,
+	}
+	measurements, _, err := client.Measurements.Disk(ctx, projectID, hostID, "data", opts)
+	if err != nil {
+		t.Fatalf("Measurements.Disk returned error: %v", err)
+	}
+
+	value := 12.5
+
+	expected := &Measurements{
+		End:         time.Date(2017, 8, 22, 20, 31, 14, 0, time.UTC),
+		Granularity: "PT1M",
+		GroupID:     "5a0a1e7e0f2912c554080adc",
+		HostID:      "22e3e3fbd8d7cd6f8ab2d7f1ff65d44b",
+		Links: []*mongodbatlas.Link{
+			{
+				Href: "https://cloud.mongodb.com/api/public/v1.0/groups/5a0a1e7e0f2912c554080adc/hosts/22e3e3fbd8d7cd6f8ab2d7f1ff65d44b/measurements",
+				Rel:  "self",
+			},
+		},
+		Measurements: []*Measurement{
+			{
+				DataPoints: []*DataPoint{
+					{
+						Timestamp: time.Date(2017, 8, 22, 20, 31, 12, 0, time.UTC),
+						Value:     &value,
+					},
+					{
+						Timestamp: time.Date(2017, 8, 22, 20, 31, 14, 0, time.UTC),
+					},
+				},
+				Name:  "OPCOUNTER_QUERY",
+				Units: "SCALAR_PER_SECOND",
+			},
+		},
+		ProcessID: "host1.example.com:27017",
+		Start:     time.Date(2017, 8, 22, 20, 30, 45, 0, time.UTC),
+	}
+
+	if diff := deep.Equal(measurements, expected); diff != nil {
+		t.Error(diff)
+	}
+}
+
+func TestMeasurements_Database(t *testing.T) {
+	setup()
+	defer teardown()
+
+	projectID := "5a0a1e7e0f2912c554080adc"
+	hostID := "22e3e3fbd8d7cd6f8ab2d7f1ff65d44b"
+
+	mux.HandleFunc(fmt.Sprintf("/groups/%s/hosts/%s/databases/test/measurements", projectID, hostID), func(w http.ResponseWriter, r *http.Request) {
+		testMethod(t, r, http.MethodGet)
+		_, _ = fmt.Fprint(w, `{
+			"end": "2017-08-22T20:31:14Z",
+			"granularity": "PT1M",
+			"groupId": "5a0a1e7e0f2912c554080adc",
+			"hostId": "22e3e3fbd8d7cd6f8ab2d7f1ff65d44b",
+			"links": [{
+				"href": "https://cloud.mongodb.com/api/public/v1.0/groups/5a0a1e7e0f2912c554080adc/hosts/22e3e3fbd8d7cd6f8ab2d7f1ff65d44b/measurements",
+				"rel": "self"
+			}],
+			"measurements": [{
+				"dataPoints": [{
+					"timestamp": "2017-08-22T20:31:12Z",
+					"value": 12.5
+				}, {
+					"timestamp": "2017-08-22T20:31:14Z",
+					"value": null
+				}],
+				"name": "OPCOUNTER_QUERY",
+				"units": "SCALAR_PER_SECOND"
+			}],
+			"processId": "host1.example.com:27017",
+			"start": "2017-08-22T20:30:45Z"
+		}`)
+	})
+
+	opts := &MeasurementsOptions{
+		Granularity: "PT1M",
+		Period:      "PT1H",
+	}
+	measurements, _, err := client.Measurements.Database(ctx, projectID, hostID, "test", opts)
+	if err != nil {
+		t.Fatalf("Measurements.Database returned error: %v", err)
+	}
+
+	value := 12.5
+
+	expected := &Measurements{
+		End:         time.Date(2017, 8, 22, 20, 31, 14, 0, time.UTC),
+		Granularity: "PT1M",
+		GroupID:     "5a0a1e7e0f2912c554080adc",
+		HostID:      "22e3e3fbd8d7cd6f8ab2d7f1ff65d44b",
+		Links: []*mongodbatlas.Link{
+			{
+				Href: "https://cloud.mongodb.com/api/public/v1.0/groups/5a0a1e7e0f2912c554080adc/hosts/22e3e3fbd8d7cd6f8ab2d7f1ff65d44b/measurements",
+				Rel:  "self",
+			},
+		},
+		Measurements: []*Measurement{
+			{
+				DataPoints: []*DataPoint{
+					{
+						Timestamp: time.Date(2017, 8, 22, 20, 31, 12, 0, time.UTC),
+						Value:     &value,
+					},
+					{
+						Timestamp: time.Date(2017, 8, 22, 20, 31, 14, 0, time.UTC),
+					},
+				},
+				Name:  "OPCOUNTER_QUERY",
+				Units: "SCALAR_PER_SECOND",
+			},
+		},
+		ProcessID: "host1.example.com:27017",
+		Start:     time.Date(2017, 8, 22, 20, 30, 45, 0, time.UTC),
+	}
+
+	if diff := deep.Equal(measurements, expected); diff != nil {
+		t.Error(diff)
+	}
+}
+
+func TestMeasurements_invalidOptions(t *testing.T) {
+	start := time.Now().Add(-time.Hour)
+	end := time.Now()
+
+	tests := map[string]*MeasurementsOptions{
+		"nil":               nil,
+		"missing range":     {Granularity: "PT1M"},
+		"missing end":       {Granularity: "PT1M", Start: &start},
+		"missing grain":     {Period: "PT1H"},
+		"period with range": {Granularity: "PT1M", Period: "PT1H", Start: &start, End: &end},
+	}
+
+	for name, opts := range tests {
+		opts := opts
+		t.Run(name, func(t *testing.T) {
+			if _, _, err := NewClient(nil).Measurements.Host(ctx, "1", "2", opts); err == nil {
+				t.Error("expected an error")
+			}
+		})
+	}
+}
+
+func TestMeasurements_ListDisks(t *testing.T) {
+	setup()
+	defer teardown()
+
+	projectID := "5a0a1e7e0f2912c554080adc"
+	hostID := "22e3e3fbd8d7cd6f8ab2d7f1ff65d44b"
+
+	mux.HandleFunc(fmt.Sprintf("/groups/%s/hosts/%s/disks", projectID, hostID), func(w http.ResponseWriter, r *http.Request) {
+		testMethod(t, r, http.MethodGet)
+		_, _ = fmt.Fprint(w, `{
+			"links": [],
+			"results": [{
+				"links": [],
+				"partitionName": "data"
+			}],
+			"totalCount": 1
+		}`)
+	})
+
+	disks, _, err := client.Measurements.ListDisks(ctx, projectID, hostID, nil)
+	if err != nil {
+		t.Fatalf("Measurements.ListDisks returned error: %v", err)
+	}
+
+	expected := &Disks{
+		Links: []*mongodbatlas.Link{},
+		Results: []*Disk{
+			{
+				Links:         []*mongodbatlas.Link{},
+				PartitionName: "data",
+			},
+		},
+		TotalCount: 1,
+	}
+
+	if diff := deep.Equal(disks, expected); diff != nil {
+		t.Error(diff)
+	}
+}
+
+func TestMeasurements_ListDatabases(t *testing.T) {
+	setup()
+	defer teardown()
+
+	projectID := "5a0a1e7e0f2912c554080adc"
+	hostID := "22e3e3fbd8d7cd6f8ab2d7f1ff65d44b"
+
+	mux.HandleFunc(fmt.Sprintf("/groups/%s/hosts/%s/databases", projectID, hostID), func(w http.ResponseWriter, r *http.Request) {
+		testMethod(t, r, http.MethodGet)
+		testQuery(t, r, url.Values{
+			"pageNum":      {"1"},
+			"itemsPerPage": {"100"},
+		})
+		_, _ = fmt.Fprint(w, `{
+			"links": [],
+			"results": [{
+				"databaseName": "test",
+				"links": []
+			}],
+			"totalCount": 1
+		}`)
+	})
+
+	opts := &mongodbatlas.ListOptions{PageNum: 1, ItemsPerPage: 100}
+	databases, _, err := client.Measurements.ListDatabases(ctx, projectID, hostID, opts)
+	if err != nil {
+		t.Fatalf("Measurements.ListDatabases returned error: %v", err)
+	}
+
+	expected := &Databases{
+		Links: []*mongodbatlas.Link{},
+		Results: []*Database{
+			{
+				DatabaseName: "test",
+				Links:        []*mongodbatlas.Link{},
+			},
+		},
+		TotalCount: 1,
+	}
+
+	if diff := deep.Equal(databases, expected); diff != nil {
+		t.Error(diff)
+	}
+}
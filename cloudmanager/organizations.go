@@ -33,8 +33,12 @@ type OrganizationsService interface {
 	GetAllOrganizations(context.Context) (*Organizations, *atlas.Response, error)
 	GetOneOrganization(context.Context, string) (*Organization, *atlas.Response, error)
 	GetProjects(context.Context, string) (*Projects, *atlas.Response, error)
+	ListProjects(context.Context, string, *atlas.ListOptions) (*Projects, *atlas.Response, error)
+	ListUsers(context.Context, string, *atlas.ListOptions) (*Users, *atlas.Response, error)
 	Create(context.Context, *Organization) (*Organization, *atlas.Response, error)
+	Update(context.Context, string, *Organization) (*Organization, *atlas.Response, error)
 	Delete(context.Context, string) (*atlas.Response, error)
+	DeleteWithOptions(context.Context, string, *OrganizationDeleteOptions) (*atlas.Response, error)
 }
 
 // OrganizationsServiceOp handles communication with the Projects related methods of the
@@ -59,6 +63,24 @@ type Organizations struct {
 	TotalCount int             `json:"totalCount"`
 }
 
+// OrganizationDeleteOptions control the deletion of an organization which still has projects
+type OrganizationDeleteOptions struct {
+	// Cascade deletes the projects of the organization before the organization itself
+	Cascade bool
+	// Progress, if set, is called after each project is deleted with the number deleted so far and the total
+	Progress func(project *Project, deleted, total int)
+}
+
+// OrganizationNotEmptyError is returned when deleting an organization which still has projects without cascading
+type OrganizationNotEmptyError struct {
+	OrgID        string
+	ProjectCount int
+}
+
+func (e *OrganizationNotEmptyError) Error() string {
+	return fmt.Sprintf("organization %s still has %d project(s), delete them first or set Cascade", e.OrgID, e.ProjectCount)
+}
+
 // GetAllOrganizations gets all organizations.
 // See more: https://docs.cloudmanager.mongodb.com/reference/api/organizations/organization-get-all/
 func (s *OrganizationsServiceOp) GetAllOrganizations(ctx context.Context) (*Organizations, *atlas.Response, error) {
@@ -104,14 +126,24 @@ func (s *OrganizationsServiceOp) GetOneOrganization(ctx context.Context, orgID s
 	return root, resp, err
 }
 
-// GetProjects gets all projects for the given organization ID
+// GetProjects gets the first page of projects for the given organization ID
 // See more: https://docs.cloudmanager.mongodb.com/reference/api/organizations/organization-get-all-projects/
 func (s *OrganizationsServiceOp) GetProjects(ctx context.Context, orgID string) (*Projects, *atlas.Response, error) {
+	return s.ListProjects(ctx, orgID, nil)
+}
+
+// ListProjects gets a page of the projects for the given organization ID
+// See more: https://docs.cloudmanager.mongodb.com/reference/api/organizations/organization-get-all-projects/
+func (s *OrganizationsServiceOp) ListProjects(ctx context.Context, orgID string, opts *atlas.ListOptions) (*Projects, *atlas.Response, error) {
 	if orgID == "" {
 		return nil, nil, atlas.NewArgError("orgID", "must be set")
 	}
 
-	path := fmt.Sprintf("%s/%s/groups", orgsBasePath, orgID)
+	basePath := fmt.Sprintf("%s/%s/groups", orgsBasePath, orgID)
+	path, err := setListOptions(basePath, opts)
+	if err != nil {
+		return nil, nil, err
+	}
 
 	req, err := s.client.NewRequest(ctx, http.MethodGet, path, nil)
 	if err != nil {
@@ -124,7 +156,42 @@ func (s *OrganizationsServiceOp) GetProjects(ctx context.Context, orgID string)
 		return nil, resp, err
 	}
 
-	return root, resp, err
+	if l := root.Links; l != nil {
+		resp.Links = l
+	}
+
+	return root, resp, nil
+}
+
+// ListUsers gets the users of an organization, with their roles.
+// See more: https://docs.cloudmanager.mongodb.com/reference/api/organizations/organization-get-all-users/
+func (s *OrganizationsServiceOp) ListUsers(ctx context.Context, orgID string, opts *atlas.ListOptions) (*Users, *atlas.Response, error) {
+	if orgID == "" {
+		return nil, nil, atlas.NewArgError("orgID", "must be set")
+	}
+
+	basePath := fmt.Sprintf("%s/%s/users", orgsBasePath, orgID)
+	path, err := setListOptions(basePath, opts)
+	if err != nil {
+		return nil, nil, err
+	}
+
+	req, err := s.client.NewRequest(ctx, http.MethodGet, path, nil)
+	if err != nil {
+		return nil, nil, err
+	}
+
+	root := new(Users)
+	resp, err := s.client.Do(ctx, req, root)
+	if err != nil {
+		return nil, resp, err
+	}
+
+	if l := root.Links; l != nil {
+		resp.Links = l
+	}
+
+	return root, resp, nil
 }
 
 // Create creates an organization.
@@ -148,13 +215,94 @@ func (s *OrganizationsServiceOp) Create(ctx context.Context, createRequest *Orga
 	return root, resp, err
 }
 
-// Delete deletes an organization.
+// Update renames an organization.
+// See more: https://docs.cloudmanager.mongodb.com/reference/api/organizations/organization-rename/
+func (s *OrganizationsServiceOp) Update(ctx context.Context, orgID string, updateRequest *Organization) (*Organization, *atlas.Response, error) {
+	if orgID == "" {
+		return nil, nil, atlas.NewArgError("orgID", "must be set")
+	}
+	if updateRequest == nil {
+		return nil, nil, atlas.NewArgError("updateRequest", "cannot be nil")
+	}
+	if updateRequest.Name == "" {
+		return nil, nil, atlas.NewArgError("name", "must be set")
+	}
+
+	path := fmt.Sprintf("%s/%s", orgsBasePath, orgID)
+
+	req, err := s.client.NewRequest(ctx, http.MethodPatch, path, &Organization{Name: updateRequest.Name})
+	if err != nil {
+		return nil, nil, err
+	}
+
+	root := new(Organization)
+	resp, err := s.client.Do(ctx, req, root)
+	if err != nil {
+		return nil, resp, err
+	}
+
+	return root, resp, err
+}
+
+// Delete deletes an organization. If the organization still has projects, an *OrganizationNotEmptyError is returned
+// and nothing is deleted; use DeleteWithOptions to delete the projects as well.
 // See more: https://docs.cloudmanager.mongodb.com/reference/api/organizations/organization-delete-one/
 func (s *OrganizationsServiceOp) Delete(ctx context.Context, orgID string) (*atlas.Response, error) {
+	return s.DeleteWithOptions(ctx, orgID, nil)
+}
+
+// DeleteWithOptions deletes an organization. If the organization still has projects, an *OrganizationNotEmptyError is
+// returned, unless opts.Cascade is set, in which case the projects are deleted one by one first.
+// If deleting a project fails, or ctx is cancelled, the remaining projects and the organization are kept.
+// See more: https://docs.cloudmanager.mongodb.com/reference/api/organizations/organization-delete-one/
+func (s *OrganizationsServiceOp) DeleteWithOptions(ctx context.Context, orgID string, opts *OrganizationDeleteOptions) (*atlas.Response, error) {
 	if orgID == "" {
 		return nil, atlas.NewArgError("orgID", "must be set")
 	}
+	if opts == nil {
+		opts = &OrganizationDeleteOptions{}
+	}
+
+	var (
+		projects []*Project
+		resp     *atlas.Response
+	)
+	listOpts := &atlas.ListOptions{}
+	err := listPages(listOpts, func() (int, int, error) {
+		page, r, err := s.ListProjects(ctx, orgID, listOpts)
+		resp = r
+		if err != nil {
+			return 0, 0, err
+		}
+		projects = append(projects, page.Results...)
+		return len(page.Results), page.TotalCount, nil
+	})
+	if err != nil {
+		return resp, err
+	}
+
+	if len(projects) > 0 && !opts.Cascade {
+		return nil, &OrganizationNotEmptyError{OrgID: orgID, ProjectCount: len(projects)}
+	}
+
+	for i, project := range projects {
+		// stop between deletions once cancelled, rather than relying on the next request to fail
+		if err := ctx.Err(); err != nil {
+			return nil, fmt.Errorf("deleted %d of %d project(s): %w", i, len(projects), err)
+		}
+		if resp, err := s.client.Projects.Delete(ctx, project.ID); err != nil {
+			return resp, fmt.Errorf("deleting project %s (%s): %w", project.Name, project.ID, err)
+		}
+		if opts.Progress != nil {
+			opts.Progress(project, i+1, len(projects))
+		}
+	}
+
+	return s.delete(ctx, orgID)
+}
 
+// delete deletes an organization without checking for projects first
+func (s *OrganizationsServiceOp) delete(ctx context.Context, orgID string) (*atlas.Response, error) {
 	basePath := fmt.Sprintf("%s/%s", orgsBasePath, orgID)
 
 	req, err := s.client.NewRequest(ctx, http.MethodDelete, basePath, nil)
@@ -15,8 +15,11 @@
 package cloudmanager
 
 import (
+	"context"
+	"errors"
 	"fmt"
 	"net/http"
+	"net/url"
 	"testing"
 
 	"github.com/go-test/deep"
@@ -271,6 +274,10 @@ func TestOrganizations_Delete(t *testing.T) {
 
 	orgID := "5a0a1e7e0f2912c554080adc"
 
+	mux.HandleFunc(fmt.Sprintf("/orgs/%s/groups", orgID), func(w http.ResponseWriter, r *http.Request) {
+		testMethod(t, r, http.MethodGet)
+		_, _ = fmt.Fprint(w, `{"links": [], "results": [], "totalCount": 0}`)
+	})
 	mux.HandleFunc(fmt.Sprintf("/orgs/%s", orgID), func(w http.ResponseWriter, r *http.Request) {
 		testMethod(t, r, http.MethodDelete)
 	})
@@ -280,3 +287,265 @@ func TestOrganizations_Delete(t *testing.T) {
 		t.Fatalf("Organizations.Delete returned error: %v", err)
 	}
 }
+
+func TestOrganizations_Delete_notEmpty(t *testing.T) {
+	setup()
+	defer teardown()
+
+	orgID := "5a0a1e7e0f2912c554080adc"
+	deleted := handleOrgWithProjects(t, orgID, "")
+
+	_, err := client.Organizations.Delete(ctx, orgID)
+
+	expected := &OrganizationNotEmptyError{OrgID: orgID, ProjectCount: 3}
+	if diff := deep.Equal(err, expected); diff != nil {
+		t.Error(diff)
+	}
+	if len(*deleted) != 0 {
+		t.Errorf("expected nothing to be deleted, got %v", *deleted)
+	}
+}
+
+func TestOrganizations_ListProjects(t *testing.T) {
+	setup()
+	defer teardown()
+
+	orgID := "5980cfdf0b6d97029d82f86e"
+
+	mux.HandleFunc(fmt.Sprintf("/orgs/%s/groups", orgID), func(w http.ResponseWriter, r *http.Request) {
+		testMethod(t, r, http.MethodGet)
+		testQuery(t, r, url.Values{"pageNum": {"2"}, "itemsPerPage": {"1"}})
+		_, _ = fmt.Fprint(w, `{"links": [], "results": [{"id": "56a10a80e4b0fd3b9a9bb0c2", "name": "ProjectBar"}], "totalCount": 2}`)
+	})
+
+	projects, _, err := client.Organizations.ListProjects(ctx, orgID, &mongodbatlas.ListOptions{PageNum: 2, ItemsPerPage: 1})
+	if err != nil {
+		t.Fatalf("Organizations.ListProjects returned error: %v", err)
+	}
+
+	expected := &Projects{
+		Links:      []*mongodbatlas.Link{},
+		Results:    []*Project{{ID: "56a10a80e4b0fd3b9a9bb0c2", Name: "ProjectBar"}},
+		TotalCount: 2,
+	}
+
+	if diff := deep.Equal(projects, expected); diff != nil {
+		t.Error(diff)
+	}
+}
+
+func TestOrganizations_ListUsers(t *testing.T) {
+	setup()
+	defer teardown()
+
+	orgID := "5a0a1e7e0f2912c554080adb"
+
+	mux.HandleFunc(fmt.Sprintf("/orgs/%s/users", orgID), func(w http.ResponseWriter, r *http.Request) {
+		testMethod(t, r, http.MethodGet)
+		_, _ = fmt.Fprint(w, `{
+			"links": [],
+			"results": [{
+				"emailAddress": "jane.doe@example.com",
+				"firstName": "Jane",
+				"id": "533dc19ce4b00835ff81e2eb",
+				"lastName": "Doe",
+				"links": [],
+				"roles": [
+					{"orgId": "5a0a1e7e0f2912c554080adb", "roleName": "ORG_MEMBER"},
+					{"groupId": "5a0a1e7e0f2912c554080adc", "roleName": "GROUP_READ_ONLY"}
+				],
+				"username": "jane.doe@example.com"
+			}],
+			"totalCount": 1
+		}`)
+	})
+
+	users, _, err := client.Organizations.ListUsers(ctx, orgID, nil)
+	if err != nil {
+		t.Fatalf("Organizations.ListUsers returned error: %v", err)
+	}
+
+	expected := &Users{
+		Links: []*mongodbatlas.Link{},
+		Results: []*User{
+			{
+				EmailAddress: "jane.doe@example.com",
+				FirstName:    "Jane",
+				ID:           "533dc19ce4b00835ff81e2eb",
+				LastName:     "Doe",
+				Links:        []*mongodbatlas.Link{},
+				Roles: []*UserRole{
+					{OrgID: "5a0a1e7e0f2912c554080adb", RoleName: string(RoleOrgMember)},
+					{GroupID: "5a0a1e7e0f2912c554080adc", RoleName: string(RoleGroupReadOnly)},
+				},
+				Username: "jane.doe@example.com",
+			},
+		},
+		TotalCount: 1,
+	}
+
+	if diff := deep.Equal(users, expected); diff != nil {
+		t.Error(diff)
+	}
+}
+
+func TestOrganizations_Update(t *testing.T) {
+	setup()
+	defer teardown()
+
+	orgID := "5a0a1e7e0f2912c554080adc"
+
+	mux.HandleFunc(fmt.Sprintf("/orgs/%s", orgID), func(w http.ResponseWriter, r *http.Request) {
+		testMethod(t, r, http.MethodPatch)
+
+		expected := map[string]interface{}{"name": "OrgBar"}
+		if diff := deep.Equal(decodeBody(t, r), expected); diff != nil {
+			t.Error(diff)
+		}
+
+		_, _ = fmt.Fprint(w, `{"id": "5a0a1e7e0f2912c554080adc", "links": [], "name": "OrgBar"}`)
+	})
+
+	org, _, err := client.Organizations.Update(ctx, orgID, &Organization{ID: orgID, Name: "OrgBar"})
+	if err != nil {
+		t.Fatalf("Organizations.Update returned error: %v", err)
+	}
+
+	expected := &Organization{ID: orgID, Links: []*mongodbatlas.Link{}, Name: "OrgBar"}
+	if diff := deep.Equal(org, expected); diff != nil {
+		t.Error(diff)
+	}
+}
+
+// handleOrgWithProjects serves an organization with three projects over two pages, and records the deletions
+func handleOrgWithProjects(t *testing.T, orgID string, failProjectID string) *[]string {
+	var deleted []string
+
+	pages := map[string]string{
+		"1": `[{"id": "p1", "name": "ProjectOne"}, {"id": "p2", "name": "ProjectTwo"}]`,
+		"2": `[{"id": "p3", "name": "ProjectThree"}]`,
+	}
+
+	mux.HandleFunc(fmt.Sprintf("/orgs/%s/groups", orgID), func(w http.ResponseWriter, r *http.Request) {
+		testMethod(t, r, http.MethodGet)
+
+		results, ok := pages[r.URL.Query().Get("pageNum")]
+		if !ok {
+			results = "[]"
+		}
+		_, _ = fmt.Fprintf(w, `{"links": [], "results": %s, "totalCount": 3}`, results)
+	})
+
+	mux.HandleFunc("/groups/", func(w http.ResponseWriter, r *http.Request) {
+		testMethod(t, r, http.MethodDelete)
+
+		projectID := r.URL.Path[len("/groups/"):]
+		if projectID == failProjectID {
+			w.WriteHeader(http.StatusConflict)
+			_, _ = fmt.Fprint(w, `{"detail": "project has active deployments", "error": 409, "errorCode": "CANNOT_CLOSE_GROUP_ACTIVE_ATLAS_CLUSTERS", "reason": "Conflict"}`)
+			return
+		}
+		deleted = append(deleted, projectID)
+	})
+
+	mux.HandleFunc(fmt.Sprintf("/orgs/%s", orgID), func(w http.ResponseWriter, r *http.Request) {
+		testMethod(t, r, http.MethodDelete)
+		deleted = append(deleted, orgID)
+	})
+
+	return &deleted
+}
+
+func TestOrganizations_DeleteWithOptions_notEmpty(t *testing.T) {
+	setup()
+	defer teardown()
+
+	orgID := "5a0a1e7e0f2912c554080adc"
+	deleted := handleOrgWithProjects(t, orgID, "")
+
+	_, err := client.Organizations.DeleteWithOptions(ctx, orgID, nil)
+
+	var notEmpty *OrganizationNotEmptyError
+	if !errors.As(err, &notEmpty) {
+		t.Fatalf("expected an *OrganizationNotEmptyError, got %v", err)
+	}
+	if notEmpty.ProjectCount != 3 {
+		t.Errorf("expected 3 projects, got %d", notEmpty.ProjectCount)
+	}
+	if len(*deleted) != 0 {
+		t.Errorf("expected nothing to be deleted, got %v", *deleted)
+	}
+}
+
+func TestOrganizations_DeleteWithOptions_cascade(t *testing.T) {
+	setup()
+	defer teardown()
+
+	orgID := "5a0a1e7e0f2912c554080adc"
+	deleted := handleOrgWithProjects(t, orgID, "")
+
+	var progress []string
+	opts := &OrganizationDeleteOptions{
+		Cascade: true,
+		Progress: func(project *Project, deleted, total int) {
+			progress = append(progress, fmt.Sprintf("%s %d/%d", project.Name, deleted, total))
+		},
+	}
+
+	_, err := client.Organizations.DeleteWithOptions(ctx, orgID, opts)
+	if err != nil {
+		t.Fatalf("Organizations.DeleteWithOptions returned error: %v", err)
+	}
+
+	if diff := deep.Equal(*deleted, []string{"p1", "p2", "p3", orgID}); diff != nil {
+		t.Error(diff)
+	}
+	if diff := deep.Equal(progress, []string{"ProjectOne 1/3", "ProjectTwo 2/3", "ProjectThree 3/3"}); diff != nil {
+		t.Error(diff)
+	}
+}
+
+func TestOrganizations_DeleteWithOptions_cascadeFailure(t *testing.T) {
+	setup()
+	defer teardown()
+
+	orgID := "5a0a1e7e0f2912c554080adc"
+	deleted := handleOrgWithProjects(t, orgID, "p2")
+
+	_, err := client.Organizations.DeleteWithOptions(ctx, orgID, &OrganizationDeleteOptions{Cascade: true})
+
+	var apiErr *mongodbatlas.ErrorResponse
+	if !errors.As(err, &apiErr) {
+		t.Fatalf("expected an *ErrorResponse, got %v", err)
+	}
+	if diff := deep.Equal(*deleted, []string{"p1"}); diff != nil {
+		t.Error(diff)
+	}
+}
+
+func TestOrganizations_DeleteWithOptions_cancelled(t *testing.T) {
+	setup()
+	defer teardown()
+
+	orgID := "5a0a1e7e0f2912c554080adc"
+	deleted := handleOrgWithProjects(t, orgID, "")
+
+	cancelCtx, cancel := context.WithCancel(ctx)
+	defer cancel()
+	opts := &OrganizationDeleteOptions{
+		Cascade: true,
+		Progress: func(project *Project, deleted, total int) {
+			if deleted == 2 {
+				cancel()
+			}
+		},
+	}
+
+	_, err := client.Organizations.DeleteWithOptions(cancelCtx, orgID, opts)
+	if !errors.Is(err, context.Canceled) {
+		t.Fatalf("expected context.Canceled, got %v", err)
+	}
+	if diff := deep.Equal(*deleted, []string{"p1", "p2"}); diff != nil {
+		t.Error(diff)
+	}
+}
@@ -18,6 +18,7 @@ import (
 	"context"
 	"fmt"
 	"net/http"
+	"path"
 
 	atlas "github.com/mongodb/go-client-mongodb-atlas/mongodbatlas"
 )
@@ -34,7 +35,13 @@ type ProjectsService interface {
 	GetOneProject(context.Context, string) (*Project, *atlas.Response, error)
 	GetOneProjectByName(context.Context, string) (*Project, *atlas.Response, error)
 	Create(context.Context, *Project) (*Project, *atlas.Response, error)
+	Update(context.Context, string, *ProjectUpdateRequest) (*Project, *atlas.Response, error)
 	Delete(context.Context, string) (*atlas.Response, error)
+	ListByTag(context.Context, string) ([]*Project, error)
+	ListByNamePattern(context.Context, NamePattern) ([]*Project, error)
+	ListUsers(context.Context, string, *ProjectUsersListOptions) (*Users, *atlas.Response, error)
+	AddUsers(context.Context, string, []*ProjectUser) (*atlas.Response, error)
+	RemoveUser(context.Context, string, string) (*atlas.Response, error)
 }
 
 // ProjectsServiceOp handles communication with the Projects related methods of the
@@ -78,6 +85,41 @@ type Projects struct {
 	TotalCount int           `json:"totalCount"`
 }
 
+// ProjectUpdateRequest the fields of a project to modify; unset fields are left unchanged.
+// Tags replaces all the tags of the project, so a pointer to an empty slice removes them.
+type ProjectUpdateRequest struct {
+	Name string    `json:"name,omitempty"`
+	Tags *[]string `json:"tags,omitempty"`
+}
+
+// NamePattern matches project names, e.g. a *regexp.Regexp or a Glob
+type NamePattern interface {
+	MatchString(string) bool
+}
+
+// Glob a shell pattern matching project names, with the syntax of path.Match
+type Glob string
+
+// MatchString reports whether name matches the glob; a malformed glob matches nothing
+func (g Glob) MatchString(name string) bool {
+	matched, err := path.Match(string(g), name)
+	return err == nil && matched
+}
+
+// ProjectUsersListOptions filter the users returned by ListUsers
+type ProjectUsersListOptions struct {
+	FlattenTeams    bool `url:"flattenTeams,omitempty"`
+	IncludeOrgUsers bool `url:"includeOrgUsers,omitempty"`
+
+	atlas.ListOptions
+}
+
+// ProjectUser an existing user to add to a project, with its project roles
+type ProjectUser struct {
+	ID    string      `json:"id"`
+	Roles []*UserRole `json:"roles"`
+}
+
 // GetAllProjects gets all projects.
 // See more: https://docs.cloudmanager.mongodb.com/reference/api/groups/get-all-groups-for-current-user/
 func (s *ProjectsServiceOp) GetAllProjects(ctx context.Context) (*Projects, *atlas.Response, error) {
@@ -167,6 +209,101 @@ func (s *ProjectsServiceOp) Create(ctx context.Context, createRequest *Project)
 	return root, resp, err
 }
 
+// Update updates the name or tags of a project; only the specified fields are modified.
+// See more: https://docs.opsmanager.mongodb.com/current/reference/api/groups/update-one-group/
+func (s *ProjectsServiceOp) Update(ctx context.Context, projectID string, updateRequest *ProjectUpdateRequest) (*Project, *atlas.Response, error) {
+	if projectID == "" {
+		return nil, nil, atlas.NewArgError("projectID", "must be set")
+	}
+	if updateRequest == nil {
+		return nil, nil, atlas.NewArgError("updateRequest", "cannot be nil")
+	}
+	if updateRequest.Name == "" && updateRequest.Tags == nil {
+		return nil, nil, atlas.NewArgError("updateRequest", "must set name or tags")
+	}
+
+	path := fmt.Sprintf("%s/%s", projectBasePath, projectID)
+
+	req, err := s.client.NewRequest(ctx, http.MethodPatch, path, updateRequest)
+	if err != nil {
+		return nil, nil, err
+	}
+
+	root := new(Project)
+	resp, err := s.client.Do(ctx, req, root)
+	if err != nil {
+		return nil, resp, err
+	}
+
+	return root, resp, err
+}
+
+// ListByTag gets the projects, across all pages, which have the given tag.
+func (s *ProjectsServiceOp) ListByTag(ctx context.Context, tag string) ([]*Project, error) {
+	if tag == "" {
+		return nil, atlas.NewArgError("tag", "must be set")
+	}
+
+	return s.listMatching(ctx, func(p *Project) bool {
+		for _, t := range p.Tags {
+			if t != nil && *t == tag {
+				return true
+			}
+		}
+		return false
+	})
+}
+
+// ListByNamePattern gets the projects, across all pages, whose name matches pattern.
+func (s *ProjectsServiceOp) ListByNamePattern(ctx context.Context, pattern NamePattern) ([]*Project, error) {
+	if pattern == nil {
+		return nil, atlas.NewArgError("pattern", "cannot be nil")
+	}
+	if g, ok := pattern.(Glob); ok {
+		if _, err := path.Match(string(g), ""); err != nil {
+			return nil, atlas.NewArgError("pattern", fmt.Sprintf("%q is not a valid glob", g))
+		}
+	}
+
+	return s.listMatching(ctx, func(p *Project) bool {
+		return pattern.MatchString(p.Name)
+	})
+}
+
+// listMatching pages through all the projects of the current user and keeps those matching match
+func (s *ProjectsServiceOp) listMatching(ctx context.Context, match func(*Project) bool) ([]*Project, error) {
+	var projects []*Project
+	opts := &atlas.ListOptions{}
+	err := listPages(opts, func() (int, int, error) {
+		path, err := setListOptions(projectBasePath, opts)
+		if err != nil {
+			return 0, 0, err
+		}
+
+		req, err := s.client.NewRequest(ctx, http.MethodGet, path, nil)
+		if err != nil {
+			return 0, 0, err
+		}
+
+		page := new(Projects)
+		if _, err := s.client.Do(ctx, req, page); err != nil {
+			return 0, 0, err
+		}
+
+		for _, p := range page.Results {
+			if match(p) {
+				projects = append(projects, p)
+			}
+		}
+		return len(page.Results), page.TotalCount, nil
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	return projects, nil
+}
+
 // Delete deletes a project.
 // See more: https://docs.cloudmanager.mongodb.com/reference/api/groups/delete-one-group/
 func (s *ProjectsServiceOp) Delete(ctx context.Context, projectID string) (*atlas.Response, error) {
@@ -185,3 +322,94 @@ func (s *ProjectsServiceOp) Delete(ctx context.Context, projectID string) (*atla
 
 	return resp, err
 }
+
+// ListUsers gets the users of a project.
+// See more: https://docs.cloudmanager.mongodb.com/reference/api/groups/get-all-users-in-one-group/
+func (s *ProjectsServiceOp) ListUsers(ctx context.Context, projectID string, opts *ProjectUsersListOptions) (*Users, *atlas.Response, error) {
+	if projectID == "" {
+		return nil, nil, atlas.NewArgError("projectID", "must be set")
+	}
+
+	basePath := fmt.Sprintf("%s/%s/users", projectBasePath, projectID)
+	path, err := setListOptions(basePath, opts)
+	if err != nil {
+		return nil, nil, err
+	}
+
+	req, err := s.client.NewRequest(ctx, http.MethodGet, path, nil)
+	if err != nil {
+		return nil, nil, err
+	}
+
+	root := new(Users)
+	resp, err := s.client.Do(ctx, req, root)
+	if err != nil {
+		return nil, resp, err
+	}
+
+	if l := root.Links; l != nil {
+		resp.Links = l
+	}
+
+	return root, resp, nil
+}
+
+// AddUsers adds existing users to a project; only project roles can be granted.
+// See more: https://docs.cloudmanager.mongodb.com/reference/api/groups/add-users-to-one-group/
+func (s *ProjectsServiceOp) AddUsers(ctx context.Context, projectID string, users []*ProjectUser) (*atlas.Response, error) {
+	if projectID == "" {
+		return nil, atlas.NewArgError("projectID", "must be set")
+	}
+	if len(users) == 0 {
+		return nil, atlas.NewArgError("users", "must be set")
+	}
+	for _, user := range users {
+		if user == nil || user.ID == "" {
+			return nil, atlas.NewArgError("id", "must be set")
+		}
+		if len(user.Roles) == 0 {
+			return nil, atlas.NewArgError("roles", "must be set")
+		}
+		for _, role := range user.Roles {
+			if role == nil {
+				return nil, atlas.NewArgError("roles", "cannot contain nil")
+			}
+			if roleName := RoleName(role.RoleName); !containsRole(ProjectRoles, roleName) {
+				return nil, &InvalidRoleError{RoleName: roleName, Reason: "only project roles can be granted in a project"}
+			}
+		}
+	}
+
+	path := fmt.Sprintf("%s/%s/users", projectBasePath, projectID)
+
+	req, err := s.client.NewRequest(ctx, http.MethodPost, path, users)
+	if err != nil {
+		return nil, err
+	}
+
+	resp, err := s.client.Do(ctx, req, nil)
+
+	return resp, err
+}
+
+// RemoveUser removes a user from a project.
+// See more: https://docs.cloudmanager.mongodb.com/reference/api/groups/remove-one-user-from-one-group/
+func (s *ProjectsServiceOp) RemoveUser(ctx context.Context, projectID, userID string) (*atlas.Response, error) {
+	if projectID == "" {
+		return nil, atlas.NewArgError("projectID", "must be set")
+	}
+	if userID == "" {
+		return nil, atlas.NewArgError("userID", "must be set")
+	}
+
+	path := fmt.Sprintf("%s/%s/users/%s", projectBasePath, projectID, userID)
+
+	req, err := s.client.NewRequest(ctx, http.MethodDelete, path, nil)
+	if err != nil {
+		return nil, err
+	}
+
+	resp, err := s.client.Do(ctx, req, nil)
+
+	return resp, err
+}
@@ -17,6 +17,8 @@ package cloudmanager
 import (
 	"fmt"
 	"net/http"
+	"net/url"
+	"regexp"
 	"testing"
 
 	"github.com/go-test/deep"
@@ -389,3 +391,275 @@ func TestProject_Delete(t *testing.T) {
 		t.Fatalf("Projects.Delete returned error: %v", err)
 	}
 }
+
+func TestProject_ListUsers(t *testing.T) {
+	setup()
+	defer teardown()
+
+	projectID := "5a0a1e7e0f2912c554080adc"
+
+	mux.HandleFunc(fmt.Sprintf("/groups/%s/users", projectID), func(w http.ResponseWriter, r *http.Request) {
+		testMethod(t, r, http.MethodGet)
+		testQuery(t, r, url.Values{"flattenTeams": {"true"}, "pageNum": {"2"}, "itemsPerPage": {"1"}})
+		_, _ = fmt.Fprint(w, `{
+			"links": [],
+			"results": [{
+				"emailAddress": "jane.doe@example.com",
+				"firstName": "Jane",
+				"id": "533dc19ce4b00835ff81e2eb",
+				"lastName": "Doe",
+				"links": [],
+				"roles": [
+					{"orgId": "5a0a1e7e0f2912c554080adb", "roleName": "ORG_MEMBER"},
+					{"groupId": "5a0a1e7e0f2912c554080adc", "roleName": "GROUP_READ_ONLY"}
+				],
+				"username": "jane.doe@example.com"
+			}],
+			"totalCount": 2
+		}`)
+	})
+
+	opts := &ProjectUsersListOptions{
+		FlattenTeams: true,
+		ListOptions:  mongodbatlas.ListOptions{PageNum: 2, ItemsPerPage: 1},
+	}
+	users, _, err := client.Projects.ListUsers(ctx, projectID, opts)
+	if err != nil {
+		t.Fatalf("Projects.ListUsers returned error: %v", err)
+	}
+
+	expected := &Users{
+		Links: []*mongodbatlas.Link{},
+		Results: []*User{
+			{
+				EmailAddress: "jane.doe@example.com",
+				FirstName:    "Jane",
+				ID:           "533dc19ce4b00835ff81e2eb",
+				LastName:     "Doe",
+				Links:        []*mongodbatlas.Link{},
+				Roles: []*UserRole{
+					{OrgID: "5a0a1e7e0f2912c554080adb", RoleName: string(RoleOrgMember)},
+					{GroupID: "5a0a1e7e0f2912c554080adc", RoleName: string(RoleGroupReadOnly)},
+				},
+				Username: "jane.doe@example.com",
+			},
+		},
+		TotalCount: 2,
+	}
+
+	if diff := deep.Equal(users, expected); diff != nil {
+		t.Error(diff)
+	}
+}
+
+func TestProject_AddUsers(t *testing.T) {
+	setup()
+	defer teardown()
+
+	projectID := "5a0a1e7e0f2912c554080adc"
+
+	mux.HandleFunc(fmt.Sprintf("/groups/%s/users", projectID), func(w http.ResponseWriter, r *http.Request) {
+		testMethod(t, r, http.MethodPost)
+
+		expected := []interface{}{
+			map[string]interface{}{
+				"id":    "533dc19ce4b00835ff81e2eb",
+				"roles": []interface{}{map[string]interface{}{"roleName": "GROUP_READ_ONLY"}},
+			},
+		}
+		if diff := deep.Equal(decodeBody(t, r), expected); diff != nil {
+			t.Error(diff)
+		}
+	})
+
+	users := []*ProjectUser{{ID: "533dc19ce4b00835ff81e2eb", Roles: []*UserRole{{RoleName: string(RoleGroupReadOnly)}}}}
+	_, err := client.Projects.AddUsers(ctx, projectID, users)
+	if err != nil {
+		t.Fatalf("Projects.AddUsers returned error: %v", err)
+	}
+}
+
+func TestProject_AddUsers_orgRole(t *testing.T) {
+	setup()
+	defer teardown()
+
+	users := []*ProjectUser{{ID: "533dc19ce4b00835ff81e2eb", Roles: []*UserRole{{RoleName: string(RoleOrgOwner)}}}}
+	_, err := client.Projects.AddUsers(ctx, "5a0a1e7e0f2912c554080adc", users)
+
+	if _, ok := err.(*InvalidRoleError); !ok {
+		t.Fatalf("expected an *InvalidRoleError, got %v", err)
+	}
+}
+
+func TestProject_RemoveUser(t *testing.T) {
+	setup()
+	defer teardown()
+
+	projectID := "5a0a1e7e0f2912c554080adc"
+	userID := "533dc19ce4b00835ff81e2eb"
+
+	mux.HandleFunc(fmt.Sprintf("/groups/%s/users/%s", projectID, userID), func(w http.ResponseWriter, r *http.Request) {
+		testMethod(t, r, http.MethodDelete)
+	})
+
+	_, err := client.Projects.RemoveUser(ctx, projectID, userID)
+	if err != nil {
+		t.Fatalf("Projects.RemoveUser returned error: %v", err)
+	}
+}
+
+func TestProject_Update(t *testing.T) {
+	setup()
+	defer teardown()
+
+	projectID := "5a0a1e7e0f2912c554080adc"
+
+	mux.HandleFunc(fmt.Sprintf("/groups/%s", projectID), func(w http.ResponseWriter, r *http.Request) {
+		testMethod(t, r, http.MethodPatch)
+
+		expected := map[string]interface{}{
+			"name": "ProjectBar",
+			"tags": []interface{}{"prod"},
+		}
+		if diff := deep.Equal(decodeBody(t, r), expected); diff != nil {
+			t.Error(diff)
+		}
+
+		_, _ = fmt.Fprint(w, `{"id": "5a0a1e7e0f2912c554080adc", "name": "ProjectBar", "orgId": "5a0a1e7e0f2912c554080adb", "tags": ["prod"]}`)
+	})
+
+	project, _, err := client.Projects.Update(ctx, projectID, &ProjectUpdateRequest{Name: "ProjectBar", Tags: &[]string{"prod"}})
+	if err != nil {
+		t.Fatalf("Projects.Update returned error: %v", err)
+	}
+
+	tag := "prod"
+	expected := &Project{
+		ID:    "5a0a1e7e0f2912c554080adc",
+		Name:  "ProjectBar",
+		OrgID: "5a0a1e7e0f2912c554080adb",
+		Tags:  []*string{&tag},
+	}
+
+	if diff := deep.Equal(project, expected); diff != nil {
+		t.Error(diff)
+	}
+}
+
+func TestProject_Update_clearTags(t *testing.T) {
+	setup()
+	defer teardown()
+
+	projectID := "5a0a1e7e0f2912c554080adc"
+
+	mux.HandleFunc(fmt.Sprintf("/groups/%s", projectID), func(w http.ResponseWriter, r *http.Request) {
+		testMethod(t, r, http.MethodPatch)
+
+		expected := map[string]interface{}{"tags": []interface{}{}}
+		if diff := deep.Equal(decodeBody(t, r), expected); diff != nil {
+			t.Error(diff)
+		}
+
+		_, _ = fmt.Fprint(w, `{"id": "5a0a1e7e0f2912c554080adc", "name": "ProjectBar"}`)
+	})
+
+	_, _, err := client.Projects.Update(ctx, projectID, &ProjectUpdateRequest{Tags: &[]string{}})
+	if err != nil {
+		t.Fatalf("Projects.Update returned error: %v", err)
+	}
+}
+
+func TestProject_Update_empty(t *testing.T) {
+	setup()
+	defer teardown()
+
+	_, _, err := client.Projects.Update(ctx, "5a0a1e7e0f2912c554080adc", &ProjectUpdateRequest{})
+	if err == nil {
+		t.Fatal("expected an error")
+	}
+}
+
+// handleProjectPages serves three projects over two pages
+func handleProjectPages(t *testing.T) {
+	pages := map[string]string{
+		"1": `[
+			{"id": "1", "name": "prod-billing", "tags": ["prod", "billing"]},
+			{"id": "2", "name": "staging-billing", "tags": ["staging"]}
+		]`,
+		"2": `[{"id": "3", "name": "prod-search", "tags": ["prod"]}]`,
+	}
+
+	mux.HandleFunc("/groups", func(w http.ResponseWriter, r *http.Request) {
+		testMethod(t, r, http.MethodGet)
+
+		results, ok := pages[r.URL.Query().Get("pageNum")]
+		if !ok {
+			results = "[]"
+		}
+		_, _ = fmt.Fprintf(w, `{"links": [], "results": %s, "totalCount": 3}`, results)
+	})
+}
+
+func projectIDs(projects []*Project) []string {
+	ids := make([]string, len(projects))
+	for i, p := range projects {
+		ids[i] = p.ID
+	}
+	return ids
+}
+
+func TestProject_ListByTag(t *testing.T) {
+	setup()
+	defer teardown()
+
+	handleProjectPages(t)
+
+	projects, err := client.Projects.ListByTag(ctx, "prod")
+	if err != nil {
+		t.Fatalf("Projects.ListByTag returned error: %v", err)
+	}
+
+	if diff := deep.Equal(projectIDs(projects), []string{"1", "3"}); diff != nil {
+		t.Error(diff)
+	}
+}
+
+func TestProject_ListByNamePattern(t *testing.T) {
+	tests := map[string]struct {
+		pattern  NamePattern
+		expected []string
+	}{
+		"glob":     {pattern: Glob("prod-*"), expected: []string{"1", "3"}},
+		"regex":    {pattern: regexp.MustCompile(`-billing$`), expected: []string{"1", "2"}},
+		"no match": {pattern: Glob("dev-*"), expected: []string{}},
+	}
+
+	for name, tc := range tests {
+		tc := tc
+		t.Run(name, func(t *testing.T) {
+			setup()
+			defer teardown()
+
+			handleProjectPages(t)
+
+			projects, err := client.Projects.ListByNamePattern(ctx, tc.pattern)
+			if err != nil {
+				t.Fatalf("Projects.ListByNamePattern returned error: %v", err)
+			}
+
+			if diff := deep.Equal(projectIDs(projects), tc.expected); diff != nil {
+				t.Error(diff)
+			}
+		})
+	}
+}
+
+func TestProject_ListByNamePattern_invalidGlob(t *testing.T) {
+	setup()
+	defer teardown()
+
+	_, err := client.Projects.ListByNamePattern(ctx, Glob("prod-["))
+	if err == nil {
+		t.Fatal("expected an error")
+	}
+}
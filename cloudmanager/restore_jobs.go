@@ -0,0 +1,569 @@
+// Copyright 2020 MongoDB Inc
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package cloudmanager
+
+import (
+	"context"
+	"crypto/md5"
+	"crypto/sha1"
+	"crypto/sha256"
+	"encoding/hex"
+	"errors"
+	"fmt"
+	"hash"
+	"io"
+	"net/http"
+	"net/url"
+	"os"
+	"path"
+	"strconv"
+	"strings"
+	"time"
+
+	atlas "github.com/mongodb/go-client-mongodb-atlas/mongodbatlas"
+)
+
+const (
+	restoreJobsBasePath = "groups/%s/clusters/%s/restoreJobs"
+
+	// DefaultRestorePollInterval the default interval between two checks of a restore job
+	DefaultRestorePollInterval = 10 * time.Second
+
+	// maxDownloadAttempts the number of times a download is resumed after a network error
+	maxDownloadAttempts = 5
+)
+
+// downloadRetryDelay the delay before the first attempt to resume a download, doubled after each further attempt
+var downloadRetryDelay = time.Second
+
+var (
+	// ErrChecksumMismatch is returned when a downloaded archive does not match the checksum reported by the server
+	ErrChecksumMismatch = errors.New("the downloaded archive does not match its checksum")
+	// ErrChecksumUnavailable is returned when an archive was downloaded, but cannot be verified,
+	// as the server did not report a checksum for it
+	ErrChecksumUnavailable = errors.New("no checksum was reported for the downloaded archive, it could not be verified")
+	// ErrRangeNotSupported is returned when a download to an io.Writer cannot be resumed, as the server ignored the range
+	ErrRangeNotSupported = errors.New("the server does not support resuming the download")
+)
+
+// DeliveryMethod how the restored data is delivered
+type DeliveryMethod string
+
+// Delivery methods
+const (
+	DeliveryHTTP DeliveryMethod = "HTTP"
+	DeliverySCP  DeliveryMethod = "SCP"
+)
+
+// RestoreJobStatus the state of a restore job
+type RestoreJobStatus string
+
+// Restore job statuses
+const (
+	RestoreJobFinished   RestoreJobStatus = "FINISHED"
+	RestoreJobInProgress RestoreJobStatus = "IN_PROGRESS"
+	RestoreJobBroken     RestoreJobStatus = "BROKEN"
+	RestoreJobKilled     RestoreJobStatus = "KILLED"
+)
+
+// DeliveryStatus the state of the delivery of a restore job
+type DeliveryStatus string
+
+// Delivery statuses
+const (
+	DeliveryNotStarted           DeliveryStatus = "NOT_STARTED"
+	DeliveryInProgress           DeliveryStatus = "IN_PROGRESS"
+	DeliveryReady                DeliveryStatus = "READY"
+	DeliveryFailed               DeliveryStatus = "FAILED"
+	DeliveryExpired              DeliveryStatus = "EXPIRED"
+	DeliveryMaxDownloadsExceeded DeliveryStatus = "MAX_DOWNLOADS_EXCEEDED"
+)
+
+// RestoreJobError is returned when a restore job can no longer be delivered
+type RestoreJobError struct {
+	JobID  string
+	Status string
+}
+
+func (e *RestoreJobError) Error() string {
+	return fmt.Sprintf("restore job %s cannot be delivered: %s", e.JobID, e.Status)
+}
+
+// RestoreJobsService is an interface for interfacing with the Restore Jobs
+// endpoints of the MongoDB Cloud Manager API.
+// See more: https://docs.cloudmanager.mongodb.com/reference/api/restorejobs/
+type RestoreJobsService interface {
+	List(context.Context, string, string, *atlas.ListOptions) (*RestoreJobs, *atlas.Response, error)
+	Get(context.Context, string, string, string) (*RestoreJob, *atlas.Response, error)
+	Create(context.Context, string, string, *RestoreJobRequest) (*RestoreJobs, *atlas.Response, error)
+	WaitUntilReady(context.Context, string, string, string, time.Duration) (*RestoreJob, error)
+	Download(context.Context, *RestoreJob, io.Writer) error
+	DownloadToFile(context.Context, *RestoreJob, string) error
+}
+
+// RestoreJobsServiceOp handles communication with the Restore Jobs related methods of the
+// MongoDB Cloud Manager API
+type RestoreJobsServiceOp struct {
+	client *Client
+}
+
+var _ RestoreJobsService = &RestoreJobsServiceOp{}
+
+// Delivery describes how the restored data is delivered.
+// URL is set by the server for HTTP deliveries; the remaining fields configure SCP deliveries.
+type Delivery struct {
+	MethodName       DeliveryMethod `json:"methodName"`
+	StatusName       DeliveryStatus `json:"statusName,omitempty"`
+	URL              string         `json:"url,omitempty"`
+	Expires          string         `json:"expires,omitempty"`
+	ExpirationHours  int            `json:"expirationHours,omitempty"`
+	MaxDownloads     int            `json:"maxDownloads,omitempty"`
+	Format           string         `json:"format,omitempty"` // ARCHIVE or INDIVIDUAL
+	Hostname         string         `json:"hostname,omitempty"`
+	Port             int            `json:"port,omitempty"`
+	Username         string         `json:"username,omitempty"`
+	Password         string         `json:"password,omitempty"`
+	PasswordTypeName string         `json:"passwordTypeName,omitempty"` // PASSWORD or SSH_KEY
+	TargetDirectory  string         `json:"targetDirectory,omitempty"`
+}
+
+// RestoreJobHash the checksum of a restored file
+type RestoreJobHash struct {
+	FileName string `json:"fileName"`
+	Hash     string `json:"hash"`
+	TypeName string `json:"typeName"` // SHA1, MD5 or SHA256
+}
+
+// RestoreJob represents a restore of a snapshot
+type RestoreJob struct {
+	ID                string             `json:"id"`
+	ClusterID         string             `json:"clusterId,omitempty"`
+	Created           string             `json:"created,omitempty"`
+	Delivery          *Delivery          `json:"delivery,omitempty"`
+	EncryptionEnabled bool               `json:"encryptionEnabled,omitempty"`
+	GroupID           string             `json:"groupId,omitempty"`
+	Hashes            []*RestoreJobHash  `json:"hashes,omitempty"`
+	Links             []*atlas.Link      `json:"links,omitempty"`
+	MasterKeyUUID     string             `json:"masterKeyUUID,omitempty"`
+	PointInTime       bool               `json:"pointInTime,omitempty"`
+	SnapshotID        string             `json:"snapshotId,omitempty"`
+	StatusName        RestoreJobStatus   `json:"statusName,omitempty"`
+	Timestamp         *SnapshotTimestamp `json:"timestamp,omitempty"`
+}
+
+// RestoreJobs represents a array of restore jobs
+type RestoreJobs struct {
+	Links      []*atlas.Link `json:"links"`
+	Results    []*RestoreJob `json:"results"`
+	TotalCount int           `json:"totalCount"`
+}
+
+// RestoreJobRequest the snapshot to restore, and how to deliver it
+type RestoreJobRequest struct {
+	SnapshotID string    `json:"snapshotId"`
+	Delivery   *Delivery `json:"delivery"`
+}
+
+// List gets the restore jobs of a cluster.
+// See more: https://docs.cloudmanager.mongodb.com/reference/api/restorejobs/get-all-restore-jobs-for-one-cluster/
+func (s *RestoreJobsServiceOp) List(ctx context.Context, projectID, clusterID string, opts *atlas.ListOptions) (*RestoreJobs, *atlas.Response, error) {
+	if projectID == "" {
+		return nil, nil, atlas.NewArgError("projectID", "must be set")
+	}
+	if clusterID == "" {
+		return nil, nil, atlas.NewArgError("clusterID", "must be set")
+	}
+
+	basePath := fmt.Sprintf(restoreJobsBasePath, projectID, clusterID)
+	path, err := setListOptions(basePath, opts)
+	if err != nil {
+		return nil, nil, err
+	}
+
+	req, err := s.client.NewRequest(ctx, http.MethodGet, path, nil)
+	if err != nil {
+		return nil, nil, err
+	}
+
+	root := new(RestoreJobs)
+	resp, err := s.client.Do(ctx, req, root)
+	if err != nil {
+		return nil, resp, err
+	}
+
+	if l := root.Links; l != nil {
+		resp.Links = l
+	}
+
+	return root, resp, nil
+}
+
+// Get gets a single restore job of a cluster.
+// See more: https://docs.cloudmanager.mongodb.com/reference/api/restorejobs/get-one-single-restore-job-for-one-cluster/
+func (s *RestoreJobsServiceOp) Get(ctx context.Context, projectID, clusterID, jobID string) (*RestoreJob, *atlas.Response, error) {
+	if projectID == "" {
+		return nil, nil, atlas.NewArgError("projectID", "must be set")
+	}
+	if clusterID == "" {
+		return nil, nil, atlas.NewArgError("clusterID", "must be set")
+	}
+	if jobID == "" {
+		return nil, nil, atlas.NewArgError("jobID", "must be set")
+	}
+
+	basePath := fmt.Sprintf(restoreJobsBasePath, projectID, clusterID)
+	path := fmt.Sprintf("%s/%s", basePath, jobID)
+
+	req, err := s.client.NewRequest(ctx, http.MethodGet, path, nil)
+	if err != nil {
+		return nil, nil, err
+	}
+
+	root := new(RestoreJob)
+	resp, err := s.client.Do(ctx, req, root)
+	if err != nil {
+		return nil, resp, err
+	}
+
+	return root, resp, err
+}
+
+// Create restores a snapshot of a cluster; one job is created per replica set and config server of a sharded cluster.
+// See more: https://docs.cloudmanager.mongodb.com/reference/api/restorejobs/create-one-restore-job-for-one-cluster/
+func (s *RestoreJobsServiceOp) Create(ctx context.Context, projectID, clusterID string, createRequest *RestoreJobRequest) (*RestoreJobs, *atlas.Response, error) {
+	if projectID == "" {
+		return nil, nil, atlas.NewArgError("projectID", "must be set")
+	}
+	if clusterID == "" {
+		return nil, nil, atlas.NewArgError("clusterID", "must be set")
+	}
+	if createRequest == nil {
+		return nil, nil, atlas.NewArgError("createRequest", "cannot be nil")
+	}
+	if createRequest.SnapshotID == "" {
+		return nil, nil, atlas.NewArgError("snapshotID", "must be set")
+	}
+	if createRequest.Delivery == nil || createRequest.Delivery.MethodName == "" {
+		return nil, nil, atlas.NewArgError("delivery.methodName", "must be set")
+	}
+
+	path := fmt.Sprintf(restoreJobsBasePath, projectID, clusterID)
+
+	req, err := s.client.NewRequest(ctx, http.MethodPost, path, createRequest)
+	if err != nil {
+		return nil, nil, err
+	}
+
+	root := new(RestoreJobs)
+	resp, err := s.client.Do(ctx, req, root)
+	if err != nil {
+		return nil, resp, err
+	}
+
+	return root, resp, err
+}
+
+// WaitUntilReady polls a restore job until its HTTP download is ready, or its SCP delivery finished.
+// The wait can be bounded with a context deadline.
+func (s *RestoreJobsServiceOp) WaitUntilReady(ctx context.Context, projectID, clusterID, jobID string, interval time.Duration) (*RestoreJob, error) {
+	if interval <= 0 {
+		interval = DefaultRestorePollInterval
+	}
+
+	for {
+		job, _, err := s.Get(ctx, projectID, clusterID, jobID)
+		if err != nil {
+			return nil, err
+		}
+
+		ready, err := isRestoreJobReady(job)
+		if err != nil || ready {
+			return job, err
+		}
+
+		select {
+		case <-ctx.Done():
+			return job, ctx.Err()
+		case <-time.After(interval):
+		}
+	}
+}
+
+// isRestoreJobReady returns true if the restored data can be retrieved, and an error if it never will
+func isRestoreJobReady(job *RestoreJob) (bool, error) {
+	switch job.StatusName {
+	case RestoreJobBroken, RestoreJobKilled:
+		return false, &RestoreJobError{JobID: job.ID, Status: string(job.StatusName)}
+	}
+
+	if job.Delivery == nil {
+		return false, nil
+	}
+
+	switch job.Delivery.StatusName {
+	case DeliveryFailed, DeliveryExpired, DeliveryMaxDownloadsExceeded:
+		return false, &RestoreJobError{JobID: job.ID, Status: string(job.Delivery.StatusName)}
+	}
+
+	if job.Delivery.MethodName == DeliveryHTTP {
+		return job.Delivery.StatusName == DeliveryReady, nil
+	}
+	return job.StatusName == RestoreJobFinished, nil
+}
+
+// Download streams the archive of a ready HTTP restore job to the writer, and verifies its checksum.
+// Interrupted transfers are resumed with range requests.
+// If the job reports no checksum, the archive is downloaded but ErrChecksumUnavailable is returned.
+func (s *RestoreJobsServiceOp) Download(ctx context.Context, job *RestoreJob, w io.Writer) error {
+	expected, h, err := restoreJobHash(job)
+	if err != nil {
+		return err
+	}
+	if h != nil {
+		w = io.MultiWriter(w, h)
+	}
+
+	if err := s.download(ctx, job, w, 0, nil); err != nil {
+		return err
+	}
+
+	return verifyChecksum(expected, h)
+}
+
+// DownloadToFile downloads the archive of a ready HTTP restore job to a file, and verifies its checksum.
+// If the file exists, the download resumes at its end; the file is removed if the checksum does not match.
+// If the job reports no checksum, the file is kept but ErrChecksumUnavailable is returned.
+func (s *RestoreJobsServiceOp) DownloadToFile(ctx context.Context, job *RestoreJob, filename string) error {
+	expected, h, err := restoreJobHash(job)
+	if err != nil {
+		return err
+	}
+
+	f, err := os.OpenFile(filename, os.O_CREATE|os.O_RDWR, 0600)
+	if err != nil {
+		return err
+	}
+	defer f.Close()
+
+	// hash what was already downloaded, which also moves to the end of the file
+	var offset int64
+	if h != nil {
+		offset, err = io.Copy(h, f)
+	} else {
+		offset, err = f.Seek(0, io.SeekEnd)
+	}
+	if err != nil {
+		return err
+	}
+
+	var w io.Writer = f
+	if h != nil {
+		w = io.MultiWriter(f, h)
+	}
+	restart := func() error {
+		if err := f.Truncate(0); err != nil {
+			return err
+		}
+		if _, err := f.Seek(0, io.SeekStart); err != nil {
+			return err
+		}
+		if h != nil {
+			h.Reset()
+		}
+		return nil
+	}
+
+	if err := s.download(ctx, job, w, offset, restart); err != nil {
+		return err
+	}
+
+	err = verifyChecksum(expected, h)
+	if errors.Is(err, ErrChecksumMismatch) {
+		_ = os.Remove(filename)
+	}
+	return err
+}
+
+// retryableError an error after which the download can be resumed
+type retryableError struct {
+	err error
+}
+
+func (e *retryableError) Error() string {
+	return e.err.Error()
+}
+
+// download streams the archive to the writer, starting at the offset; restart is called if the server
+// sends the whole archive again, and may be nil if the writer cannot be rewound
+func (s *RestoreJobsServiceOp) download(ctx context.Context, job *RestoreJob, w io.Writer, offset int64, restart func() error) error {
+	if job == nil || job.Delivery == nil || job.Delivery.URL == "" {
+		return atlas.NewArgError("job.delivery.url", "must be set, only ready HTTP restore jobs can be downloaded")
+	}
+
+	var err error
+	delay := downloadRetryDelay
+	for attempt := 1; attempt <= maxDownloadAttempts; attempt++ {
+		offset, err = s.downloadFrom(ctx, job.Delivery.URL, w, offset, restart)
+
+		var retryable *retryableError
+		if !errors.As(err, &retryable) {
+			return err
+		}
+		if attempt == maxDownloadAttempts {
+			break
+		}
+
+		select {
+		case <-ctx.Done():
+			return ctx.Err()
+		case <-time.After(delay):
+		}
+		delay *= 2
+	}
+
+	return fmt.Errorf("download interrupted %d times: %w", maxDownloadAttempts, err)
+}
+
+// downloadFrom requests the archive from the offset, and returns the offset reached
+func (s *RestoreJobsServiceOp) downloadFrom(ctx context.Context, downloadURL string, w io.Writer, offset int64, restart func() error) (int64, error) {
+	req, err := s.client.NewRequest(ctx, http.MethodGet, downloadURL, nil)
+	if err != nil {
+		return offset, err
+	}
+	req.Header.Set("Accept", "*/*")
+	if offset > 0 {
+		req.Header.Set("Range", fmt.Sprintf("bytes=%d-", offset))
+	}
+
+	// the body is streamed instead of going through Do, and the completion callback
+	// is not invoked, since it may read the whole archive into memory
+	resp, err := s.client.client.Do(req.WithContext(ctx))
+	if err != nil {
+		if ctx.Err() != nil {
+			return offset, ctx.Err()
+		}
+		return offset, &retryableError{err: err}
+	}
+	defer resp.Body.Close()
+
+	switch {
+	case resp.StatusCode == http.StatusRequestedRangeNotSatisfiable && offset > 0:
+		// the archive was already downloaded entirely; the checksum verifies what was downloaded before
+		return offset, nil
+	case resp.StatusCode == http.StatusOK && offset > 0:
+		if restart == nil {
+			return offset, ErrRangeNotSupported
+		}
+		if err := restart(); err != nil {
+			return offset, err
+		}
+		offset = 0
+	case resp.StatusCode == http.StatusPartialContent:
+		start, err := contentRangeStart(resp.Header.Get("Content-Range"))
+		if err != nil {
+			return offset, err
+		}
+		if start != offset {
+			return offset, fmt.Errorf("the server resumed the download at byte %d instead of %d", start, offset)
+		}
+	default:
+		if err := atlas.CheckResponse(resp); err != nil {
+			return offset, err
+		}
+	}
+
+	cw := &countingWriter{w: w}
+	_, err = io.Copy(cw, resp.Body)
+	offset += cw.n
+	if err != nil && cw.err == nil && ctx.Err() == nil {
+		return offset, &retryableError{err: err}
+	}
+
+	return offset, err
+}
+
+// contentRangeStart parses the first byte position of a Content-Range header, e.g. "bytes 100-199/200"
+func contentRangeStart(contentRange string) (int64, error) {
+	r := strings.TrimPrefix(contentRange, "bytes ")
+	if i := strings.Index(r, "-"); i > 0 {
+		return strconv.ParseInt(r[:i], 10, 64)
+	}
+	return 0, fmt.Errorf("invalid Content-Range %q", contentRange)
+}
+
+// countingWriter counts the bytes written, and records whether the writer failed
+type countingWriter struct {
+	w   io.Writer
+	n   int64
+	err error
+}
+
+func (c *countingWriter) Write(p []byte) (int, error) {
+	n, err := c.w.Write(p)
+	c.n += int64(n)
+	if err != nil {
+		c.err = err
+	}
+	return n, err
+}
+
+// restoreJobHash returns the checksum reported for the archive of a restore job, and a hash to compute it.
+// The hash is nil if no checksum was reported; if several were, but none is for the delivered file, an error is returned.
+func restoreJobHash(job *RestoreJob) (string, hash.Hash, error) {
+	if job == nil || len(job.Hashes) == 0 {
+		return "", nil, nil
+	}
+
+	var selected *RestoreJobHash
+	if job.Delivery != nil {
+		if u, err := url.Parse(job.Delivery.URL); err == nil {
+			name := path.Base(u.Path)
+			for _, h := range job.Hashes {
+				if h.FileName == name {
+					selected = h
+				}
+			}
+		}
+	}
+	if selected == nil && len(job.Hashes) == 1 {
+		selected = job.Hashes[0]
+	}
+	if selected == nil {
+		return "", nil, fmt.Errorf("none of the %d checksums reported for restore job %s is for the delivered file", len(job.Hashes), job.ID)
+	}
+
+	switch strings.ToUpper(selected.TypeName) {
+	case "SHA1":
+		return selected.Hash, sha1.New(), nil
+	case "MD5":
+		return selected.Hash, md5.New(), nil
+	case "SHA256":
+		return selected.Hash, sha256.New(), nil
+	default:
+		return "", nil, fmt.Errorf("unsupported checksum type %q", selected.TypeName)
+	}
+}
+
+// verifyChecksum compares the computed hash with the expected hex encoded checksum
+func verifyChecksum(expected string, h hash.Hash) error {
+	if h == nil {
+		return ErrChecksumUnavailable
+	}
+
+	if actual := hex.EncodeToString(h.Sum(nil)); !strings.EqualFold(actual, expected) {
+		return fmt.Errorf("%w: expected %s, got %s", ErrChecksumMismatch, expected, actual)
+	}
+	return nil
+}
@@ -0,0 +1,629 @@
+// Copyright 2020 MongoDB Inc
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package cloudmanager
+
+import (
+	"bytes"
+	"crypto/sha1"
+	"encoding/hex"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io/ioutil"
+	"net/http"
+	"os"
+	"path/filepath"
+	"strconv"
+	"strings"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/go-test/deep"
+	"github.com/mongodb/go-client-mongodb-atlas/mongodbatlas"
+)
+
+func TestRestoreJobs_List(t *testing.T) {
+	setup()
+	defer teardown()
+
+	projectID := "5a0a1e7e0f2912c554080adc"
+	clusterID := "7c88887e0f2912c554080ae1"
+
+	mux.HandleFunc(fmt.Sprintf("/groups/%s/clusters/%s/restoreJobs", projectID, clusterID), func(w http.ResponseWriter, r *http.Request) {
+		testMethod(t, r, http.MethodGet)
+		_, _ = fmt.Fprint(w, `{
+			"links": [],
+			"results": [{
+				"clusterId": "7c88887e0f2912c554080ae1",
+				"created": "2017-12-26T16:32:16Z",
+				"delivery": {
+					"expirationHours": 48,
+					"expires": "2017-12-28T16:32:16Z",
+					"maxDownloads": 1,
+					"methodName": "HTTP",
+					"statusName": "READY",
+					"url": "https://api-backup.example.com/backup/restore/v2/pull/5a4279d4fcc178500596745a/rs0.tar.gz"
+				},
+				"groupId": "5a0a1e7e0f2912c554080adc",
+				"hashes": [{"fileName": "rs0.tar.gz", "hash": "5b0d6d0d7b1e4a3f3c3b7f8d3a34a0a7f2bf1f3a", "typeName": "SHA1"}],
+				"id": "5a4279d4fcc178500596745a",
+				"links": [],
+				"snapshotId": "5a4279d4fcc178500596745b",
+				"statusName": "FINISHED",
+				"timestamp": {"date": "2017-12-26T16:32:15Z", "increment": 1}
+			}],
+			"totalCount": 1
+		}`)
+	})
+
+	jobs, _, err := client.RestoreJobs.List(ctx, projectID, clusterID, nil)
+	if err != nil {
+		t.Fatalf("RestoreJobs.List returned error: %v", err)
+	}
+
+	expected := &RestoreJobs{
+		Links: []*mongodbatlas.Link{},
+		Results: []*RestoreJob{
+			{
+				ID:        "5a4279d4fcc178500596745a",
+				ClusterID: "7c88887e0f2912c554080ae1",
+				Created:   "2017-12-26T16:32:16Z",
+				Delivery: &Delivery{
+					ExpirationHours: 48,
+					Expires:         "2017-12-28T16:32:16Z",
+					MaxDownloads:    1,
+					MethodName:      DeliveryHTTP,
+					StatusName:      DeliveryReady,
+					URL:             "https://api-backup.example.com/backup/restore/v2/pull/5a4279d4fcc178500596745a/rs0.tar.gz",
+				},
+				GroupID: "5a0a1e7e0f2912c554080adc",
+				Hashes: []*RestoreJobHash{
+					{FileName: "rs0.tar.gz", Hash: "5b0d6d0d7b1e4a3f3c3b7f8d3a34a0a7f2bf1f3a", TypeName: "SHA1"},
+				},
+				Links:      []*mongodbatlas.Link{},
+				SnapshotID: "5a4279d4fcc178500596745b",
+				StatusName: RestoreJobFinished,
+				Timestamp:  &SnapshotTimestamp{Date: "2017-12-26T16:32:15Z", Increment: 1},
+			},
+		},
+		TotalCount: 1,
+	}
+
+	if diff := deep.Equal(jobs, expected); diff != nil {
+		t.Error(diff)
+	}
+}
+
+func TestRestoreJobs_Get(t *testing.T) {
+	setup()
+	defer teardown()
+
+	projectID := "5a0a1e7e0f2912c554080adc"
+	clusterID := "7c88887e0f2912c554080ae1"
+	jobID := "5a4279d4fcc178500596745a"
+
+	mux.HandleFunc(fmt.Sprintf("/groups/%s/clusters/%s/restoreJobs/%s", projectID, clusterID, jobID), func(w http.ResponseWriter, r *http.Request) {
+		testMethod(t, r, http.MethodGet)
+		_, _ = fmt.Fprint(w, `{
+			"clusterId": "7c88887e0f2912c554080ae1",
+			"created": "2017-12-26T16:32:16Z",
+			"delivery": {
+				"expirationHours": 48,
+				"expires": "2017-12-28T16:32:16Z",
+				"maxDownloads": 1,
+				"methodName": "HTTP",
+				"statusName": "READY",
+				"url": "https://api-backup.example.com/backup/restore/v2/pull/5a4279d4fcc178500596745a/rs0.tar.gz"
+			},
+			"groupId": "5a0a1e7e0f2912c554080adc",
+			"hashes": [{"fileName": "rs0.tar.gz", "hash": "5b0d6d0d7b1e4a3f3c3b7f8d3a34a0a7f2bf1f3a", "typeName": "SHA1"}],
+			"id": "5a4279d4fcc178500596745a",
+			"links": [],
+			"snapshotId": "5a4279d4fcc178500596745b",
+			"statusName": "FINISHED",
+			"timestamp": {"date": "2017-12-26T16:32:15Z", "increment": 1}
+		}`)
+	})
+
+	job, _, err := client.RestoreJobs.Get(ctx, projectID, clusterID, jobID)
+	if err != nil {
+		t.Fatalf("RestoreJobs.Get returned error: %v", err)
+	}
+
+	expected := &RestoreJob{
+		ID:        "5a4279d4fcc178500596745a",
+		ClusterID: "7c88887e0f2912c554080ae1",
+		Created:   "2017-12-26T16:32:16Z",
+		Delivery: &Delivery{
+			ExpirationHours: 48,
+			Expires:         "2017-12-28T16:32:16Z",
+			MaxDownloads:    1,
+			MethodName:      DeliveryHTTP,
+			StatusName:      DeliveryReady,
+			URL:             "https://api-backup.example.com/backup/restore/v2/pull/5a4279d4fcc178500596745a/rs0.tar.gz",
+		},
+		GroupID: "5a0a1e7e0f2912c554080adc",
+		Hashes: []*RestoreJobHash{
+			{FileName: "rs0.tar.gz", Hash: "5b0d6d0d7b1e4a3f3c3b7f8d3a34a0a7f2bf1f3a", TypeName: "SHA1"},
+		},
+		Links:      []*mongodbatlas.Link{},
+		SnapshotID: "5a4279d4fcc178500596745b",
+		StatusName: RestoreJobFinished,
+		Timestamp:  &SnapshotTimestamp{Date: "2017-12-26T16:32:15Z", Increment: 1},
+	}
+
+	if diff := deep.Equal(job, expected); diff != nil {
+		t.Error(diff)
+	}
+}
+
+func TestRestoreJobs_Create(t *testing.T) {
+	setup()
+	defer teardown()
+
+	projectID := "5a0a1e7e0f2912c554080adc"
+	clusterID := "7c88887e0f2912c554080ae1"
+
+	mux.HandleFunc(fmt.Sprintf("/groups/%s/clusters/%s/restoreJobs", projectID, clusterID), func(w http.ResponseWriter, r *http.Request) {
+		testMethod(t, r, http.MethodPost)
+
+		expected := map[string]interface{}{
+			"snapshotId": "5a4279d4fcc178500596745b",
+			"delivery": map[string]interface{}{
+				"methodName":      "SCP",
+				"format":          "ARCHIVE",
+				"hostname":        "backup.example.com",
+				"port":            float64(22),
+				"username":        "restore",
+				"password":        "secret",
+				"targetDirectory": "/data/restore",
+			},
+		}
+		var v map[string]interface{}
+		if err := json.NewDecoder(r.Body).Decode(&v); err != nil {
+			t.Fatalf("decode json: %v", err)
+		}
+		if diff := deep.Equal(v, expected); diff != nil {
+			t.Error(diff)
+		}
+
+		_, _ = fmt.Fprint(w, `{
+			"links": [],
+			"results": [{
+				"clusterId": "7c88887e0f2912c554080ae1",
+				"created": "2017-12-26T16:32:16Z",
+				"delivery": {
+					"expirationHours": 48,
+					"expires": "2017-12-28T16:32:16Z",
+					"maxDownloads": 1,
+					"methodName": "HTTP",
+					"statusName": "READY",
+					"url": "https://api-backup.example.com/backup/restore/v2/pull/5a4279d4fcc178500596745a/rs0.tar.gz"
+				},
+				"groupId": "5a0a1e7e0f2912c554080adc",
+				"hashes": [{"fileName": "rs0.tar.gz", "hash": "5b0d6d0d7b1e4a3f3c3b7f8d3a34a0a7f2bf1f3a", "typeName": "SHA1"}],
+				"id": "5a4279d4fcc178500596745a",
+				"links": [],
+				"snapshotId": "5a4279d4fcc178500596745b",
+				"statusName": "FINISHED",
+				"timestamp": {"date": "2017-12-26T16:32:15Z", "increment": 1}
+			}],
+			"totalCount": 1
+		}`)
+	})
+
+	createRequest := &RestoreJobRequest{
+		SnapshotID: "5a4279d4fcc178500596745b",
+		Delivery: &Delivery{
+			MethodName:      DeliverySCP,
+			Format:          "ARCHIVE",
+			Hostname:        "backup.example.com",
+			Port:            22,
+			Username:        "restore",
+			Password:        "secret",
+			TargetDirectory: "/data/restore",
+		},
+	}
+	jobs, _, err := client.RestoreJobs.Create(ctx, projectID, clusterID, createRequest)
+	if err != nil {
+		t.Fatalf("RestoreJobs.Create returned error: %v", err)
+	}
+
+	expected := []*RestoreJob{
+		{
+			ID:        "5a4279d4fcc178500596745a",
+			ClusterID: "7c88887e0f2912c554080ae1",
+			Created:   "2017-12-26T16:32:16Z",
+			Delivery: &Delivery{
+				ExpirationHours: 48,
+				Expires:         "2017-12-28T16:32:16Z",
+				MaxDownloads:    1,
+				MethodName:      DeliveryHTTP,
+				StatusName:      DeliveryReady,
+				URL:             "https://api-backup.example.com/backup/restore/v2/pull/5a4279d4fcc178500596745a/rs0.tar.gz",
+			},
+			GroupID: "5a0a1e7e0f2912c554080adc",
+			Hashes: []*RestoreJobHash{
+				{FileName: "rs0.tar.gz", Hash: "5b0d6d0d7b1e4a3f3c3b7f8d3a34a0a7f2bf1f3a", TypeName: "SHA1"},
+			},
+			Links:      []*mongodbatlas.Link{},
+			SnapshotID: "5a4279d4fcc178500596745b",
+			StatusName: RestoreJobFinished,
+			Timestamp:  &SnapshotTimestamp{Date: "2017-12-26T16:32:15Z", Increment: 1},
+		},
+	}
+
+	if diff := deep.Equal(jobs.Results, expected); diff != nil {
+		t.Error(diff)
+	}
+}
+
+func TestRestoreJobs_WaitUntilReady(t *testing.T) {
+	setup()
+	defer teardown()
+
+	projectID := "5a0a1e7e0f2912c554080adc"
+	clusterID := "7c88887e0f2912c554080ae1"
+	jobID := "5a4279d4fcc178500596745a"
+
+	var mu sync.Mutex
+	statuses := []DeliveryStatus{DeliveryNotStarted, DeliveryInProgress, DeliveryReady}
+	mux.HandleFunc(fmt.Sprintf("/groups/%s/clusters/%s/restoreJobs/%s", projectID, clusterID, jobID), func(w http.ResponseWriter, r *http.Request) {
+		mu.Lock()
+		defer mu.Unlock()
+		status := statuses[0]
+		if len(statuses) > 1 {
+			statuses = statuses[1:]
+		}
+		_, _ = fmt.Fprintf(w, `{"id": %q, "statusName": "IN_PROGRESS", "delivery": {"methodName": "HTTP", "statusName": %q}}`, jobID, status)
+	})
+
+	job, err := client.RestoreJobs.WaitUntilReady(ctx, projectID, clusterID, jobID, time.Millisecond)
+	if err != nil {
+		t.Fatalf("RestoreJobs.WaitUntilReady returned error: %v", err)
+	}
+	if job.Delivery.StatusName != DeliveryReady {
+		t.Errorf("expected %s, got %s", DeliveryReady, job.Delivery.StatusName)
+	}
+}
+
+func TestRestoreJobs_WaitUntilReady_expired(t *testing.T) {
+	setup()
+	defer teardown()
+
+	projectID := "5a0a1e7e0f2912c554080adc"
+	clusterID := "7c88887e0f2912c554080ae1"
+	jobID := "5a4279d4fcc178500596745a"
+
+	mux.HandleFunc(fmt.Sprintf("/groups/%s/clusters/%s/restoreJobs/%s", projectID, clusterID, jobID), func(w http.ResponseWriter, r *http.Request) {
+		_, _ = fmt.Fprintf(w, `{"id": %q, "statusName": "FINISHED", "delivery": {"methodName": "HTTP", "statusName": "EXPIRED"}}`, jobID)
+	})
+
+	_, err := client.RestoreJobs.WaitUntilReady(ctx, projectID, clusterID, jobID, time.Millisecond)
+
+	expected := &RestoreJobError{JobID: jobID, Status: "EXPIRED"}
+	if diff := deep.Equal(err, expected); diff != nil {
+		t.Error(diff)
+	}
+}
+
+// fakeArchiveServer serves an archive honoring range requests; the first response is cut after cutAfter bytes
+type fakeArchiveServer struct {
+	mu          sync.Mutex
+	archive     []byte
+	cutAfter    int
+	ignoreRange bool
+	ranges      []string
+}
+
+func (s *fakeArchiveServer) ServeHTTP(w http.ResponseWriter, r *http.Request) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	s.ranges = append(s.ranges, r.Header.Get("Range"))
+
+	start := 0
+	if rng := r.Header.Get("Range"); rng != "" && !s.ignoreRange {
+		start, _ = strconv.Atoi(strings.TrimSuffix(strings.TrimPrefix(rng, "bytes="), "-"))
+		if start >= len(s.archive) {
+			w.WriteHeader(http.StatusRequestedRangeNotSatisfiable)
+			return
+		}
+		w.Header().Set("Content-Range", fmt.Sprintf("bytes %d-%d/%d", start, len(s.archive)-1, len(s.archive)))
+		w.Header().Set("Content-Length", strconv.Itoa(len(s.archive)-start))
+		w.WriteHeader(http.StatusPartialContent)
+	} else {
+		w.Header().Set("Content-Length", strconv.Itoa(len(s.archive)))
+		w.WriteHeader(http.StatusOK)
+	}
+
+	if s.cutAfter > 0 {
+		// send part of the body, then drop the connection
+		_, _ = w.Write(s.archive[start : start+s.cutAfter])
+		s.cutAfter = 0
+		w.(http.Flusher).Flush()
+		conn, _, err := w.(http.Hijacker).Hijack()
+		if err == nil {
+			_ = conn.Close()
+		}
+		return
+	}
+	_, _ = w.Write(s.archive[start:])
+}
+
+// fastDownloadRetries shortens the delay between download attempts, and returns a function restoring it
+func fastDownloadRetries() func() {
+	downloadRetryDelay = time.Millisecond
+	return func() { downloadRetryDelay = time.Second }
+}
+
+func archiveJob(archive []byte) *RestoreJob {
+	sum := sha1.Sum(archive)
+	return &RestoreJob{
+		ID:       "5a4279d4fcc178500596745a",
+		Delivery: &Delivery{MethodName: DeliveryHTTP, StatusName: DeliveryReady, URL: server.URL + "/pull/5a4279d4fcc178500596745a/rs0.tar.gz"},
+		Hashes: []*RestoreJobHash{
+			{FileName: "other.tar.gz", Hash: "0000", TypeName: "SHA1"},
+			{FileName: "rs0.tar.gz", Hash: strings.ToUpper(hex.EncodeToString(sum[:])), TypeName: "SHA1"},
+		},
+	}
+}
+
+func TestRestoreJobs_Download_resume(t *testing.T) {
+	setup()
+	defer teardown()
+	defer fastDownloadRetries()()
+
+	archive := bytes.Repeat([]byte("0123456789"), 1000)
+	fake := &fakeArchiveServer{archive: archive, cutAfter: 4096}
+	mux.Handle("/pull/", fake)
+
+	var buf bytes.Buffer
+	if err := client.RestoreJobs.Download(ctx, archiveJob(archive), &buf); err != nil {
+		t.Fatalf("RestoreJobs.Download returned error: %v", err)
+	}
+
+	if !bytes.Equal(buf.Bytes(), archive) {
+		t.Errorf("expected %d bytes of the archive, got %d", len(archive), buf.Len())
+	}
+	if diff := deep.Equal(fake.ranges, []string{"", "bytes=4096-"}); diff != nil {
+		t.Error(diff)
+	}
+}
+
+func TestRestoreJobs_Download_rangeNotSupported(t *testing.T) {
+	setup()
+	defer teardown()
+	defer fastDownloadRetries()()
+
+	archive := bytes.Repeat([]byte("0123456789"), 1000)
+	mux.Handle("/pull/", &fakeArchiveServer{archive: archive, cutAfter: 4096, ignoreRange: true})
+
+	err := client.RestoreJobs.Download(ctx, archiveJob(archive), ioutil.Discard)
+	if !errors.Is(err, ErrRangeNotSupported) {
+		t.Fatalf("expected ErrRangeNotSupported, got %v", err)
+	}
+}
+
+func TestRestoreJobs_DownloadToFile(t *testing.T) {
+	setup()
+	defer teardown()
+
+	archive := bytes.Repeat([]byte("0123456789"), 1000)
+	fake := &fakeArchiveServer{archive: archive}
+	mux.Handle("/pull/", fake)
+
+	// a previous download stopped after 1000 bytes
+	dir, err := ioutil.TempDir("", "restore")
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer os.RemoveAll(dir)
+	filename := filepath.Join(dir, "rs0.tar.gz")
+	if err := ioutil.WriteFile(filename, archive[:1000], 0600); err != nil {
+		t.Fatal(err)
+	}
+
+	if err := client.RestoreJobs.DownloadToFile(ctx, archiveJob(archive), filename); err != nil {
+		t.Fatalf("RestoreJobs.DownloadToFile returned error: %v", err)
+	}
+
+	downloaded, err := ioutil.ReadFile(filename)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !bytes.Equal(downloaded, archive) {
+		t.Errorf("expected %d bytes of the archive, got %d", len(archive), len(downloaded))
+	}
+	if diff := deep.Equal(fake.ranges, []string{"bytes=1000-"}); diff != nil {
+		t.Error(diff)
+	}
+}
+
+func TestRestoreJobs_DownloadToFile_restart(t *testing.T) {
+	setup()
+	defer teardown()
+	defer fastDownloadRetries()()
+
+	archive := bytes.Repeat([]byte("0123456789"), 1000)
+	mux.Handle("/pull/", &fakeArchiveServer{archive: archive, cutAfter: 4096, ignoreRange: true})
+
+	dir, err := ioutil.TempDir("", "restore")
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer os.RemoveAll(dir)
+	filename := filepath.Join(dir, "rs0.tar.gz")
+
+	if err := client.RestoreJobs.DownloadToFile(ctx, archiveJob(archive), filename); err != nil {
+		t.Fatalf("RestoreJobs.DownloadToFile returned error: %v", err)
+	}
+
+	downloaded, err := ioutil.ReadFile(filename)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !bytes.Equal(downloaded, archive) {
+		t.Errorf("expected %d bytes of the archive, got %d", len(archive), len(downloaded))
+	}
+}
+
+func TestRestoreJobs_DownloadToFile_checksumMismatch(t *testing.T) {
+	setup()
+	defer teardown()
+
+	archive := bytes.Repeat([]byte("0123456789"), 1000)
+	mux.Handle("/pull/", &fakeArchiveServer{archive: archive})
+
+	dir, err := ioutil.TempDir("", "restore")
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer os.RemoveAll(dir)
+	filename := filepath.Join(dir, "rs0.tar.gz")
+
+	job := archiveJob([]byte("another archive"))
+	err = client.RestoreJobs.DownloadToFile(ctx, job, filename)
+	if !errors.Is(err, ErrChecksumMismatch) {
+		t.Fatalf("expected ErrChecksumMismatch, got %v", err)
+	}
+	if _, err := os.Stat(filename); !os.IsNotExist(err) {
+		t.Errorf("expected the corrupted file to be removed, got %v", err)
+	}
+}
+
+func TestRestoreJobs_DownloadToFile_alreadyDownloaded(t *testing.T) {
+	setup()
+	defer teardown()
+
+	archive := bytes.Repeat([]byte("0123456789"), 1000)
+	fake := &fakeArchiveServer{archive: archive}
+	mux.Handle("/pull/", fake)
+
+	// the file has the size of the archive, but not its contents
+	dir, err := ioutil.TempDir("", "restore")
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer os.RemoveAll(dir)
+	filename := filepath.Join(dir, "rs0.tar.gz")
+	if err := ioutil.WriteFile(filename, bytes.Repeat([]byte("x"), len(archive)), 0600); err != nil {
+		t.Fatal(err)
+	}
+
+	err = client.RestoreJobs.DownloadToFile(ctx, archiveJob(archive), filename)
+	if !errors.Is(err, ErrChecksumMismatch) {
+		t.Fatalf("expected ErrChecksumMismatch, got %v", err)
+	}
+	if diff := deep.Equal(fake.ranges, []string{"bytes=10000-"}); diff != nil {
+		t.Error(diff)
+	}
+}
+
+func TestRestoreJobs_DownloadToFile_noChecksum(t *testing.T) {
+	setup()
+	defer teardown()
+
+	archive := bytes.Repeat([]byte("0123456789"), 1000)
+	mux.Handle("/pull/", &fakeArchiveServer{archive: archive})
+
+	dir, err := ioutil.TempDir("", "restore")
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer os.RemoveAll(dir)
+	filename := filepath.Join(dir, "rs0.tar.gz")
+
+	job := archiveJob(archive)
+	job.Hashes = nil
+	err = client.RestoreJobs.DownloadToFile(ctx, job, filename)
+	if !errors.Is(err, ErrChecksumUnavailable) {
+		t.Fatalf("expected ErrChecksumUnavailable, got %v", err)
+	}
+
+	downloaded, err := ioutil.ReadFile(filename)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !bytes.Equal(downloaded, archive) {
+		t.Errorf("expected the unverified archive to be kept, got %d bytes", len(downloaded))
+	}
+
+	// an existing file is not verified either
+	err = client.RestoreJobs.DownloadToFile(ctx, job, filename)
+	if !errors.Is(err, ErrChecksumUnavailable) {
+		t.Fatalf("expected ErrChecksumUnavailable, got %v", err)
+	}
+}
+
+func TestRestoreJobs_Download_backoff(t *testing.T) {
+	setup()
+	defer teardown()
+	defer fastDownloadRetries()()
+
+	var mu sync.Mutex
+	var attempts []time.Time
+	mux.HandleFunc("/pull/", func(w http.ResponseWriter, r *http.Request) {
+		mu.Lock()
+		attempts = append(attempts, time.Now())
+		mu.Unlock()
+		w.Header().Set("Content-Length", "100")
+		w.WriteHeader(http.StatusOK)
+		w.(http.Flusher).Flush()
+		if conn, _, err := w.(http.Hijacker).Hijack(); err == nil {
+			_ = conn.Close()
+		}
+	})
+
+	err := client.RestoreJobs.Download(ctx, archiveJob([]byte("archive")), ioutil.Discard)
+	if err == nil || !strings.Contains(err.Error(), "download interrupted 5 times") {
+		t.Fatalf("expected the download to give up, got %v", err)
+	}
+
+	mu.Lock()
+	defer mu.Unlock()
+	if len(attempts) != maxDownloadAttempts {
+		t.Fatalf("expected %d attempts, got %d", maxDownloadAttempts, len(attempts))
+	}
+	for i := 1; i < len(attempts); i++ {
+		// 1ms, 2ms, 4ms, 8ms
+		if delay, minimum := attempts[i].Sub(attempts[i-1]), time.Millisecond<<uint(i-1); delay < minimum {
+			t.Errorf("expected attempt %d to be delayed by at least %v, got %v", i+1, minimum, delay)
+		}
+	}
+}
+
+func TestRestoreJobs_Download_unknownChecksum(t *testing.T) {
+	setup()
+	defer teardown()
+
+	job := &RestoreJob{
+		ID:       "5a4279d4fcc178500596745a",
+		Delivery: &Delivery{MethodName: DeliveryHTTP, StatusName: DeliveryReady, URL: server.URL + "/pull/5a4279d4fcc178500596745a/rs0.tar.gz"},
+		Hashes: []*RestoreJobHash{
+			{FileName: "rs1.tar.gz", Hash: "0000", TypeName: "SHA1"},
+			{FileName: "rs2.tar.gz", Hash: "1111", TypeName: "SHA1"},
+		},
+	}
+	mux.HandleFunc("/pull/", func(w http.ResponseWriter, r *http.Request) {
+		t.Error("the archive should not be downloaded")
+	})
+
+	err := client.RestoreJobs.Download(ctx, job, ioutil.Discard)
+	if err == nil || !strings.Contains(err.Error(), "none of the 2 checksums") {
+		t.Fatalf("expected an error about the checksums, got %v", err)
+	}
+}
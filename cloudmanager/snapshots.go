@@ -0,0 +1,157 @@
+// Copyright 2020 MongoDB Inc
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package cloudmanager
+
+import (
+	"context"
+	"fmt"
+	"net/http"
+
+	atlas "github.com/mongodb/go-client-mongodb-atlas/mongodbatlas"
+)
+
+const (
+	snapshotsBasePath = "groups/%s/clusters/%s/snapshots"
+)
+
+// SnapshotsService is an interface for interfacing with the Snapshots
+// endpoints of the MongoDB Cloud Manager API.
+// See more: https://docs.cloudmanager.mongodb.com/reference/api/snapshots/
+type SnapshotsService interface {
+	List(context.Context, string, string, *atlas.ListOptions) (*Snapshots, *atlas.Response, error)
+	Get(context.Context, string, string, string) (*Snapshot, *atlas.Response, error)
+}
+
+// SnapshotsServiceOp handles communication with the Snapshots related methods of the
+// MongoDB Cloud Manager API
+type SnapshotsServiceOp struct {
+	client *Client
+}
+
+var _ SnapshotsService = &SnapshotsServiceOp{}
+
+// SnapshotTimestamp a point in time of the oplog
+type SnapshotTimestamp struct {
+	Date      string `json:"date"`
+	Increment int64  `json:"increment"`
+}
+
+// NamespaceFilter the namespaces included in, or excluded from, a snapshot
+type NamespaceFilter struct {
+	FilterList []string `json:"filterList"`
+	FilterType string   `json:"filterType"` // whitelist or blacklist
+}
+
+// SnapshotPart the snapshot of a replica set or config server, which is part of a cluster snapshot
+type SnapshotPart struct {
+	ClusterID          string  `json:"clusterId"`
+	CompressionSetting string  `json:"compressionSetting,omitempty"`
+	DataSizeBytes      float64 `json:"dataSizeBytes,omitempty"`
+	EncryptionEnabled  bool    `json:"encryptionEnabled,omitempty"`
+	FileSizeBytes      float64 `json:"fileSizeBytes,omitempty"`
+	MasterKeyUUID      string  `json:"masterKeyUUID,omitempty"`
+	MongodVersion      string  `json:"mongodVersion,omitempty"`
+	ReplicaSetName     string  `json:"replicaSetName,omitempty"`
+	StorageSizeBytes   float64 `json:"storageSizeBytes,omitempty"`
+	TypeName           string  `json:"typeName"`
+}
+
+// Snapshot represents a snapshot of a cluster
+type Snapshot struct {
+	ID                        string             `json:"id"`
+	ClusterID                 string             `json:"clusterId"`
+	Complete                  bool               `json:"complete"`
+	Created                   *SnapshotTimestamp `json:"created,omitempty"`
+	DoNotDelete               bool               `json:"doNotDelete,omitempty"`
+	Expires                   string             `json:"expires,omitempty"`
+	GroupID                   string             `json:"groupId"`
+	IsPossiblyInconsistent    bool               `json:"isPossiblyInconsistent,omitempty"`
+	LastOplogAppliedTimestamp *SnapshotTimestamp `json:"lastOplogAppliedTimestamp,omitempty"`
+	Links                     []*atlas.Link      `json:"links,omitempty"`
+	MissingShards             []*Host            `json:"missingShards,omitempty"`
+	NamespaceFilterList       *NamespaceFilter   `json:"namespaceFilterList,omitempty"`
+	Parts                     []*SnapshotPart    `json:"parts,omitempty"`
+}
+
+// Snapshots represents a array of snapshots
+type Snapshots struct {
+	Links      []*atlas.Link `json:"links"`
+	Results    []*Snapshot   `json:"results"`
+	TotalCount int           `json:"totalCount"`
+}
+
+// List gets the snapshots of a cluster, most recent first.
+// See more: https://docs.cloudmanager.mongodb.com/reference/api/snapshots/get-all-snapshots-for-one-cluster/
+func (s *SnapshotsServiceOp) List(ctx context.Context, projectID, clusterID string, opts *atlas.ListOptions) (*Snapshots, *atlas.Response, error) {
+	if projectID == "" {
+		return nil, nil, atlas.NewArgError("projectID", "must be set")
+	}
+	if clusterID == "" {
+		return nil, nil, atlas.NewArgError("clusterID", "must be set")
+	}
+
+	basePath := fmt.Sprintf(snapshotsBasePath, projectID, clusterID)
+	path, err := setListOptions(basePath, opts)
+	if err != nil {
+		return nil, nil, err
+	}
+
+	req, err := s.client.NewRequest(ctx, http.MethodGet, path, nil)
+	if err != nil {
+		return nil, nil, err
+	}
+
+	root := new(Snapshots)
+	resp, err := s.client.Do(ctx, req, root)
+	if err != nil {
+		return nil, resp, err
+	}
+
+	if l := root.Links; l != nil {
+		resp.Links = l
+	}
+
+	return root, resp, nil
+}
+
+// Get gets a single snapshot of a cluster.
+// See more: https://docs.cloudmanager.mongodb.com/reference/api/snapshots/get-one-snapshot-for-one-cluster/
+func (s *SnapshotsServiceOp) Get(ctx context.Context, projectID, clusterID, snapshotID string) (*Snapshot, *atlas.Response, error) {
+	if projectID == "" {
+		return nil, nil, atlas.NewArgError("projectID", "must be set")
+	}
+	if clusterID == "" {
+		return nil, nil, atlas.NewArgError("clusterID", "must be set")
+	}
+	if snapshotID == "" {
+		return nil, nil, atlas.NewArgError("snapshotID", "must be set")
+	}
+
+	basePath := fmt.Sprintf(snapshotsBasePath, projectID, clusterID)
+	path := fmt.Sprintf("%s/%s", basePath, snapshotID)
+
+	req, err := s.client.NewRequest(ctx, http.MethodGet, path, nil)
+	if err != nil {
+		return nil, nil, err
+	}
+
+	root := new(Snapshot)
+	resp, err := s.client.Do(ctx, req, root)
+	if err != nil {
+		return nil, resp, err
+	}
+
+	return root, resp, err
+}
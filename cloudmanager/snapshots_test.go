@@ -0,0 +1,165 @@
+// Copyright 2020 MongoDB Inc
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package cloudmanager
+
+import (
+	"fmt"
+	"net/http"
+	"testing"
+
+	"github.com/go-test/deep"
+	"github.com/mongodb/go-client-mongodb-atlas/mongodbatlas"
+)
+
+func TestSnapshots_List(t *testing.T) {
+	setup()
+	defer teardown()
+
+	projectID := "5a0a1e7e0f2912c554080adc"
+	clusterID := "7c88887e0f2912c554080ae1"
+
+	mux.HandleFunc(fmt.Sprintf("/groups/%s/clusters/%s/snapshots", projectID, clusterID), func(w http.ResponseWriter, r *http.Request) {
+		testMethod(t, r, http.MethodGet)
+		_, _ = fmt.Fprint(w, `{
+			"links": [],
+			"results": [{
+				"clusterId": "7c88887e0f2912c554080ae1",
+				"complete": true,
+				"created": {"date": "2017-12-26T16:32:16Z", "increment": 1},
+				"doNotDelete": false,
+				"expires": "2017-12-28T16:32:16Z",
+				"groupId": "5a0a1e7e0f2912c554080adc",
+				"id": "5a4279d4fcc178500596745a",
+				"lastOplogAppliedTimestamp": {"date": "2017-12-26T16:32:15Z", "increment": 1},
+				"links": [],
+				"parts": [{
+					"clusterId": "7c88887e0f2912c554080ae1",
+					"compressionSetting": "GZIP",
+					"dataSizeBytes": 4502,
+					"fileSizeBytes": 324760,
+					"mongodVersion": "3.6.0",
+					"replicaSetName": "rs0",
+					"storageSizeBytes": 53248,
+					"typeName": "REPLICA_SET"
+				}]
+			}],
+			"totalCount": 1
+		}`)
+	})
+
+	snapshots, _, err := client.Snapshots.List(ctx, projectID, clusterID, nil)
+	if err != nil {
+		t.Fatalf("Snapshots.List returned error: %v", err)
+	}
+
+	expected := &Snapshots{
+		Links: []*mongodbatlas.Link{},
+		Results: []*Snapshot{
+			{
+				ID:                        "5a4279d4fcc178500596745a",
+				ClusterID:                 "7c88887e0f2912c554080ae1",
+				Complete:                  true,
+				Created:                   &SnapshotTimestamp{Date: "2017-12-26T16:32:16Z", Increment: 1},
+				Expires:                   "2017-12-28T16:32:16Z",
+				GroupID:                   "5a0a1e7e0f2912c554080adc",
+				LastOplogAppliedTimestamp: &SnapshotTimestamp{Date: "2017-12-26T16:32:15Z", Increment: 1},
+				Links:                     []*mongodbatlas.Link{},
+				Parts: []*SnapshotPart{
+					{
+						ClusterID:          "7c88887e0f2912c554080ae1",
+						CompressionSetting: "GZIP",
+						DataSizeBytes:      4502,
+						FileSizeBytes:      324760,
+						MongodVersion:      "3.6.0",
+						ReplicaSetName:     "rs0",
+						StorageSizeBytes:   53248,
+						TypeName:           "REPLICA_SET",
+					},
+				},
+			},
+		},
+		TotalCount: 1,
+	}
+
+	if diff := deep.Equal(snapshots, expected); diff != nil {
+		t.Error(diff)
+	}
+}
+
+func TestSnapshots_Get(t *testing.T) {
+	setup()
+	defer teardown()
+
+	projectID := "5a0a1e7e0f2912c554080adc"
+	clusterID := "7c88887e0f2912c554080ae1"
+	snapshotID := "5a4279d4fcc178500596745a"
+
+	mux.HandleFunc(fmt.Sprintf("/groups/%s/clusters/%s/snapshots/%s", projectID, clusterID, snapshotID), func(w http.ResponseWriter, r *http.Request) {
+		testMethod(t, r, http.MethodGet)
+		_, _ = fmt.Fprint(w, `{
+			"clusterId": "7c88887e0f2912c554080ae1",
+			"complete": true,
+			"created": {"date": "2017-12-26T16:32:16Z", "increment": 1},
+			"doNotDelete": false,
+			"expires": "2017-12-28T16:32:16Z",
+			"groupId": "5a0a1e7e0f2912c554080adc",
+			"id": "5a4279d4fcc178500596745a",
+			"lastOplogAppliedTimestamp": {"date": "2017-12-26T16:32:15Z", "increment": 1},
+			"links": [],
+			"parts": [{
+				"clusterId": "7c88887e0f2912c554080ae1",
+				"compressionSetting": "GZIP",
+				"dataSizeBytes": 4502,
+				"fileSizeBytes": 324760,
+				"mongodVersion": "3.6.0",
+				"replicaSetName": "rs0",
+				"storageSizeBytes": 53248,
+				"typeName": "REPLICA_SET"
+			}]
+		}`)
+	})
+
+	snapshot, _, err := client.Snapshots.Get(ctx, projectID, clusterID, snapshotID)
+	if err != nil {
+		t.Fatalf("Snapshots.Get returned error: %v", err)
+	}
+
+	expected := &Snapshot{
+		ID:                        "5a4279d4fcc178500596745a",
+		ClusterID:                 "7c88887e0f2912c554080ae1",
+		Complete:                  true,
+		Created:                   &SnapshotTimestamp{Date: "2017-12-26T16:32:16Z", Increment: 1},
+		Expires:                   "2017-12-28T16:32:16Z",
+		GroupID:                   "5a0a1e7e0f2912c554080adc",
+		LastOplogAppliedTimestamp: &SnapshotTimestamp{Date: "2017-12-26T16:32:15Z", Increment: 1},
+		Links:                     []*mongodbatlas.Link{},
+		Parts: []*SnapshotPart{
+			{
+				ClusterID:          "7c88887e0f2912c554080ae1",
+				CompressionSetting: "GZIP",
+				DataSizeBytes:      4502,
+				FileSizeBytes:      324760,
+				MongodVersion:      "3.6.0",
+				ReplicaSetName:     "rs0",
+				StorageSizeBytes:   53248,
+				TypeName:           "REPLICA_SET",
+			},
+		},
+	}
+
+	if diff := deep.Equal(snapshot, expected); diff != nil {
+		t.Error(diff)
+	}
+}
@@ -0,0 +1,483 @@
+// Copyright 2020 MongoDB Inc
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package cloudmanager
+
+import (
+	"context"
+	"fmt"
+	"net/http"
+	"net/url"
+
+	atlas "github.com/mongodb/go-client-mongodb-atlas/mongodbatlas"
+)
+
+const (
+	teamsBasePath        = "orgs/%s/teams"
+	projectTeamsBasePath = "groups/%s/teams"
+)
+
+// TeamsService is an interface for interfacing with the Teams
+// endpoints of the MongoDB Cloud Manager API.
+// See more: https://docs.cloudmanager.mongodb.com/reference/api/teams/
+type TeamsService interface {
+	List(context.Context, string, *atlas.ListOptions) (*Teams, *atlas.Response, error)
+	Get(context.Context, string, string) (*Team, *atlas.Response, error)
+	GetByName(context.Context, string, string) (*Team, *atlas.Response, error)
+	Create(context.Context, string, *Team) (*Team, *atlas.Response, error)
+	Rename(context.Context, string, string, string) (*Team, *atlas.Response, error)
+	Delete(context.Context, string, string) (*atlas.Response, error)
+	ListUsers(context.Context, string, string, *atlas.ListOptions) (*Users, *atlas.Response, error)
+	AddUsers(context.Context, string, string, []string) (*Users, *atlas.Response, error)
+	RemoveUser(context.Context, string, string, string) (*atlas.Response, error)
+	ListProjectTeams(context.Context, string) (*TeamsAssigned, *atlas.Response, error)
+	AddToProject(context.Context, string, []*Result) (*TeamsAssigned, *atlas.Response, error)
+	UpdateProjectRoles(context.Context, string, string, []RoleName) (*TeamsAssigned, *atlas.Response, error)
+	RemoveFromProject(context.Context, string, string) (*atlas.Response, error)
+}
+
+// TeamsServiceOp handles communication with the Teams related methods of the
+// MongoDB Cloud Manager API
+type TeamsServiceOp struct {
+	client *Client
+}
+
+var _ TeamsService = &TeamsServiceOp{}
+
+// Team represents a team of an organization.
+// Usernames is only used to add the initial members when creating a team.
+type Team struct {
+	ID        string        `json:"id,omitempty"`
+	Links     []*atlas.Link `json:"links,omitempty"`
+	Name      string        `json:"name"`
+	Usernames []string      `json:"usernames,omitempty"`
+}
+
+// Teams represents a array of teams
+type Teams struct {
+	Links      []*atlas.Link `json:"links"`
+	Results    []*Team       `json:"results"`
+	TotalCount int           `json:"totalCount"`
+}
+
+// Users represents a array of users
+type Users struct {
+	Links      []*atlas.Link `json:"links"`
+	Results    []*User       `json:"results"`
+	TotalCount int           `json:"totalCount"`
+}
+
+// Result the roles of a team in a project
+type Result struct {
+	Links     []*atlas.Link `json:"links,omitempty"`
+	RoleNames []RoleName    `json:"roleNames"`
+	TeamID    string        `json:"teamId"`
+}
+
+// TeamsAssigned represents the teams of a project, and their roles
+type TeamsAssigned struct {
+	Links      []*atlas.Link `json:"links"`
+	Results    []*Result     `json:"results"`
+	TotalCount int           `json:"totalCount"`
+}
+
+// teamUser the body of a request adding a user to a team
+type teamUser struct {
+	ID string `json:"id"`
+}
+
+// teamRoles the body of a request updating the roles of a team in a project
+type teamRoles struct {
+	RoleNames []RoleName `json:"roleNames"`
+}
+
+// validateTeamRoles returns an *InvalidRoleError unless every role is a project role
+func validateTeamRoles(roleNames []RoleName) error {
+	if len(roleNames) == 0 {
+		return atlas.NewArgError("roleNames", "must be set")
+	}
+	return validateRoleNames(roleNames, ProjectRoles, "teams can only be granted project roles")
+}
+
+// List gets the teams of an organization.
+// See more: https://docs.cloudmanager.mongodb.com/reference/api/teams/teams-get-all/
+func (s *TeamsServiceOp) List(ctx context.Context, orgID string, opts *atlas.ListOptions) (*Teams, *atlas.Response, error) {
+	if orgID == "" {
+		return nil, nil, atlas.NewArgError("orgID", "must be set")
+	}
+
+	basePath := fmt.Sprintf(teamsBasePath, orgID)
+	path, err := setListOptions(basePath, opts)
+	if err != nil {
+		return nil, nil, err
+	}
+
+	req, err := s.client.NewRequest(ctx, http.MethodGet, path, nil)
+	if err != nil {
+		return nil, nil, err
+	}
+
+	root := new(Teams)
+	resp, err := s.client.Do(ctx, req, root)
+	if err != nil {
+		return nil, resp, err
+	}
+
+	if l := root.Links; l != nil {
+		resp.Links = l
+	}
+
+	return root, resp, nil
+}
+
+// Get gets a single team by its ID.
+// See more: https://docs.cloudmanager.mongodb.com/reference/api/teams/teams-get-one-by-id/
+func (s *TeamsServiceOp) Get(ctx context.Context, orgID, teamID string) (*Team, *atlas.Response, error) {
+	if orgID == "" {
+		return nil, nil, atlas.NewArgError("orgID", "must be set")
+	}
+	if teamID == "" {
+		return nil, nil, atlas.NewArgError("teamID", "must be set")
+	}
+
+	basePath := fmt.Sprintf(teamsBasePath, orgID)
+	path := fmt.Sprintf("%s/%s", basePath, teamID)
+
+	return s.get(ctx, path)
+}
+
+// GetByName gets a single team by its name.
+// See more: https://docs.cloudmanager.mongodb.com/reference/api/teams/teams-get-one-by-name/
+func (s *TeamsServiceOp) GetByName(ctx context.Context, orgID, teamName string) (*Team, *atlas.Response, error) {
+	if orgID == "" {
+		return nil, nil, atlas.NewArgError("orgID", "must be set")
+	}
+	if teamName == "" {
+		return nil, nil, atlas.NewArgError("teamName", "must be set")
+	}
+
+	basePath := fmt.Sprintf(teamsBasePath, orgID)
+	path := fmt.Sprintf("%s/byName/%s", basePath, url.PathEscape(teamName))
+
+	return s.get(ctx, path)
+}
+
+func (s *TeamsServiceOp) get(ctx context.Context, path string) (*Team, *atlas.Response, error) {
+	req, err := s.client.NewRequest(ctx, http.MethodGet, path, nil)
+	if err != nil {
+		return nil, nil, err
+	}
+
+	root := new(Team)
+	resp, err := s.client.Do(ctx, req, root)
+	if err != nil {
+		return nil, resp, err
+	}
+
+	return root, resp, err
+}
+
+// Create creates a team with its initial members; at least one username is required.
+// See more: https://docs.cloudmanager.mongodb.com/reference/api/teams/teams-create-one/
+func (s *TeamsServiceOp) Create(ctx context.Context, orgID string, createRequest *Team) (*Team, *atlas.Response, error) {
+	if orgID == "" {
+		return nil, nil, atlas.NewArgError("orgID", "must be set")
+	}
+	if createRequest == nil {
+		return nil, nil, atlas.NewArgError("createRequest", "cannot be nil")
+	}
+	if createRequest.Name == "" {
+		return nil, nil, atlas.NewArgError("name", "must be set")
+	}
+	if len(createRequest.Usernames) == 0 {
+		return nil, nil, atlas.NewArgError("usernames", "must be set")
+	}
+
+	path := fmt.Sprintf(teamsBasePath, orgID)
+
+	req, err := s.client.NewRequest(ctx, http.MethodPost, path, createRequest)
+	if err != nil {
+		return nil, nil, err
+	}
+
+	root := new(Team)
+	resp, err := s.client.Do(ctx, req, root)
+	if err != nil {
+		return nil, resp, err
+	}
+
+	return root, resp, err
+}
+
+// Rename changes the name of a team.
+// See more: https://docs.cloudmanager.mongodb.com/reference/api/teams/teams-rename-one/
+func (s *TeamsServiceOp) Rename(ctx context.Context, orgID, teamID, teamName string) (*Team, *atlas.Response, error) {
+	if orgID == "" {
+		return nil, nil, atlas.NewArgError("orgID", "must be set")
+	}
+	if teamID == "" {
+		return nil, nil, atlas.NewArgError("teamID", "must be set")
+	}
+	if teamName == "" {
+		return nil, nil, atlas.NewArgError("teamName", "must be set")
+	}
+
+	basePath := fmt.Sprintf(teamsBasePath, orgID)
+	path := fmt.Sprintf("%s/%s", basePath, teamID)
+
+	req, err := s.client.NewRequest(ctx, http.MethodPatch, path, &Team{Name: teamName})
+	if err != nil {
+		return nil, nil, err
+	}
+
+	root := new(Team)
+	resp, err := s.client.Do(ctx, req, root)
+	if err != nil {
+		return nil, resp, err
+	}
+
+	return root, resp, err
+}
+
+// Delete deletes a team; its members keep their other roles.
+// See more: https://docs.cloudmanager.mongodb.com/reference/api/teams/teams-delete-one/
+func (s *TeamsServiceOp) Delete(ctx context.Context, orgID, teamID string) (*atlas.Response, error) {
+	if orgID == "" {
+		return nil, atlas.NewArgError("orgID", "must be set")
+	}
+	if teamID == "" {
+		return nil, atlas.NewArgError("teamID", "must be set")
+	}
+
+	basePath := fmt.Sprintf(teamsBasePath, orgID)
+	path := fmt.Sprintf("%s/%s", basePath, teamID)
+
+	req, err := s.client.NewRequest(ctx, http.MethodDelete, path, nil)
+	if err != nil {
+		return nil, err
+	}
+
+	resp, err := s.client.Do(ctx, req, nil)
+
+	return resp, err
+}
+
+// ListUsers gets the members of a team.
+// See more: https://docs.cloudmanager.mongodb.com/reference/api/teams/teams-get-all-users/
+func (s *TeamsServiceOp) ListUsers(ctx context.Context, orgID, teamID string, opts *atlas.ListOptions) (*Users, *atlas.Response, error) {
+	if orgID == "" {
+		return nil, nil, atlas.NewArgError("orgID", "must be set")
+	}
+	if teamID == "" {
+		return nil, nil, atlas.NewArgError("teamID", "must be set")
+	}
+
+	basePath := fmt.Sprintf(teamsBasePath, orgID)
+	path, err := setListOptions(fmt.Sprintf("%s/%s/users", basePath, teamID), opts)
+	if err != nil {
+		return nil, nil, err
+	}
+
+	req, err := s.client.NewRequest(ctx, http.MethodGet, path, nil)
+	if err != nil {
+		return nil, nil, err
+	}
+
+	root := new(Users)
+	resp, err := s.client.Do(ctx, req, root)
+	if err != nil {
+		return nil, resp, err
+	}
+
+	if l := root.Links; l != nil {
+		resp.Links = l
+	}
+
+	return root, resp, nil
+}
+
+// AddUsers adds existing users of the organization to a team, and returns the members of the team.
+// See more: https://docs.cloudmanager.mongodb.com/reference/api/teams/teams-add-user/
+func (s *TeamsServiceOp) AddUsers(ctx context.Context, orgID, teamID string, userIDs []string) (*Users, *atlas.Response, error) {
+	if orgID == "" {
+		return nil, nil, atlas.NewArgError("orgID", "must be set")
+	}
+	if teamID == "" {
+		return nil, nil, atlas.NewArgError("teamID", "must be set")
+	}
+	if len(userIDs) == 0 {
+		return nil, nil, atlas.NewArgError("userIDs", "must be set")
+	}
+
+	basePath := fmt.Sprintf(teamsBasePath, orgID)
+	path := fmt.Sprintf("%s/%s/users", basePath, teamID)
+
+	users := make([]*teamUser, len(userIDs))
+	for i, userID := range userIDs {
+		users[i] = &teamUser{ID: userID}
+	}
+
+	req, err := s.client.NewRequest(ctx, http.MethodPost, path, users)
+	if err != nil {
+		return nil, nil, err
+	}
+
+	root := new(Users)
+	resp, err := s.client.Do(ctx, req, root)
+	if err != nil {
+		return nil, resp, err
+	}
+
+	return root, resp, err
+}
+
+// RemoveUser removes a user from a team.
+// See more: https://docs.cloudmanager.mongodb.com/reference/api/teams/teams-remove-user/
+func (s *TeamsServiceOp) RemoveUser(ctx context.Context, orgID, teamID, userID string) (*atlas.Response, error) {
+	if orgID == "" {
+		return nil, atlas.NewArgError("orgID", "must be set")
+	}
+	if teamID == "" {
+		return nil, atlas.NewArgError("teamID", "must be set")
+	}
+	if userID == "" {
+		return nil, atlas.NewArgError("userID", "must be set")
+	}
+
+	basePath := fmt.Sprintf(teamsBasePath, orgID)
+	path := fmt.Sprintf("%s/%s/users/%s", basePath, teamID, userID)
+
+	req, err := s.client.NewRequest(ctx, http.MethodDelete, path, nil)
+	if err != nil {
+		return nil, err
+	}
+
+	resp, err := s.client.Do(ctx, req, nil)
+
+	return resp, err
+}
+
+// ListProjectTeams gets the teams assigned to a project, and their roles.
+// See more: https://docs.cloudmanager.mongodb.com/reference/api/groups/project-get-teams/
+func (s *TeamsServiceOp) ListProjectTeams(ctx context.Context, projectID string) (*TeamsAssigned, *atlas.Response, error) {
+	if projectID == "" {
+		return nil, nil, atlas.NewArgError("projectID", "must be set")
+	}
+
+	path := fmt.Sprintf(projectTeamsBasePath, projectID)
+
+	req, err := s.client.NewRequest(ctx, http.MethodGet, path, nil)
+	if err != nil {
+		return nil, nil, err
+	}
+
+	root := new(TeamsAssigned)
+	resp, err := s.client.Do(ctx, req, root)
+	if err != nil {
+		return nil, resp, err
+	}
+
+	if l := root.Links; l != nil {
+		resp.Links = l
+	}
+
+	return root, resp, nil
+}
+
+// AddToProject assigns teams to a project with the given project roles.
+// See more: https://docs.cloudmanager.mongodb.com/reference/api/groups/project-add-team/
+func (s *TeamsServiceOp) AddToProject(ctx context.Context, projectID string, teams []*Result) (*TeamsAssigned, *atlas.Response, error) {
+	if projectID == "" {
+		return nil, nil, atlas.NewArgError("projectID", "must be set")
+	}
+	if len(teams) == 0 {
+		return nil, nil, atlas.NewArgError("teams", "must be set")
+	}
+	for _, team := range teams {
+		if team == nil || team.TeamID == "" {
+			return nil, nil, atlas.NewArgError("teamID", "must be set")
+		}
+		if err := validateTeamRoles(team.RoleNames); err != nil {
+			return nil, nil, err
+		}
+	}
+
+	path := fmt.Sprintf(projectTeamsBasePath, projectID)
+
+	req, err := s.client.NewRequest(ctx, http.MethodPost, path, teams)
+	if err != nil {
+		return nil, nil, err
+	}
+
+	root := new(TeamsAssigned)
+	resp, err := s.client.Do(ctx, req, root)
+	if err != nil {
+		return nil, resp, err
+	}
+
+	return root, resp, err
+}
+
+// UpdateProjectRoles replaces the roles of a team in a project.
+// See more: https://docs.cloudmanager.mongodb.com/reference/api/teams/teams-update-roles/
+func (s *TeamsServiceOp) UpdateProjectRoles(ctx context.Context, projectID, teamID string, roleNames []RoleName) (*TeamsAssigned, *atlas.Response, error) {
+	if projectID == "" {
+		return nil, nil, atlas.NewArgError("projectID", "must be set")
+	}
+	if teamID == "" {
+		return nil, nil, atlas.NewArgError("teamID", "must be set")
+	}
+	if err := validateTeamRoles(roleNames); err != nil {
+		return nil, nil, err
+	}
+
+	basePath := fmt.Sprintf(projectTeamsBasePath, projectID)
+	path := fmt.Sprintf("%s/%s", basePath, teamID)
+
+	req, err := s.client.NewRequest(ctx, http.MethodPatch, path, &teamRoles{RoleNames: roleNames})
+	if err != nil {
+		return nil, nil, err
+	}
+
+	root := new(TeamsAssigned)
+	resp, err := s.client.Do(ctx, req, root)
+	if err != nil {
+		return nil, resp, err
+	}
+
+	return root, resp, err
+}
+
+// RemoveFromProject removes a team from a project.
+// See more: https://docs.cloudmanager.mongodb.com/reference/api/groups/project-remove-team/
+func (s *TeamsServiceOp) RemoveFromProject(ctx context.Context, projectID, teamID string) (*atlas.Response, error) {
+	if projectID == "" {
+		return nil, atlas.NewArgError("projectID", "must be set")
+	}
+	if teamID == "" {
+		return nil, atlas.NewArgError("teamID", "must be set")
+	}
+
+	basePath := fmt.Sprintf(projectTeamsBasePath, projectID)
+	path := fmt.Sprintf("%s/%s", basePath, teamID)
+
+	req, err := s.client.NewRequest(ctx, http.MethodDelete, path, nil)
+	if err != nil {
+		return nil, err
+	}
+
+	resp, err := s.client.Do(ctx, req, nil)
+
+	return resp, err
+}
@@ -0,0 +1,383 @@
+// Copyright 2020 MongoDB Inc
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package cloudmanager
+
+import (
+	"fmt"
+	"net/http"
+	"testing"
+
+	"github.com/go-test/deep"
+	"github.com/mongodb/go-client-mongodb-atlas/mongodbatlas"
+)
+
+func TestTeams_List(t *testing.T) {
+	setup()
+	defer teardown()
+
+	orgID := "5a0a1e7e0f2912c554080adb"
+
+	mux.HandleFunc(fmt.Sprintf("/orgs/%s/teams", orgID), func(w http.ResponseWriter, r *http.Request) {
+		testMethod(t, r, http.MethodGet)
+		_, _ = fmt.Fprint(w, `{
+			"links": [],
+			"results": [{
+				"id": "6b610e1087d9d66b272f0c86",
+				"links": [],
+				"name": "DBAs"
+			}],
+			"totalCount": 1
+		}`)
+	})
+
+	teams, _, err := client.Teams.List(ctx, orgID, nil)
+	if err != nil {
+		t.Fatalf("Teams.List returned error: %v", err)
+	}
+
+	expected := &Teams{
+		Links: []*mongodbatlas.Link{},
+		Results: []*Team{
+			{
+				ID:    "6b610e1087d9d66b272f0c86",
+				Links: []*mongodbatlas.Link{},
+				Name:  "DBAs",
+			},
+		},
+		TotalCount: 1,
+	}
+
+	if diff := deep.Equal(teams, expected); diff != nil {
+		t.Error(diff)
+	}
+}
+
+func TestTeams_GetByName(t *testing.T) {
+	setup()
+	defer teardown()
+
+	orgID := "5a0a1e7e0f2912c554080adb"
+
+	mux.HandleFunc(fmt.Sprintf("/orgs/%s/teams/byName/DBAs", orgID), func(w http.ResponseWriter, r *http.Request) {
+		testMethod(t, r, http.MethodGet)
+		_, _ = fmt.Fprint(w, `{
+			"id": "6b610e1087d9d66b272f0c86",
+			"links": [],
+			"name": "DBAs"
+		}`)
+	})
+
+	team, _, err := client.Teams.GetByName(ctx, orgID, "DBAs")
+	if err != nil {
+		t.Fatalf("Teams.GetByName returned error: %v", err)
+	}
+
+	expected := &Team{
+		ID:    "6b610e1087d9d66b272f0c86",
+		Links: []*mongodbatlas.Link{},
+		Name:  "DBAs",
+	}
+
+	if diff := deep.Equal(team, expected); diff != nil {
+		t.Error(diff)
+	}
+}
+
+func TestTeams_Create(t *testing.T) {
+	setup()
+	defer teardown()
+
+	orgID := "5a0a1e7e0f2912c554080adb"
+
+	mux.HandleFunc(fmt.Sprintf("/orgs/%s/teams", orgID), func(w http.ResponseWriter, r *http.Request) {
+		testMethod(t, r, http.MethodPost)
+
+		expected := map[string]interface{}{
+			"name":      "DBAs",
+			"usernames": []interface{}{"jane.doe@example.com"},
+		}
+		if diff := deep.Equal(decodeBody(t, r), expected); diff != nil {
+			t.Error(diff)
+		}
+
+		_, _ = fmt.Fprint(w, `{
+			"id": "6b610e1087d9d66b272f0c86",
+			"links": [],
+			"name": "DBAs"
+		}`)
+	})
+
+	team, _, err := client.Teams.Create(ctx, orgID, &Team{Name: "DBAs", Usernames: []string{"jane.doe@example.com"}})
+	if err != nil {
+		t.Fatalf("Teams.Create returned error: %v", err)
+	}
+
+	expected := &Team{
+		ID:    "6b610e1087d9d66b272f0c86",
+		Links: []*mongodbatlas.Link{},
+		Name:  "DBAs",
+	}
+
+	if diff := deep.Equal(team, expected); diff != nil {
+		t.Error(diff)
+	}
+}
+
+func TestTeams_Rename(t *testing.T) {
+	setup()
+	defer teardown()
+
+	orgID := "5a0a1e7e0f2912c554080adb"
+	teamID := "6b610e1087d9d66b272f0c86"
+
+	mux.HandleFunc(fmt.Sprintf("/orgs/%s/teams/%s", orgID, teamID), func(w http.ResponseWriter, r *http.Request) {
+		testMethod(t, r, http.MethodPatch)
+
+		expected := map[string]interface{}{"name": "DBAs"}
+		if diff := deep.Equal(decodeBody(t, r), expected); diff != nil {
+			t.Error(diff)
+		}
+
+		_, _ = fmt.Fprint(w, `{
+			"id": "6b610e1087d9d66b272f0c86",
+			"links": [],
+			"name": "DBAs"
+		}`)
+	})
+
+	team, _, err := client.Teams.Rename(ctx, orgID, teamID, "DBAs")
+	if err != nil {
+		t.Fatalf("Teams.Rename returned error: %v", err)
+	}
+
+	expected := &Team{
+		ID:    "6b610e1087d9d66b272f0c86",
+		Links: []*mongodbatlas.Link{},
+		Name:  "DBAs",
+	}
+
+	if diff := deep.Equal(team, expected); diff != nil {
+		t.Error(diff)
+	}
+}
+
+func TestTeams_Delete(t *testing.T) {
+	setup()
+	defer teardown()
+
+	orgID := "5a0a1e7e0f2912c554080adb"
+	teamID := "6b610e1087d9d66b272f0c86"
+
+	mux.HandleFunc(fmt.Sprintf("/orgs/%s/teams/%s", orgID, teamID), func(w http.ResponseWriter, r *http.Request) {
+		testMethod(t, r, http.MethodDelete)
+	})
+
+	_, err := client.Teams.Delete(ctx, orgID, teamID)
+	if err != nil {
+		t.Fatalf("Teams.Delete returned error: %v", err)
+	}
+}
+
+func TestTeams_AddUsers(t *testing.T) {
+	setup()
+	defer teardown()
+
+	orgID := "5a0a1e7e0f2912c554080adb"
+	teamID := "6b610e1087d9d66b272f0c86"
+
+	mux.HandleFunc(fmt.Sprintf("/orgs/%s/teams/%s/users", orgID, teamID), func(w http.ResponseWriter, r *http.Request) {
+		testMethod(t, r, http.MethodPost)
+
+		expected := []interface{}{map[string]interface{}{"id": "533dc19ce4b00835ff81e2eb"}}
+		if diff := deep.Equal(decodeBody(t, r), expected); diff != nil {
+			t.Error(diff)
+		}
+
+		_, _ = fmt.Fprint(w, `{
+			"links": [],
+			"results": [{
+				"emailAddress": "jane.doe@example.com",
+				"firstName": "Jane",
+				"id": "533dc19ce4b00835ff81e2eb",
+				"lastName": "Doe",
+				"links": [],
+				"roles": [
+					{"orgId": "5a0a1e7e0f2912c554080adb", "roleName": "ORG_MEMBER"},
+					{"groupId": "5a0a1e7e0f2912c554080adc", "roleName": "GROUP_READ_ONLY"}
+				],
+				"username": "jane.doe@example.com"
+			}],
+			"totalCount": 1
+		}`)
+	})
+
+	users, _, err := client.Teams.AddUsers(ctx, orgID, teamID, []string{"533dc19ce4b00835ff81e2eb"})
+	if err != nil {
+		t.Fatalf("Teams.AddUsers returned error: %v", err)
+	}
+
+	expected := &Users{
+		Links: []*mongodbatlas.Link{},
+		Results: []*User{
+			{
+				EmailAddress: "jane.doe@example.com",
+				FirstName:    "Jane",
+				ID:           "533dc19ce4b00835ff81e2eb",
+				LastName:     "Doe",
+				Links:        []*mongodbatlas.Link{},
+				Roles: []*UserRole{
+					{OrgID: "5a0a1e7e0f2912c554080adb", RoleName: string(RoleOrgMember)},
+					{GroupID: "5a0a1e7e0f2912c554080adc", RoleName: string(RoleGroupReadOnly)},
+				},
+				Username: "jane.doe@example.com",
+			},
+		},
+		TotalCount: 1,
+	}
+
+	if diff := deep.Equal(users, expected); diff != nil {
+		t.Error(diff)
+	}
+}
+
+func TestTeams_RemoveUser(t *testing.T) {
+	setup()
+	defer teardown()
+
+	orgID := "5a0a1e7e0f2912c554080adb"
+	teamID := "6b610e1087d9d66b272f0c86"
+	userID := "533dc19ce4b00835ff81e2eb"
+
+	mux.HandleFunc(fmt.Sprintf("/orgs/%s/teams/%s/users/%s", orgID, teamID, userID), func(w http.ResponseWriter, r *http.Request) {
+		testMethod(t, r, http.MethodDelete)
+	})
+
+	_, err := client.Teams.RemoveUser(ctx, orgID, teamID, userID)
+	if err != nil {
+		t.Fatalf("Teams.RemoveUser returned error: %v", err)
+	}
+}
+
+func TestTeams_AddToProject(t *testing.T) {
+	setup()
+	defer teardown()
+
+	projectID := "5a0a1e7e0f2912c554080adc"
+
+	mux.HandleFunc(fmt.Sprintf("/groups/%s/teams", projectID), func(w http.ResponseWriter, r *http.Request) {
+		testMethod(t, r, http.MethodPost)
+
+		expected := []interface{}{
+			map[string]interface{}{"roleNames": []interface{}{"GROUP_OWNER"}, "teamId": "6b610e1087d9d66b272f0c86"},
+		}
+		if diff := deep.Equal(decodeBody(t, r), expected); diff != nil {
+			t.Error(diff)
+		}
+
+		_, _ = fmt.Fprint(w, `{
+			"links": [],
+			"results": [{"links": [], "roleNames": ["GROUP_OWNER"], "teamId": "6b610e1087d9d66b272f0c86"}],
+			"totalCount": 1
+		}`)
+	})
+
+	teams := []*Result{{TeamID: "6b610e1087d9d66b272f0c86", RoleNames: []RoleName{RoleGroupOwner}}}
+	assigned, _, err := client.Teams.AddToProject(ctx, projectID, teams)
+	if err != nil {
+		t.Fatalf("Teams.AddToProject returned error: %v", err)
+	}
+
+	expected := &TeamsAssigned{
+		Links: []*mongodbatlas.Link{},
+		Results: []*Result{
+			{Links: []*mongodbatlas.Link{}, RoleNames: []RoleName{RoleGroupOwner}, TeamID: "6b610e1087d9d66b272f0c86"},
+		},
+		TotalCount: 1,
+	}
+
+	if diff := deep.Equal(assigned, expected); diff != nil {
+		t.Error(diff)
+	}
+}
+
+func TestTeams_AddToProject_invalidRole(t *testing.T) {
+	setup()
+	defer teardown()
+
+	teams := []*Result{{TeamID: "6b610e1087d9d66b272f0c86", RoleNames: []RoleName{RoleOrgOwner}}}
+	_, _, err := client.Teams.AddToProject(ctx, "5a0a1e7e0f2912c554080adc", teams)
+
+	expected := &InvalidRoleError{RoleName: RoleOrgOwner, Reason: "teams can only be granted project roles"}
+	if diff := deep.Equal(err, expected); diff != nil {
+		t.Error(diff)
+	}
+}
+
+func TestTeams_UpdateProjectRoles(t *testing.T) {
+	setup()
+	defer teardown()
+
+	projectID := "5a0a1e7e0f2912c554080adc"
+	teamID := "6b610e1087d9d66b272f0c86"
+
+	mux.HandleFunc(fmt.Sprintf("/groups/%s/teams/%s", projectID, teamID), func(w http.ResponseWriter, r *http.Request) {
+		testMethod(t, r, http.MethodPatch)
+
+		expected := map[string]interface{}{"roleNames": []interface{}{"GROUP_OWNER"}}
+		if diff := deep.Equal(decodeBody(t, r), expected); diff != nil {
+			t.Error(diff)
+		}
+
+		_, _ = fmt.Fprint(w, `{
+			"links": [],
+			"results": [{"links": [], "roleNames": ["GROUP_OWNER"], "teamId": "6b610e1087d9d66b272f0c86"}],
+			"totalCount": 1
+		}`)
+	})
+
+	assigned, _, err := client.Teams.UpdateProjectRoles(ctx, projectID, teamID, []RoleName{RoleGroupOwner})
+	if err != nil {
+		t.Fatalf("Teams.UpdateProjectRoles returned error: %v", err)
+	}
+
+	expected := &TeamsAssigned{
+		Links: []*mongodbatlas.Link{},
+		Results: []*Result{
+			{Links: []*mongodbatlas.Link{}, RoleNames: []RoleName{RoleGroupOwner}, TeamID: "6b610e1087d9d66b272f0c86"},
+		},
+		TotalCount: 1,
+	}
+
+	if diff := deep.Equal(assigned, expected); diff != nil {
+		t.Error(diff)
+	}
+}
+
+func TestTeams_RemoveFromProject(t *testing.T) {
+	setup()
+	defer teardown()
+
+	projectID := "5a0a1e7e0f2912c554080adc"
+	teamID := "6b610e1087d9d66b272f0c86"
+
+	mux.HandleFunc(fmt.Sprintf("/groups/%s/teams/%s", projectID, teamID), func(w http.ResponseWriter, r *http.Request) {
+		testMethod(t, r, http.MethodDelete)
+	})
+
+	_, err := client.Teams.RemoveFromProject(ctx, projectID, teamID)
+	if err != nil {
+		t.Fatalf("Teams.RemoveFromProject returned error: %v", err)
+	}
+}
@@ -71,7 +71,7 @@ type WhitelistOpts struct {
 
 // User wrapper for a user response, augmented with a few extra fields
 type User struct {
-	Username     string        `json:"username"`
+	Username     string        `json:"username,omitempty"`
 	Password     string        `json:"password,omitempty"`
 	FirstName    string        `json:"firstName,omitempty"`
 	LastName     string        `json:"lastName,omitempty"`
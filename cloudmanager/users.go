@@ -0,0 +1,457 @@
+// Copyright 2020 MongoDB Inc
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package cloudmanager
+
+import (
+	"context"
+	"fmt"
+	"net"
+	"net/http"
+	"net/url"
+
+	atlas "github.com/mongodb/go-client-mongodb-atlas/mongodbatlas"
+)
+
+const (
+	usersBasePath = "users"
+)
+
+// RoleName the name of a role granted to a user, team or API key
+type RoleName string
+
+// Organization roles, granted with an OrgID
+const (
+	RoleOrgOwner        RoleName = "ORG_OWNER"
+	RoleOrgMember       RoleName = "ORG_MEMBER"
+	RoleOrgGroupCreator RoleName = "ORG_GROUP_CREATOR"
+	RoleOrgReadOnly     RoleName = "ORG_READ_ONLY"
+)
+
+// Project roles, granted with a GroupID
+const (
+	RoleGroupOwner               RoleName = "GROUP_OWNER"
+	RoleGroupReadOnly            RoleName = "GROUP_READ_ONLY"
+	RoleGroupAutomationAdmin     RoleName = "GROUP_AUTOMATION_ADMIN"
+	RoleGroupBackupAdmin         RoleName = "GROUP_BACKUP_ADMIN"
+	RoleGroupMonitoringAdmin     RoleName = "GROUP_MONITORING_ADMIN"
+	RoleGroupUserAdmin           RoleName = "GROUP_USER_ADMIN"
+	RoleGroupDataAccessAdmin     RoleName = "GROUP_DATA_ACCESS_ADMIN"
+	RoleGroupDataAccessReadWrite RoleName = "GROUP_DATA_ACCESS_READ_WRITE"
+	RoleGroupDataAccessReadOnly  RoleName = "GROUP_DATA_ACCESS_READ_ONLY"
+)
+
+// Global roles, granted on the whole deployment
+const (
+	RoleGlobalOwner           RoleName = "GLOBAL_OWNER"
+	RoleGlobalReadOnly        RoleName = "GLOBAL_READ_ONLY"
+	RoleGlobalAutomationAdmin RoleName = "GLOBAL_AUTOMATION_ADMIN"
+	RoleGlobalBackupAdmin     RoleName = "GLOBAL_BACKUP_ADMIN"
+	RoleGlobalMonitoringAdmin RoleName = "GLOBAL_MONITORING_ADMIN"
+	RoleGlobalUserAdmin       RoleName = "GLOBAL_USER_ADMIN"
+)
+
+var (
+	// OrgRoles the roles which can be granted on an organization
+	OrgRoles = []RoleName{RoleOrgOwner, RoleOrgMember, RoleOrgGroupCreator, RoleOrgReadOnly}
+
+	// ProjectRoles the roles which can be granted on a project
+	ProjectRoles = []RoleName{
+		RoleGroupOwner,
+		RoleGroupReadOnly,
+		RoleGroupAutomationAdmin,
+		RoleGroupBackupAdmin,
+		RoleGroupMonitoringAdmin,
+		RoleGroupUserAdmin,
+		RoleGroupDataAccessAdmin,
+		RoleGroupDataAccessReadWrite,
+		RoleGroupDataAccessReadOnly,
+	}
+
+	// GlobalRoles the roles which can be granted on the whole deployment
+	GlobalRoles = []RoleName{
+		RoleGlobalOwner,
+		RoleGlobalReadOnly,
+		RoleGlobalAutomationAdmin,
+		RoleGlobalBackupAdmin,
+		RoleGlobalMonitoringAdmin,
+		RoleGlobalUserAdmin,
+	}
+)
+
+func containsRole(roles []RoleName, role RoleName) bool {
+	for _, r := range roles {
+		if r == role {
+			return true
+		}
+	}
+	return false
+}
+
+// validateRoleNames returns an *InvalidRoleError for the first role which is not allowed
+func validateRoleNames(roleNames, allowed []RoleName, reason string) error {
+	for _, roleName := range roleNames {
+		if !containsRole(allowed, roleName) {
+			return &InvalidRoleError{RoleName: roleName, Reason: reason}
+		}
+	}
+	return nil
+}
+
+// InvalidRoleError is returned when a role name is unknown, or granted on the wrong kind of entity
+type InvalidRoleError struct {
+	RoleName RoleName
+	Reason   string
+}
+
+func (e *InvalidRoleError) Error() string {
+	return fmt.Sprintf("invalid role %q: %s", e.RoleName, e.Reason)
+}
+
+// ValidateRole returns an *InvalidRoleError if the role is unknown, or is not granted on the entity it applies to:
+// organization roles need an OrgID, project roles a GroupID, and global roles neither
+func ValidateRole(role *UserRole) error {
+	roleName := RoleName(role.RoleName)
+	switch {
+	case containsRole(OrgRoles, roleName):
+		if role.OrgID == "" || role.GroupID != "" {
+			return &InvalidRoleError{RoleName: roleName, Reason: "organization roles must be granted with an orgId only"}
+		}
+	case containsRole(ProjectRoles, roleName):
+		if role.GroupID == "" || role.OrgID != "" {
+			return &InvalidRoleError{RoleName: roleName, Reason: "project roles must be granted with a groupId only"}
+		}
+	case containsRole(GlobalRoles, roleName):
+		if role.OrgID != "" || role.GroupID != "" {
+			return &InvalidRoleError{RoleName: roleName, Reason: "global roles cannot be granted with an orgId or groupId"}
+		}
+	default:
+		return &InvalidRoleError{RoleName: roleName, Reason: "unknown role"}
+	}
+	return nil
+}
+
+// ValidateRoles validates each role, see ValidateRole
+func ValidateRoles(roles []*UserRole) error {
+	for _, role := range roles {
+		if role == nil {
+			return atlas.NewArgError("roles", "cannot contain nil")
+		}
+		if err := ValidateRole(role); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// UsersService is an interface for interfacing with the Users
+// endpoints of the MongoDB Ops Manager API.
+// See more: https://docs.opsmanager.mongodb.com/current/reference/api/users/
+type UsersService interface {
+	Get(context.Context, string) (*User, *atlas.Response, error)
+	GetByName(context.Context, string) (*User, *atlas.Response, error)
+	Create(context.Context, *User) (*User, *atlas.Response, error)
+	Update(context.Context, string, *User) (*User, *atlas.Response, error)
+	Delete(context.Context, string) (*atlas.Response, error)
+	ListAccessList(context.Context, string, *atlas.ListOptions) (*AccessListEntries, *atlas.Response, error)
+	GetAccessListEntry(context.Context, string, string) (*AccessListEntry, *atlas.Response, error)
+	AddAccessList(context.Context, string, []*AccessListEntry) (*AccessListEntries, *atlas.Response, error)
+	RemoveAccessListEntry(context.Context, string, string) (*atlas.Response, error)
+	EnsureAccessListIP(context.Context, string, string, string) (*AccessListEntry, error)
+}
+
+// UsersServiceOp handles communication with the Users related methods of the
+// MongoDB Ops Manager API
+type UsersServiceOp struct {
+	client *Client
+}
+
+var _ UsersService = &UsersServiceOp{}
+
+// Get gets a single user by its ID.
+// See more: https://docs.opsmanager.mongodb.com/current/reference/api/user-get-by-id/
+func (s *UsersServiceOp) Get(ctx context.Context, userID string) (*User, *atlas.Response, error) {
+	if userID == "" {
+		return nil, nil, atlas.NewArgError("userID", "must be set")
+	}
+
+	path := fmt.Sprintf("%s/%s", usersBasePath, userID)
+
+	return s.get(ctx, path)
+}
+
+// GetByName gets a single user by its username.
+// See more: https://docs.opsmanager.mongodb.com/current/reference/api/user-get-by-name/
+func (s *UsersServiceOp) GetByName(ctx context.Context, username string) (*User, *atlas.Response, error) {
+	if username == "" {
+		return nil, nil, atlas.NewArgError("username", "must be set")
+	}
+
+	path := fmt.Sprintf("%s/byName/%s", usersBasePath, url.PathEscape(username))
+
+	return s.get(ctx, path)
+}
+
+func (s *UsersServiceOp) get(ctx context.Context, path string) (*User, *atlas.Response, error) {
+	req, err := s.client.NewRequest(ctx, http.MethodGet, path, nil)
+	if err != nil {
+		return nil, nil, err
+	}
+
+	root := new(User)
+	resp, err := s.client.Do(ctx, req, root)
+	if err != nil {
+		return nil, resp, err
+	}
+
+	return root, resp, err
+}
+
+// Create creates a user; the username and password are required, and the roles are validated first.
+// See more: https://docs.opsmanager.mongodb.com/current/reference/api/user-create/
+func (s *UsersServiceOp) Create(ctx context.Context, createRequest *User) (*User, *atlas.Response, error) {
+	if createRequest == nil {
+		return nil, nil, atlas.NewArgError("createRequest", "cannot be nil")
+	}
+	if createRequest.Username == "" {
+		return nil, nil, atlas.NewArgError("username", "must be set")
+	}
+	if createRequest.Password == "" {
+		return nil, nil, atlas.NewArgError("password", "must be set")
+	}
+	if err := ValidateRoles(createRequest.Roles); err != nil {
+		return nil, nil, err
+	}
+
+	req, err := s.client.NewRequest(ctx, http.MethodPost, usersBasePath, createRequest)
+	if err != nil {
+		return nil, nil, err
+	}
+
+	root := new(User)
+	resp, err := s.client.Do(ctx, req, root)
+	if err != nil {
+		return nil, resp, err
+	}
+
+	return root, resp, err
+}
+
+// Update updates the profile of a user; only the specified fields are modified.
+// When roles are specified, they replace all the roles of the user, and are validated first.
+// See more: https://docs.opsmanager.mongodb.com/current/reference/api/user-update/
+func (s *UsersServiceOp) Update(ctx context.Context, userID string, updateRequest *User) (*User, *atlas.Response, error) {
+	if userID == "" {
+		return nil, nil, atlas.NewArgError("userID", "must be set")
+	}
+	if updateRequest == nil {
+		return nil, nil, atlas.NewArgError("updateRequest", "cannot be nil")
+	}
+	if err := ValidateRoles(updateRequest.Roles); err != nil {
+		return nil, nil, err
+	}
+
+	path := fmt.Sprintf("%s/%s", usersBasePath, userID)
+
+	req, err := s.client.NewRequest(ctx, http.MethodPatch, path, updateRequest)
+	if err != nil {
+		return nil, nil, err
+	}
+
+	root := new(User)
+	resp, err := s.client.Do(ctx, req, root)
+	if err != nil {
+		return nil, resp, err
+	}
+
+	return root, resp, err
+}
+
+// Delete deletes a user.
+// See more: https://docs.opsmanager.mongodb.com/current/reference/api/user-delete/
+func (s *UsersServiceOp) Delete(ctx context.Context, userID string) (*atlas.Response, error) {
+	if userID == "" {
+		return nil, atlas.NewArgError("userID", "must be set")
+	}
+
+	path := fmt.Sprintf("%s/%s", usersBasePath, userID)
+
+	req, err := s.client.NewRequest(ctx, http.MethodDelete, path, nil)
+	if err != nil {
+		return nil, err
+	}
+
+	resp, err := s.client.Do(ctx, req, nil)
+
+	return resp, err
+}
+
+// ListAccessList gets the IP addresses and CIDR blocks from which a user can access the API.
+// See more: https://docs.opsmanager.mongodb.com/current/reference/api/access-list/get-all-access-list-entries-for-one-user/
+func (s *UsersServiceOp) ListAccessList(ctx context.Context, userID string, opts *atlas.ListOptions) (*AccessListEntries, *atlas.Response, error) {
+	if userID == "" {
+		return nil, nil, atlas.NewArgError("userID", "must be set")
+	}
+
+	path, err := setListOptions(fmt.Sprintf("%s/%s/accessList", usersBasePath, userID), opts)
+	if err != nil {
+		return nil, nil, err
+	}
+
+	req, err := s.client.NewRequest(ctx, http.MethodGet, path, nil)
+	if err != nil {
+		return nil, nil, err
+	}
+
+	root := new(AccessListEntries)
+	resp, err := s.client.Do(ctx, req, root)
+	if err != nil {
+		return nil, resp, err
+	}
+
+	if l := root.Links; l != nil {
+		resp.Links = l
+	}
+
+	return root, resp, nil
+}
+
+// GetAccessListEntry gets a single IP address or CIDR block from the access list of a user.
+// See more: https://docs.opsmanager.mongodb.com/current/reference/api/access-list/get-one-entry-for-one-user/
+func (s *UsersServiceOp) GetAccessListEntry(ctx context.Context, userID, ipAddressOrCIDR string) (*AccessListEntry, *atlas.Response, error) {
+	if userID == "" {
+		return nil, nil, atlas.NewArgError("userID", "must be set")
+	}
+	if ipAddressOrCIDR == "" {
+		return nil, nil, atlas.NewArgError("ipAddressOrCIDR", "must be set")
+	}
+
+	path := fmt.Sprintf("%s/%s/accessList/%s", usersBasePath, userID, url.PathEscape(ipAddressOrCIDR))
+
+	req, err := s.client.NewRequest(ctx, http.MethodGet, path, nil)
+	if err != nil {
+		return nil, nil, err
+	}
+
+	root := new(AccessListEntry)
+	resp, err := s.client.Do(ctx, req, root)
+	if err != nil {
+		return nil, resp, err
+	}
+
+	return root, resp, err
+}
+
+// AddAccessList allows a user to access the API from IP addresses or CIDR blocks, and returns the whole access list.
+// See more: https://docs.opsmanager.mongodb.com/current/reference/api/access-list/add-entries-to-access-list/
+func (s *UsersServiceOp) AddAccessList(ctx context.Context, userID string, entries []*AccessListEntry) (*AccessListEntries, *atlas.Response, error) {
+	if userID == "" {
+		return nil, nil, atlas.NewArgError("userID", "must be set")
+	}
+	if err := validateAccessListEntries(entries); err != nil {
+		return nil, nil, err
+	}
+
+	path := fmt.Sprintf("%s/%s/accessList", usersBasePath, userID)
+
+	req, err := s.client.NewRequest(ctx, http.MethodPost, path, entries)
+	if err != nil {
+		return nil, nil, err
+	}
+
+	root := new(AccessListEntries)
+	resp, err := s.client.Do(ctx, req, root)
+	if err != nil {
+		return nil, resp, err
+	}
+
+	return root, resp, err
+}
+
+// RemoveAccessListEntry removes an IP address or CIDR block from the access list of a user.
+// See more: https://docs.opsmanager.mongodb.com/current/reference/api/access-list/delete-one-access-list-entry/
+func (s *UsersServiceOp) RemoveAccessListEntry(ctx context.Context, userID, ipAddressOrCIDR string) (*atlas.Response, error) {
+	if userID == "" {
+		return nil, atlas.NewArgError("userID", "must be set")
+	}
+	if ipAddressOrCIDR == "" {
+		return nil, atlas.NewArgError("ipAddressOrCIDR", "must be set")
+	}
+
+	path := fmt.Sprintf("%s/%s/accessList/%s", usersBasePath, userID, url.PathEscape(ipAddressOrCIDR))
+
+	req, err := s.client.NewRequest(ctx, http.MethodDelete, path, nil)
+	if err != nil {
+		return nil, err
+	}
+
+	resp, err := s.client.Do(ctx, req, nil)
+
+	return resp, err
+}
+
+// EnsureAccessListIP makes sure a user can access the API from ipAddress, typically the egress IP of a CI job
+// about to switch to API key authentication. If an entry of the access list already covers the address,
+// either as an IP address or within a CIDR block, it is returned unchanged; otherwise the address is added
+// with the given comment and the new entry is returned.
+func (s *UsersServiceOp) EnsureAccessListIP(ctx context.Context, userID, ipAddress, comment string) (*AccessListEntry, error) {
+	ip := net.ParseIP(ipAddress)
+	if ip == nil {
+		return nil, atlas.NewArgError("ipAddress", fmt.Sprintf("%q is not a valid IP address", ipAddress))
+	}
+
+	var existing *AccessListEntry
+	opts := &atlas.ListOptions{}
+	err := listPages(opts, func() (int, int, error) {
+		page, _, err := s.ListAccessList(ctx, userID, opts)
+		if err != nil {
+			return 0, 0, err
+		}
+		for _, entry := range page.Results {
+			if accessListEntryCovers(entry, ip) {
+				existing = entry
+				return 0, 0, errStopListing
+			}
+		}
+		return len(page.Results), page.TotalCount, nil
+	})
+	if err != nil || existing != nil {
+		return existing, err
+	}
+
+	entries, _, err := s.AddAccessList(ctx, userID, []*AccessListEntry{{IPAddress: ipAddress, Comment: comment}})
+	if err != nil {
+		return nil, err
+	}
+	for _, entry := range entries.Results {
+		if accessListEntryCovers(entry, ip) {
+			return entry, nil
+		}
+	}
+
+	return &AccessListEntry{IPAddress: ipAddress, Comment: comment}, nil
+}
+
+// accessListEntryCovers reports whether ip is the IP address of entry, or falls within its CIDR block
+func accessListEntryCovers(entry *AccessListEntry, ip net.IP) bool {
+	if entry.IPAddress != "" && ip.Equal(net.ParseIP(entry.IPAddress)) {
+		return true
+	}
+	if entry.CIDRBlock != "" {
+		if _, ipNet, err := net.ParseCIDR(entry.CIDRBlock); err == nil && ipNet.Contains(ip) {
+			return true
+		}
+	}
+	return false
+}
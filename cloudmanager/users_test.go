@@ -0,0 +1,521 @@
+// Copyright 2020 MongoDB Inc
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package cloudmanager
+
+import (
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"testing"
+
+	"github.com/go-test/deep"
+	"github.com/mongodb/go-client-mongodb-atlas/mongodbatlas"
+)
+
+func TestUsers_Get(t *testing.T) {
+	setup()
+	defer teardown()
+
+	userID := "533dc19ce4b00835ff81e2eb"
+
+	mux.HandleFunc(fmt.Sprintf("/users/%s", userID), func(w http.ResponseWriter, r *http.Request) {
+		testMethod(t, r, http.MethodGet)
+		_, _ = fmt.Fprint(w, `{
+			"emailAddress": "jane.doe@example.com",
+			"firstName": "Jane",
+			"id": "533dc19ce4b00835ff81e2eb",
+			"lastName": "Doe",
+			"links": [],
+			"roles": [
+				{"orgId": "5a0a1e7e0f2912c554080adb", "roleName": "ORG_MEMBER"},
+				{"groupId": "5a0a1e7e0f2912c554080adc", "roleName": "GROUP_READ_ONLY"}
+			],
+			"username": "jane.doe@example.com"
+		}`)
+	})
+
+	user, _, err := client.Users.Get(ctx, userID)
+	if err != nil {
+		t.Fatalf("Users.Get returned error: %v", err)
+	}
+
+	expected := &User{
+		EmailAddress: "jane.doe@example.com",
+		FirstName:    "Jane",
+		ID:           "533dc19ce4b00835ff81e2eb",
+		LastName:     "Doe",
+		Links:        []*mongodbatlas.Link{},
+		Roles: []*UserRole{
+			{OrgID: "5a0a1e7e0f2912c554080adb", RoleName: string(RoleOrgMember)},
+			{GroupID: "5a0a1e7e0f2912c554080adc", RoleName: string(RoleGroupReadOnly)},
+		},
+		Username: "jane.doe@example.com",
+	}
+
+	if diff := deep.Equal(user, expected); diff != nil {
+		t.Error(diff)
+	}
+}
+
+func TestUsers_GetByName(t *testing.T) {
+	setup()
+	defer teardown()
+
+	mux.HandleFunc("/users/byName/jane.doe@example.com", func(w http.ResponseWriter, r *http.Request) {
+		testMethod(t, r, http.MethodGet)
+		_, _ = fmt.Fprint(w, `{
+			"emailAddress": "jane.doe@example.com",
+			"firstName": "Jane",
+			"id": "533dc19ce4b00835ff81e2eb",
+			"lastName": "Doe",
+			"links": [],
+			"roles": [
+				{"orgId": "5a0a1e7e0f2912c554080adb", "roleName": "ORG_MEMBER"},
+				{"groupId": "5a0a1e7e0f2912c554080adc", "roleName": "GROUP_READ_ONLY"}
+			],
+			"username": "jane.doe@example.com"
+		}`)
+	})
+
+	user, _, err := client.Users.GetByName(ctx, "jane.doe@example.com")
+	if err != nil {
+		t.Fatalf("Users.GetByName returned error: %v", err)
+	}
+
+	expected := &User{
+		EmailAddress: "jane.doe@example.com",
+		FirstName:    "Jane",
+		ID:           "533dc19ce4b00835ff81e2eb",
+		LastName:     "Doe",
+		Links:        []*mongodbatlas.Link{},
+		Roles: []*UserRole{
+			{OrgID: "5a0a1e7e0f2912c554080adb", RoleName: string(RoleOrgMember)},
+			{GroupID: "5a0a1e7e0f2912c554080adc", RoleName: string(RoleGroupReadOnly)},
+		},
+		Username: "jane.doe@example.com",
+	}
+
+	if diff := deep.Equal(user, expected); diff != nil {
+		t.Error(diff)
+	}
+}
+
+func TestUsers_Create(t *testing.T) {
+	setup()
+	defer teardown()
+
+	mux.HandleFunc("/users", func(w http.ResponseWriter, r *http.Request) {
+		testMethod(t, r, http.MethodPost)
+
+		expected := map[string]interface{}{
+			"emailAddress": "jane.doe@example.com",
+			"firstName":    "Jane",
+			"lastName":     "Doe",
+			"password":     "changeme",
+			"roles": []interface{}{
+				map[string]interface{}{"orgId": "5a0a1e7e0f2912c554080adb", "roleName": "ORG_MEMBER"},
+				map[string]interface{}{"groupId": "5a0a1e7e0f2912c554080adc", "roleName": "GROUP_READ_ONLY"},
+			},
+			"username": "jane.doe@example.com",
+		}
+		var v map[string]interface{}
+		if err := json.NewDecoder(r.Body).Decode(&v); err != nil {
+			t.Fatalf("decode json: %v", err)
+		}
+		if diff := deep.Equal(v, expected); diff != nil {
+			t.Error(diff)
+		}
+
+		_, _ = fmt.Fprint(w, `{
+			"emailAddress": "jane.doe@example.com",
+			"firstName": "Jane",
+			"id": "533dc19ce4b00835ff81e2eb",
+			"lastName": "Doe",
+			"links": [],
+			"roles": [
+				{"orgId": "5a0a1e7e0f2912c554080adb", "roleName": "ORG_MEMBER"},
+				{"groupId": "5a0a1e7e0f2912c554080adc", "roleName": "GROUP_READ_ONLY"}
+			],
+			"username": "jane.doe@example.com"
+		}`)
+	})
+
+	createRequest := &User{
+		EmailAddress: "jane.doe@example.com",
+		FirstName:    "Jane",
+		ID:           "533dc19ce4b00835ff81e2eb",
+		LastName:     "Doe",
+		Links:        []*mongodbatlas.Link{},
+		Roles: []*UserRole{
+			{OrgID: "5a0a1e7e0f2912c554080adb", RoleName: string(RoleOrgMember)},
+			{GroupID: "5a0a1e7e0f2912c554080adc", RoleName: string(RoleGroupReadOnly)},
+		},
+		Username: "jane.doe@example.com",
+	}
+	createRequest.ID = ""
+	createRequest.Links = nil
+	createRequest.Password = "changeme"
+
+	user, _, err := client.Users.Create(ctx, createRequest)
+	if err != nil {
+		t.Fatalf("Users.Create returned error: %v", err)
+	}
+
+	expected := &User{
+		EmailAddress: "jane.doe@example.com",
+		FirstName:    "Jane",
+		ID:           "533dc19ce4b00835ff81e2eb",
+		LastName:     "Doe",
+		Links:        []*mongodbatlas.Link{},
+		Roles: []*UserRole{
+			{OrgID: "5a0a1e7e0f2912c554080adb", RoleName: string(RoleOrgMember)},
+			{GroupID: "5a0a1e7e0f2912c554080adc", RoleName: string(RoleGroupReadOnly)},
+		},
+		Username: "jane.doe@example.com",
+	}
+
+	if diff := deep.Equal(user, expected); diff != nil {
+		t.Error(diff)
+	}
+}
+
+func TestUsers_Update(t *testing.T) {
+	setup()
+	defer teardown()
+
+	userID := "533dc19ce4b00835ff81e2eb"
+
+	mux.HandleFunc(fmt.Sprintf("/users/%s", userID), func(w http.ResponseWriter, r *http.Request) {
+		testMethod(t, r, http.MethodPatch)
+
+		expected := map[string]interface{}{
+			"lastName": "Doe",
+			"roles": []interface{}{
+				map[string]interface{}{"roleName": "GLOBAL_READ_ONLY"},
+			},
+		}
+		var v map[string]interface{}
+		if err := json.NewDecoder(r.Body).Decode(&v); err != nil {
+			t.Fatalf("decode json: %v", err)
+		}
+		if diff := deep.Equal(v, expected); diff != nil {
+			t.Error(diff)
+		}
+
+		_, _ = fmt.Fprint(w, `{
+			"emailAddress": "jane.doe@example.com",
+			"firstName": "Jane",
+			"id": "533dc19ce4b00835ff81e2eb",
+			"lastName": "Doe",
+			"links": [],
+			"roles": [
+				{"orgId": "5a0a1e7e0f2912c554080adb", "roleName": "ORG_MEMBER"},
+				{"groupId": "5a0a1e7e0f2912c554080adc", "roleName": "GROUP_READ_ONLY"}
+			],
+			"username": "jane.doe@example.com"
+		}`)
+	})
+
+	updateRequest := &User{
+		LastName: "Doe",
+		Roles:    []*UserRole{{RoleName: string(RoleGlobalReadOnly)}},
+	}
+	user, _, err := client.Users.Update(ctx, userID, updateRequest)
+	if err != nil {
+		t.Fatalf("Users.Update returned error: %v", err)
+	}
+
+	expected := &User{
+		EmailAddress: "jane.doe@example.com",
+		FirstName:    "Jane",
+		ID:           "533dc19ce4b00835ff81e2eb",
+		LastName:     "Doe",
+		Links:        []*mongodbatlas.Link{},
+		Roles: []*UserRole{
+			{OrgID: "5a0a1e7e0f2912c554080adb", RoleName: string(RoleOrgMember)},
+			{GroupID: "5a0a1e7e0f2912c554080adc", RoleName: string(RoleGroupReadOnly)},
+		},
+		Username: "jane.doe@example.com",
+	}
+
+	if diff := deep.Equal(user, expected); diff != nil {
+		t.Error(diff)
+	}
+}
+
+func TestUsers_Update_invalidRole(t *testing.T) {
+	setup()
+	defer teardown()
+
+	updateRequest := &User{
+		Roles: []*UserRole{{RoleName: string(RoleGroupOwner), OrgID: "5a0a1e7e0f2912c554080adb"}},
+	}
+	_, _, err := client.Users.Update(ctx, "533dc19ce4b00835ff81e2eb", updateRequest)
+
+	if _, ok := err.(*InvalidRoleError); !ok {
+		t.Fatalf("expected an *InvalidRoleError, got %v", err)
+	}
+}
+
+func TestUsers_Delete(t *testing.T) {
+	setup()
+	defer teardown()
+
+	userID := "533dc19ce4b00835ff81e2eb"
+
+	mux.HandleFunc(fmt.Sprintf("/users/%s", userID), func(w http.ResponseWriter, r *http.Request) {
+		testMethod(t, r, http.MethodDelete)
+	})
+
+	_, err := client.Users.Delete(ctx, userID)
+	if err != nil {
+		t.Fatalf("Users.Delete returned error: %v", err)
+	}
+}
+
+func TestValidateRole(t *testing.T) {
+	orgID := "5a0a1e7e0f2912c554080adb"
+	projectID := "5a0a1e7e0f2912c554080adc"
+
+	tests := []struct {
+		role  *UserRole
+		valid bool
+	}{
+		{&UserRole{RoleName: string(RoleOrgOwner), OrgID: orgID}, true},
+		{&UserRole{RoleName: string(RoleOrgOwner), GroupID: projectID}, false},
+		{&UserRole{RoleName: string(RoleGroupOwner), GroupID: projectID}, true},
+		{&UserRole{RoleName: string(RoleGroupOwner)}, false},
+		{&UserRole{RoleName: string(RoleGlobalOwner)}, true},
+		{&UserRole{RoleName: string(RoleGlobalOwner), OrgID: orgID}, false},
+		{&UserRole{RoleName: "GROUP_SUPERUSER", GroupID: projectID}, false},
+	}
+
+	for _, tt := range tests {
+		err := ValidateRole(tt.role)
+		if (err == nil) != tt.valid {
+			t.Errorf("ValidateRole(%+v) = %v, expected valid: %v", tt.role, err, tt.valid)
+		}
+	}
+}
+
+func TestUsers_ListAccessList(t *testing.T) {
+	setup()
+	defer teardown()
+
+	userID := "533dc19ce4b00835ff81e2eb"
+
+	mux.HandleFunc(fmt.Sprintf("/users/%s/accessList", userID), func(w http.ResponseWriter, r *http.Request) {
+		testMethod(t, r, http.MethodGet)
+		_, _ = fmt.Fprint(w, `{
+			"links": [],
+			"results": [
+				{"cidrBlock": "198.51.100.0/24", "comment": "office", "count": 3, "created": "2020-09-07T15:23:25Z"},
+				{"ipAddress": "203.0.113.7", "cidrBlock": "203.0.113.7/32", "comment": "ci", "count": 0, "created": "2020-09-08T10:01:12Z"}
+			],
+			"totalCount": 2
+		}`)
+	})
+
+	entries, _, err := client.Users.ListAccessList(ctx, userID, nil)
+	if err != nil {
+		t.Fatalf("Users.ListAccessList returned error: %v", err)
+	}
+
+	expected := &AccessListEntries{
+		Links: []*mongodbatlas.Link{},
+		Results: []*AccessListEntry{
+			{CIDRBlock: "198.51.100.0/24", Comment: "office", Count: 3, Created: "2020-09-07T15:23:25Z"},
+			{IPAddress: "203.0.113.7", CIDRBlock: "203.0.113.7/32", Comment: "ci", Created: "2020-09-08T10:01:12Z"},
+		},
+		TotalCount: 2,
+	}
+
+	if diff := deep.Equal(entries, expected); diff != nil {
+		t.Error(diff)
+	}
+}
+
+func TestUsers_GetAccessListEntry(t *testing.T) {
+	setup()
+	defer teardown()
+
+	userID := "533dc19ce4b00835ff81e2eb"
+
+	mux.HandleFunc(fmt.Sprintf("/users/%s/accessList/", userID), func(w http.ResponseWriter, r *http.Request) {
+		testMethod(t, r, http.MethodGet)
+
+		expected := fmt.Sprintf("/users/%s/accessList/198.51.100.0%%2F24", userID)
+		if r.URL.EscapedPath() != expected {
+			t.Errorf("expected path %s, got %s", expected, r.URL.EscapedPath())
+		}
+
+		_, _ = fmt.Fprint(w, `{"cidrBlock": "198.51.100.0/24", "comment": "office", "count": 3}`)
+	})
+
+	entry, _, err := client.Users.GetAccessListEntry(ctx, userID, "198.51.100.0/24")
+	if err != nil {
+		t.Fatalf("Users.GetAccessListEntry returned error: %v", err)
+	}
+
+	expected := &AccessListEntry{CIDRBlock: "198.51.100.0/24", Comment: "office", Count: 3}
+	if diff := deep.Equal(entry, expected); diff != nil {
+		t.Error(diff)
+	}
+}
+
+func TestUsers_AddAccessList(t *testing.T) {
+	setup()
+	defer teardown()
+
+	userID := "533dc19ce4b00835ff81e2eb"
+
+	mux.HandleFunc(fmt.Sprintf("/users/%s/accessList", userID), func(w http.ResponseWriter, r *http.Request) {
+		testMethod(t, r, http.MethodPost)
+
+		expected := []interface{}{
+			map[string]interface{}{"cidrBlock": "198.51.100.0/24", "comment": "office"},
+			map[string]interface{}{"ipAddress": "203.0.113.7", "comment": "ci"},
+		}
+		if diff := deep.Equal(decodeBody(t, r), expected); diff != nil {
+			t.Error(diff)
+		}
+
+		_, _ = fmt.Fprint(w, `{
+			"links": [],
+			"results": [
+				{"cidrBlock": "198.51.100.0/24", "comment": "office", "count": 3, "created": "2020-09-07T15:23:25Z"},
+				{"ipAddress": "203.0.113.7", "cidrBlock": "203.0.113.7/32", "comment": "ci", "count": 0, "created": "2020-09-08T10:01:12Z"}
+			],
+			"totalCount": 2
+		}`)
+	})
+
+	entries, _, err := client.Users.AddAccessList(ctx, userID, []*AccessListEntry{
+		{CIDRBlock: "198.51.100.0/24", Comment: "office"},
+		{IPAddress: "203.0.113.7", Comment: "ci"},
+	})
+	if err != nil {
+		t.Fatalf("Users.AddAccessList returned error: %v", err)
+	}
+
+	if entries.TotalCount != 2 {
+		t.Errorf("expected 2 entries, got %d", entries.TotalCount)
+	}
+}
+
+func TestUsers_AddAccessList_invalid(t *testing.T) {
+	setup()
+	defer teardown()
+
+	_, _, err := client.Users.AddAccessList(ctx, "533dc19ce4b00835ff81e2eb", []*AccessListEntry{{IPAddress: "203.0.113.300"}})
+	if err == nil {
+		t.Fatal("expected an error")
+	}
+}
+
+func TestUsers_RemoveAccessListEntry(t *testing.T) {
+	setup()
+	defer teardown()
+
+	userID := "533dc19ce4b00835ff81e2eb"
+
+	mux.HandleFunc(fmt.Sprintf("/users/%s/accessList/203.0.113.7", userID), func(w http.ResponseWriter, r *http.Request) {
+		testMethod(t, r, http.MethodDelete)
+	})
+
+	_, err := client.Users.RemoveAccessListEntry(ctx, userID, "203.0.113.7")
+	if err != nil {
+		t.Fatalf("Users.RemoveAccessListEntry returned error: %v", err)
+	}
+}
+
+func TestUsers_EnsureAccessListIP(t *testing.T) {
+	userID := "533dc19ce4b00835ff81e2eb"
+
+	tests := map[string]struct {
+		ipAddress string
+		added     bool
+		expected  *AccessListEntry
+	}{
+		"listed ip": {
+			ipAddress: "203.0.113.7",
+			expected:  &AccessListEntry{IPAddress: "203.0.113.7", CIDRBlock: "203.0.113.7/32", Comment: "ci", Created: "2020-09-08T10:01:12Z"},
+		},
+		"within cidr": {
+			ipAddress: "198.51.100.42",
+			expected:  &AccessListEntry{CIDRBlock: "198.51.100.0/24", Comment: "office", Count: 3, Created: "2020-09-07T15:23:25Z"},
+		},
+		"missing": {
+			ipAddress: "192.0.2.10",
+			added:     true,
+			expected:  &AccessListEntry{IPAddress: "192.0.2.10", CIDRBlock: "192.0.2.10/32", Comment: "ci job"},
+		},
+	}
+
+	for name, tc := range tests {
+		tc := tc
+		t.Run(name, func(t *testing.T) {
+			setup()
+			defer teardown()
+
+			var added bool
+			mux.HandleFunc(fmt.Sprintf("/users/%s/accessList", userID), func(w http.ResponseWriter, r *http.Request) {
+				if r.Method == http.MethodGet {
+					_, _ = fmt.Fprint(w, `{
+						"links": [],
+						"results": [
+							{"cidrBlock": "198.51.100.0/24", "comment": "office", "count": 3, "created": "2020-09-07T15:23:25Z"},
+							{"ipAddress": "203.0.113.7", "cidrBlock": "203.0.113.7/32", "comment": "ci", "count": 0, "created": "2020-09-08T10:01:12Z"}
+						],
+						"totalCount": 2
+					}`)
+					return
+				}
+
+				testMethod(t, r, http.MethodPost)
+				added = true
+
+				expected := []interface{}{map[string]interface{}{"ipAddress": tc.ipAddress, "comment": "ci job"}}
+				if diff := deep.Equal(decodeBody(t, r), expected); diff != nil {
+					t.Error(diff)
+				}
+
+				_, _ = fmt.Fprintf(w, `{"links": [], "results": [{"ipAddress": %q, "cidrBlock": "%s/32", "comment": "ci job"}], "totalCount": 3}`, tc.ipAddress, tc.ipAddress)
+			})
+
+			entry, err := client.Users.EnsureAccessListIP(ctx, userID, tc.ipAddress, "ci job")
+			if err != nil {
+				t.Fatalf("Users.EnsureAccessListIP returned error: %v", err)
+			}
+
+			if added != tc.added {
+				t.Errorf("expected added to be %v, got %v", tc.added, added)
+			}
+			if diff := deep.Equal(entry, tc.expected); diff != nil {
+				t.Error(diff)
+			}
+		})
+	}
+}
+
+func TestUsers_EnsureAccessListIP_invalid(t *testing.T) {
+	setup()
+	defer teardown()
+
+	_, err := client.Users.EnsureAccessListIP(ctx, "533dc19ce4b00835ff81e2eb", "198.51.100.0/24", "ci job")
+	if err == nil {
+		t.Fatal("expected an error")
+	}
+}
@@ -7,4 +7,5 @@ require (
 	github.com/go-test/deep v1.0.1
 	github.com/google/go-querystring v1.0.0
 	github.com/mongodb/go-client-mongodb-atlas v0.1.3
+	gopkg.in/yaml.v2 v2.4.0
 )
@@ -63,3 +63,24 @@ func (client opsManagerClient) GetAutomationConfig(projectID string) (Automation
 
 	return result, nil
 }
+
+// GetAutomationConfigDocument returns the automation config as a generic document, including any fields which
+// AutomationConfig does not model; numbers are decoded as json.Number, so that they can be sent back unchanged
+// https://docs.opsmanager.mongodb.com/master/reference/api/automation-config/#get-the-automation-configuration
+func (client opsManagerClient) GetAutomationConfigDocument(projectID string) (map[string]interface{}, error) {
+	var result map[string]interface{}
+
+	url := client.resolver.Of("/groups/%s/automationConfig", projectID)
+	resp := client.GetJSON(url)
+	if resp.IsError() {
+		return result, resp.Err
+	}
+	defer httpclient.CloseResponseBodyIfNotNil(resp)
+
+	decoder := json.NewDecoder(resp.Response.Body)
+	decoder.UseNumber()
+	err := decoder.Decode(&result)
+	useful.PanicOnUnrecoverableError(err)
+
+	return result, nil
+}
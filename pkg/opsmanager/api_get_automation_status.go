@@ -21,12 +21,14 @@ import (
 	"github.com/mongodb-labs/pcgc/pkg/useful"
 )
 
-// Process process status
-
 // AutomationStatusResponse automation status
+//
+// Processes used to be decoded as []Process; they are now []ProcessStatus, which also carries the detailed plan
+// and the errors reported by the agents. Callers which only need the names of the remaining moves can use
+// ProcessStatus.Plan.Moves(), which returns the []string previously found in Process.Plan.
 type AutomationStatusResponse struct {
-	Processes   []Process `json:"processes"`
-	GoalVersion int       `json:"goalVersion"`
+	Processes   []ProcessStatus `json:"processes"`
+	GoalVersion int             `json:"goalVersion"`
 }
 
 // GetAutomationStatus
@@ -42,7 +42,7 @@ type RawAutomationConfig struct {
 	MonitoringAgentTemplate          map[string]interface{} `json:"monitoringAgentTemplate,omitempty"`
 	BackupAgentTemplate              map[string]interface{} `json:"backupAgentTemplate,omitempty"`
 	CPSModuleTemplate                map[string]interface{} `json:"cpsModuleTemplate,omitempty"`
-	DeploymentJobStatuses            []interface{}
+	DeploymentJobStatuses            []DeploymentJobStatus  `json:"deploymentJobStatuses,omitempty"`
 }
 
 // GetRawAutomationConfig returns the RAW automation config, just like the Automation Agent sees it
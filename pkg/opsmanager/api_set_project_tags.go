@@ -18,6 +18,7 @@ import (
 	"bytes"
 	"encoding/json"
 
+	"github.com/mongodb-labs/pcgc/pkg/httpclient"
 	"github.com/mongodb-labs/pcgc/pkg/useful"
 )
 
@@ -36,6 +37,7 @@ func (client opsManagerClient) SetProjectTags(projectID string, tags []string) (
 	if resp.IsError() {
 		return result, resp.Err
 	}
+	defer httpclient.CloseResponseBodyIfNotNil(resp)
 
 	decoder := json.NewDecoder(resp.Response.Body)
 	err = decoder.Decode(&result)
@@ -45,3 +45,28 @@ func (client opsManagerClient) UpdateAutomationConfig(projectID string, config A
 
 	return result, nil
 }
+
+// UpdateAutomationConfigDocument replaces the automation config with the given generic document
+// https://docs.opsmanager.mongodb.com/master/reference/api/automation-config/#update-the-automation-configuration
+func (client opsManagerClient) UpdateAutomationConfigDocument(projectID string, doc map[string]interface{}) (map[string]interface{}, error) {
+	var result map[string]interface{}
+
+	bodyBytes, err := json.Marshal(doc)
+	if err != nil {
+		return result, err
+	}
+
+	url := client.resolver.Of("/groups/%s/automationConfig", projectID)
+	resp := client.PutJSON(url, bytes.NewReader(bodyBytes))
+	if resp.IsError() {
+		return result, resp.Err
+	}
+	defer httpclient.CloseResponseBodyIfNotNil(resp)
+
+	decoder := json.NewDecoder(resp.Response.Body)
+	decoder.UseNumber()
+	err2 := decoder.Decode(&result)
+	useful.PanicOnUnrecoverableError(err2)
+
+	return result, nil
+}
@@ -0,0 +1,53 @@
+// Copyright 2020 MongoDB Inc
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package opsmanager
+
+import (
+	"bytes"
+	"encoding/json"
+
+	"github.com/mongodb-labs/pcgc/pkg/httpclient"
+	"github.com/mongodb-labs/pcgc/pkg/useful"
+)
+
+// UpdateLDAPGroupMappings replaces the LDAP group to role mappings of a project; the other project fields are not modified
+// https://docs.opsmanager.mongodb.com/master/reference/api/groups/update-one-group/
+func (client opsManagerClient) UpdateLDAPGroupMappings(projectID string, mappings []LDAPGroupMapping) (ProjectResponse, error) {
+	var result ProjectResponse
+
+	// an empty list removes all the mappings, so it must be sent rather than omitted
+	if mappings == nil {
+		mappings = []LDAPGroupMapping{}
+	}
+	request := map[string][]LDAPGroupMapping{"ldapGroupMappings": mappings}
+
+	body, err := json.Marshal(request)
+	if err != nil {
+		return result, err
+	}
+
+	url := client.resolver.Of("/groups/%s", projectID)
+	resp := client.PatchJSON(url, bytes.NewReader(body))
+	if resp.IsError() {
+		return result, resp.Err
+	}
+	defer httpclient.CloseResponseBodyIfNotNil(resp)
+
+	decoder := json.NewDecoder(resp.Response.Body)
+	err = decoder.Decode(&result)
+	useful.PanicOnUnrecoverableError(err)
+
+	return result, nil
+}
@@ -0,0 +1,97 @@
+// Copyright 2020 MongoDB Inc
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package opsmanager
+
+import (
+	"fmt"
+	"io/ioutil"
+	"net/http"
+	"net/http/httptest"
+	"reflect"
+	"testing"
+
+	"github.com/mongodb-labs/pcgc/pkg/httpclient"
+)
+
+func TestUpdateLDAPGroupMappings(t *testing.T) {
+	tests := []struct {
+		name     string
+		mappings []LDAPGroupMapping
+		body     string
+	}{
+		{
+			name:     "replace",
+			mappings: []LDAPGroupMapping{{RoleName: "GROUP_OWNER", LdapGroups: []string{"admins"}}},
+			body:     `{"ldapGroupMappings":[{"roleName":"GROUP_OWNER","ldapGroups":["admins"]}]}`,
+		},
+		{
+			name:     "remove all",
+			mappings: nil,
+			body:     `{"ldapGroupMappings":[]}`,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+				if r.Method != http.MethodPatch || r.URL.Path != "/api/public/v1.0/groups/5e66185d917b220fbd8bb4d1" {
+					t.Errorf("unexpected request %s %s", r.Method, r.URL.Path)
+				}
+				body, err := ioutil.ReadAll(r.Body)
+				if err != nil {
+					t.Fatal(err)
+				}
+				if string(body) != tt.body {
+					t.Errorf("expected body %s, got %s", tt.body, body)
+				}
+
+				fmt.Fprint(w, `{
+					"id": "5e66185d917b220fbd8bb4d1",
+					"name": "test",
+					"ldapGroupMappings": [{"roleName": "GROUP_OWNER", "ldapGroups": ["admins"]}]
+				}`)
+			}))
+			defer server.Close()
+			client := NewDefaultClient(httpclient.NewURLResolverWithPrefix(server.URL, PublicAPIPrefix))
+
+			project, err := client.UpdateLDAPGroupMappings("5e66185d917b220fbd8bb4d1", tt.mappings)
+			if err != nil {
+				t.Fatal(err)
+			}
+
+			expected := ProjectResponse{
+				ID:                "5e66185d917b220fbd8bb4d1",
+				Name:              "test",
+				LDAPGroupMappings: []LDAPGroupMapping{{RoleName: "GROUP_OWNER", LdapGroups: []string{"admins"}}},
+			}
+			if !reflect.DeepEqual(project, expected) {
+				t.Errorf("expected %+v, got %+v", expected, project)
+			}
+		})
+	}
+}
+
+func TestUpdateLDAPGroupMappings_error(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.WriteHeader(http.StatusNotFound)
+		fmt.Fprint(w, `{"detail": "No group with ID 5e66185d917b220fbd8bb4d1 exists.", "error": 404, "errorCode": "GROUP_NOT_FOUND"}`)
+	}))
+	defer server.Close()
+	client := NewDefaultClient(httpclient.NewURLResolverWithPrefix(server.URL, PublicAPIPrefix))
+
+	if _, err := client.UpdateLDAPGroupMappings("5e66185d917b220fbd8bb4d1", nil); err == nil {
+		t.Fatal("expected an error")
+	}
+}
@@ -0,0 +1,406 @@
+// Copyright 2020 MongoDB Inc
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package opsmanager
+
+import (
+	"bytes"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"sort"
+	"strings"
+)
+
+// The helpers below edit automation config documents, as returned by GetAutomationConfigDocument, in place.
+// Unlike AutomationConfig, a document keeps every field Ops Manager returned, so that writing it back does not
+// drop the fields which AutomationConfig does not model.
+
+// documentList returns the list stored at key in doc, or nil
+func documentList(doc map[string]interface{}, key string) []interface{} {
+	list, _ := doc[key].([]interface{})
+	return list
+}
+
+// findDocumentElement returns the element of the list stored at key in doc whose field has the given value
+func findDocumentElement(doc map[string]interface{}, key string, field string, value interface{}) (map[string]interface{}, error) {
+	for _, e := range documentList(doc, key) {
+		if element, ok := e.(map[string]interface{}); ok && fmt.Sprintf("%v", element[field]) == fmt.Sprintf("%v", value) {
+			return element, nil
+		}
+	}
+
+	return nil, fmt.Errorf("no element of %s has %s %v", key, field, value)
+}
+
+// removeDocumentElements removes the elements of the list stored at key in doc whose field has the given value
+func removeDocumentElements(doc map[string]interface{}, key string, field string, value interface{}) {
+	list := documentList(doc, key)
+	result := make([]interface{}, 0, len(list))
+	for _, e := range list {
+		if element, ok := e.(map[string]interface{}); ok && fmt.Sprintf("%v", element[field]) == fmt.Sprintf("%v", value) {
+			continue
+		}
+		result = append(result, e)
+	}
+	doc[key] = result
+}
+
+// subDocument returns the document stored at key in doc, adding an empty one if there is none
+func subDocument(doc map[string]interface{}, key string) map[string]interface{} {
+	if sub, ok := doc[key].(map[string]interface{}); ok {
+		return sub
+	}
+
+	sub := make(map[string]interface{})
+	doc[key] = sub
+	return sub
+}
+
+// DocumentEdit sets, or removes, the value found at Path in an automation config document
+// The elements of Path are document keys or, to select an element of a list, documents holding the identity fields
+// of the element (e.g. {"name": "rs_0"} for a process); setting a list element which does not exist appends it
+type DocumentEdit struct {
+	Path   []interface{} `json:"path"`
+	Value  interface{}   `json:"value,omitempty"`
+	Remove bool          `json:"remove,omitempty"`
+}
+
+// documentListIdentities the fields identifying the elements of the lists of an automation config document,
+// by the keys leading to the list; lists which are not listed here are always replaced as a whole
+var documentListIdentities = map[string][]string{
+	"processes":           {"name"},
+	"replicaSets":         {"_id"},
+	"replicaSets.members": {"_id"},
+	"sharding":            {"name"},
+	"auth.usersWanted":    {"user", "db"},
+	"monitoringVersions":  {"hostname"},
+	"backupVersions":      {"hostname"},
+}
+
+// toDocument converts v into a generic document, as returned by GetAutomationConfigDocument
+func toDocument(v interface{}) (map[string]interface{}, error) {
+	data, err := json.Marshal(v)
+	if err != nil {
+		return nil, err
+	}
+
+	doc, err := decodeDocument(data)
+	if err != nil {
+		return nil, err
+	}
+	result, ok := doc.(map[string]interface{})
+	if !ok {
+		return nil, errors.New("the automation config must be a document")
+	}
+	return result, nil
+}
+
+// documentVersion returns the version of an automation config document
+func documentVersion(doc map[string]interface{}) (int, bool) {
+	switch v := doc["version"].(type) {
+	case json.Number:
+		i, err := v.Int64()
+		return int(i), err == nil
+	case float64:
+		return int(v), true
+	}
+
+	return 0, false
+}
+
+// diffDocuments appends the edits which turn before into after to edits
+// Documents and identified lists are compared element by element, so that the edits leave any other field alone
+func diffDocuments(before interface{}, after interface{}, path []interface{}, keys []string, edits *[]DocumentEdit) {
+	if b, ok := before.(map[string]interface{}); ok {
+		if a, ok := after.(map[string]interface{}); ok {
+			for _, k := range sortedKeys(b) {
+				if _, ok := a[k]; !ok {
+					*edits = append(*edits, DocumentEdit{Path: appendPath(path, k), Remove: true})
+				}
+			}
+			for _, k := range sortedKeys(a) {
+				if v, ok := b[k]; ok {
+					diffDocuments(v, a[k], appendPath(path, k), append(keys[:len(keys):len(keys)], k), edits)
+				} else {
+					*edits = append(*edits, DocumentEdit{Path: appendPath(path, k), Value: a[k]})
+				}
+			}
+			return
+		}
+	}
+
+	if identity, ok := documentListIdentities[strings.Join(keys, ".")]; ok {
+		b, bOK := before.([]interface{})
+		a, aOK := after.([]interface{})
+		if bOK && aOK && identifiable(b, identity) && identifiable(a, identity) {
+			for _, e := range b {
+				if matchingSelector(a, e, identity) == nil {
+					*edits = append(*edits, DocumentEdit{Path: appendPath(path, selector(e, identity)), Remove: true})
+				}
+			}
+			for _, e := range a {
+				if match := matchingSelector(b, e, identity); match != nil {
+					diffDocuments(match, e, appendPath(path, selector(e, identity)), keys, edits)
+				} else {
+					*edits = append(*edits, DocumentEdit{Path: appendPath(path, selector(e, identity)), Value: e})
+				}
+			}
+			return
+		}
+	}
+
+	if !sameDocuments(before, after) {
+		*edits = append(*edits, DocumentEdit{Path: path, Value: after})
+	}
+}
+
+// applyDocumentEdits applies edits to doc, in order
+func applyDocumentEdits(doc map[string]interface{}, edits []DocumentEdit) error {
+	for _, edit := range edits {
+		if len(edit.Path) == 0 {
+			return errors.New("an edit must have a path")
+		}
+
+		value, err := copyValue(edit.Value)
+		if err != nil {
+			return err
+		}
+		edit.Value = value
+		if _, err := editDocument(doc, edit.Path, edit); err != nil {
+			return fmt.Errorf("could not edit %s: %w", describePath(edit.Path), err)
+		}
+	}
+
+	return nil
+}
+
+// editDocument applies edit to the value found at path in node, and returns the edited node
+func editDocument(node interface{}, path []interface{}, edit DocumentEdit) (interface{}, error) {
+	switch key := path[0].(type) {
+	case string:
+		doc, ok := node.(map[string]interface{})
+		if !ok {
+			if node != nil {
+				return nil, fmt.Errorf("%s is not a key of a document", key)
+			}
+			doc = make(map[string]interface{})
+		}
+
+		if len(path) == 1 {
+			if edit.Remove {
+				delete(doc, key)
+			} else {
+				doc[key] = edit.Value
+			}
+			return doc, nil
+		}
+
+		value, err := editDocument(doc[key], path[1:], edit)
+		if err != nil {
+			return nil, err
+		}
+		doc[key] = value
+		return doc, nil
+	case map[string]interface{}:
+		list, ok := node.([]interface{})
+		if !ok && node != nil {
+			return nil, fmt.Errorf("%v does not select an element of a list", key)
+		}
+
+		for i, e := range list {
+			element, ok := e.(map[string]interface{})
+			if !ok || !hasKeys(element, sortedKeys(key)) || !sameValues(element, key, sortedKeys(key)) {
+				continue
+			}
+
+			if len(path) == 1 {
+				if edit.Remove {
+					return append(list[:i:i], list[i+1:]...), nil
+				}
+				list[i] = edit.Value
+				return list, nil
+			}
+
+			value, err := editDocument(element, path[1:], edit)
+			if err != nil {
+				return nil, err
+			}
+			list[i] = value
+			return list, nil
+		}
+
+		switch {
+		case len(path) > 1:
+			return nil, fmt.Errorf("no element matches %v", key)
+		case edit.Remove:
+			return list, nil
+		default:
+			return append(list, edit.Value), nil
+		}
+	default:
+		return nil, fmt.Errorf("invalid path element %v", key)
+	}
+}
+
+// transformEditSecrets replaces all non-empty secrets set by edits with the result of fn
+func transformEditSecrets(edits []DocumentEdit, fn func(string) (string, error)) error {
+	for i := range edits {
+		edit := &edits[i]
+		if len(edit.Path) == 0 {
+			continue
+		}
+
+		var keys []string
+		for _, p := range edit.Path {
+			if k, ok := p.(string); ok {
+				keys = append(keys, k)
+			}
+		}
+
+		last, ok := edit.Path[len(edit.Path)-1].(string)
+		if s, isString := edit.Value.(string); ok && isString {
+			if s != "" && isSecret(keys[:len(keys)-1], last) {
+				replacement, err := fn(s)
+				if err != nil {
+					return fmt.Errorf("%s: %w", describePath(edit.Path), err)
+				}
+				edit.Value = replacement
+			}
+			continue
+		}
+
+		if err := transformSecrets(edit.Value, keys, fn); err != nil {
+			return err
+		}
+	}
+
+	return nil
+}
+
+// copyEdits deep copies edits
+func copyEdits(edits []DocumentEdit) ([]DocumentEdit, error) {
+	result := make([]DocumentEdit, 0, len(edits))
+	for _, edit := range edits {
+		value, err := copyValue(edit.Value)
+		if err != nil {
+			return nil, err
+		}
+		result = append(result, DocumentEdit{Path: edit.Path, Value: value, Remove: edit.Remove})
+	}
+
+	return result, nil
+}
+
+// copyValue deep copies a generic JSON value
+func copyValue(v interface{}) (interface{}, error) {
+	if v == nil {
+		return nil, nil
+	}
+
+	data, err := json.Marshal(v)
+	if err != nil {
+		return nil, err
+	}
+	return decodeDocument(data)
+}
+
+func appendPath(path []interface{}, element interface{}) []interface{} {
+	result := make([]interface{}, 0, len(path)+1)
+	result = append(result, path...)
+	return append(result, element)
+}
+
+// identifiable returns true if each element of list is a document with the given identity fields, and no two
+// elements have the same identity
+func identifiable(list []interface{}, identity []string) bool {
+	seen := make(map[string]bool)
+	for _, e := range list {
+		element, ok := e.(map[string]interface{})
+		if !ok || !hasKeys(element, identity) {
+			return false
+		}
+
+		id := describePath([]interface{}{selector(element, identity)})
+		if seen[id] {
+			return false
+		}
+		seen[id] = true
+	}
+
+	return true
+}
+
+// selector returns the document selecting element by its identity fields
+func selector(element interface{}, identity []string) map[string]interface{} {
+	m, _ := element.(map[string]interface{})
+	result := make(map[string]interface{}, len(identity))
+	for _, k := range identity {
+		result[k] = m[k]
+	}
+
+	return result
+}
+
+// matchingSelector returns the element of list with the same identity as element, or nil
+func matchingSelector(list []interface{}, element interface{}, identity []string) interface{} {
+	m, _ := element.(map[string]interface{})
+	for _, e := range list {
+		if candidate, ok := e.(map[string]interface{}); ok && sameValues(candidate, m, identity) {
+			return e
+		}
+	}
+
+	return nil
+}
+
+func sameDocuments(a interface{}, b interface{}) bool {
+	aData, aErr := json.Marshal(a)
+	bData, bErr := json.Marshal(b)
+	return aErr == nil && bErr == nil && bytes.Equal(aData, bData)
+}
+
+func sortedKeys(m map[string]interface{}) []string {
+	result := make([]string, 0, len(m))
+	for k := range m {
+		result = append(result, k)
+	}
+	sort.Strings(result)
+
+	return result
+}
+
+// describePath renders a path as e.g. processes[name=rs_0].hostname
+func describePath(path []interface{}) string {
+	var sb strings.Builder
+	for _, p := range path {
+		switch t := p.(type) {
+		case string:
+			if sb.Len() > 0 {
+				sb.WriteString(".")
+			}
+			sb.WriteString(t)
+		case map[string]interface{}:
+			fields := make([]string, 0, len(t))
+			for _, k := range sortedKeys(t) {
+				fields = append(fields, fmt.Sprintf("%s=%v", k, t[k]))
+			}
+			sb.WriteString("[" + strings.Join(fields, ",") + "]")
+		default:
+			_, _ = fmt.Fprintf(&sb, "[%v]", t)
+		}
+	}
+
+	return sb.String()
+}
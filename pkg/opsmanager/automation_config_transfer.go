@@ -0,0 +1,483 @@
+// Copyright 2020 MongoDB Inc
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package opsmanager
+
+import (
+	"bytes"
+	"crypto/aes"
+	"crypto/cipher"
+	"crypto/rand"
+	"encoding/base64"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"sort"
+	"strings"
+
+	"github.com/mongodb-labs/pcgc/pkg/useful"
+	"gopkg.in/yaml.v2"
+)
+
+// ConfigFormat the serialization format of an exported automation config
+type ConfigFormat string
+
+const (
+	// FormatJSON exports the automation config as indented JSON
+	FormatJSON ConfigFormat = "json"
+	// FormatYAML exports the automation config as YAML
+	FormatYAML ConfigFormat = "yaml"
+
+	// RedactedSecret replaces secrets in exported automation configs
+	RedactedSecret = "<redacted>"
+
+	encryptedSecretPrefix = "encrypted:"
+)
+
+// ConfigFileOptions configure how automation configs are exported and imported
+type ConfigFileOptions struct {
+	// Format the file format, defaults to FormatJSON (or is inferred from the file extension, when using files)
+	Format ConfigFormat
+	// EncryptionKey if set (16, 24, or 32 bytes), secrets are AES-GCM encrypted with this key, instead of being redacted
+	EncryptionKey []byte
+}
+
+// ExportAutomationConfig retrieves the automation config of the specified project and writes it to w,
+// with any secrets redacted or encrypted
+// The config is exported as returned by Ops Manager, including any fields which AutomationConfig does not model
+func ExportAutomationConfig(client Client, projectID string, w io.Writer, opts ConfigFileOptions) error {
+	doc, err := client.GetAutomationConfigDocument(projectID)
+	if err != nil {
+		return err
+	}
+
+	return WriteAutomationConfig(doc, w, opts)
+}
+
+// ExportAutomationConfigToFile exports the automation config of the specified project to the given file
+func ExportAutomationConfigToFile(client Client, projectID string, path string, opts ConfigFileOptions) error {
+	opts.Format = formatOf(path, opts.Format)
+
+	f, err := os.OpenFile(path, os.O_WRONLY|os.O_CREATE|os.O_TRUNC, 0600)
+	if err != nil {
+		return err
+	}
+	defer useful.LogError(f.Close)
+
+	return ExportAutomationConfig(client, projectID, f, opts)
+}
+
+// WriteAutomationConfig writes the canonical (sorted keys, no server-owned fields) representation of an
+// automation config document to w, with any secrets redacted or encrypted; doc is not modified
+func WriteAutomationConfig(doc map[string]interface{}, w io.Writer, opts ConfigFileOptions) error {
+	doc, err := copyDocument(doc)
+	if err != nil {
+		return err
+	}
+	stripServerOwnedDocumentFields(doc)
+
+	var protect func(string) (string, error)
+	if opts.EncryptionKey != nil {
+		protect = func(secret string) (string, error) {
+			return encryptSecret(opts.EncryptionKey, secret)
+		}
+	} else {
+		protect = func(string) (string, error) {
+			return RedactedSecret, nil
+		}
+	}
+	if err := transformSecrets(doc, nil, protect); err != nil {
+		return err
+	}
+
+	switch opts.Format {
+	case FormatJSON, "":
+		encoder := json.NewEncoder(w)
+		encoder.SetEscapeHTML(false)
+		encoder.SetIndent("", "  ")
+		return encoder.Encode(doc)
+	case FormatYAML:
+		return yaml.NewEncoder(w).Encode(numbersToYAML(doc))
+	default:
+		return fmt.Errorf("unsupported format: %s", opts.Format)
+	}
+}
+
+// ImportAutomationConfig reads an exported automation config from r and applies it to the specified project
+// Encrypted secrets are decrypted with the configured key, while redacted secrets are merged back from the
+// project's current automation config; if a redacted secret cannot be found there, the import fails
+// The document stored by Ops Manager is returned
+func ImportAutomationConfig(client Client, projectID string, r io.Reader, opts ConfigFileOptions) (map[string]interface{}, error) {
+	doc, err := ReadAutomationConfig(r, opts)
+	if err != nil {
+		return nil, err
+	}
+
+	current, err := client.GetAutomationConfigDocument(projectID)
+	if err != nil {
+		return nil, err
+	}
+
+	mergeRedactedSecrets(doc, current, nil)
+	if paths := findRedactedSecrets(doc); len(paths) > 0 {
+		return nil, fmt.Errorf("could not restore the following redacted secrets from project %s: %s", projectID, strings.Join(paths, ", "))
+	}
+
+	// updates are only accepted against the latest version
+	doc["version"] = current["version"]
+
+	return client.UpdateAutomationConfigDocument(projectID, doc)
+}
+
+// ImportAutomationConfigFromFile imports the automation config stored in the given file into the specified project
+func ImportAutomationConfigFromFile(client Client, projectID string, path string, opts ConfigFileOptions) (map[string]interface{}, error) {
+	opts.Format = formatOf(path, opts.Format)
+
+	f, err := os.Open(path)
+	if err != nil {
+		return nil, err
+	}
+	defer useful.LogError(f.Close)
+
+	return ImportAutomationConfig(client, projectID, f, opts)
+}
+
+// ReadAutomationConfig parses an exported automation config document, decrypting any encrypted secrets
+// Redacted secrets are left as RedactedSecret and server-owned fields are stripped
+func ReadAutomationConfig(r io.Reader, opts ConfigFileOptions) (map[string]interface{}, error) {
+	data, err := ioutil.ReadAll(r)
+	if err != nil {
+		return nil, err
+	}
+
+	var doc interface{}
+	switch opts.Format {
+	case FormatJSON, "":
+		doc, err = decodeDocument(data)
+	case FormatYAML:
+		err = yaml.Unmarshal(data, &doc)
+		doc = normalizeYAML(doc)
+	default:
+		return nil, fmt.Errorf("unsupported format: %s", opts.Format)
+	}
+	if err != nil {
+		return nil, err
+	}
+
+	result, ok := doc.(map[string]interface{})
+	if !ok {
+		return nil, errors.New("the automation config must be a document")
+	}
+
+	err = transformSecrets(result, nil, func(secret string) (string, error) {
+		if !strings.HasPrefix(secret, encryptedSecretPrefix) {
+			return secret, nil
+		}
+		if opts.EncryptionKey == nil {
+			return "", errors.New("the automation config contains encrypted secrets, but no encryption key was specified")
+		}
+		return decryptSecret(opts.EncryptionKey, secret)
+	})
+	if err != nil {
+		return nil, err
+	}
+	stripServerOwnedDocumentFields(result)
+
+	return result, nil
+}
+
+// stripServerOwnedDocumentFields removes fields which are set by Ops Manager or the agents and should not be carried over
+func stripServerOwnedDocumentFields(doc map[string]interface{}) {
+	delete(doc, "version")
+	if processes, ok := doc["processes"].([]interface{}); ok {
+		for _, p := range processes {
+			if process, ok := p.(map[string]interface{}); ok {
+				delete(process, "lastGoalVersionAchieved")
+				delete(process, "plan")
+			}
+		}
+	}
+}
+
+// isSecret returns true if the value found at the given key (with the given path of parent keys) is a secret
+func isSecret(path []string, key string) bool {
+	if key == "key" {
+		// the keyfile contents, not to be confused with the key of an index config
+		return len(path) == 1 && path[0] == "auth"
+	}
+
+	k := strings.ToLower(key)
+	return strings.HasSuffix(k, "pwd") || strings.HasSuffix(k, "password")
+}
+
+// transformSecrets walks the generic JSON document, replacing all non-empty secrets with the result of fn
+func transformSecrets(doc interface{}, path []string, fn func(string) (string, error)) error {
+	switch t := doc.(type) {
+	case map[string]interface{}:
+		for k, v := range t {
+			if s, ok := v.(string); ok && s != "" && isSecret(path, k) {
+				replacement, err := fn(s)
+				if err != nil {
+					return fmt.Errorf("%s.%s: %w", strings.Join(path, "."), k, err)
+				}
+				t[k] = replacement
+				continue
+			}
+			if err := transformSecrets(v, append(path, k), fn); err != nil {
+				return err
+			}
+		}
+	case []interface{}:
+		for _, v := range t {
+			if err := transformSecrets(v, path, fn); err != nil {
+				return err
+			}
+		}
+	}
+
+	return nil
+}
+
+// identityKeys the fields identifying the elements of the lists in an automation config, e.g. users by user and db;
+// they are used to find the current value of a redacted secret
+var identityKeys = [][]string{{"user", "db"}, {"_id"}, {"name"}, {"hostname"}}
+
+// mergeRedactedSecrets copies the secrets that were redacted in doc from the same location in the current document
+// List elements are matched by their identity keys, never by position; secrets which cannot be matched stay redacted
+func mergeRedactedSecrets(doc interface{}, current interface{}, path []string) {
+	switch t := doc.(type) {
+	case map[string]interface{}:
+		c, _ := current.(map[string]interface{})
+		for k, v := range t {
+			if v == RedactedSecret && isSecret(path, k) {
+				if secret, ok := c[k].(string); ok && secret != "" {
+					t[k] = secret
+				}
+				continue
+			}
+			mergeRedactedSecrets(v, c[k], append(path, k))
+		}
+	case []interface{}:
+		c, _ := current.([]interface{})
+		for _, v := range t {
+			mergeRedactedSecrets(v, matchingElement(v, c), path)
+		}
+	}
+}
+
+// matchingElement returns the element of candidates with the same identity as item, or nil
+func matchingElement(item interface{}, candidates []interface{}) interface{} {
+	m, ok := item.(map[string]interface{})
+	if !ok {
+		return nil
+	}
+
+	for _, keys := range identityKeys {
+		if !hasKeys(m, keys) {
+			continue
+		}
+		for _, c := range candidates {
+			if cm, ok := c.(map[string]interface{}); ok && hasKeys(cm, keys) && sameValues(m, cm, keys) {
+				return c
+			}
+		}
+		return nil
+	}
+
+	return nil
+}
+
+func hasKeys(m map[string]interface{}, keys []string) bool {
+	for _, k := range keys {
+		if _, ok := m[k]; !ok {
+			return false
+		}
+	}
+	return true
+}
+
+func sameValues(a map[string]interface{}, b map[string]interface{}, keys []string) bool {
+	for _, k := range keys {
+		if fmt.Sprintf("%v", a[k]) != fmt.Sprintf("%v", b[k]) {
+			return false
+		}
+	}
+	return true
+}
+
+// findRedactedSecrets returns the paths of all secrets which are still redacted
+func findRedactedSecrets(doc map[string]interface{}) []string {
+	var result []string
+	collectRedactedPaths(doc, nil, &result)
+	sort.Strings(result)
+	return result
+}
+
+func collectRedactedPaths(doc interface{}, path []string, result *[]string) {
+	switch t := doc.(type) {
+	case map[string]interface{}:
+		for k, v := range t {
+			if v == RedactedSecret && isSecret(path, k) {
+				*result = append(*result, strings.Join(append(path, k), "."))
+				continue
+			}
+			collectRedactedPaths(v, append(path, k), result)
+		}
+	case []interface{}:
+		for i, v := range t {
+			collectRedactedPaths(v, append(path, fmt.Sprintf("%d", i)), result)
+		}
+	}
+}
+
+func encryptSecret(key []byte, secret string) (string, error) {
+	gcm, err := newGCM(key)
+	if err != nil {
+		return "", err
+	}
+
+	nonce := make([]byte, gcm.NonceSize())
+	if _, err := io.ReadFull(rand.Reader, nonce); err != nil {
+		return "", err
+	}
+
+	sealed := gcm.Seal(nonce, nonce, []byte(secret), nil)
+	return encryptedSecretPrefix + base64.StdEncoding.EncodeToString(sealed), nil
+}
+
+func decryptSecret(key []byte, secret string) (string, error) {
+	gcm, err := newGCM(key)
+	if err != nil {
+		return "", err
+	}
+
+	sealed, err := base64.StdEncoding.DecodeString(strings.TrimPrefix(secret, encryptedSecretPrefix))
+	if err != nil {
+		return "", err
+	}
+	if len(sealed) < gcm.NonceSize() {
+		return "", errors.New("the encrypted secret is too short")
+	}
+
+	nonce, ciphertext := sealed[:gcm.NonceSize()], sealed[gcm.NonceSize():]
+	plaintext, err := gcm.Open(nil, nonce, ciphertext, nil)
+	if err != nil {
+		return "", err
+	}
+
+	return string(plaintext), nil
+}
+
+func newGCM(key []byte) (cipher.AEAD, error) {
+	block, err := aes.NewCipher(key)
+	if err != nil {
+		return nil, err
+	}
+
+	return cipher.NewGCM(block)
+}
+
+// decodeDocument decodes a generic JSON document, keeping numbers as json.Number so that they round-trip unchanged
+func decodeDocument(data []byte) (interface{}, error) {
+	decoder := json.NewDecoder(bytes.NewReader(data))
+	decoder.UseNumber()
+
+	var doc interface{}
+	err := decoder.Decode(&doc)
+	return doc, err
+}
+
+// copyDocument deep copies a generic JSON document
+func copyDocument(doc map[string]interface{}) (map[string]interface{}, error) {
+	data, err := json.Marshal(doc)
+	if err != nil {
+		return nil, err
+	}
+
+	result, err := decodeDocument(data)
+	if err != nil {
+		return nil, err
+	}
+	m, _ := result.(map[string]interface{})
+	return m, nil
+}
+
+// numbersToYAML converts the json.Number values of a document into numbers, which the YAML encoder would otherwise
+// write as strings
+func numbersToYAML(doc interface{}) interface{} {
+	switch t := doc.(type) {
+	case json.Number:
+		if i, err := t.Int64(); err == nil {
+			return i
+		}
+		if f, err := t.Float64(); err == nil {
+			return f
+		}
+	case map[string]interface{}:
+		for k, v := range t {
+			t[k] = numbersToYAML(v)
+		}
+	case []interface{}:
+		for i, v := range t {
+			t[i] = numbersToYAML(v)
+		}
+	}
+
+	return doc
+}
+
+// fromDocument converts a generic JSON document into the passed value
+func fromDocument(doc interface{}, v interface{}) error {
+	data, err := json.Marshal(doc)
+	if err != nil {
+		return err
+	}
+
+	return json.Unmarshal(data, v)
+}
+
+// normalizeYAML converts the map[interface{}]interface{} values produced by the YAML decoder into JSON compatible maps
+func normalizeYAML(doc interface{}) interface{} {
+	switch t := doc.(type) {
+	case map[interface{}]interface{}:
+		result := make(map[string]interface{}, len(t))
+		for k, v := range t {
+			result[fmt.Sprintf("%v", k)] = normalizeYAML(v)
+		}
+		return result
+	case []interface{}:
+		for i, v := range t {
+			t[i] = normalizeYAML(v)
+		}
+	}
+
+	return doc
+}
+
+func formatOf(path string, format ConfigFormat) ConfigFormat {
+	if format != "" {
+		return format
+	}
+
+	switch strings.ToLower(filepath.Ext(path)) {
+	case ".yaml", ".yml":
+		return FormatYAML
+	default:
+		return FormatJSON
+	}
+}
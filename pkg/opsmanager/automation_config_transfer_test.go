@@ -0,0 +1,349 @@
+// Copyright 2020 MongoDB Inc
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package opsmanager
+
+import (
+	"bytes"
+	"encoding/json"
+	"errors"
+	"reflect"
+	"strings"
+	"testing"
+)
+
+// fakeDocumentClient stores a project's automation config as a generic document
+type fakeDocumentClient struct {
+	Client
+
+	doc     map[string]interface{}
+	updates int
+}
+
+func (c *fakeDocumentClient) GetAutomationConfigDocument(string) (map[string]interface{}, error) {
+	return copyDocument(c.doc)
+}
+
+func (c *fakeDocumentClient) UpdateAutomationConfigDocument(_ string, doc map[string]interface{}) (map[string]interface{}, error) {
+	if doc["version"] != c.doc["version"] {
+		return nil, errors.New("stale version")
+	}
+
+	stored, err := copyDocument(doc)
+	if err != nil {
+		return nil, err
+	}
+	stored["version"] = json.Number("8")
+	c.doc = stored
+	c.updates++
+	return copyDocument(stored)
+}
+
+// liveDocument returns an automation config document with secrets, server-owned fields, fields which
+// AutomationConfig does not model, and a number which does not fit in a float64
+const liveDocument = `{
+	"version": 7,
+	"auth": {
+		"autoUser": "mms-automation",
+		"autoPwd": "auto-secret",
+		"key": "keyfile-secret",
+		"usersWanted": [
+			{"user": "app", "db": "admin", "initPwd": "app-secret", "roles": []},
+			{"user": "reporting", "db": "admin", "initPwd": "reporting-secret", "roles": []}
+		]
+	},
+	"ldap": {"bindQueryUser": "cn=admin", "bindQueryPassword": "ldap-secret"},
+	"indexConfigs": [{"key": [["name", 1]], "dbName": "app", "collectionName": "users"}],
+	"processes": [{
+		"name": "rs_0",
+		"hostname": "h0",
+		"lastGoalVersionAchieved": 7,
+		"plan": ["Start"],
+		"args2_6": {
+			"net": {"port": 27017, "tls": {"mode": "requireTLS", "certificateKeyFilePassword": "tls-secret"}},
+			"setParameter": {"maxTransactionLockRequestTimeoutMillis": 9007199254740993}
+		},
+		"horizons": {"external": "rs0.example.com:27017"}
+	}],
+	"onlineArchiveModules": [{"name": "oa", "enabled": true}]
+}`
+
+func newFakeDocumentClient(t *testing.T) *fakeDocumentClient {
+	t.Helper()
+
+	doc, err := decodeDocument([]byte(liveDocument))
+	if err != nil {
+		t.Fatal(err)
+	}
+	return &fakeDocumentClient{doc: doc.(map[string]interface{})}
+}
+
+// normalizedJSON converts a document into a form which can be compared regardless of how it was decoded
+func normalizedJSON(t *testing.T, doc interface{}) interface{} {
+	t.Helper()
+
+	data, err := json.Marshal(doc)
+	if err != nil {
+		t.Fatal(err)
+	}
+	result, err := decodeDocument(data)
+	if err != nil {
+		t.Fatal(err)
+	}
+	return result
+}
+
+func TestExportImportAutomationConfig(t *testing.T) {
+	key := []byte("0123456789abcdef")
+	tests := []struct {
+		name string
+		opts ConfigFileOptions
+	}{
+		{name: "redacted JSON", opts: ConfigFileOptions{Format: FormatJSON}},
+		{name: "redacted YAML", opts: ConfigFileOptions{Format: FormatYAML}},
+		{name: "encrypted JSON", opts: ConfigFileOptions{Format: FormatJSON, EncryptionKey: key}},
+		{name: "encrypted YAML", opts: ConfigFileOptions{Format: FormatYAML, EncryptionKey: key}},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			client := newFakeDocumentClient(t)
+
+			var buf bytes.Buffer
+			if err := ExportAutomationConfig(client, "p", &buf, tt.opts); err != nil {
+				t.Fatal(err)
+			}
+
+			exported := buf.String()
+			for _, secret := range []string{"auto-secret", "keyfile-secret", "app-secret", "reporting-secret", "ldap-secret", "tls-secret"} {
+				if strings.Contains(exported, secret) {
+					t.Errorf("the export contains the %s secret", secret)
+				}
+			}
+			for _, field := range []string{"lastGoalVersionAchieved", "plan", "version"} {
+				if strings.Contains(exported, field) {
+					t.Errorf("the export contains the server-owned field %s", field)
+				}
+			}
+
+			if _, err := ImportAutomationConfig(client, "p", &buf, tt.opts); err != nil {
+				t.Fatal(err)
+			}
+
+			// everything but the server-owned fields must be restored as it was
+			expected := newFakeDocumentClient(t).doc
+			expected["version"] = json.Number("8")
+			process := expected["processes"].([]interface{})[0].(map[string]interface{})
+			delete(process, "lastGoalVersionAchieved")
+			delete(process, "plan")
+			if got, want := normalizedJSON(t, client.doc), normalizedJSON(t, expected); !reflect.DeepEqual(got, want) {
+				t.Errorf("expected %v, got %v", want, got)
+			}
+		})
+	}
+}
+
+func TestImportAutomationConfig_unknownRedactedSecret(t *testing.T) {
+	client := newFakeDocumentClient(t)
+	exported := `{"auth": {"autoPwd": "<redacted>", "usersWanted": [
+		{"user": "reporting", "db": "admin", "initPwd": "<redacted>"},
+		{"user": "new", "db": "admin", "initPwd": "<redacted>"}
+	]}}`
+
+	_, err := ImportAutomationConfig(client, "p", strings.NewReader(exported), ConfigFileOptions{})
+	if err == nil || !strings.Contains(err.Error(), "auth.usersWanted.1.initPwd") {
+		t.Fatalf("expected an error about the new user's password, got %v", err)
+	}
+	if strings.Contains(err.Error(), "autoPwd") || strings.Contains(err.Error(), "usersWanted.0") {
+		t.Errorf("expected the secrets of known users to be restored, got %v", err)
+	}
+	if client.updates != 0 {
+		t.Error("expected no update")
+	}
+}
+
+func TestReadAutomationConfig_errors(t *testing.T) {
+	encrypted, err := encryptSecret([]byte("0123456789abcdef"), "secret")
+	if err != nil {
+		t.Fatal(err)
+	}
+	withSecret := `{"auth": {"autoPwd": "` + encrypted + `"}}`
+
+	tests := []struct {
+		name string
+		data string
+		opts ConfigFileOptions
+	}{
+		{name: "no encryption key", data: withSecret},
+		{name: "wrong encryption key", data: withSecret, opts: ConfigFileOptions{EncryptionKey: []byte("fedcba9876543210")}},
+		{name: "not a document", data: `[]`},
+		{name: "invalid JSON", data: `{`},
+		{name: "unsupported format", data: `{}`, opts: ConfigFileOptions{Format: "toml"}},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if _, err := ReadAutomationConfig(strings.NewReader(tt.data), tt.opts); err == nil {
+				t.Fatal("expected an error")
+			}
+		})
+	}
+}
+
+func TestIsSecret(t *testing.T) {
+	tests := []struct {
+		path     []string
+		key      string
+		expected bool
+	}{
+		{path: []string{"auth"}, key: "autoPwd", expected: true},
+		{path: []string{"auth"}, key: "key", expected: true},
+		{path: []string{"auth", "usersWanted"}, key: "initPwd", expected: true},
+		{path: []string{"ldap"}, key: "bindQueryPassword", expected: true},
+		{path: []string{"processes", "args2_6", "net", "tls"}, key: "certificateKeyFilePassword", expected: true},
+		{path: []string{"indexConfigs"}, key: "key", expected: false},
+		{path: []string{"auth"}, key: "autoUser", expected: false},
+		{path: nil, key: "key", expected: false},
+	}
+
+	for _, tt := range tests {
+		if got := isSecret(tt.path, tt.key); got != tt.expected {
+			t.Errorf("isSecret(%v, %s): expected %v, got %v", tt.path, tt.key, tt.expected, got)
+		}
+	}
+}
+
+func TestTransformSecrets(t *testing.T) {
+	doc, err := decodeDocument([]byte(`{
+		"auth": {"autoPwd": "a", "key": "", "usersWanted": [{"user": "u", "initPwd": "b"}]},
+		"indexConfigs": [{"key": "not a secret"}]
+	}`))
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	var transformed []string
+	err = transformSecrets(doc, nil, func(secret string) (string, error) {
+		transformed = append(transformed, secret)
+		return strings.ToUpper(secret), nil
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	expected, _ := decodeDocument([]byte(`{
+		"auth": {"autoPwd": "A", "key": "", "usersWanted": [{"user": "u", "initPwd": "B"}]},
+		"indexConfigs": [{"key": "not a secret"}]
+	}`))
+	if !reflect.DeepEqual(doc, expected) {
+		t.Errorf("expected %v, got %v", expected, doc)
+	}
+	if len(transformed) != 2 {
+		t.Errorf("expected only the 2 non-empty secrets to be transformed, got %v", transformed)
+	}
+
+	err = transformSecrets(doc, nil, func(string) (string, error) {
+		return "", errors.New("failed")
+	})
+	if err == nil || !strings.Contains(err.Error(), "failed") {
+		t.Errorf("expected the error to be returned, got %v", err)
+	}
+}
+
+func TestMergeRedactedSecrets(t *testing.T) {
+	doc, _ := decodeDocument([]byte(`{
+		"auth": {"autoPwd": "<redacted>", "key": "<redacted>", "usersWanted": [
+			{"user": "reporting", "db": "admin", "initPwd": "<redacted>"},
+			{"user": "app", "db": "admin", "initPwd": "<redacted>"},
+			{"user": "app", "db": "other", "initPwd": "<redacted>"}
+		]},
+		"ldap": {"bindQueryPassword": "<redacted>"},
+		"processes": [{"name": "rs_0", "args2_6": {"net": {"tls": {"certificateKeyFilePassword": "<redacted>"}}}}],
+		"indexConfigs": [{"key": "<redacted>"}]
+	}`))
+	current, _ := decodeDocument([]byte(liveDocument))
+
+	mergeRedactedSecrets(doc, current, nil)
+
+	// users are matched by user and db, not by position
+	expected, _ := decodeDocument([]byte(`{
+		"auth": {"autoPwd": "auto-secret", "key": "keyfile-secret", "usersWanted": [
+			{"user": "reporting", "db": "admin", "initPwd": "reporting-secret"},
+			{"user": "app", "db": "admin", "initPwd": "app-secret"},
+			{"user": "app", "db": "other", "initPwd": "<redacted>"}
+		]},
+		"ldap": {"bindQueryPassword": "ldap-secret"},
+		"processes": [{"name": "rs_0", "args2_6": {"net": {"tls": {"certificateKeyFilePassword": "tls-secret"}}}}],
+		"indexConfigs": [{"key": "<redacted>"}]
+	}`))
+	if !reflect.DeepEqual(doc, expected) {
+		t.Errorf("expected %v, got %v", expected, doc)
+	}
+	if paths := findRedactedSecrets(doc.(map[string]interface{})); !reflect.DeepEqual(paths, []string{"auth.usersWanted.2.initPwd"}) {
+		t.Errorf("unexpected redacted secrets %v", paths)
+	}
+}
+
+func TestEncryptSecret(t *testing.T) {
+	key := []byte("0123456789abcdef0123456789abcdef")
+
+	first, err := encryptSecret(key, "secret")
+	if err != nil {
+		t.Fatal(err)
+	}
+	second, err := encryptSecret(key, "secret")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !strings.HasPrefix(first, encryptedSecretPrefix) || strings.Contains(first, "secret") {
+		t.Errorf("unexpected encrypted secret %s", first)
+	}
+	if first == second {
+		t.Error("expected a random nonce for each encryption")
+	}
+
+	decrypted, err := decryptSecret(key, first)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if decrypted != "secret" {
+		t.Errorf("expected secret, got %s", decrypted)
+	}
+
+	if _, err := encryptSecret([]byte("short"), "secret"); err == nil {
+		t.Error("expected an error for an invalid key size")
+	}
+}
+
+func TestDecryptSecret_errors(t *testing.T) {
+	key := []byte("0123456789abcdef")
+	encrypted, err := encryptSecret(key, "secret")
+	if err != nil {
+		t.Fatal(err)
+	}
+	tampered := encrypted[:len(encrypted)-4] + "AAA="
+
+	tests := map[string]string{
+		"tampered":       tampered,
+		"too short":      encryptedSecretPrefix + "AAAA",
+		"invalid base64": encryptedSecretPrefix + "!!",
+	}
+	for name, secret := range tests {
+		t.Run(name, func(t *testing.T) {
+			if _, err := decryptSecret(key, secret); err == nil {
+				t.Fatal("expected an error")
+			}
+		})
+	}
+}
@@ -84,6 +84,10 @@ type Client interface {
 	GetAutomationConfig(projectID string) (AutomationConfig, error)
 	// https://docs.opsmanager.mongodb.com/master/reference/api/automation-config/#update-the-automation-configuration
 	UpdateAutomationConfig(projectID string, config AutomationConfig) (AutomationConfig, error)
+	// https://docs.opsmanager.mongodb.com/master/reference/api/automation-config/#get-the-automation-configuration
+	GetAutomationConfigDocument(projectID string) (map[string]interface{}, error)
+	// https://docs.opsmanager.mongodb.com/master/reference/api/automation-config/#update-the-automation-configuration
+	UpdateAutomationConfigDocument(projectID string, doc map[string]interface{}) (map[string]interface{}, error)
 	// GET /agents/api/automation/conf/v1/{projectID}
 	GetRawAutomationConfig(projectID string) (RawAutomationConfig, error)
 	// https://docs.opsmanager.mongodb.com/master/reference/api/automation-status/
@@ -102,6 +106,8 @@ type Client interface {
 	DeleteProject(projectID string) error
 	// https://docs.opsmanager.mongodb.com/master/reference/api/groups/add-or-remove-tags-from-one-group/
 	SetProjectTags(projectID string, tags []string) (ProjectResponse, error)
+	// https://docs.opsmanager.mongodb.com/master/reference/api/groups/update-one-group/
+	UpdateLDAPGroupMappings(projectID string, mappings []LDAPGroupMapping) (ProjectResponse, error)
 	// https://docs.opsmanager.mongodb.com/master/reference/api/hosts/get-all-hosts-in-group/
 	GetHosts(projectID string) (HostsResponse, error)
 	// https://docs.opsmanager.mongodb.com/master/reference/api/automation-config/index.html#update-the-monitoring-or-backup
@@ -0,0 +1,167 @@
+// Copyright 2020 MongoDB Inc
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package opsmanager
+
+import (
+	"encoding/json"
+	"fmt"
+	"sync"
+)
+
+// fakeClient keeps a project's automation config in memory, as a generic document, and implements the Client API
+// actions used by the deployment helpers; calling any other action panics
+type fakeClient struct {
+	Client
+
+	mu      sync.Mutex
+	doc     map[string]interface{}
+	updates []AutomationConfig
+	hosts   HostsResponse
+	// status, if set, reports the automation status; by default all processes are in goal state
+	status func(config AutomationConfig) AutomationStatusResponse
+	// afterUpdate, if set, is called after each update is stored, e.g. to simulate another user editing the config
+	afterUpdate func(c *fakeClient)
+}
+
+func newFakeClient(config AutomationConfig) *fakeClient {
+	if config.Version == nil {
+		version := 1
+		config.Version = &version
+	}
+	return &fakeClient{doc: mustDocument(config)}
+}
+
+func mustCopyConfig(config AutomationConfig) AutomationConfig {
+	result, err := copyAutomationConfig(config)
+	if err != nil {
+		panic(err)
+	}
+	return result
+}
+
+// mustDocument converts v into a generic document, as decoded by GetAutomationConfigDocument
+func mustDocument(v interface{}) map[string]interface{} {
+	data, err := json.Marshal(v)
+	if err != nil {
+		panic(err)
+	}
+	doc, err := decodeDocument(data)
+	if err != nil {
+		panic(err)
+	}
+	return doc.(map[string]interface{})
+}
+
+// mustConfig returns the fields of a document which AutomationConfig models
+func mustConfig(doc map[string]interface{}) AutomationConfig {
+	var result AutomationConfig
+	if err := fromDocument(doc, &result); err != nil {
+		panic(err)
+	}
+	return result
+}
+
+// config returns the stored automation config
+func (c *fakeClient) config() AutomationConfig {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	return mustConfig(c.doc)
+}
+
+func (c *fakeClient) GetAutomationConfig(string) (AutomationConfig, error) {
+	return c.config(), nil
+}
+
+func (c *fakeClient) UpdateAutomationConfig(projectID string, config AutomationConfig) (AutomationConfig, error) {
+	stored, err := c.UpdateAutomationConfigDocument(projectID, mustDocument(config))
+	if err != nil {
+		return AutomationConfig{}, err
+	}
+	return mustConfig(stored), nil
+}
+
+func (c *fakeClient) GetAutomationConfigDocument(string) (map[string]interface{}, error) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	return copyDocument(c.doc)
+}
+
+func (c *fakeClient) UpdateAutomationConfigDocument(_ string, doc map[string]interface{}) (map[string]interface{}, error) {
+	c.mu.Lock()
+	stored, err := copyDocument(doc)
+	if err != nil {
+		c.mu.Unlock()
+		return nil, err
+	}
+	stored["version"] = json.Number(fmt.Sprintf("%d", c.version()+1))
+	c.doc = stored
+	c.updates = append(c.updates, mustConfig(stored))
+	result, err := copyDocument(stored)
+	afterUpdate := c.afterUpdate
+	c.mu.Unlock()
+
+	if afterUpdate != nil {
+		afterUpdate(c)
+	}
+	return result, err
+}
+
+// version returns the version of the stored automation config; the caller must hold c.mu
+func (c *fakeClient) version() int {
+	version, err := c.doc["version"].(json.Number).Int64()
+	if err != nil {
+		panic(err)
+	}
+	return int(version)
+}
+
+// bumpVersion simulates a change made by someone else
+func (c *fakeClient) bumpVersion() {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	c.doc["version"] = json.Number(fmt.Sprintf("%d", c.version()+1))
+}
+
+func (c *fakeClient) GetAutomationStatus(string) (AutomationStatusResponse, error) {
+	config := c.config()
+	if c.status != nil {
+		return c.status(config), nil
+	}
+
+	result := AutomationStatusResponse{GoalVersion: *config.Version}
+	for _, p := range config.Processes {
+		result.Processes = append(result.Processes, ProcessStatus{Name: p.Name, Hostname: p.Hostname, LastGoalVersionAchieved: *config.Version})
+	}
+	return result, nil
+}
+
+func (c *fakeClient) GetHosts(string) (HostsResponse, error) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	return c.hosts, nil
+}
+
+func (c *fakeClient) updateCount() int {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	return len(c.updates)
+}
+
+// decodeJSON decodes a JSON document, panicking on invalid test fixtures
+func decodeJSON(data string, v interface{}) {
+	if err := json.Unmarshal([]byte(data), v); err != nil {
+		panic(err)
+	}
+}
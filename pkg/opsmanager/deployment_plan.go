@@ -0,0 +1,892 @@
+// Copyright 2020 MongoDB Inc
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package opsmanager
+
+import (
+	"bufio"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"strings"
+	"time"
+)
+
+// ChangeAction the type of change made to a deployment item
+type ChangeAction string
+
+const (
+	// ActionCreate a new item is added to the automation config
+	ActionCreate ChangeAction = "create"
+	// ActionUpdate an existing item is modified
+	ActionUpdate ChangeAction = "update"
+	// ActionDelete an existing item is removed from the automation config
+	ActionDelete ChangeAction = "delete"
+)
+
+var (
+	// ErrStalePlan is returned when the automation config was modified after the plan was computed
+	ErrStalePlan = errors.New("the automation config changed since the plan was computed")
+	// ErrPlanNotConfirmed is returned when a plan was not confirmed
+	ErrPlanNotConfirmed = errors.New("the plan was not confirmed")
+)
+
+// Change describes a single difference between the desired and the live state
+type Change struct {
+	Action  ChangeAction `json:"action"`
+	Kind    string       `json:"kind"`
+	Name    string       `json:"name"`
+	Details []string     `json:"details,omitempty"`
+}
+
+// PlanStep the edits which are made to the automation config in one update, optionally followed by waiting for goal state
+// The edits are applied to the automation config document, so that the fields AutomationConfig does not model are kept
+// NOTE: the edits may contain secrets (e.g. new users' passwords), which Save redacts or encrypts
+type PlanStep struct {
+	Description      string         `json:"description"`
+	Edits            []DocumentEdit `json:"edits"`
+	WaitForGoalState bool           `json:"waitForGoalState"`
+}
+
+// Plan the ordered list of steps required to bring a project's deployments to the desired state
+type Plan struct {
+	ProjectID   string     `json:"projectId"`
+	BaseVersion int        `json:"baseVersion"`
+	Changes     []Change   `json:"changes"`
+	Steps       []PlanStep `json:"steps"`
+}
+
+// ConfirmFunc is called before a plan is applied; the plan is only applied if it returns true
+type ConfirmFunc func(*Plan) bool
+
+// AutoApprove confirms any plan, for unattended use
+func AutoApprove(*Plan) bool {
+	return true
+}
+
+// PromptConfirmation prints the plan to out and confirms it only if the user answers 'yes' on in
+func PromptConfirmation(in io.Reader, out io.Writer) ConfirmFunc {
+	return func(plan *Plan) bool {
+		_, _ = fmt.Fprintln(out, plan.String())
+		_, _ = fmt.Fprint(out, "Apply this plan? Only 'yes' will be accepted: ")
+
+		answer, err := bufio.NewReader(in).ReadString('\n')
+		if err != nil && err != io.EOF {
+			return false
+		}
+		return strings.TrimSpace(answer) == "yes"
+	}
+}
+
+// ApplyOptions optional parameters for ApplyPlan
+type ApplyOptions struct {
+	// Confirm is asked to approve the plan before any changes are made; required
+	Confirm ConfirmFunc
+	// PollInterval how often to check for goal state, defaults to DefaultPollInterval
+	PollInterval time.Duration
+	// Timeout how long to wait for each goal state, defaults to DefaultPollTimeout
+	Timeout time.Duration
+}
+
+// IsEmpty returns true if the live state already matches the desired state
+func (p *Plan) IsEmpty() bool {
+	return len(p.Steps) == 0
+}
+
+// String renders a human readable description of the plan
+func (p *Plan) String() string {
+	var sb strings.Builder
+
+	if p.IsEmpty() {
+		_, _ = fmt.Fprintf(&sb, "Project %s is already in the desired state (automation config version %d).\n", p.ProjectID, p.BaseVersion)
+		return sb.String()
+	}
+
+	_, _ = fmt.Fprintf(&sb, "Plan for project %s (automation config version %d):\n", p.ProjectID, p.BaseVersion)
+	for _, c := range p.Changes {
+		symbol := map[ChangeAction]string{ActionCreate: "+", ActionUpdate: "~", ActionDelete: "-"}[c.Action]
+		_, _ = fmt.Fprintf(&sb, "  %s %s %s %s\n", symbol, c.Action, c.Kind, c.Name)
+		for _, d := range c.Details {
+			_, _ = fmt.Fprintf(&sb, "      %s\n", d)
+		}
+	}
+
+	_, _ = fmt.Fprintln(&sb, "Steps:")
+	for i, s := range p.Steps {
+		wait := ""
+		if s.WaitForGoalState {
+			wait = ", then wait for goal state"
+		}
+		_, _ = fmt.Fprintf(&sb, "  %d. %s%s\n", i+1, s.Description, wait)
+	}
+
+	return sb.String()
+}
+
+// Save stores the plan as JSON, so that it can be applied later
+// Secrets are AES-GCM encrypted with encryptionKey (16, 24, or 32 bytes); without a key, they are redacted,
+// and the saved plan can be reviewed but not applied
+func (p *Plan) Save(w io.Writer, encryptionKey []byte) error {
+	protect := func(string) (string, error) {
+		return RedactedSecret, nil
+	}
+	if encryptionKey != nil {
+		protect = func(secret string) (string, error) {
+			return encryptSecret(encryptionKey, secret)
+		}
+	}
+
+	saved := *p
+	saved.Steps = make([]PlanStep, 0, len(p.Steps))
+	for _, step := range p.Steps {
+		edits, err := copyEdits(step.Edits)
+		if err != nil {
+			return err
+		}
+		if err := transformEditSecrets(edits, protect); err != nil {
+			return err
+		}
+		step.Edits = edits
+		saved.Steps = append(saved.Steps, step)
+	}
+
+	encoder := json.NewEncoder(w)
+	encoder.SetEscapeHTML(false)
+	encoder.SetIndent("", "  ")
+	return encoder.Encode(saved)
+}
+
+// LoadPlan loads a plan which was previously saved, decrypting its secrets with encryptionKey
+func LoadPlan(r io.Reader, encryptionKey []byte) (*Plan, error) {
+	decoder := json.NewDecoder(r)
+	decoder.UseNumber()
+
+	var result Plan
+	if err := decoder.Decode(&result); err != nil {
+		return nil, err
+	}
+
+	for _, step := range result.Steps {
+		err := transformEditSecrets(step.Edits, func(secret string) (string, error) {
+			if !strings.HasPrefix(secret, encryptedSecretPrefix) {
+				return secret, nil
+			}
+			if encryptionKey == nil {
+				return "", errors.New("the plan contains encrypted secrets, but no encryption key was specified")
+			}
+			return decryptSecret(encryptionKey, secret)
+		})
+		if err != nil {
+			return nil, err
+		}
+	}
+
+	return &result, nil
+}
+
+// redactedSecrets returns the steps of the plan whose secrets were redacted when it was saved
+func (p *Plan) redactedSecrets() []string {
+	var result []string
+	for i, step := range p.Steps {
+		redacted := false
+		_ = transformEditSecrets(step.Edits, func(secret string) (string, error) {
+			redacted = redacted || secret == RedactedSecret
+			return secret, nil
+		})
+		if redacted {
+			result = append(result, fmt.Sprintf("step %d", i+1))
+		}
+	}
+
+	return result
+}
+
+// ComputePlan retrieves the project's automation config and computes the plan required to reach the desired state
+func ComputePlan(client Client, projectID string, spec DeploymentSpec) (*Plan, error) {
+	live, err := client.GetAutomationConfig(projectID)
+	if err != nil {
+		return nil, err
+	}
+
+	return PlanChanges(projectID, spec, live)
+}
+
+// PlanChanges computes the plan required to bring the live automation config to the desired state
+//
+// The steps are ordered so that no replica set loses quorum:
+//  1. monitoring and backup agents are updated
+//  2. clusters, members, and users are created or updated; members added to existing replica sets start as
+//     non-voting members with priority 0, and arbiters are not added yet
+//  3. each new voting member or arbiter is added to the voting set, and each vote change is made, one per step
+//  4. clusters and users which are no longer desired are deleted
+//  5. members which are no longer desired are removed from their replica set, one per step
+func PlanChanges(projectID string, spec DeploymentSpec, live AutomationConfig) (*Plan, error) {
+	if err := spec.Validate(); err != nil {
+		return nil, err
+	}
+	if live.Version == nil {
+		return nil, errors.New("the live automation config does not have a version")
+	}
+
+	plan := &Plan{ProjectID: projectID, BaseVersion: *live.Version}
+
+	working, err := copyAutomationConfig(live)
+	if err != nil {
+		return nil, err
+	}
+
+	last, err := toDocument(working)
+	if err != nil {
+		return nil, err
+	}
+	// addStep records the changes made to the working config since the previous step as a step, if there are any
+	addStep := func(description string) error {
+		current, err := toDocument(working)
+		if err != nil {
+			return err
+		}
+
+		var edits []DocumentEdit
+		diffDocuments(last, current, nil, nil, &edits)
+		if len(edits) == 0 {
+			return nil
+		}
+		last = current
+
+		plan.Steps = append(plan.Steps, PlanStep{Description: description, Edits: edits, WaitForGoalState: true})
+		return nil
+	}
+
+	if err := planAgents(&working, spec, plan); err != nil {
+		return nil, err
+	}
+	if err := addStep("update monitoring and backup agents"); err != nil {
+		return nil, err
+	}
+
+	votingChanges, err := planCreatesAndUpdates(&working, spec, plan)
+	if err != nil {
+		return nil, err
+	}
+	if err := addStep("create and update clusters, members, and users"); err != nil {
+		return nil, err
+	}
+	for _, m := range votingChanges {
+		m.apply(&working)
+		if err := addStep(m.description); err != nil {
+			return nil, err
+		}
+	}
+
+	removals := planDeletes(&working, spec, plan)
+	if err := addStep("delete clusters and users"); err != nil {
+		return nil, err
+	}
+	for _, m := range removals {
+		m.apply(&working)
+		if err := addStep(m.description); err != nil {
+			return nil, err
+		}
+	}
+
+	return plan, nil
+}
+
+// memberStep a change to a single replica set member which gets a step of its own,
+// so that the voting members of a replica set change by at most one member at a time
+type memberStep struct {
+	description string
+	apply       func(*AutomationConfig)
+}
+
+// ApplyPlan applies a plan, after it was confirmed
+// The plan is rejected with ErrStalePlan if the project's automation config changed since the plan was computed
+// Each step edits the automation config document, keeping the fields which AutomationConfig does not model
+func ApplyPlan(client Client, plan *Plan, opts ApplyOptions) error {
+	if opts.Confirm == nil {
+		return errors.New("a confirmation function is required")
+	}
+	if plan.IsEmpty() {
+		return nil
+	}
+	if redacted := plan.redactedSecrets(); len(redacted) > 0 {
+		return fmt.Errorf("the secrets of %s were redacted when the plan was saved, save it with an encryption key to apply it", strings.Join(redacted, ", "))
+	}
+
+	if err := checkPlanVersion(client, plan); err != nil {
+		return err
+	}
+	if !opts.Confirm(plan) {
+		return ErrPlanNotConfirmed
+	}
+
+	// the version each step expects to replace: the base version, then the version written by the previous step
+	expected := plan.BaseVersion
+	for i, step := range plan.Steps {
+		doc, err := client.GetAutomationConfigDocument(plan.ProjectID)
+		if err != nil {
+			return err
+		}
+		// the confirmation, or the previous step, could have taken a while; never overwrite changes made in the meantime
+		version, ok := documentVersion(doc)
+		if !ok || version != expected {
+			if i == 0 {
+				return staleError(plan, doc)
+			}
+			return fmt.Errorf("step %d (%s): the automation config was modified while the plan was applied: %w", i+1, step.Description, staleError(plan, doc))
+		}
+
+		if err := applyDocumentEdits(doc, step.Edits); err != nil {
+			return fmt.Errorf("step %d (%s) failed: %w", i+1, step.Description, err)
+		}
+		updated, err := client.UpdateAutomationConfigDocument(plan.ProjectID, doc)
+		if err != nil {
+			return fmt.Errorf("step %d (%s) failed: %w", i+1, step.Description, err)
+		}
+		// Ops Manager increments the version of each accepted automation config
+		expected = version + 1
+		if v, ok := documentVersion(updated); ok {
+			expected = v
+		}
+
+		if step.WaitForGoalState {
+			if err := WaitForGoalState(client, plan.ProjectID, opts.PollInterval, opts.Timeout); err != nil {
+				return fmt.Errorf("step %d (%s) did not reach goal state: %w", i+1, step.Description, err)
+			}
+		}
+	}
+
+	return nil
+}
+
+func checkPlanVersion(client Client, plan *Plan) error {
+	doc, err := client.GetAutomationConfigDocument(plan.ProjectID)
+	if err != nil {
+		return err
+	}
+	if version, ok := documentVersion(doc); !ok || version != plan.BaseVersion {
+		return staleError(plan, doc)
+	}
+
+	return nil
+}
+
+func staleError(plan *Plan, doc map[string]interface{}) error {
+	current := "unknown"
+	if version, ok := documentVersion(doc); ok {
+		current = fmt.Sprintf("%d", version)
+	}
+
+	return fmt.Errorf("plan computed against version %d, but project %s is at version %s: %w", plan.BaseVersion, plan.ProjectID, current, ErrStalePlan)
+}
+
+func copyAutomationConfig(config AutomationConfig) (AutomationConfig, error) {
+	var result AutomationConfig
+
+	data, err := json.Marshal(config)
+	if err != nil {
+		return result, err
+	}
+
+	err = json.Unmarshal(data, &result)
+	return result, err
+}
+
+func planAgents(config *AutomationConfig, spec DeploymentSpec, plan *Plan) error {
+	if spec.MonitoringAgents != nil {
+		versions, err := planAgentVersions("monitoringAgent", config.MonitoringVersions, spec.MonitoringAgents, plan)
+		if err != nil {
+			return err
+		}
+		config.MonitoringVersions = versions
+	}
+	if spec.BackupAgents != nil {
+		versions, err := planAgentVersions("backupAgent", config.BackupVersions, spec.BackupAgents, plan)
+		if err != nil {
+			return err
+		}
+		config.BackupVersions = versions
+	}
+
+	return nil
+}
+
+// planAgentVersions returns the desired agents; new agents without a version get the version of the live agents
+func planAgentVersions(kind string, live []*AgentVersion, desired []AgentSpec, plan *Plan) ([]*AgentVersion, error) {
+	defaultVersion := ""
+	for _, v := range live {
+		if v.Name != "" {
+			defaultVersion = v.Name
+			break
+		}
+	}
+
+	result := make([]*AgentVersion, 0, len(desired))
+	for _, a := range desired {
+		var existing *AgentVersion
+		for _, v := range live {
+			if v.Hostname == a.Hostname {
+				existing = v
+			}
+		}
+
+		switch {
+		case existing == nil:
+			version := a.Version
+			if version == "" {
+				version = defaultVersion
+			}
+			if version == "" {
+				return nil, fmt.Errorf("%s %s: a version must be set, since no %s is deployed yet", kind, a.Hostname, kind)
+			}
+			plan.addChange(ActionCreate, kind, a.Hostname, describeVersion(version))
+			result = append(result, &AgentVersion{Hostname: a.Hostname, Name: version})
+		case a.Version != "" && existing.Name != a.Version:
+			plan.addChange(ActionUpdate, kind, a.Hostname, fmt.Sprintf("version: %s -> %s", existing.Name, a.Version))
+			existing.Name = a.Version
+			result = append(result, existing)
+		default:
+			result = append(result, existing)
+		}
+	}
+
+	for _, v := range live {
+		if !containsAgent(desired, v.Hostname) {
+			plan.addChange(ActionDelete, kind, v.Hostname)
+		}
+	}
+
+	return result, nil
+}
+
+// planCreatesAndUpdates creates and updates clusters, members, and users in config,
+// and returns the changes to the voting members of existing replica sets, which must be applied one at a time
+func planCreatesAndUpdates(config *AutomationConfig, spec DeploymentSpec, plan *Plan) ([]memberStep, error) {
+	sharded := shardedReplicaSets(config)
+
+	var votingChanges []memberStep
+	for _, c := range spec.Clusters {
+		if sharded[c.Name] {
+			return nil, fmt.Errorf("cluster %s is part of a sharded cluster and cannot be managed by the spec", c.Name)
+		}
+
+		// the members of a new replica set are all added at once, it has no quorum to lose yet
+		rs, err := findReplicaSet(config, c.Name)
+		created := err != nil
+		if created {
+			plan.addChange(ActionCreate, "replicaSet", c.Name, fmt.Sprintf("members: %d", len(c.Members)), describeVersion(c.Version))
+			config.ReplicaSets = append(config.ReplicaSets, ReplicaSet{ID: c.Name, ProtocolVersion: "1"})
+			rs = &config.ReplicaSets[len(config.ReplicaSets)-1]
+		}
+
+		nextID := nextMemberID(rs)
+		for _, m := range c.Members {
+			process := findMemberProcess(config, c.Name, m.Hostname, m.Port)
+			if process == nil {
+				step, err := createMember(config, rs, c, m, nextID, created, plan)
+				if err != nil {
+					return nil, err
+				}
+				if step != nil {
+					votingChanges = append(votingChanges, *step)
+				}
+				nextID++
+				continue
+			}
+
+			if step := updateMember(rs, process, c, m, plan); step != nil {
+				votingChanges = append(votingChanges, *step)
+			}
+		}
+	}
+
+	if spec.Users != nil {
+		for _, u := range spec.Users {
+			existing := findUser(config, u.Username, u.Database)
+			if existing == nil {
+				plan.addChange(ActionCreate, "user", u.Username+"@"+u.Database, describeRoles(u.Roles))
+				config.Auth.UsersWanted = append(config.Auth.UsersWanted, UserWanted{DB: u.Database, Roles: u.Roles, User: u.Username, InitPwd: u.Password})
+				continue
+			}
+
+			if !sameRoles(existing.Roles, u.Roles) {
+				plan.addChange(ActionUpdate, "user", u.Username+"@"+u.Database, fmt.Sprintf("roles: %s -> %s", describeRoles(existing.Roles), describeRoles(u.Roles)))
+				existing.Roles = u.Roles
+			}
+		}
+	}
+
+	return votingChanges, nil
+}
+
+// memberVotes returns the desired votes and priority of a member; arbiters always vote and can never become primary
+func memberVotes(m MemberSpec) (votes float64, priority float64) {
+	votes, priority = 1, 1
+	if m.ArbiterOnly {
+		priority = 0
+	}
+	if m.Priority != nil {
+		priority = *m.Priority
+	}
+	if m.Votes != nil {
+		votes = *m.Votes
+	}
+
+	return votes, priority
+}
+
+// createMember adds a member and its process to config. In a new replica set, the member is added as desired.
+// In an existing replica set, a voting member is added without votes and promoted by the returned step,
+// and an arbiter, which cannot be added without a vote, is only added by the returned step.
+func createMember(config *AutomationConfig, rs *ReplicaSet, c ClusterSpec, m MemberSpec, id int, newReplicaSet bool, plan *Plan) (*memberStep, error) {
+	name := fmt.Sprintf("%s_%d", c.Name, id)
+	if _, err := findProcess(config, name); err == nil {
+		return nil, fmt.Errorf("cannot add %s:%d to %s, a process named %s already exists", m.Hostname, m.Port, c.Name, name)
+	}
+
+	logPath := m.LogPath
+	if logPath == "" {
+		logPath = strings.TrimSuffix(m.DBPath, "/") + "/mongodb.log"
+	}
+
+	process := &Process{
+		Name:                        name,
+		ProcessType:                 "mongod",
+		Version:                     c.Version,
+		AuthSchemaVersion:           5,
+		FeatureCompatibilityVersion: c.FeatureCompatibilityVersion,
+		Hostname:                    m.Hostname,
+		Args26: &Args26{
+			NET:         &Net{Port: m.Port},
+			Storage:     &StorageArg{DBPath: m.DBPath},
+			SystemLog:   &SystemLog{Destination: "file", Path: logPath},
+			Replication: &ReplicationArg{ReplSetName: c.Name},
+		},
+		LogRotate: &LogRotate{SizeThresholdMB: 1000, TimeThresholdHrs: 24},
+	}
+
+	votes, priority := memberVotes(m)
+	member := Member{ID: id, Host: name, ArbiterOnly: m.ArbiterOnly, Hidden: m.Hidden, Priority: priority, Votes: votes}
+	details := []string{fmt.Sprintf("host: %s:%d", m.Hostname, m.Port), describeVersion(c.Version)}
+
+	var step *memberStep
+	switch {
+	case newReplicaSet || votes == 0:
+		config.Processes = append(config.Processes, process)
+		rs.Members = append(rs.Members, member)
+	case m.ArbiterOnly:
+		details = append(details, "added in a step of its own")
+		step = &memberStep{
+			description: fmt.Sprintf("add arbiter %s to %s", name, c.Name),
+			apply: func(config *AutomationConfig) {
+				rs, err := findReplicaSet(config, c.Name)
+				if err != nil {
+					return
+				}
+				config.Processes = append(config.Processes, process)
+				rs.Members = append(rs.Members, member)
+			},
+		}
+	default:
+		details = append(details, "added as a non-voting member, then promoted")
+		config.Processes = append(config.Processes, process)
+		nonVoting := member
+		nonVoting.Votes, nonVoting.Priority = 0, 0
+		rs.Members = append(rs.Members, nonVoting)
+		step = setVotesStep(fmt.Sprintf("promote %s to a voting member of %s", name, c.Name), c.Name, name, votes, priority)
+	}
+
+	plan.addChange(ActionCreate, "process", name, details...)
+	return step, nil
+}
+
+// setVotesStep returns a step which changes the votes and priority of a member
+func setVotesStep(description string, replicaSetName string, processName string, votes float64, priority float64) *memberStep {
+	return &memberStep{
+		description: description,
+		apply: func(config *AutomationConfig) {
+			rs, err := findReplicaSet(config, replicaSetName)
+			if err != nil {
+				return
+			}
+			for i := range rs.Members {
+				if rs.Members[i].Host == processName {
+					rs.Members[i].Votes = votes
+					rs.Members[i].Priority = priority
+				}
+			}
+		},
+	}
+}
+
+// updateMember updates a member and its process in place, except for a change of votes,
+// which is returned as a step of its own along with any priority change
+func updateMember(rs *ReplicaSet, process *Process, c ClusterSpec, m MemberSpec, plan *Plan) *memberStep {
+	var (
+		details []string
+		step    *memberStep
+	)
+
+	if process.Version != c.Version {
+		details = append(details, fmt.Sprintf("version: %s -> %s", process.Version, c.Version))
+		process.Version = c.Version
+	}
+	if c.FeatureCompatibilityVersion != "" && process.FeatureCompatibilityVersion != c.FeatureCompatibilityVersion {
+		details = append(details, fmt.Sprintf("featureCompatibilityVersion: %s -> %s", process.FeatureCompatibilityVersion, c.FeatureCompatibilityVersion))
+		process.FeatureCompatibilityVersion = c.FeatureCompatibilityVersion
+	}
+	if m.LogPath != "" && process.Args26.SystemLog != nil && process.Args26.SystemLog.Path != m.LogPath {
+		details = append(details, fmt.Sprintf("logPath: %s -> %s", process.Args26.SystemLog.Path, m.LogPath))
+		process.Args26.SystemLog.Path = m.LogPath
+	}
+
+	for i := range rs.Members {
+		member := &rs.Members[i]
+		if member.Host != process.Name {
+			continue
+		}
+
+		if member.Hidden != m.Hidden {
+			details = append(details, fmt.Sprintf("hidden: %t -> %t", member.Hidden, m.Hidden))
+			member.Hidden = m.Hidden
+		}
+		if member.ArbiterOnly != m.ArbiterOnly {
+			details = append(details, fmt.Sprintf("arbiterOnly: %t -> %t", member.ArbiterOnly, m.ArbiterOnly))
+			member.ArbiterOnly = m.ArbiterOnly
+		}
+
+		priority := member.Priority
+		if m.Priority != nil && member.Priority != *m.Priority {
+			details = append(details, fmt.Sprintf("priority: %v -> %v", member.Priority, *m.Priority))
+			priority = *m.Priority
+		}
+		if m.Votes != nil && member.Votes != *m.Votes {
+			details = append(details, fmt.Sprintf("votes: %v -> %v", member.Votes, *m.Votes))
+			step = setVotesStep(fmt.Sprintf("change the votes of %s in %s to %v", process.Name, c.Name, *m.Votes), c.Name, process.Name, *m.Votes, priority)
+		} else {
+			member.Priority = priority
+		}
+	}
+
+	if len(details) > 0 {
+		plan.addChange(ActionUpdate, "process", process.Name, details...)
+	}
+	return step
+}
+
+// planDeletes deletes the clusters and users which are no longer desired from config,
+// and returns the removals of members of the remaining replica sets, which must be applied one at a time
+func planDeletes(config *AutomationConfig, spec DeploymentSpec, plan *Plan) []memberStep {
+	var removals []memberStep
+
+	if spec.Clusters != nil {
+		sharded := shardedReplicaSets(config)
+
+		desired := make(map[string]ClusterSpec)
+		for _, c := range spec.Clusters {
+			desired[c.Name] = c
+		}
+
+		removed := make(map[string]bool)
+		replicaSets := make([]ReplicaSet, 0, len(config.ReplicaSets))
+		for _, rs := range config.ReplicaSets {
+			c, ok := desired[rs.ID]
+			if !ok && !sharded[rs.ID] {
+				plan.addChange(ActionDelete, "replicaSet", rs.ID)
+				for _, m := range rs.Members {
+					plan.addChange(ActionDelete, "process", m.Host)
+					removed[m.Host] = true
+				}
+				continue
+			}
+
+			if ok {
+				for _, m := range rs.Members {
+					if process, err := findProcess(config, m.Host); err == nil && !containsMember(c, process) {
+						plan.addChange(ActionDelete, "process", m.Host, fmt.Sprintf("host: %s:%d", process.Hostname, processPort(process)))
+						removals = append(removals, removeMemberStep(rs.ID, m.Host))
+					}
+				}
+			}
+			replicaSets = append(replicaSets, rs)
+		}
+		config.ReplicaSets = replicaSets
+		config.Processes = withoutProcesses(config.Processes, removed)
+	}
+
+	if spec.Users != nil {
+		users := make([]UserWanted, 0, len(config.Auth.UsersWanted))
+		for _, u := range config.Auth.UsersWanted {
+			// never remove the user which the automation agent authenticates with
+			if u.User == config.Auth.AutoUser || containsUser(spec.Users, u.User, u.DB) {
+				users = append(users, u)
+				continue
+			}
+
+			plan.addChange(ActionDelete, "user", u.User+"@"+u.DB)
+			config.Auth.UsersDeleted = append(config.Auth.UsersDeleted, map[string]interface{}{"user": u.User, "dbs": []string{u.DB}})
+		}
+		config.Auth.UsersWanted = users
+	}
+
+	return removals
+}
+
+// removeMemberStep returns a step which removes a member from its replica set, along with its process
+func removeMemberStep(replicaSetName string, processName string) memberStep {
+	return memberStep{
+		description: fmt.Sprintf("remove %s from %s", processName, replicaSetName),
+		apply: func(config *AutomationConfig) {
+			if rs, err := findReplicaSet(config, replicaSetName); err == nil {
+				members := make([]Member, 0, len(rs.Members))
+				for _, m := range rs.Members {
+					if m.Host != processName {
+						members = append(members, m)
+					}
+				}
+				rs.Members = members
+			}
+			config.Processes = withoutProcesses(config.Processes, map[string]bool{processName: true})
+		},
+	}
+}
+
+func withoutProcesses(processes []*Process, removed map[string]bool) []*Process {
+	result := make([]*Process, 0, len(processes))
+	for _, p := range processes {
+		if !removed[p.Name] {
+			result = append(result, p)
+		}
+	}
+
+	return result
+}
+
+func (p *Plan) addChange(action ChangeAction, kind string, name string, details ...string) {
+	var d []string
+	for _, detail := range details {
+		if detail != "" {
+			d = append(d, detail)
+		}
+	}
+
+	p.Changes = append(p.Changes, Change{Action: action, Kind: kind, Name: name, Details: d})
+}
+
+// shardedReplicaSets returns the names of all replica sets which are part of a sharded cluster
+func shardedReplicaSets(config *AutomationConfig) map[string]bool {
+	result := make(map[string]bool)
+	for _, s := range config.Sharding {
+		if s.ConfigServerReplica != "" {
+			result[s.ConfigServerReplica] = true
+		}
+		for _, shard := range s.Shards {
+			result[shard.Rs] = true
+		}
+	}
+
+	return result
+}
+
+func findMemberProcess(config *AutomationConfig, replicaSetName string, hostname string, port int) *Process {
+	for _, p := range config.Processes {
+		if p.Args26 == nil || p.Args26.Replication == nil || p.Args26.Replication.ReplSetName != replicaSetName {
+			continue
+		}
+		if p.Hostname == hostname && processPort(p) == port {
+			return p
+		}
+	}
+
+	return nil
+}
+
+func containsMember(c ClusterSpec, process *Process) bool {
+	for _, m := range c.Members {
+		if m.Hostname == process.Hostname && m.Port == processPort(process) {
+			return true
+		}
+	}
+
+	return false
+}
+
+func findUser(config *AutomationConfig, username string, db string) *UserWanted {
+	for i := range config.Auth.UsersWanted {
+		if u := &config.Auth.UsersWanted[i]; u.User == username && u.DB == db {
+			return u
+		}
+	}
+
+	return nil
+}
+
+func containsUser(users []UserSpec, username string, db string) bool {
+	for _, u := range users {
+		if u.Username == username && u.Database == db {
+			return true
+		}
+	}
+
+	return false
+}
+
+func containsAgent(agents []AgentSpec, hostname string) bool {
+	for _, a := range agents {
+		if a.Hostname == hostname {
+			return true
+		}
+	}
+
+	return false
+}
+
+func sameRoles(a []Role, b []Role) bool {
+	if len(a) != len(b) {
+		return false
+	}
+
+	set := make(map[Role]int)
+	for _, r := range a {
+		set[r]++
+	}
+	for _, r := range b {
+		set[r]--
+	}
+	for _, count := range set {
+		if count != 0 {
+			return false
+		}
+	}
+
+	return true
+}
+
+func describeRoles(roles []Role) string {
+	names := make([]string, 0, len(roles))
+	for _, r := range roles {
+		names = append(names, r.Role+"@"+r.DB)
+	}
+
+	return "[" + strings.Join(names, ", ") + "]"
+}
+
+func describeVersion(version string) string {
+	if version == "" {
+		return ""
+	}
+
+	return "version: " + version
+}
@@ -0,0 +1,598 @@
+// Copyright 2020 MongoDB Inc
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package opsmanager
+
+import (
+	"bytes"
+	"errors"
+	"fmt"
+	"reflect"
+	"sort"
+	"strings"
+	"testing"
+	"time"
+)
+
+// liveConfig returns version 5 of an automation config with a three members replica set, rs,
+// whose processes rs_0, rs_1, rs_2 run on h0:27017, h1:27017, h2:27017, and a monitoring agent on h0
+func liveConfig() AutomationConfig {
+	var config AutomationConfig
+	decodeJSON(`{
+		"version": 5,
+		"auth": {"autoUser": "mms-automation", "usersWanted": [
+			{"user": "mms-automation", "db": "admin", "roles": []},
+			{"user": "app", "db": "admin", "roles": [{"role": "readWrite", "db": "app"}]}
+		]},
+		"monitoringVersions": [{"hostname": "h0", "name": "6.4.0.433-1"}],
+		"processes": [
+			{"name": "rs_0", "processType": "mongod", "version": "4.2.8", "hostname": "h0",
+			 "args2_6": {"net": {"port": 27017}, "replication": {"replSetName": "rs"}, "storage": {"dbPath": "/data"}, "systemLog": {"destination": "file", "path": "/data/mongodb.log"}}},
+			{"name": "rs_1", "processType": "mongod", "version": "4.2.8", "hostname": "h1",
+			 "args2_6": {"net": {"port": 27017}, "replication": {"replSetName": "rs"}, "storage": {"dbPath": "/data"}, "systemLog": {"destination": "file", "path": "/data/mongodb.log"}}},
+			{"name": "rs_2", "processType": "mongod", "version": "4.2.8", "hostname": "h2",
+			 "args2_6": {"net": {"port": 27017}, "replication": {"replSetName": "rs"}, "storage": {"dbPath": "/data"}, "systemLog": {"destination": "file", "path": "/data/mongodb.log"}}}
+		],
+		"replicaSets": [{"_id": "rs", "protocolVersion": "1", "members": [
+			{"_id": 0, "host": "rs_0", "priority": 1, "votes": 1},
+			{"_id": 1, "host": "rs_1", "priority": 1, "votes": 1},
+			{"_id": 2, "host": "rs_2", "priority": 1, "votes": 1}
+		]}]
+	}`, &config)
+	return config
+}
+
+// clusterSpec returns the spec of a 4.2.8 replica set with a member on port 27017 of each host
+func clusterSpec(name string, hosts ...string) ClusterSpec {
+	c := ClusterSpec{Name: name, Version: "4.2.8"}
+	for _, h := range hosts {
+		c.Members = append(c.Members, MemberSpec{Hostname: h, Port: 27017, DBPath: "/data"})
+	}
+	return c
+}
+
+func float64Ptr(f float64) *float64 {
+	return &f
+}
+
+// votingMembers returns the processes which vote in the given replica set
+func votingMembers(config *AutomationConfig, replicaSetName string) map[string]bool {
+	result := map[string]bool{}
+	if rs, err := findReplicaSet(config, replicaSetName); err == nil {
+		for _, m := range rs.Members {
+			if m.Votes > 0 {
+				result[m.Host] = true
+			}
+		}
+	}
+	return result
+}
+
+// stepConfigs returns the automation config resulting from each step of the plan, when applied to live
+func stepConfigs(t *testing.T, live AutomationConfig, plan *Plan) []*AutomationConfig {
+	t.Helper()
+
+	doc := mustDocument(live)
+	result := make([]*AutomationConfig, 0, len(plan.Steps))
+	for i, step := range plan.Steps {
+		if err := applyDocumentEdits(doc, step.Edits); err != nil {
+			t.Fatalf("step %d (%s): %v", i+1, step.Description, err)
+		}
+		config := mustConfig(doc)
+		result = append(result, &config)
+	}
+	return result
+}
+
+// checkQuorumSafe fails the test if any step adds or removes more than one voting member of an existing replica set
+func checkQuorumSafe(t *testing.T, live AutomationConfig, plan *Plan) {
+	t.Helper()
+
+	previous := live
+	for i, config := range stepConfigs(t, live, plan) {
+		step := plan.Steps[i]
+		for _, rs := range previous.ReplicaSets {
+			before := votingMembers(&previous, rs.ID)
+			after := votingMembers(config, rs.ID)
+			if len(after) == 0 {
+				// the replica set was deleted
+				continue
+			}
+
+			changed := 0
+			for host := range before {
+				if !after[host] {
+					changed++
+				}
+			}
+			for host := range after {
+				if !before[host] {
+					changed++
+				}
+			}
+			if changed > 1 {
+				t.Errorf("step %d (%s) changes %d voting members of %s at once", i+1, step.Description, changed, rs.ID)
+			}
+		}
+		previous = *config
+	}
+}
+
+func findTestMember(t *testing.T, config *AutomationConfig, replicaSetName string, processName string) Member {
+	t.Helper()
+
+	rs, err := findReplicaSet(config, replicaSetName)
+	if err != nil {
+		t.Fatal(err)
+	}
+	member, err := findMember(rs, processName)
+	if err != nil {
+		t.Fatal(err)
+	}
+	return member
+}
+
+func stepDescriptions(plan *Plan) []string {
+	result := make([]string, 0, len(plan.Steps))
+	for _, s := range plan.Steps {
+		result = append(result, s.Description)
+	}
+	return result
+}
+
+func TestPlanChanges(t *testing.T) {
+	tests := []struct {
+		name  string
+		spec  DeploymentSpec
+		steps []string
+		check func(t *testing.T, configs []*AutomationConfig)
+	}{
+		{
+			name:  "already in the desired state",
+			spec:  DeploymentSpec{Clusters: []ClusterSpec{clusterSpec("rs", "h0", "h1", "h2")}},
+			steps: []string{},
+		},
+		{
+			name:  "unmanaged sections are left untouched",
+			spec:  DeploymentSpec{},
+			steps: []string{},
+		},
+		{
+			name:  "version upgrade",
+			spec:  DeploymentSpec{Clusters: []ClusterSpec{{Name: "rs", Version: "4.4.1", Members: clusterSpec("rs", "h0", "h1", "h2").Members}}},
+			steps: []string{"create and update clusters, members, and users"},
+			check: func(t *testing.T, configs []*AutomationConfig) {
+				for _, p := range configs[0].Processes {
+					if p.Version != "4.4.1" {
+						t.Errorf("expected %s to be upgraded, got %s", p.Name, p.Version)
+					}
+				}
+			},
+		},
+		{
+			name:  "new replica set members are all added at once",
+			spec:  DeploymentSpec{Clusters: []ClusterSpec{clusterSpec("rs", "h0", "h1", "h2"), clusterSpec("other", "h3", "h4", "h5")}},
+			steps: []string{"create and update clusters, members, and users"},
+			check: func(t *testing.T, configs []*AutomationConfig) {
+				if voting := votingMembers(configs[0], "other"); len(voting) != 3 {
+					t.Errorf("expected 3 voting members, got %v", voting)
+				}
+			},
+		},
+		{
+			name: "members are added without votes, then promoted one at a time",
+			spec: DeploymentSpec{Clusters: []ClusterSpec{clusterSpec("rs", "h0", "h1", "h2", "h3", "h4")}},
+			steps: []string{
+				"create and update clusters, members, and users",
+				"promote rs_3 to a voting member of rs",
+				"promote rs_4 to a voting member of rs",
+			},
+			check: func(t *testing.T, configs []*AutomationConfig) {
+				added := findTestMember(t, configs[0], "rs", "rs_3")
+				if added.Votes != 0 || added.Priority != 0 {
+					t.Errorf("expected rs_3 to be added without votes and priority, got %+v", added)
+				}
+				promoted := findTestMember(t, configs[2], "rs", "rs_4")
+				if promoted.Votes != 1 || promoted.Priority != 1 {
+					t.Errorf("expected rs_4 to be promoted, got %+v", promoted)
+				}
+			},
+		},
+		{
+			name: "non-voting members need no promotion",
+			spec: DeploymentSpec{Clusters: []ClusterSpec{{Name: "rs", Version: "4.2.8", Members: append(clusterSpec("rs", "h0", "h1", "h2").Members,
+				MemberSpec{Hostname: "h3", Port: 27017, DBPath: "/data", Votes: float64Ptr(0), Priority: float64Ptr(0), Hidden: true})}}},
+			steps: []string{"create and update clusters, members, and users"},
+		},
+		{
+			name: "arbiters are added in a step of their own",
+			spec: DeploymentSpec{Clusters: []ClusterSpec{{Name: "rs", Version: "4.2.8", Members: append(clusterSpec("rs", "h0", "h1", "h2").Members,
+				MemberSpec{Hostname: "h3", Port: 27017, DBPath: "/data", ArbiterOnly: true})}}},
+			steps: []string{"add arbiter rs_3 to rs"},
+			check: func(t *testing.T, configs []*AutomationConfig) {
+				arbiter := findTestMember(t, configs[0], "rs", "rs_3")
+				if !arbiter.ArbiterOnly || arbiter.Votes != 1 || arbiter.Priority != 0 {
+					t.Errorf("unexpected arbiter %+v", arbiter)
+				}
+				if _, err := findProcess(configs[0], "rs_3"); err != nil {
+					t.Error(err)
+				}
+			},
+		},
+		{
+			name: "vote changes get a step of their own",
+			spec: DeploymentSpec{Clusters: []ClusterSpec{{Name: "rs", Version: "4.2.8", Members: []MemberSpec{
+				{Hostname: "h0", Port: 27017, DBPath: "/data"},
+				{Hostname: "h1", Port: 27017, DBPath: "/data", Priority: float64Ptr(2)},
+				{Hostname: "h2", Port: 27017, DBPath: "/data", Votes: float64Ptr(0), Priority: float64Ptr(0)},
+			}}}},
+			steps: []string{
+				"create and update clusters, members, and users",
+				"change the votes of rs_2 in rs to 0",
+			},
+			check: func(t *testing.T, configs []*AutomationConfig) {
+				if m := findTestMember(t, configs[0], "rs", "rs_1"); m.Priority != 2 {
+					t.Errorf("expected the priority of rs_1 to be updated in place, got %+v", m)
+				}
+				if m := findTestMember(t, configs[0], "rs", "rs_2"); m.Votes != 1 || m.Priority != 1 {
+					t.Errorf("expected rs_2 to keep voting until its own step, got %+v", m)
+				}
+				if m := findTestMember(t, configs[1], "rs", "rs_2"); m.Votes != 0 || m.Priority != 0 {
+					t.Errorf("expected rs_2 to stop voting, got %+v", m)
+				}
+			},
+		},
+		{
+			name: "members are removed one at a time",
+			spec: DeploymentSpec{Clusters: []ClusterSpec{clusterSpec("rs", "h0")}},
+			steps: []string{
+				"remove rs_1 from rs",
+				"remove rs_2 from rs",
+			},
+			check: func(t *testing.T, configs []*AutomationConfig) {
+				last := configs[1]
+				if len(last.Processes) != 1 || last.Processes[0].Name != "rs_0" {
+					t.Errorf("expected only rs_0 to remain, got %d processes", len(last.Processes))
+				}
+			},
+		},
+		{
+			name:  "replica sets are deleted at once",
+			spec:  DeploymentSpec{Clusters: []ClusterSpec{}},
+			steps: []string{"delete clusters and users"},
+			check: func(t *testing.T, configs []*AutomationConfig) {
+				if config := configs[0]; len(config.ReplicaSets) != 0 || len(config.Processes) != 0 {
+					t.Errorf("expected no replica sets and processes, got %+v", config)
+				}
+			},
+		},
+		{
+			name: "users are created, updated, and deleted, but never the automation user",
+			spec: DeploymentSpec{Users: []UserSpec{{Username: "reporting", Database: "admin", Password: "secret", Roles: []Role{{Role: "read", DB: "app"}}}}},
+			steps: []string{
+				"create and update clusters, members, and users",
+				"delete clusters and users",
+			},
+			check: func(t *testing.T, configs []*AutomationConfig) {
+				users := configs[1].Auth.UsersWanted
+				if len(users) != 2 || users[0].User != "mms-automation" || users[1].User != "reporting" {
+					t.Errorf("unexpected users %+v", users)
+				}
+			},
+		},
+		{
+			name:  "new agents default to the version of the live agents",
+			spec:  DeploymentSpec{MonitoringAgents: []AgentSpec{{Hostname: "h0"}, {Hostname: "h1"}}},
+			steps: []string{"update monitoring and backup agents"},
+			check: func(t *testing.T, configs []*AutomationConfig) {
+				versions := configs[0].MonitoringVersions
+				if len(versions) != 2 || versions[1].Hostname != "h1" || versions[1].Name != "6.4.0.433-1" {
+					t.Errorf("unexpected monitoring agents %+v", versions)
+				}
+			},
+		},
+	}
+
+	for _, tc := range tests {
+		tc := tc
+		t.Run(tc.name, func(t *testing.T) {
+			live := liveConfig()
+
+			plan, err := PlanChanges("5a0a1e7e0f2912c554080adc", tc.spec, live)
+			if err != nil {
+				t.Fatalf("PlanChanges returned error: %v", err)
+			}
+
+			if got := stepDescriptions(plan); !reflect.DeepEqual(got, tc.steps) {
+				t.Errorf("expected steps %q, got %q", tc.steps, got)
+			}
+			if plan.BaseVersion != 5 {
+				t.Errorf("expected base version 5, got %d", plan.BaseVersion)
+			}
+			for i, step := range plan.Steps {
+				for _, edit := range step.Edits {
+					if edit.Path[0] == "version" {
+						t.Errorf("step %d should not edit the version, it is set when the plan is applied", i+1)
+					}
+				}
+			}
+			checkQuorumSafe(t, live, plan)
+
+			if tc.check != nil && len(plan.Steps) == len(tc.steps) {
+				tc.check(t, stepConfigs(t, live, plan))
+			}
+		})
+	}
+}
+
+func TestPlanChanges_errors(t *testing.T) {
+	sharded := liveConfig()
+	sharded.Sharding = []Sharding{{Name: "sharded", Shards: []Shard{{ID: "shard0", Rs: "rs"}}}}
+
+	noAgents := liveConfig()
+	noAgents.MonitoringVersions = nil
+
+	noVersion := liveConfig()
+	noVersion.Version = nil
+
+	tests := []struct {
+		name string
+		live AutomationConfig
+		spec DeploymentSpec
+	}{
+		{name: "sharded replica set", live: sharded, spec: DeploymentSpec{Clusters: []ClusterSpec{clusterSpec("rs", "h0")}}},
+		{name: "new agent without a version", live: noAgents, spec: DeploymentSpec{MonitoringAgents: []AgentSpec{{Hostname: "h0"}}}},
+		{name: "live config without a version", live: noVersion, spec: DeploymentSpec{}},
+		{name: "invalid spec", live: liveConfig(), spec: DeploymentSpec{Clusters: []ClusterSpec{{Name: "rs"}}}},
+	}
+
+	for _, tc := range tests {
+		tc := tc
+		t.Run(tc.name, func(t *testing.T) {
+			if _, err := PlanChanges("5a0a1e7e0f2912c554080adc", tc.spec, tc.live); err == nil {
+				t.Error("expected an error")
+			}
+		})
+	}
+}
+
+func TestPlanChanges_changes(t *testing.T) {
+	spec := DeploymentSpec{Clusters: []ClusterSpec{clusterSpec("rs", "h0", "h1", "h3")}}
+
+	plan, err := PlanChanges("5a0a1e7e0f2912c554080adc", spec, liveConfig())
+	if err != nil {
+		t.Fatalf("PlanChanges returned error: %v", err)
+	}
+
+	var got []string
+	for _, c := range plan.Changes {
+		got = append(got, fmt.Sprintf("%s %s %s", c.Action, c.Kind, c.Name))
+	}
+	sort.Strings(got)
+
+	expected := []string{"create process rs_3", "delete process rs_2"}
+	if !reflect.DeepEqual(got, expected) {
+		t.Errorf("expected changes %q, got %q", expected, got)
+	}
+}
+
+func TestPlan_SaveAndLoad(t *testing.T) {
+	spec := DeploymentSpec{
+		Clusters: []ClusterSpec{clusterSpec("rs", "h0", "h1", "h2", "h3")},
+		Users:    []UserSpec{{Username: "app", Database: "admin", Roles: []Role{{Role: "readWrite", DB: "app"}}}, {Username: "reporting", Database: "admin", Password: "s3cr3t", Roles: []Role{{Role: "read", DB: "app"}}}},
+	}
+	live := liveConfig()
+	plan, err := PlanChanges("5a0a1e7e0f2912c554080adc", spec, live)
+	if err != nil {
+		t.Fatalf("PlanChanges returned error: %v", err)
+	}
+	key := []byte("0123456789abcdef")
+
+	var buf bytes.Buffer
+	if err := plan.Save(&buf, key); err != nil {
+		t.Fatalf("Save returned error: %v", err)
+	}
+	if strings.Contains(buf.String(), "s3cr3t") {
+		t.Errorf("expected the saved plan not to contain the password, got %s", buf.String())
+	}
+	loaded, err := LoadPlan(&buf, key)
+	if err != nil {
+		t.Fatalf("LoadPlan returned error: %v", err)
+	}
+
+	if !reflect.DeepEqual(stepDescriptions(loaded), stepDescriptions(plan)) || loaded.BaseVersion != plan.BaseVersion {
+		t.Errorf("expected %+v, got %+v", plan, loaded)
+	}
+	configs, loadedConfigs := stepConfigs(t, live, plan), stepConfigs(t, live, loaded)
+	if !reflect.DeepEqual(loadedConfigs, configs) {
+		t.Errorf("expected the loaded plan to make the same changes, got %+v", loadedConfigs)
+	}
+	if user := findUser(loadedConfigs[0], "reporting", "admin"); user == nil || user.InitPwd != "s3cr3t" {
+		t.Errorf("expected the password to be decrypted, got %+v", user)
+	}
+}
+
+func TestPlan_SaveRedacted(t *testing.T) {
+	spec := DeploymentSpec{Users: []UserSpec{{Username: "reporting", Database: "admin", Password: "s3cr3t", Roles: []Role{{Role: "read", DB: "app"}}}}}
+	client := newFakeClient(liveConfig())
+	plan, err := ComputePlan(client, "5a0a1e7e0f2912c554080adc", spec)
+	if err != nil {
+		t.Fatalf("ComputePlan returned error: %v", err)
+	}
+
+	var buf bytes.Buffer
+	if err := plan.Save(&buf, nil); err != nil {
+		t.Fatalf("Save returned error: %v", err)
+	}
+	if strings.Contains(buf.String(), "s3cr3t") || !strings.Contains(buf.String(), RedactedSecret) {
+		t.Errorf("expected the password to be redacted, got %s", buf.String())
+	}
+	if user := findUser(stepConfigs(t, liveConfig(), plan)[0], "reporting", "admin"); user == nil || user.InitPwd != "s3cr3t" {
+		t.Errorf("expected Save to leave the plan unchanged, got %+v", user)
+	}
+
+	loaded, err := LoadPlan(&buf, nil)
+	if err != nil {
+		t.Fatalf("LoadPlan returned error: %v", err)
+	}
+	if err := ApplyPlan(client, loaded, applyOptions()); err == nil {
+		t.Error("expected a plan with redacted secrets to be rejected")
+	}
+	if len(client.updates) != 0 {
+		t.Errorf("expected no updates, got %d", len(client.updates))
+	}
+}
+
+func applyOptions() ApplyOptions {
+	return ApplyOptions{Confirm: AutoApprove, PollInterval: time.Millisecond, Timeout: time.Second}
+}
+
+func TestApplyPlan(t *testing.T) {
+	client := newFakeClient(liveConfig())
+
+	plan, err := ComputePlan(client, "5a0a1e7e0f2912c554080adc", DeploymentSpec{Clusters: []ClusterSpec{clusterSpec("rs", "h0", "h1", "h2", "h3")}})
+	if err != nil {
+		t.Fatalf("ComputePlan returned error: %v", err)
+	}
+
+	if err := ApplyPlan(client, plan, applyOptions()); err != nil {
+		t.Fatalf("ApplyPlan returned error: %v", err)
+	}
+
+	if len(client.updates) != 2 {
+		t.Fatalf("expected 2 updates, got %d", len(client.updates))
+	}
+	for i, update := range client.updates {
+		if *update.Version != 6+i {
+			t.Errorf("expected update %d to be version %d, got %d", i+1, 6+i, *update.Version)
+		}
+	}
+	if m := findTestMember(t, &client.updates[1], "rs", "rs_3"); m.Votes != 1 {
+		t.Errorf("expected rs_3 to be promoted, got %+v", m)
+	}
+}
+
+func TestApplyPlan_keepsUnmodeledFields(t *testing.T) {
+	client := newFakeClient(liveConfig())
+
+	rs := client.doc["replicaSets"].([]interface{})[0].(map[string]interface{})
+	rs["settings"] = map[string]interface{}{"chainingAllowed": false}
+	member := rs["members"].([]interface{})[0].(map[string]interface{})
+	member["tags"] = map[string]interface{}{"dc": "east"}
+	process := client.doc["processes"].([]interface{})[0].(map[string]interface{})
+	process["horizons"] = map[string]interface{}{"external": "rs0.example.com:27017"}
+	client.doc["ldap"] = map[string]interface{}{"servers": "ldap.example.com"}
+
+	spec := DeploymentSpec{Clusters: []ClusterSpec{{Name: "rs", Version: "4.4.1", Members: clusterSpec("rs", "h0", "h1", "h3").Members}}}
+	plan, err := ComputePlan(client, "5a0a1e7e0f2912c554080adc", spec)
+	if err != nil {
+		t.Fatalf("ComputePlan returned error: %v", err)
+	}
+	if err := ApplyPlan(client, plan, applyOptions()); err != nil {
+		t.Fatalf("ApplyPlan returned error: %v", err)
+	}
+
+	doc := client.doc
+	if doc["ldap"] == nil {
+		t.Error("expected the ldap settings to be kept")
+	}
+	rs, err = findDocumentElement(doc, "replicaSets", "_id", "rs")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if rs["settings"] == nil {
+		t.Errorf("expected the replica set settings to be kept, got %v", rs)
+	}
+	if member, err := findDocumentElement(rs, "members", "host", "rs_0"); err != nil || member["tags"] == nil {
+		t.Errorf("expected the tags of rs_0 to be kept, got %v", member)
+	}
+	process, err = findDocumentElement(doc, "processes", "name", "rs_0")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if process["horizons"] == nil || process["version"] != "4.4.1" {
+		t.Errorf("expected rs_0 to be upgraded and keep its horizons, got %v", process)
+	}
+	if _, err := findDocumentElement(doc, "processes", "name", "rs_2"); err == nil {
+		t.Error("expected rs_2 to be removed")
+	}
+}
+
+func TestApplyPlan_stale(t *testing.T) {
+	client := newFakeClient(liveConfig())
+
+	plan, err := ComputePlan(client, "5a0a1e7e0f2912c554080adc", DeploymentSpec{Clusters: []ClusterSpec{clusterSpec("rs", "h0")}})
+	if err != nil {
+		t.Fatalf("ComputePlan returned error: %v", err)
+	}
+	client.bumpVersion()
+
+	confirmed := false
+	opts := applyOptions()
+	opts.Confirm = func(*Plan) bool {
+		confirmed = true
+		return true
+	}
+
+	if err := ApplyPlan(client, plan, opts); !errors.Is(err, ErrStalePlan) {
+		t.Fatalf("expected ErrStalePlan, got %v", err)
+	}
+	if confirmed || len(client.updates) != 0 {
+		t.Errorf("expected nothing to be confirmed or applied, got %d updates", len(client.updates))
+	}
+}
+
+func TestApplyPlan_modifiedDuringApply(t *testing.T) {
+	client := newFakeClient(liveConfig())
+
+	plan, err := ComputePlan(client, "5a0a1e7e0f2912c554080adc", DeploymentSpec{Clusters: []ClusterSpec{clusterSpec("rs", "h0")}})
+	if err != nil {
+		t.Fatalf("ComputePlan returned error: %v", err)
+	}
+	if len(plan.Steps) != 2 {
+		t.Fatalf("expected 2 steps, got %q", stepDescriptions(plan))
+	}
+
+	// someone else edits the config while the first step is applied
+	client.afterUpdate = func(c *fakeClient) {
+		c.afterUpdate = nil
+		c.bumpVersion()
+	}
+
+	if err := ApplyPlan(client, plan, applyOptions()); !errors.Is(err, ErrStalePlan) {
+		t.Fatalf("expected ErrStalePlan, got %v", err)
+	}
+	if len(client.updates) != 1 {
+		t.Errorf("expected only the first step to be applied, got %d updates", len(client.updates))
+	}
+}
+
+func TestApplyPlan_notConfirmed(t *testing.T) {
+	client := newFakeClient(liveConfig())
+
+	plan, err := ComputePlan(client, "5a0a1e7e0f2912c554080adc", DeploymentSpec{Clusters: []ClusterSpec{clusterSpec("rs", "h0")}})
+	if err != nil {
+		t.Fatalf("ComputePlan returned error: %v", err)
+	}
+
+	opts := applyOptions()
+	opts.Confirm = PromptConfirmation(bytes.NewBufferString("no\n"), &bytes.Buffer{})
+
+	if err := ApplyPlan(client, plan, opts); !errors.Is(err, ErrPlanNotConfirmed) {
+		t.Fatalf("expected ErrPlanNotConfirmed, got %v", err)
+	}
+	if len(client.updates) != 0 {
+		t.Errorf("expected nothing to be applied, got %d updates", len(client.updates))
+	}
+}
@@ -0,0 +1,172 @@
+// Copyright 2020 MongoDB Inc
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package opsmanager
+
+import (
+	"encoding/json"
+	"fmt"
+	"io"
+	"io/ioutil"
+	"os"
+
+	"github.com/mongodb-labs/pcgc/pkg/useful"
+	"gopkg.in/yaml.v2"
+)
+
+// DeploymentSpec describes the desired state of a project's deployments
+//
+// Sections which are omitted (nil) are not managed: their live state is left untouched.
+// Sections which are specified, even if empty, are authoritative: anything missing from them is deleted.
+// Replica sets which are part of a sharded cluster are never managed by the spec.
+type DeploymentSpec struct {
+	Clusters         []ClusterSpec `json:"clusters"`
+	Users            []UserSpec    `json:"users"`
+	MonitoringAgents []AgentSpec   `json:"monitoringAgents"`
+	BackupAgents     []AgentSpec   `json:"backupAgents"`
+}
+
+// ClusterSpec describes a replica set
+type ClusterSpec struct {
+	Name                        string       `json:"name"`
+	Version                     string       `json:"version"`
+	FeatureCompatibilityVersion string       `json:"featureCompatibilityVersion,omitempty"`
+	Members                     []MemberSpec `json:"members"`
+}
+
+// MemberSpec describes a single replica set member and the mongod process backing it
+type MemberSpec struct {
+	Hostname    string   `json:"hostname"`
+	Port        int      `json:"port"`
+	DBPath      string   `json:"dbPath"`
+	LogPath     string   `json:"logPath,omitempty"`
+	Priority    *float64 `json:"priority,omitempty"`
+	Votes       *float64 `json:"votes,omitempty"`
+	Hidden      bool     `json:"hidden,omitempty"`
+	ArbiterOnly bool     `json:"arbiterOnly,omitempty"`
+}
+
+// UserSpec describes a database user; the password is only used when the user is created
+type UserSpec struct {
+	Username string `json:"username"`
+	Database string `json:"database"`
+	Password string `json:"password,omitempty"`
+	Roles    []Role `json:"roles,omitempty"`
+}
+
+// AgentSpec describes a monitoring or backup agent; if the version is not specified, any version is accepted
+type AgentSpec struct {
+	Hostname string `json:"hostname"`
+	Version  string `json:"version,omitempty"`
+}
+
+// ReadDeploymentSpec parses a deployment spec in the specified format
+func ReadDeploymentSpec(r io.Reader, format ConfigFormat) (DeploymentSpec, error) {
+	var result DeploymentSpec
+
+	data, err := ioutil.ReadAll(r)
+	if err != nil {
+		return result, err
+	}
+
+	switch format {
+	case FormatJSON, "":
+		err = json.Unmarshal(data, &result)
+	case FormatYAML:
+		var doc interface{}
+		if err = yaml.Unmarshal(data, &doc); err == nil {
+			err = fromDocument(normalizeYAML(doc), &result)
+		}
+	default:
+		return result, fmt.Errorf("unsupported format: %s", format)
+	}
+	if err != nil {
+		return result, err
+	}
+
+	return result, result.Validate()
+}
+
+// ReadDeploymentSpecFromFile parses the deployment spec stored in the given file, inferring its format from the extension
+func ReadDeploymentSpecFromFile(path string) (DeploymentSpec, error) {
+	f, err := os.Open(path)
+	if err != nil {
+		return DeploymentSpec{}, err
+	}
+	defer useful.LogError(f.Close)
+
+	return ReadDeploymentSpec(f, formatOf(path, ""))
+}
+
+// Validate checks that the spec is complete and internally consistent
+func (s DeploymentSpec) Validate() error {
+	clusters := make(map[string]bool)
+	addresses := make(map[string]bool)
+	for _, c := range s.Clusters {
+		if c.Name == "" {
+			return fmt.Errorf("all clusters must have a name")
+		}
+		if clusters[c.Name] {
+			return fmt.Errorf("cluster %s is defined more than once", c.Name)
+		}
+		clusters[c.Name] = true
+
+		if c.Version == "" {
+			return fmt.Errorf("cluster %s: version must be set", c.Name)
+		}
+		if len(c.Members) == 0 {
+			return fmt.Errorf("cluster %s: at least one member must be defined", c.Name)
+		}
+
+		for _, m := range c.Members {
+			if m.Hostname == "" || m.Port == 0 || m.DBPath == "" {
+				return fmt.Errorf("cluster %s: all members must define a hostname, port, and dbPath", c.Name)
+			}
+
+			address := fmt.Sprintf("%s:%d", m.Hostname, m.Port)
+			if addresses[address] {
+				return fmt.Errorf("cluster %s: %s is used by more than one member", c.Name, address)
+			}
+			addresses[address] = true
+		}
+	}
+
+	users := make(map[string]bool)
+	for _, u := range s.Users {
+		if u.Username == "" || u.Database == "" {
+			return fmt.Errorf("all users must define a username and database")
+		}
+
+		key := u.Username + "@" + u.Database
+		if users[key] {
+			return fmt.Errorf("user %s is defined more than once", key)
+		}
+		users[key] = true
+	}
+
+	for _, agents := range [][]AgentSpec{s.MonitoringAgents, s.BackupAgents} {
+		hostnames := make(map[string]bool)
+		for _, a := range agents {
+			if a.Hostname == "" {
+				return fmt.Errorf("all agents must define a hostname")
+			}
+			if hostnames[a.Hostname] {
+				return fmt.Errorf("agent %s is defined more than once", a.Hostname)
+			}
+			hostnames[a.Hostname] = true
+		}
+	}
+
+	return nil
+}
@@ -0,0 +1,203 @@
+// Copyright 2020 MongoDB Inc
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package opsmanager
+
+import (
+	"bytes"
+	"fmt"
+	"sync"
+	"text/template"
+)
+
+// DefaultBulkConcurrency the number of projects updated in parallel, if none was specified
+const DefaultBulkConcurrency = 4
+
+// DeploymentTemplate a DeploymentSpec document (JSON or YAML) containing text/template actions
+//
+// Templates are rendered with TemplateData, e.g.:
+//
+//	clusters:
+//	- name: {{ .Project.Name }}-rs
+//	  version: {{ .Params.version }}
+//	  members:
+//	  {{- range $i, $host := .Params.hosts }}
+//	  - {hostname: {{ $host }}, port: {{ $.Params.port }}, dbPath: /data/{{ $i }}}
+//	  {{- end }}
+type DeploymentTemplate struct {
+	tmpl   *template.Template
+	format ConfigFormat
+}
+
+// TemplateData the data passed to a DeploymentTemplate when it is rendered
+type TemplateData struct {
+	Project ProjectResponse
+	Params  map[string]interface{}
+}
+
+// ParseDeploymentTemplate parses a deployment template; referencing parameters which are not defined is an error
+func ParseDeploymentTemplate(name string, text string, format ConfigFormat) (*DeploymentTemplate, error) {
+	tmpl, err := template.New(name).Option("missingkey=error").Parse(text)
+	if err != nil {
+		return nil, err
+	}
+
+	return &DeploymentTemplate{tmpl: tmpl, format: format}, nil
+}
+
+// Render renders the template for the specified project and returns the resulting, validated, spec
+func (t *DeploymentTemplate) Render(project ProjectResponse, params map[string]interface{}) (DeploymentSpec, error) {
+	var buf bytes.Buffer
+	if err := t.tmpl.Execute(&buf, TemplateData{Project: project, Params: params}); err != nil {
+		return DeploymentSpec{}, err
+	}
+
+	return ReadDeploymentSpec(&buf, t.format)
+}
+
+// BulkApplyOptions configure ApplyTemplateToProjects
+type BulkApplyOptions struct {
+	ApplyOptions
+
+	// Filter selects the projects the template is applied to; if nil, all projects are selected
+	Filter func(ProjectResponse) bool
+	// Parameters returns the template parameters for each project; required
+	// It is called for several projects in parallel, so it must be safe for concurrent use
+	Parameters func(ProjectResponse) (map[string]interface{}, error)
+	// Concurrency the maximum number of projects updated in parallel, defaults to DefaultBulkConcurrency
+	Concurrency int
+	// DryRun if true, plans are only computed and never applied
+	DryRun bool
+}
+
+// ProjectResult the outcome of applying a template to a single project
+type ProjectResult struct {
+	Project ProjectResponse
+	Plan    *Plan
+	Err     error
+}
+
+// ApplyTemplateToProjects renders the template for each of the user's projects and brings them to the resulting state
+//
+// The plans of all projects are computed in parallel, then each non-empty plan is confirmed with opts.Confirm,
+// one project at a time, on the calling goroutine (so that e.g. PromptConfirmation prompts do not interleave),
+// and finally the confirmed plans are applied in parallel. A plan which is declined fails with ErrPlanNotConfirmed.
+// A failure in one project does not stop the others; check each ProjectResult for errors
+// Like ApplyPlan, each project's automation config document is edited, keeping the fields AutomationConfig does not model
+func ApplyTemplateToProjects(client Client, tmpl *DeploymentTemplate, opts BulkApplyOptions) ([]ProjectResult, error) {
+	if opts.Parameters == nil {
+		return nil, fmt.Errorf("a parameters function is required")
+	}
+	if !opts.DryRun && opts.Confirm == nil {
+		return nil, fmt.Errorf("a confirmation function is required")
+	}
+
+	projects, err := client.GetAllProjects()
+	if err != nil {
+		return nil, err
+	}
+
+	var selected []ProjectResponse
+	for _, p := range projects.Results {
+		if opts.Filter == nil || opts.Filter(p) {
+			selected = append(selected, p)
+		}
+	}
+
+	concurrency := opts.Concurrency
+	if concurrency <= 0 {
+		concurrency = DefaultBulkConcurrency
+	}
+
+	results := make([]ProjectResult, len(selected))
+	forEachProject(len(selected), concurrency, func(i int) {
+		results[i] = planTemplateForProject(client, tmpl, selected[i], opts)
+	})
+	if opts.DryRun {
+		return results, nil
+	}
+
+	confirmed := make([]bool, len(results))
+	for i := range results {
+		if results[i].Err != nil || results[i].Plan.IsEmpty() {
+			continue
+		}
+		if !opts.Confirm(results[i].Plan) {
+			results[i].Err = ErrPlanNotConfirmed
+			continue
+		}
+		confirmed[i] = true
+	}
+
+	// the plans were already confirmed; ApplyPlan still rejects those which became stale in the meantime
+	applyOpts := opts.ApplyOptions
+	applyOpts.Confirm = AutoApprove
+	forEachProject(len(results), concurrency, func(i int) {
+		if confirmed[i] {
+			results[i].Err = applyProjectPlan(client, results[i].Plan, applyOpts)
+		}
+	})
+
+	return results, nil
+}
+
+// forEachProject calls fn with the index of each of n projects, running at most concurrency calls in parallel
+func forEachProject(n int, concurrency int, fn func(i int)) {
+	semaphore := make(chan struct{}, concurrency)
+	var wg sync.WaitGroup
+	for i := 0; i < n; i++ {
+		wg.Add(1)
+		semaphore <- struct{}{}
+		go func(i int) {
+			defer wg.Done()
+			defer func() { <-semaphore }()
+
+			fn(i)
+		}(i)
+	}
+	wg.Wait()
+}
+
+// recoverProjectFailure turns a panic (e.g. a malformed response) into an error, so it only fails the project which caused it
+func recoverProjectFailure(err *error) {
+	if r := recover(); r != nil {
+		*err = fmt.Errorf("unexpected failure: %v", r)
+	}
+}
+
+func planTemplateForProject(client Client, tmpl *DeploymentTemplate, project ProjectResponse, opts BulkApplyOptions) (result ProjectResult) {
+	result.Project = project
+	defer recoverProjectFailure(&result.Err)
+
+	params, err := opts.Parameters(project)
+	if err != nil {
+		result.Err = fmt.Errorf("could not determine the template parameters: %w", err)
+		return
+	}
+
+	spec, err := tmpl.Render(project, params)
+	if err != nil {
+		result.Err = fmt.Errorf("could not render the template: %w", err)
+		return
+	}
+
+	result.Plan, result.Err = ComputePlan(client, project.ID, spec)
+	return
+}
+
+func applyProjectPlan(client Client, plan *Plan, opts ApplyOptions) (err error) {
+	defer recoverProjectFailure(&err)
+
+	return ApplyPlan(client, plan, opts)
+}
@@ -0,0 +1,257 @@
+// Copyright 2020 MongoDB Inc
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package opsmanager
+
+import (
+	"errors"
+	"strings"
+	"sync"
+	"sync/atomic"
+	"testing"
+	"time"
+)
+
+// fakeProjectsClient serves several projects, each backed by its own fakeClient
+type fakeProjectsClient struct {
+	Client
+
+	projects ProjectsResponse
+	clients  map[string]*fakeClient
+}
+
+func newFakeProjectsClient(ids ...string) *fakeProjectsClient {
+	c := &fakeProjectsClient{clients: map[string]*fakeClient{}}
+	for _, id := range ids {
+		c.projects.Results = append(c.projects.Results, ProjectResponse{ID: id, Name: "project-" + id})
+		c.clients[id] = newFakeClient(liveConfig())
+	}
+	c.projects.TotalCount = len(ids)
+	return c
+}
+
+func (c *fakeProjectsClient) GetAllProjects() (ProjectsResponse, error) {
+	return c.projects, nil
+}
+
+func (c *fakeProjectsClient) GetAutomationConfig(projectID string) (AutomationConfig, error) {
+	return c.clients[projectID].GetAutomationConfig(projectID)
+}
+
+func (c *fakeProjectsClient) UpdateAutomationConfig(projectID string, config AutomationConfig) (AutomationConfig, error) {
+	return c.clients[projectID].UpdateAutomationConfig(projectID, config)
+}
+
+func (c *fakeProjectsClient) GetAutomationConfigDocument(projectID string) (map[string]interface{}, error) {
+	return c.clients[projectID].GetAutomationConfigDocument(projectID)
+}
+
+func (c *fakeProjectsClient) UpdateAutomationConfigDocument(projectID string, doc map[string]interface{}) (map[string]interface{}, error) {
+	return c.clients[projectID].UpdateAutomationConfigDocument(projectID, doc)
+}
+
+func (c *fakeProjectsClient) GetAutomationStatus(projectID string) (AutomationStatusResponse, error) {
+	return c.clients[projectID].GetAutomationStatus(projectID)
+}
+
+// testTemplate adds a member on the host given by the "host" parameter to the rs replica set of liveConfig
+func testTemplate(t *testing.T) *DeploymentTemplate {
+	t.Helper()
+
+	tmpl, err := ParseDeploymentTemplate("test", `{"clusters": [{"name": "rs", "version": "4.2.8", "members": [
+		{"hostname": "h0", "port": 27017, "dbPath": "/data"},
+		{"hostname": "h1", "port": 27017, "dbPath": "/data"},
+		{"hostname": "h2", "port": 27017, "dbPath": "/data"},
+		{"hostname": "{{ .Params.host }}", "port": 27017, "dbPath": "/data"}
+	]}]}`, FormatJSON)
+	if err != nil {
+		t.Fatal(err)
+	}
+	return tmpl
+}
+
+func hostParameter(ProjectResponse) (map[string]interface{}, error) {
+	return map[string]interface{}{"host": "h3"}, nil
+}
+
+func TestApplyTemplateToProjects(t *testing.T) {
+	client := newFakeProjectsClient("1", "2", "3", "4")
+
+	// detect any confirmations running at the same time
+	var active, overlaps int32
+	var mu sync.Mutex
+	var confirmed []string
+	opts := BulkApplyOptions{ApplyOptions: applyOptions(), Parameters: hostParameter, Concurrency: 4}
+	opts.Confirm = func(plan *Plan) bool {
+		if atomic.AddInt32(&active, 1) > 1 {
+			atomic.AddInt32(&overlaps, 1)
+		}
+		defer atomic.AddInt32(&active, -1)
+		time.Sleep(5 * time.Millisecond)
+
+		mu.Lock()
+		defer mu.Unlock()
+		confirmed = append(confirmed, plan.ProjectID)
+		return plan.ProjectID != "2"
+	}
+
+	results, err := ApplyTemplateToProjects(client, testTemplate(t), opts)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if overlaps != 0 {
+		t.Errorf("Confirm was called concurrently %d times", overlaps)
+	}
+	if got := strings.Join(confirmed, ","); got != "1,2,3,4" {
+		t.Errorf("expected the projects to be confirmed in order, got %s", got)
+	}
+	if len(results) != 4 {
+		t.Fatalf("expected 4 results, got %d", len(results))
+	}
+	for _, r := range results {
+		updated := client.clients[r.Project.ID].updateCount() > 0
+		if r.Project.ID == "2" {
+			if !errors.Is(r.Err, ErrPlanNotConfirmed) {
+				t.Errorf("expected ErrPlanNotConfirmed for the declined project, got %v", r.Err)
+			}
+			if updated {
+				t.Error("the declined project should not be updated")
+			}
+			continue
+		}
+		if r.Err != nil {
+			t.Errorf("project %s: %v", r.Project.ID, r.Err)
+		}
+		if !updated {
+			t.Errorf("project %s was not updated", r.Project.ID)
+		}
+	}
+}
+
+func TestApplyTemplateToProjects_keepsUnmodeledFields(t *testing.T) {
+	client := newFakeProjectsClient("1", "2", "3")
+	for id, c := range client.clients {
+		member := c.doc["replicaSets"].([]interface{})[0].(map[string]interface{})["members"].([]interface{})[0].(map[string]interface{})
+		member["tags"] = map[string]interface{}{"project": id}
+		c.doc["ldap"] = map[string]interface{}{"servers": "ldap-" + id + ".example.com"}
+	}
+
+	opts := BulkApplyOptions{ApplyOptions: applyOptions(), Parameters: hostParameter}
+	opts.Confirm = AutoApprove
+	results, err := ApplyTemplateToProjects(client, testTemplate(t), opts)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	for _, r := range results {
+		if r.Err != nil {
+			t.Fatalf("project %s: %v", r.Project.ID, r.Err)
+		}
+
+		doc := client.clients[r.Project.ID].doc
+		if ldap, _ := doc["ldap"].(map[string]interface{}); ldap["servers"] != "ldap-"+r.Project.ID+".example.com" {
+			t.Errorf("project %s: expected its ldap settings to be kept, got %v", r.Project.ID, doc["ldap"])
+		}
+		rs, err := findDocumentElement(doc, "replicaSets", "_id", "rs")
+		if err != nil {
+			t.Fatal(err)
+		}
+		member, err := findDocumentElement(rs, "members", "host", "rs_0")
+		if err != nil {
+			t.Fatal(err)
+		}
+		if tags, _ := member["tags"].(map[string]interface{}); tags["project"] != r.Project.ID {
+			t.Errorf("project %s: expected the tags of rs_0 to be kept, got %v", r.Project.ID, member["tags"])
+		}
+		if _, err := findDocumentElement(rs, "members", "host", "rs_3"); err != nil {
+			t.Errorf("project %s: expected h3 to be added: %v", r.Project.ID, err)
+		}
+	}
+}
+
+func TestApplyTemplateToProjects_dryRun(t *testing.T) {
+	client := newFakeProjectsClient("1", "2")
+	opts := BulkApplyOptions{
+		Parameters: hostParameter,
+		DryRun:     true,
+		Filter:     func(p ProjectResponse) bool { return p.ID == "2" },
+	}
+
+	results, err := ApplyTemplateToProjects(client, testTemplate(t), opts)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if len(results) != 1 || results[0].Project.ID != "2" {
+		t.Fatalf("expected only the filtered project, got %+v", results)
+	}
+	if results[0].Err != nil {
+		t.Fatal(results[0].Err)
+	}
+	if results[0].Plan.IsEmpty() {
+		t.Error("expected a plan adding h3")
+	}
+	for id, c := range client.clients {
+		if c.updateCount() != 0 {
+			t.Errorf("project %s was updated during a dry run", id)
+		}
+	}
+}
+
+func TestApplyTemplateToProjects_errors(t *testing.T) {
+	client := newFakeProjectsClient("1", "2", "3")
+	opts := BulkApplyOptions{ApplyOptions: applyOptions()}
+	opts.Parameters = func(p ProjectResponse) (map[string]interface{}, error) {
+		switch p.ID {
+		case "1":
+			return nil, errors.New("no parameters")
+		case "2":
+			return map[string]interface{}{}, nil
+		case "3":
+			panic("unexpected")
+		}
+		return hostParameter(p)
+	}
+
+	results, err := ApplyTemplateToProjects(client, testTemplate(t), opts)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	expected := []string{
+		"could not determine the template parameters",
+		"could not render the template",
+		"unexpected failure",
+	}
+	for i, r := range results {
+		if r.Err == nil || !strings.Contains(r.Err.Error(), expected[i]) {
+			t.Errorf("project %s: expected an error containing %q, got %v", r.Project.ID, expected[i], r.Err)
+		}
+		if client.clients[r.Project.ID].updateCount() != 0 {
+			t.Errorf("project %s should not be updated", r.Project.ID)
+		}
+	}
+}
+
+func TestApplyTemplateToProjects_invalidOptions(t *testing.T) {
+	client := newFakeProjectsClient("1")
+
+	if _, err := ApplyTemplateToProjects(client, testTemplate(t), BulkApplyOptions{Parameters: hostParameter}); err == nil {
+		t.Error("expected an error without a confirmation function")
+	}
+	if _, err := ApplyTemplateToProjects(client, testTemplate(t), BulkApplyOptions{ApplyOptions: applyOptions()}); err == nil {
+		t.Error("expected an error without a parameters function")
+	}
+}
@@ -0,0 +1,192 @@
+// Copyright 2020 MongoDB Inc
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package opsmanager
+
+import (
+	"fmt"
+	"strings"
+	"time"
+)
+
+// GoalStateReport explains why a project is not in goal state
+type GoalStateReport struct {
+	GoalVersion int                   `json:"goalVersion"`
+	Processes   []ProcessGoalState    `json:"processes,omitempty"`
+	Jobs        []DeploymentJobStatus `json:"jobs,omitempty"`
+}
+
+// ProcessGoalState describes a process which did not reach the goal version
+type ProcessGoalState struct {
+	Name                    string        `json:"name"`
+	Address                 string        `json:"address"`
+	LastGoalVersionAchieved int           `json:"lastGoalVersionAchieved"`
+	Move                    string        `json:"move,omitempty"`
+	Step                    string        `json:"step,omitempty"`
+	StuckFor                time.Duration `json:"stuckFor,omitempty"`
+	LastError               string        `json:"lastError,omitempty"`
+	ErrorCount              int           `json:"errorCount,omitempty"`
+}
+
+// InGoalState returns true if all processes reached the goal version and there are no running jobs
+func (r GoalStateReport) InGoalState() bool {
+	return len(r.Processes) == 0 && len(r.Jobs) == 0
+}
+
+// Reasons returns a human readable description of why each process or job is not done,
+// e.g. "host-1:27017 stuck on step WaitRsInit for 12m"
+func (r GoalStateReport) Reasons() []string {
+	var result []string
+
+	for _, p := range r.Processes {
+		var sb strings.Builder
+		sb.WriteString(p.Address)
+
+		switch {
+		case p.Step != "":
+			_, _ = fmt.Fprintf(&sb, " stuck on step %s", p.Step)
+		case p.Move != "":
+			_, _ = fmt.Fprintf(&sb, " stuck on move %s", p.Move)
+		default:
+			_, _ = fmt.Fprintf(&sb, " at goal version %d, expected %d", p.LastGoalVersionAchieved, r.GoalVersion)
+		}
+		if p.StuckFor > 0 {
+			_, _ = fmt.Fprintf(&sb, " for %s", formatDuration(p.StuckFor))
+		}
+		if p.LastError != "" {
+			_, _ = fmt.Fprintf(&sb, " (%d errors, last: %s)", p.ErrorCount, p.LastError)
+		}
+
+		result = append(result, sb.String())
+	}
+
+	for _, j := range r.Jobs {
+		description := fmt.Sprintf("job %s on %s is %s", j.Type, j.Hostname, j.Status)
+		if j.ErrorMessage != "" {
+			description += ": " + j.ErrorMessage
+		}
+		result = append(result, description)
+	}
+
+	return result
+}
+
+// String renders the report
+func (r GoalStateReport) String() string {
+	if r.InGoalState() {
+		return fmt.Sprintf("in goal state (version %d)", r.GoalVersion)
+	}
+
+	return fmt.Sprintf("not in goal state (version %d):\n  %s", r.GoalVersion, strings.Join(r.Reasons(), "\n  "))
+}
+
+// ExplainGoalState reports which processes of the specified project did not yet reach goal state, and why
+func ExplainGoalState(client Client, projectID string) (GoalStateReport, error) {
+	status, err := client.GetAutomationStatus(projectID)
+	if err != nil {
+		return GoalStateReport{}, err
+	}
+
+	config, err := client.GetAutomationConfig(projectID)
+	if err != nil {
+		return GoalStateReport{}, err
+	}
+
+	raw, err := client.GetRawAutomationConfig(projectID)
+	if err != nil {
+		return GoalStateReport{}, err
+	}
+
+	return NewGoalStateReport(status, config, raw.DeploymentJobStatuses, time.Now()), nil
+}
+
+// NewGoalStateReport builds a goal state report out of an automation status, the automation config
+// (used to resolve process ports), and the deployment job statuses (used to tell how long processes whose
+// plan has no step details have been working on their current move)
+func NewGoalStateReport(status AutomationStatusResponse, config AutomationConfig, jobs []DeploymentJobStatus, now time.Time) GoalStateReport {
+	result := GoalStateReport{GoalVersion: status.GoalVersion}
+
+	for _, p := range status.Processes {
+		if p.LastGoalVersionAchieved == status.GoalVersion {
+			continue
+		}
+
+		state := ProcessGoalState{
+			Name:                    p.Name,
+			Address:                 p.Hostname,
+			LastGoalVersionAchieved: p.LastGoalVersionAchieved,
+			LastError:               p.LastError,
+			ErrorCount:              p.ErrorCount,
+		}
+		if state.LastError == "" {
+			state.LastError = p.ErrorCodeHumanReadable
+		}
+		if process, err := findProcess(&config, p.Name); err == nil {
+			state.Address = fmt.Sprintf("%s:%d", p.Hostname, processPort(process))
+		}
+
+		move, step := p.Plan.InProgress()
+		if move != nil {
+			state.Move = move.Move
+		}
+		if step != nil {
+			state.Step = step.Step
+		}
+		if started := progressStarted(step, p, jobs); started != nil {
+			state.StuckFor = now.Sub(started.Time)
+		}
+
+		result.Processes = append(result.Processes, state)
+	}
+
+	for _, j := range jobs {
+		if !j.IsFinished() {
+			result.Jobs = append(result.Jobs, j)
+		}
+	}
+
+	return result
+}
+
+// progressStarted returns when the process started working on its current step or, if the plan has no step details,
+// when its running deployment job started; nil if neither is known
+func progressStarted(step *AutomationStep, p ProcessStatus, jobs []DeploymentJobStatus) *Timestamp {
+	if step != nil && step.Started != nil {
+		return step.Started
+	}
+
+	for _, j := range jobs {
+		if j.IsFinished() || j.Started == nil {
+			continue
+		}
+		if j.ProcessName == p.Name || (j.ProcessName == "" && j.Hostname == p.Hostname) {
+			return j.Started
+		}
+	}
+
+	return nil
+}
+
+// formatDuration formats durations with a granularity appropriate for reports, e.g. 45s, 12m, 1h5m
+func formatDuration(d time.Duration) string {
+	switch {
+	case d < time.Minute:
+		return d.Round(time.Second).String()
+	case d < time.Hour:
+		return fmt.Sprintf("%dm", int(d.Round(time.Minute).Minutes()))
+	default:
+		d = d.Round(time.Minute)
+		return fmt.Sprintf("%dh%dm", int(d.Hours()), int(d.Minutes())%60)
+	}
+}
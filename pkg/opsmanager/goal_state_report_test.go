@@ -0,0 +1,105 @@
+// Copyright 2020 MongoDB Inc
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package opsmanager
+
+import (
+	"reflect"
+	"strings"
+	"testing"
+	"time"
+)
+
+func TestNewGoalStateReport(t *testing.T) {
+	now := time.Date(2020, 6, 1, 12, 30, 0, 0, time.UTC)
+	ago := func(d time.Duration) *Timestamp {
+		return &Timestamp{now.Add(-d)}
+	}
+
+	var status AutomationStatusResponse
+	decodeJSON(`{"goalVersion": 6, "processes": [
+		{"name": "rs_0", "hostname": "h0", "lastGoalVersionAchieved": 6, "plan": []},
+		{"name": "rs_1", "hostname": "h1", "lastGoalVersionAchieved": 5, "plan": [
+			{"move": "Start", "steps": [{"step": "WaitRsInit", "started": "2020-06-01T12:18:00Z"}]}
+		], "lastError": "connection refused", "errorCount": 3},
+		{"name": "rs_2", "hostname": "h2", "lastGoalVersionAchieved": 5, "plan": ["Download", "Start"],
+		 "errorCodeHumanReadable": "download failed", "errorCount": 1},
+		{"name": "other", "hostname": "h3", "lastGoalVersionAchieved": 5, "plan": []}
+	]}`, &status)
+	jobs := []DeploymentJobStatus{
+		{Type: "Download", ProcessName: "rs_2", Hostname: "h2", Status: "RUNNING", Started: ago(45 * time.Second)},
+		{Type: "Download", ProcessName: "rs_0", Hostname: "h0", Status: "COMPLETED", Started: ago(time.Hour), Completed: ago(time.Minute)},
+	}
+	config := liveConfig()
+	config.Processes[2].Args26.NET = nil
+
+	report := NewGoalStateReport(status, config, jobs, now)
+
+	expected := []ProcessGoalState{
+		{Name: "rs_1", Address: "h1:27017", LastGoalVersionAchieved: 5, Move: "Start", Step: "WaitRsInit",
+			StuckFor: 12 * time.Minute, LastError: "connection refused", ErrorCount: 3},
+		{Name: "rs_2", Address: "h2:27017", LastGoalVersionAchieved: 5, Move: "Download",
+			StuckFor: 45 * time.Second, LastError: "download failed", ErrorCount: 1},
+		{Name: "other", Address: "h3", LastGoalVersionAchieved: 5},
+	}
+	if !reflect.DeepEqual(report.Processes, expected) {
+		t.Errorf("expected %+v, got %+v", expected, report.Processes)
+	}
+	if len(report.Jobs) != 1 || report.Jobs[0].ProcessName != "rs_2" {
+		t.Errorf("expected only the running job, got %+v", report.Jobs)
+	}
+	if report.InGoalState() {
+		t.Error("expected the project not to be in goal state")
+	}
+
+	expectedReasons := []string{
+		"h1:27017 stuck on step WaitRsInit for 12m (3 errors, last: connection refused)",
+		"h2:27017 stuck on move Download for 45s (1 errors, last: download failed)",
+		"h3 at goal version 5, expected 6",
+		"job Download on h2 is RUNNING",
+	}
+	if reasons := report.Reasons(); !reflect.DeepEqual(reasons, expectedReasons) {
+		t.Errorf("expected %q, got %q", expectedReasons, reasons)
+	}
+	if s := report.String(); !strings.HasPrefix(s, "not in goal state (version 6):\n  h1:27017") {
+		t.Errorf("unexpected report %q", s)
+	}
+}
+
+func TestNewGoalStateReport_inGoalState(t *testing.T) {
+	status := AutomationStatusResponse{GoalVersion: 6, Processes: []ProcessStatus{{Name: "rs_0", LastGoalVersionAchieved: 6}}}
+
+	report := NewGoalStateReport(status, liveConfig(), nil, time.Now())
+
+	if !report.InGoalState() {
+		t.Errorf("expected the project to be in goal state, got %+v", report)
+	}
+	if s := report.String(); s != "in goal state (version 6)" {
+		t.Errorf("unexpected report %q", s)
+	}
+}
+
+func TestFormatDuration(t *testing.T) {
+	tests := map[time.Duration]string{
+		45 * time.Second:                "45s",
+		12*time.Minute + 20*time.Second: "12m",
+		65 * time.Minute:                "1h5m",
+	}
+
+	for d, expected := range tests {
+		if got := formatDuration(d); got != expected {
+			t.Errorf("formatDuration(%v): expected %s, got %s", d, expected, got)
+		}
+	}
+}
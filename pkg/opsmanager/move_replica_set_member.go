@@ -0,0 +1,307 @@
+// Copyright 2020 MongoDB Inc
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package opsmanager
+
+import (
+	"fmt"
+	"time"
+)
+
+const (
+	// ReplicaStateSecondary the replica state reported by the hosts API for secondary members
+	ReplicaStateSecondary = "SECONDARY"
+	// DefaultMongoDBPort the port mongod listens on when net.port is not set
+	DefaultMongoDBPort = 27017
+)
+
+// MoveMemberOptions optional parameters for MoveReplicaSetMember
+type MoveMemberOptions struct {
+	// NewProcessName the name of the process created on the target host, defaults to <replicaSetName>_<memberID>
+	NewProcessName string
+	// NewPort the port the new member listens on, defaults to the port of the member being moved
+	NewPort int
+	// PollInterval how often to check if the new member has caught up, defaults to DefaultPollInterval
+	PollInterval time.Duration
+	// Timeout how long to wait for each goal state, defaults to DefaultPollTimeout
+	Timeout time.Duration
+}
+
+// maxVotingMembers the maximum number of voting members of a replica set
+const maxVotingMembers = 7
+
+// MoveReplicaSetMember moves a replica set member, identified by its process name, onto a new host
+//
+// The move is done without the replica set losing quorum, changing its voting members one at a time:
+//  1. a copy of the old process is added on the new host, as a hidden, non-voting member
+//  2. the function waits until automation reaches goal state and the new member is reported as SECONDARY
+//  3. the old member's votes and priority are given to the new member, and the function waits for goal state
+//  4. the old member and its process are removed, and the function waits for goal state once more
+//
+// The automation config is edited as a document, so that the fields AutomationConfig does not model are kept,
+// and the new member and its process keep every setting of the old ones (e.g. member tags, or TLS settings)
+func MoveReplicaSetMember(client Client, projectID string, replicaSetName string, processName string, newHostname string, opts MoveMemberOptions) error {
+	doc, err := client.GetAutomationConfigDocument(projectID)
+	if err != nil {
+		return err
+	}
+	var config AutomationConfig
+	if err := fromDocument(doc, &config); err != nil {
+		return err
+	}
+
+	rs, err := findReplicaSet(&config, replicaSetName)
+	if err != nil {
+		return err
+	}
+	oldMember, err := findMember(rs, processName)
+	if err != nil {
+		return err
+	}
+	oldProcess, err := findProcess(&config, processName)
+	if err != nil {
+		return err
+	}
+	if oldMember.Votes > 0 && len(votingMemberHosts(rs)) >= maxVotingMembers {
+		return fmt.Errorf("replica set %s already has %d voting members, so %s cannot be promoted before %s is removed", replicaSetName, maxVotingMembers, newHostname, processName)
+	}
+
+	newMemberID := nextMemberID(rs)
+	newProcessName := opts.NewProcessName
+	if newProcessName == "" {
+		newProcessName = fmt.Sprintf("%s_%d", replicaSetName, newMemberID)
+	}
+	newPort := processPort(oldProcess)
+	if opts.NewPort != 0 {
+		newPort = opts.NewPort
+	}
+
+	if _, err := findProcess(&config, newProcessName); err == nil {
+		return fmt.Errorf("a process named %s already exists in project %s", newProcessName, projectID)
+	}
+	for _, p := range config.Processes {
+		if p.Hostname == newHostname && processPort(p) == newPort {
+			return fmt.Errorf("%s:%d is already used by process %s", newHostname, newPort, p.Name)
+		}
+	}
+
+	// 1. add the new member, without allowing it to participate in elections
+	if err := addMovedMember(doc, replicaSetName, processName, newProcessName, newMemberID, newHostname, opts.NewPort); err != nil {
+		return err
+	}
+	if _, err := client.UpdateAutomationConfigDocument(projectID, doc); err != nil {
+		return err
+	}
+
+	// 2. wait for the new member to catch up
+	err = pollUntil(opts.PollInterval, opts.Timeout, func() (bool, error) {
+		done, err := isInGoalState(client, projectID)
+		if err != nil || !done {
+			return false, err
+		}
+
+		return isHostInReplicaState(client, projectID, newHostname, newPort, ReplicaStateSecondary)
+	})
+	if err != nil {
+		return fmt.Errorf("new member %s:%d did not become %s: %w", newHostname, newPort, ReplicaStateSecondary, err)
+	}
+
+	// 3. give the old member's votes and priority to the new member; the config is reloaded, its version changed
+	err = updateMoveDocument(client, projectID, opts, func(doc map[string]interface{}) error {
+		return promoteMovedMember(doc, replicaSetName, processName, newProcessName)
+	})
+	if err != nil {
+		return fmt.Errorf("could not promote %s: %w", newProcessName, err)
+	}
+
+	// 4. remove the old member
+	err = updateMoveDocument(client, projectID, opts, func(doc map[string]interface{}) error {
+		rs, err := findDocumentElement(doc, "replicaSets", "_id", replicaSetName)
+		if err != nil {
+			return err
+		}
+		removeDocumentElements(rs, "members", "host", processName)
+		removeDocumentElements(doc, "processes", "name", processName)
+		return nil
+	})
+	if err != nil {
+		return fmt.Errorf("could not remove %s: %w", processName, err)
+	}
+
+	return nil
+}
+
+// updateMoveDocument reloads the automation config, edits it with fn, and waits until the change was applied
+func updateMoveDocument(client Client, projectID string, opts MoveMemberOptions, fn func(map[string]interface{}) error) error {
+	doc, err := client.GetAutomationConfigDocument(projectID)
+	if err != nil {
+		return err
+	}
+	if err := fn(doc); err != nil {
+		return err
+	}
+	if _, err := client.UpdateAutomationConfigDocument(projectID, doc); err != nil {
+		return err
+	}
+
+	return WaitForGoalState(client, projectID, opts.PollInterval, opts.Timeout)
+}
+
+// addMovedMember adds a copy of the old member and its process to doc, as a hidden member without votes or priority
+func addMovedMember(doc map[string]interface{}, replicaSetName string, processName string, newProcessName string, newMemberID int, newHostname string, newPort int) error {
+	rs, err := findDocumentElement(doc, "replicaSets", "_id", replicaSetName)
+	if err != nil {
+		return err
+	}
+	oldMember, err := findDocumentElement(rs, "members", "host", processName)
+	if err != nil {
+		return err
+	}
+	oldProcess, err := findDocumentElement(doc, "processes", "name", processName)
+	if err != nil {
+		return err
+	}
+
+	process, err := copyDocument(oldProcess)
+	if err != nil {
+		return err
+	}
+	// drop the state reported by the agents
+	delete(process, "plan")
+	delete(process, "lastGoalVersionAchieved")
+	process["name"] = newProcessName
+	process["hostname"] = newHostname
+	if newPort != 0 {
+		subDocument(subDocument(process, "args2_6"), "net")["port"] = newPort
+	}
+
+	member, err := copyDocument(oldMember)
+	if err != nil {
+		return err
+	}
+	member["_id"] = newMemberID
+	member["host"] = newProcessName
+	member["hidden"] = true
+	member["priority"] = 0
+	member["votes"] = 0
+
+	doc["processes"] = append(documentList(doc, "processes"), process)
+	rs["members"] = append(documentList(rs, "members"), member)
+	return nil
+}
+
+// movedMemberFields the fields of the old member which the new member takes over once it caught up
+var movedMemberFields = []string{"hidden", "priority", "votes", "slaveDelay", "secondaryDelaySecs"}
+
+// promoteMovedMember gives the votes, priority, and visibility of the old member to the new member
+func promoteMovedMember(doc map[string]interface{}, replicaSetName string, processName string, newProcessName string) error {
+	rs, err := findDocumentElement(doc, "replicaSets", "_id", replicaSetName)
+	if err != nil {
+		return err
+	}
+	oldMember, err := findDocumentElement(rs, "members", "host", processName)
+	if err != nil {
+		return err
+	}
+	newMember, err := findDocumentElement(rs, "members", "host", newProcessName)
+	if err != nil {
+		return err
+	}
+
+	for _, field := range movedMemberFields {
+		if value, ok := oldMember[field]; ok {
+			newMember[field] = value
+		} else {
+			delete(newMember, field)
+		}
+	}
+	return nil
+}
+
+// votingMemberHosts returns the process names of the voting members of rs
+func votingMemberHosts(rs *ReplicaSet) []string {
+	var result []string
+	for _, m := range rs.Members {
+		if m.Votes > 0 {
+			result = append(result, m.Host)
+		}
+	}
+
+	return result
+}
+
+func findReplicaSet(config *AutomationConfig, name string) (*ReplicaSet, error) {
+	for i := range config.ReplicaSets {
+		if config.ReplicaSets[i].ID == name {
+			return &config.ReplicaSets[i], nil
+		}
+	}
+
+	return nil, fmt.Errorf("replica set %s not found", name)
+}
+
+func findMember(rs *ReplicaSet, processName string) (Member, error) {
+	for _, m := range rs.Members {
+		if m.Host == processName {
+			return m, nil
+		}
+	}
+
+	return Member{}, fmt.Errorf("process %s is not a member of replica set %s", processName, rs.ID)
+}
+
+func findProcess(config *AutomationConfig, name string) (*Process, error) {
+	for _, p := range config.Processes {
+		if p.Name == name {
+			return p, nil
+		}
+	}
+
+	return nil, fmt.Errorf("process %s not found", name)
+}
+
+func nextMemberID(rs *ReplicaSet) int {
+	next := 0
+	for _, m := range rs.Members {
+		if m.ID >= next {
+			next = m.ID + 1
+		}
+	}
+
+	return next
+}
+
+// processPort returns the port the process listens on, as reported by the hosts API
+func processPort(process *Process) int {
+	if process.Args26 == nil || process.Args26.NET == nil || process.Args26.NET.Port == 0 {
+		return DefaultMongoDBPort
+	}
+
+	return process.Args26.NET.Port
+}
+
+func isHostInReplicaState(client Client, projectID string, hostname string, port int, state string) (bool, error) {
+	hosts, err := client.GetHosts(projectID)
+	if err != nil {
+		return false, err
+	}
+
+	for _, host := range hosts.Results {
+		if host.Hostname == hostname && host.Port == port {
+			return host.ReplicaStateName == state, nil
+		}
+	}
+
+	return false, nil
+}
@@ -0,0 +1,236 @@
+// Copyright 2020 MongoDB Inc
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package opsmanager
+
+import (
+	"errors"
+	"fmt"
+	"testing"
+	"time"
+)
+
+func moveOptions() MoveMemberOptions {
+	return MoveMemberOptions{PollInterval: time.Millisecond, Timeout: 50 * time.Millisecond}
+}
+
+// secondaryHost reports hostname:port as a secondary in the hosts API
+func secondaryHost(hostname string, port int) HostsResponse {
+	return HostsResponse{Results: []HostResponse{{Hostname: hostname, Port: port, ReplicaStateName: ReplicaStateSecondary}}}
+}
+
+func TestMoveReplicaSetMember(t *testing.T) {
+	client := newFakeClient(liveConfig())
+	client.hosts = secondaryHost("h3", 27017)
+
+	if err := MoveReplicaSetMember(client, "p", "rs", "rs_1", "h3", moveOptions()); err != nil {
+		t.Fatal(err)
+	}
+
+	if len(client.updates) != 3 {
+		t.Fatalf("expected 3 updates, got %d", len(client.updates))
+	}
+
+	added := findTestMember(t, &client.updates[0], "rs", "rs_3")
+	if added.Votes != 0 || added.Priority != 0 || !added.Hidden {
+		t.Errorf("expected rs_3 to be added as a hidden, non-voting member, got %+v", added)
+	}
+	if _, err := findProcess(&client.updates[0], "rs_1"); err != nil {
+		t.Error("expected rs_1 to be kept until the new member caught up")
+	}
+
+	promoted := findTestMember(t, &client.updates[1], "rs", "rs_3")
+	if promoted.Votes != 1 || promoted.Priority != 1 || promoted.Hidden {
+		t.Errorf("expected rs_3 to take over the votes of rs_1, got %+v", promoted)
+	}
+	if old := findTestMember(t, &client.updates[1], "rs", "rs_1"); old.Votes != 1 {
+		t.Errorf("expected rs_1 to keep its vote until rs_3 was promoted, got %+v", old)
+	}
+
+	final := client.updates[2]
+	if _, err := findProcess(&final, "rs_1"); err == nil {
+		t.Error("expected rs_1 to be removed")
+	}
+	rs, err := findReplicaSet(&final, "rs")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if _, err := findMember(rs, "rs_1"); err == nil {
+		t.Error("expected rs_1 to be removed from rs")
+	}
+	process, err := findProcess(&final, "rs_3")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if process.Hostname != "h3" || process.Args26.Storage.DBPath != "/data" {
+		t.Errorf("expected a copy of rs_1 on h3, got %+v", process)
+	}
+
+	// each update adds or removes at most one voting member
+	previous := liveConfig()
+	for i := range client.updates {
+		before, after := votingMembers(&previous, "rs"), votingMembers(&client.updates[i], "rs")
+		changed := 0
+		for host := range before {
+			if !after[host] {
+				changed++
+			}
+		}
+		for host := range after {
+			if !before[host] {
+				changed++
+			}
+		}
+		if changed > 1 {
+			t.Errorf("update %d changes %d voting members at once", i+1, changed)
+		}
+		previous = client.updates[i]
+	}
+}
+
+func TestMoveReplicaSetMember_keepsUnmodeledFields(t *testing.T) {
+	client := newFakeClient(liveConfig())
+	client.hosts = secondaryHost("h3", 27017)
+
+	rs := client.doc["replicaSets"].([]interface{})[0].(map[string]interface{})
+	rs["settings"] = map[string]interface{}{"chainingAllowed": false}
+	rs["writeConcernMajorityJournalDefault"] = true
+	oldMember := rs["members"].([]interface{})[1].(map[string]interface{})
+	oldMember["tags"] = map[string]interface{}{"dc": "east"}
+	oldMember["buildIndexes"] = true
+	oldProcess := client.doc["processes"].([]interface{})[1].(map[string]interface{})
+	oldProcess["horizons"] = map[string]interface{}{"external": "rs1.example.com:27017"}
+	client.doc["auth"].(map[string]interface{})["autoAuthMechanisms"] = []interface{}{"SCRAM-SHA-256"}
+
+	if err := MoveReplicaSetMember(client, "p", "rs", "rs_1", "h3", moveOptions()); err != nil {
+		t.Fatal(err)
+	}
+
+	doc := client.doc
+	rs, err := findDocumentElement(doc, "replicaSets", "_id", "rs")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if rs["settings"] == nil || rs["writeConcernMajorityJournalDefault"] != true {
+		t.Errorf("expected the replica set settings to be kept, got %v", rs)
+	}
+	member, err := findDocumentElement(rs, "members", "host", "rs_3")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if member["tags"] == nil || member["buildIndexes"] != true {
+		t.Errorf("expected the new member to keep the tags of rs_1, got %v", member)
+	}
+	process, err := findDocumentElement(doc, "processes", "name", "rs_3")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if process["horizons"] == nil {
+		t.Errorf("expected the new process to keep the horizons of rs_1, got %v", process)
+	}
+	if doc["auth"].(map[string]interface{})["autoAuthMechanisms"] == nil {
+		t.Error("expected the auth settings to be kept")
+	}
+}
+
+func TestMoveReplicaSetMember_tooManyVotingMembers(t *testing.T) {
+	config := liveConfig()
+	for i := 3; i < maxVotingMembers; i++ {
+		config.ReplicaSets[0].Members = append(config.ReplicaSets[0].Members, Member{ID: i, Host: fmt.Sprintf("rs_%d", i), Priority: 1, Votes: 1})
+	}
+	client := newFakeClient(config)
+
+	if err := MoveReplicaSetMember(client, "p", "rs", "rs_1", "h9", moveOptions()); err == nil {
+		t.Fatal("expected an error")
+	}
+	if len(client.updates) != 0 {
+		t.Errorf("expected no updates, got %d", len(client.updates))
+	}
+}
+
+func TestMoveReplicaSetMember_defaultPort(t *testing.T) {
+	config := liveConfig()
+	for _, p := range config.Processes {
+		p.Args26.NET = nil
+	}
+	client := newFakeClient(config)
+	// the hosts API reports the port mongod actually listens on
+	client.hosts = secondaryHost("h3", 27017)
+
+	if err := MoveReplicaSetMember(client, "p", "rs", "rs_1", "h3", moveOptions()); err != nil {
+		t.Fatal(err)
+	}
+}
+
+func TestMoveReplicaSetMember_options(t *testing.T) {
+	client := newFakeClient(liveConfig())
+	client.hosts = secondaryHost("h2", 27018)
+
+	opts := moveOptions()
+	opts.NewProcessName = "moved"
+	opts.NewPort = 27018
+	if err := MoveReplicaSetMember(client, "p", "rs", "rs_1", "h2", opts); err != nil {
+		t.Fatal(err)
+	}
+
+	config := client.config()
+	process, err := findProcess(&config, "moved")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if process.Hostname != "h2" || processPort(process) != 27018 {
+		t.Errorf("expected the new process on h2:27018, got %s:%d", process.Hostname, processPort(process))
+	}
+}
+
+func TestMoveReplicaSetMember_notCaughtUp(t *testing.T) {
+	client := newFakeClient(liveConfig())
+	client.hosts = HostsResponse{Results: []HostResponse{{Hostname: "h3", Port: 27017, ReplicaStateName: "STARTUP2"}}}
+
+	err := MoveReplicaSetMember(client, "p", "rs", "rs_1", "h3", moveOptions())
+	if !errors.Is(err, ErrWaitTimeout) {
+		t.Fatalf("expected ErrWaitTimeout, got %v", err)
+	}
+	if len(client.updates) != 1 {
+		t.Errorf("expected the old member to be kept, got %d updates", len(client.updates))
+	}
+}
+
+func TestMoveReplicaSetMember_errors(t *testing.T) {
+	tests := []struct {
+		name           string
+		replicaSetName string
+		processName    string
+		newHostname    string
+		opts           MoveMemberOptions
+	}{
+		{name: "unknown replica set", replicaSetName: "other", processName: "rs_1", newHostname: "h3"},
+		{name: "unknown member", replicaSetName: "rs", processName: "rs_9", newHostname: "h3"},
+		{name: "address in use", replicaSetName: "rs", processName: "rs_1", newHostname: "h2"},
+		{name: "process name in use", replicaSetName: "rs", processName: "rs_1", newHostname: "h3", opts: MoveMemberOptions{NewProcessName: "rs_2"}},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			client := newFakeClient(liveConfig())
+
+			if err := MoveReplicaSetMember(client, "p", tt.replicaSetName, tt.processName, tt.newHostname, tt.opts); err == nil {
+				t.Fatal("expected an error")
+			}
+			if len(client.updates) != 0 {
+				t.Errorf("expected no updates, got %d", len(client.updates))
+			}
+		})
+	}
+}
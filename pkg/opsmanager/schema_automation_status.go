@@ -0,0 +1,173 @@
+// Copyright 2020 MongoDB Inc
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package opsmanager
+
+import (
+	"bytes"
+	"encoding/json"
+	"strconv"
+	"time"
+)
+
+// Timestamp a point in time, which Ops Manager reports either as an ISO-8601 string or as milliseconds since the epoch
+type Timestamp struct {
+	time.Time
+}
+
+// UnmarshalJSON accepts ISO-8601 strings, epoch milliseconds, and null
+func (t *Timestamp) UnmarshalJSON(data []byte) error {
+	if bytes.Equal(data, []byte("null")) {
+		return nil
+	}
+
+	if len(data) > 0 && data[0] == '"' {
+		return json.Unmarshal(data, &t.Time)
+	}
+
+	millis, err := strconv.ParseInt(string(data), 10, 64)
+	if err != nil {
+		return err
+	}
+	t.Time = time.Unix(0, millis*int64(time.Millisecond)).UTC()
+	return nil
+}
+
+// AutomationStep a single step of an automation move
+type AutomationStep struct {
+	Step       string     `json:"step"`
+	StepDoc    string     `json:"stepDoc,omitempty"`
+	IsWaitStep bool       `json:"isWaitStep,omitempty"`
+	Started    *Timestamp `json:"started,omitempty"`
+	Completed  *Timestamp `json:"completed,omitempty"`
+	Result     string     `json:"result,omitempty"`
+}
+
+// AutomationMove a move the automation agent makes towards the goal state, e.g. Download, Start, WaitRsInit
+type AutomationMove struct {
+	Move    string           `json:"move"`
+	MoveDoc string           `json:"moveDoc,omitempty"`
+	Steps   []AutomationStep `json:"steps,omitempty"`
+}
+
+// AutomationPlan the moves the automation agent still has to make to reach the goal state
+//
+// The public API reports plans as a list of move names (e.g. ["Download", "Start"]), while the agents report
+// detailed moves, including steps; both formats are accepted
+type AutomationPlan []AutomationMove
+
+// UnmarshalJSON decodes either a list of move names or a list of detailed moves
+func (p *AutomationPlan) UnmarshalJSON(data []byte) error {
+	var raw []json.RawMessage
+	if err := json.Unmarshal(data, &raw); err != nil {
+		return err
+	}
+
+	result := make(AutomationPlan, 0, len(raw))
+	for _, r := range raw {
+		var move AutomationMove
+		if len(r) > 0 && r[0] == '"' {
+			if err := json.Unmarshal(r, &move.Move); err != nil {
+				return err
+			}
+		} else if err := json.Unmarshal(r, &move); err != nil {
+			return err
+		}
+		result = append(result, move)
+	}
+
+	*p = result
+	return nil
+}
+
+// MarshalJSON encodes the plan as a list of move names, unless any of the moves contains details
+func (p AutomationPlan) MarshalJSON() ([]byte, error) {
+	if p == nil {
+		return []byte("null"), nil
+	}
+
+	names := make([]string, 0, len(p))
+	for _, m := range p {
+		if m.MoveDoc != "" || len(m.Steps) > 0 {
+			return json.Marshal([]AutomationMove(p))
+		}
+		names = append(names, m.Move)
+	}
+
+	return json.Marshal(names)
+}
+
+// Moves returns the names of all the moves in the plan
+func (p AutomationPlan) Moves() []string {
+	result := make([]string, 0, len(p))
+	for _, m := range p {
+		result = append(result, m.Move)
+	}
+
+	return result
+}
+
+// InProgress returns the move and step the agent is currently working on
+// If the plan does not contain step details, the first move is returned, with a nil step
+// The second return value is nil if no details are available or no step was started
+func (p AutomationPlan) InProgress() (*AutomationMove, *AutomationStep) {
+	for i := range p {
+		move := &p[i]
+		for j := range move.Steps {
+			step := &move.Steps[j]
+			if step.Completed == nil {
+				if step.Started == nil {
+					return move, nil
+				}
+				return move, step
+			}
+		}
+		if len(move.Steps) == 0 {
+			return move, nil
+		}
+	}
+
+	return nil, nil
+}
+
+// ProcessStatus the automation status of a single process
+type ProcessStatus struct {
+	Name                    string         `json:"name"`
+	Hostname                string         `json:"hostname"`
+	LastGoalVersionAchieved int            `json:"lastGoalVersionAchieved"`
+	Plan                    AutomationPlan `json:"plan"`
+	ErrorCode               int            `json:"errorCode,omitempty"`
+	ErrorCodeDescription    string         `json:"errorCodeDescription,omitempty"`
+	ErrorCodeHumanReadable  string         `json:"errorCodeHumanReadable,omitempty"`
+	LastError               string         `json:"lastError,omitempty"`
+	ErrorCount              int            `json:"errorCount,omitempty"`
+}
+
+// DeploymentJobStatus the status of a deployment job, as seen by the automation agents
+type DeploymentJobStatus struct {
+	ID           string     `json:"id,omitempty"`
+	Type         string     `json:"type,omitempty"`
+	Hostname     string     `json:"hostname,omitempty"`
+	ProcessName  string     `json:"processName,omitempty"`
+	Status       string     `json:"status,omitempty"`
+	ErrorMessage string     `json:"errorMessage,omitempty"`
+	Started      *Timestamp `json:"started,omitempty"`
+	Updated      *Timestamp `json:"updated,omitempty"`
+	Completed    *Timestamp `json:"completed,omitempty"`
+}
+
+// IsFinished returns true if the job completed, successfully or not
+func (j DeploymentJobStatus) IsFinished() bool {
+	return j.Completed != nil
+}
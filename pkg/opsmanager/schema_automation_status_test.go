@@ -0,0 +1,204 @@
+// Copyright 2020 MongoDB Inc
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package opsmanager
+
+import (
+	"encoding/json"
+	"reflect"
+	"testing"
+	"time"
+)
+
+func TestTimestamp_UnmarshalJSON(t *testing.T) {
+	expected := time.Date(2020, 6, 1, 12, 30, 0, 0, time.UTC)
+	tests := []struct {
+		name     string
+		data     string
+		expected time.Time
+		wantErr  bool
+	}{
+		{name: "ISO-8601", data: `"2020-06-01T12:30:00Z"`, expected: expected},
+		{name: "epoch milliseconds", data: `1591014600000`, expected: expected},
+		{name: "null", data: `null`},
+		{name: "invalid string", data: `"yesterday"`, wantErr: true},
+		{name: "invalid number", data: `1.5`, wantErr: true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			var ts Timestamp
+			err := json.Unmarshal([]byte(tt.data), &ts)
+			if tt.wantErr {
+				if err == nil {
+					t.Fatal("expected an error")
+				}
+				return
+			}
+			if err != nil {
+				t.Fatal(err)
+			}
+			if !ts.Time.Equal(tt.expected) {
+				t.Errorf("expected %v, got %v", tt.expected, ts.Time)
+			}
+		})
+	}
+}
+
+func TestAutomationPlan_UnmarshalJSON(t *testing.T) {
+	started := Timestamp{time.Date(2020, 6, 1, 12, 30, 0, 0, time.UTC)}
+	tests := []struct {
+		name     string
+		data     string
+		expected AutomationPlan
+		wantErr  bool
+	}{
+		{
+			name:     "move names",
+			data:     `["Download", "Start"]`,
+			expected: AutomationPlan{{Move: "Download"}, {Move: "Start"}},
+		},
+		{
+			name: "detailed moves",
+			data: `[{"move": "WaitRsInit", "moveDoc": "Wait for the replica set to be initialized",
+				"steps": [{"step": "WaitRsInit", "isWaitStep": true, "started": "2020-06-01T12:30:00Z", "completed": null}]}]`,
+			expected: AutomationPlan{{Move: "WaitRsInit", MoveDoc: "Wait for the replica set to be initialized",
+				Steps: []AutomationStep{{Step: "WaitRsInit", IsWaitStep: true, Started: &started}}}},
+		},
+		{
+			name:     "mixed",
+			data:     `["Download", {"move": "Start"}]`,
+			expected: AutomationPlan{{Move: "Download"}, {Move: "Start"}},
+		},
+		{
+			name:     "empty",
+			data:     `[]`,
+			expected: AutomationPlan{},
+		},
+		{
+			name:    "not a list",
+			data:    `"Download"`,
+			wantErr: true,
+		},
+		{
+			name:    "invalid move",
+			data:    `[1]`,
+			wantErr: true,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			var plan AutomationPlan
+			err := json.Unmarshal([]byte(tt.data), &plan)
+			if tt.wantErr {
+				if err == nil {
+					t.Fatal("expected an error")
+				}
+				return
+			}
+			if err != nil {
+				t.Fatal(err)
+			}
+			if !reflect.DeepEqual(plan, tt.expected) {
+				t.Errorf("expected %+v, got %+v", tt.expected, plan)
+			}
+		})
+	}
+}
+
+func TestAutomationPlan_MarshalJSON(t *testing.T) {
+	tests := []struct {
+		name     string
+		plan     AutomationPlan
+		expected string
+	}{
+		{name: "nil", plan: nil, expected: `null`},
+		{name: "move names", plan: AutomationPlan{{Move: "Download"}, {Move: "Start"}}, expected: `["Download","Start"]`},
+		{
+			name:     "detailed moves",
+			plan:     AutomationPlan{{Move: "Download"}, {Move: "Start", Steps: []AutomationStep{{Step: "StartFresh"}}}},
+			expected: `[{"move":"Download"},{"move":"Start","steps":[{"step":"StartFresh"}]}]`,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			data, err := json.Marshal(tt.plan)
+			if err != nil {
+				t.Fatal(err)
+			}
+			if string(data) != tt.expected {
+				t.Errorf("expected %s, got %s", tt.expected, data)
+			}
+
+			// the process plan is omitted when empty, and must round-trip otherwise
+			var decoded AutomationPlan
+			if err := json.Unmarshal(data, &decoded); err != nil {
+				t.Fatal(err)
+			}
+			if tt.plan != nil && !reflect.DeepEqual(decoded, tt.plan) {
+				t.Errorf("expected %+v to round-trip, got %+v", tt.plan, decoded)
+			}
+		})
+	}
+}
+
+func TestAutomationPlan_InProgress(t *testing.T) {
+	started := &Timestamp{time.Date(2020, 6, 1, 12, 30, 0, 0, time.UTC)}
+	tests := []struct {
+		name         string
+		plan         AutomationPlan
+		expectedMove string
+		expectedStep string
+	}{
+		{name: "empty"},
+		{name: "move names", plan: AutomationPlan{{Move: "Download"}, {Move: "Start"}}, expectedMove: "Download"},
+		{
+			name: "step in progress",
+			plan: AutomationPlan{
+				{Move: "Download", Steps: []AutomationStep{{Step: "Download", Started: started, Completed: started}}},
+				{Move: "Start", Steps: []AutomationStep{{Step: "StartFresh", Started: started}, {Step: "WaitRsInit"}}},
+			},
+			expectedMove: "Start",
+			expectedStep: "StartFresh",
+		},
+		{
+			name:         "step not started",
+			plan:         AutomationPlan{{Move: "Start", Steps: []AutomationStep{{Step: "StartFresh"}}}},
+			expectedMove: "Start",
+		},
+		{
+			name: "all steps completed",
+			plan: AutomationPlan{{Move: "Start", Steps: []AutomationStep{{Step: "StartFresh", Started: started, Completed: started}}}},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			move, step := tt.plan.InProgress()
+
+			var moveName, stepName string
+			if move != nil {
+				moveName = move.Move
+			}
+			if step != nil {
+				stepName = step.Step
+			}
+			if moveName != tt.expectedMove || stepName != tt.expectedStep {
+				t.Errorf("expected %q/%q, got %q/%q", tt.expectedMove, tt.expectedStep, moveName, stepName)
+			}
+		})
+	}
+}
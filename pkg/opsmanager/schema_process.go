@@ -63,18 +63,19 @@ type LogRotate struct {
 }
 
 // Process represents a single process in a deployment
+// Plan used to be a []string; use Plan.Moves() to get the names of the moves
 type Process struct {
-	Name                        string     `json:"name,omitempty"`
-	ProcessType                 string     `json:"processType,omitempty"`
-	Version                     string     `json:"version,omitempty"`
-	AuthSchemaVersion           int        `json:"authSchemaVersion,omitempty"`
-	FeatureCompatibilityVersion string     `json:"featureCompatibilityVersion,omitempty"`
-	Disabled                    bool       `json:"disabled,omitempty"`
-	ManualMode                  bool       `json:"manualMode,omitempty"`
-	Hostname                    string     `json:"hostname,omitempty"`
-	Args26                      *Args26    `json:"args2_6,omitempty"`
-	LogRotate                   *LogRotate `json:"logRotate,omitempty"`
-	Plan                        []string   `json:"plan,omitempty"`
-	LastGoalVersionAchieved     int        `json:"lastGoalVersionAchieved,omitempty"`
-	Cluster                     string     `json:"cluster,omitempty"`
+	Name                        string         `json:"name,omitempty"`
+	ProcessType                 string         `json:"processType,omitempty"`
+	Version                     string         `json:"version,omitempty"`
+	AuthSchemaVersion           int            `json:"authSchemaVersion,omitempty"`
+	FeatureCompatibilityVersion string         `json:"featureCompatibilityVersion,omitempty"`
+	Disabled                    bool           `json:"disabled,omitempty"`
+	ManualMode                  bool           `json:"manualMode,omitempty"`
+	Hostname                    string         `json:"hostname,omitempty"`
+	Args26                      *Args26        `json:"args2_6,omitempty"`
+	LogRotate                   *LogRotate     `json:"logRotate,omitempty"`
+	Plan                        AutomationPlan `json:"plan,omitempty"`
+	LastGoalVersionAchieved     int            `json:"lastGoalVersionAchieved,omitempty"`
+	Cluster                     string         `json:"cluster,omitempty"`
 }
@@ -0,0 +1,80 @@
+// Copyright 2020 MongoDB Inc
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package opsmanager
+
+import (
+	"errors"
+	"fmt"
+	"time"
+)
+
+const (
+	// DefaultPollInterval the interval used when polling Ops Manager, if none was specified
+	DefaultPollInterval = 5 * time.Second
+	// DefaultPollTimeout the maximum time spent polling Ops Manager, if none was specified
+	DefaultPollTimeout = 30 * time.Minute
+)
+
+// ErrWaitTimeout is returned when a condition was not met in the allotted time
+var ErrWaitTimeout = errors.New("timed out while waiting for the condition to be met")
+
+// WaitForGoalState polls the automation status of the specified project until all processes have reached the goal version
+// https://docs.opsmanager.mongodb.com/master/reference/api/automation-status/
+func WaitForGoalState(client Client, projectID string, interval time.Duration, timeout time.Duration) error {
+	return pollUntil(interval, timeout, func() (bool, error) {
+		return isInGoalState(client, projectID)
+	})
+}
+
+func isInGoalState(client Client, projectID string) (bool, error) {
+	status, err := client.GetAutomationStatus(projectID)
+	if err != nil {
+		return false, err
+	}
+
+	for _, process := range status.Processes {
+		if process.LastGoalVersionAchieved != status.GoalVersion {
+			return false, nil
+		}
+	}
+
+	return true, nil
+}
+
+// pollUntil calls the condition every interval, until it returns true, an error, or the timeout expires
+func pollUntil(interval time.Duration, timeout time.Duration, condition func() (bool, error)) error {
+	if interval <= 0 {
+		interval = DefaultPollInterval
+	}
+	if timeout <= 0 {
+		timeout = DefaultPollTimeout
+	}
+
+	deadline := time.Now().Add(timeout)
+	for {
+		done, err := condition()
+		if err != nil {
+			return err
+		}
+		if done {
+			return nil
+		}
+
+		if time.Now().Add(interval).After(deadline) {
+			return fmt.Errorf("gave up after %v: %w", timeout, ErrWaitTimeout)
+		}
+		time.Sleep(interval)
+	}
+}
@@ -0,0 +1,75 @@
+// Copyright 2020 MongoDB Inc
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package opsmanager
+
+import (
+	"errors"
+	"testing"
+	"time"
+)
+
+// failingStatusClient fails every automation status request
+type failingStatusClient struct {
+	Client
+}
+
+func (failingStatusClient) GetAutomationStatus(string) (AutomationStatusResponse, error) {
+	return AutomationStatusResponse{}, errors.New("unavailable")
+}
+
+func TestWaitForGoalState(t *testing.T) {
+	client := newFakeClient(liveConfig())
+	polls := 0
+	client.status = func(config AutomationConfig) AutomationStatusResponse {
+		polls++
+		// rs_2 reaches the goal version on the third poll
+		achieved := *config.Version
+		if polls < 3 {
+			achieved--
+		}
+		return AutomationStatusResponse{GoalVersion: *config.Version, Processes: []ProcessStatus{
+			{Name: "rs_0", LastGoalVersionAchieved: *config.Version},
+			{Name: "rs_2", LastGoalVersionAchieved: achieved},
+		}}
+	}
+
+	if err := WaitForGoalState(client, "p", time.Millisecond, time.Second); err != nil {
+		t.Fatal(err)
+	}
+	if polls != 3 {
+		t.Errorf("expected 3 polls, got %d", polls)
+	}
+}
+
+func TestWaitForGoalState_timeout(t *testing.T) {
+	client := newFakeClient(liveConfig())
+	client.status = func(config AutomationConfig) AutomationStatusResponse {
+		return AutomationStatusResponse{GoalVersion: *config.Version, Processes: []ProcessStatus{
+			{Name: "rs_0", LastGoalVersionAchieved: *config.Version - 1},
+		}}
+	}
+
+	err := WaitForGoalState(client, "p", time.Millisecond, 10*time.Millisecond)
+	if !errors.Is(err, ErrWaitTimeout) {
+		t.Fatalf("expected ErrWaitTimeout, got %v", err)
+	}
+}
+
+func TestWaitForGoalState_error(t *testing.T) {
+	err := WaitForGoalState(failingStatusClient{}, "p", time.Millisecond, time.Second)
+	if err == nil || errors.Is(err, ErrWaitTimeout) {
+		t.Fatalf("expected the status error, got %v", err)
+	}
+}